@@ -0,0 +1,142 @@
+// Package animation plays animated images, such as animated GIFs, in the
+// terminal using the sixel or Kitty graphics protocols.
+//
+// Decode the animation into full [Frame]s, turn them into timed [Update]s
+// for the graphics protocol the terminal supports, and [Play] them.
+//
+//	g, err := gif.DecodeAll(f)
+//	if err != nil {
+//	  return err
+//	}
+//	updates, err := animation.SixelUpdates(animation.DecodeGIF(g))
+//	if err != nil {
+//	  return err
+//	}
+//	return animation.Play(ctx, os.Stdout, updates, animation.GIFLoops(g))
+package animation
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// Frame is a full frame of an animation.
+type Frame struct {
+	// Image is the frame image.
+	Image image.Image
+
+	// Delay is the time to show the frame.
+	Delay time.Duration
+}
+
+// DecodeGIF returns the full frames of an animated GIF. GIF frames often only
+// cover the part of the image that changes, so each frame is drawn over the
+// previous ones according to the disposal method of the frame before it.
+func DecodeGIF(g *gif.GIF) []Frame {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		for _, m := range g.Image {
+			bounds = bounds.Union(m.Bounds())
+		}
+	}
+
+	frames := make([]Frame, 0, len(g.Image))
+	canvas := image.NewNRGBA(bounds)
+	var previous *image.NRGBA
+	for i, m := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = cloneNRGBA(canvas)
+		}
+
+		draw.Draw(canvas, m.Bounds(), m, m.Bounds().Min, draw.Over)
+
+		var delay time.Duration
+		if i < len(g.Delay) {
+			// GIF delays are in hundredths of a second.
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond //nolint:gomnd
+		}
+		frames = append(frames, Frame{Image: cloneNRGBA(canvas), Delay: delay})
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, m.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return frames
+}
+
+// GIFLoops returns the number of times to play an animated GIF, for [Play].
+func GIFLoops(g *gif.GIF) int {
+	switch {
+	case g.LoopCount == 0:
+		return 0
+	case g.LoopCount < 0:
+		return 1
+	default:
+		return g.LoopCount + 1
+	}
+}
+
+// cloneNRGBA returns a copy of m.
+func cloneNRGBA(m *image.NRGBA) *image.NRGBA {
+	c := *m
+	c.Pix = append([]byte(nil), m.Pix...)
+	return &c
+}
+
+// Update is a sequence to write to the terminal, followed by a delay before
+// the next update.
+type Update struct {
+	Sequence string
+	Delay    time.Duration
+}
+
+// Play writes the updates to w, waiting for the delay of each update before
+// writing the next one. It plays the updates the given number of times, or
+// forever if loops is zero, until ctx is done.
+func Play(ctx context.Context, w io.Writer, updates []Update, loops int) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	for n := 0; loops == 0 || n < loops; n++ {
+		for _, u := range updates {
+			if err := ctx.Err(); err != nil {
+				return err //nolint:wrapcheck
+			}
+			if _, err := io.WriteString(w, u.Sequence); err != nil {
+				return err //nolint:wrapcheck
+			}
+			if u.Delay > 0 {
+				if err := sleep(ctx, u.Delay); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-t.C:
+		return nil
+	}
+}
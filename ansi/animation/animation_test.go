@@ -0,0 +1,152 @@
+package animation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	red   = color.NRGBA{R: 0xff, A: 0xff}
+	blue  = color.NRGBA{B: 0xff, A: 0xff}
+	green = color.NRGBA{G: 0xff, A: 0xff}
+	none  = color.NRGBA{}
+)
+
+func testGIF() *gif.GIF {
+	pal := color.Palette{color.Transparent, red, blue, green}
+	frame := func(x0, x1 int, c uint8) *image.Paletted {
+		m := image.NewPaletted(image.Rect(x0, 0, x1, 1), pal)
+		for i := range m.Pix {
+			m.Pix[i] = c
+		}
+		return m
+	}
+	return &gif.GIF{
+		Image: []*image.Paletted{
+			frame(0, 3, 1),
+			frame(1, 2, 2),
+			frame(2, 3, 3),
+			frame(0, 1, 0),
+		},
+		Delay:    []int{10, 20, 0, 5},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalPrevious, gif.DisposalNone},
+		Config:   image.Config{Width: 3, Height: 1},
+	}
+}
+
+func TestDecodeGIF(t *testing.T) {
+	frames := DecodeGIF(testGIF())
+	want := []struct {
+		pixels [3]color.NRGBA
+		delay  time.Duration
+	}{
+		{[3]color.NRGBA{red, red, red}, 100 * time.Millisecond},
+		{[3]color.NRGBA{red, blue, red}, 200 * time.Millisecond},
+		// The blue pixel was disposed to the background.
+		{[3]color.NRGBA{red, none, green}, 0},
+		// The green pixel was disposed to the previous frame, and the
+		// transparent pixel doesn't change anything.
+		{[3]color.NRGBA{red, none, red}, 50 * time.Millisecond},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(want))
+	}
+	for i, w := range want {
+		f := frames[i]
+		for x, c := range w.pixels {
+			if got := f.Image.At(x, 0); got != c {
+				t.Errorf("frame %d pixel %d = %v, want %v", i, x, got, c)
+			}
+		}
+		if f.Delay != w.delay {
+			t.Errorf("frame %d delay = %v, want %v", i, f.Delay, w.delay)
+		}
+	}
+}
+
+func TestGIFLoops(t *testing.T) {
+	for loopCount, want := range map[int]int{0: 0, -1: 1, 2: 3} {
+		if got := GIFLoops(&gif.GIF{LoopCount: loopCount}); got != want {
+			t.Errorf("GIFLoops(%d) = %d, want %d", loopCount, got, want)
+		}
+	}
+}
+
+func TestPlay(t *testing.T) {
+	updates := []Update{{Sequence: "a"}, {Sequence: "b", Delay: time.Millisecond}}
+	var buf bytes.Buffer
+	if err := Play(context.Background(), &buf, updates, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "abab" {
+		t.Errorf("Play() wrote %q, want %q", got, "abab")
+	}
+
+	// Playing forever stops when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	buf.Reset()
+	if err := Play(ctx, &buf, updates, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Play() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if buf.Len() < 4 {
+		t.Errorf("Play() wrote %q, want more than one loop", buf.String())
+	}
+}
+
+func TestSixelUpdates(t *testing.T) {
+	updates, err := SixelUpdates(DecodeGIF(testGIF()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 4 {
+		t.Fatalf("got %d updates, want 4", len(updates))
+	}
+	for i, u := range updates {
+		if !strings.HasPrefix(u.Sequence, "\x1b7\x1bP0;1q") || !strings.HasSuffix(u.Sequence, "\x1b\\\x1b8") {
+			t.Errorf("update %d = %q, want a sixel image between cursor save and restore", i, u.Sequence)
+		}
+	}
+	if updates[1].Delay != 200*time.Millisecond {
+		t.Errorf("update 1 delay = %v, want 200ms", updates[1].Delay)
+	}
+}
+
+func TestKittyUpdates(t *testing.T) {
+	updates, err := KittyUpdates(DecodeGIF(testGIF()), 7, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 5 {
+		t.Fatalf("got %d updates, want 5", len(updates))
+	}
+
+	first := updates[0].Sequence
+	if !strings.HasPrefix(first, "\x1b_Gf=100,q=2,i=7,C=1,a=T;") {
+		t.Errorf("first update = %q, want a transmit and put", first)
+	}
+	if !strings.HasSuffix(first, "\x1b_Ga=a,i=7,q=2,r=1,z=100\x1b\\") {
+		t.Errorf("first update = %q, want the root frame gap", first)
+	}
+	if second := updates[1].Sequence; !strings.HasPrefix(second, "\x1b_Gf=100,q=2,i=7,a=f,z=200;") {
+		t.Errorf("second update = %q, want a frame", second)
+	}
+	if third := updates[2].Sequence; !strings.HasPrefix(third, "\x1b_Gf=100,q=2,i=7,a=f,z=1;") {
+		t.Errorf("third update = %q, want a frame with the minimum gap", third)
+	}
+	if last, want := updates[4].Sequence, "\x1b_Ga=a,i=7,q=2,s=3,v=1\x1b\\"; last != want {
+		t.Errorf("last update = %q, want %q", last, want)
+	}
+	for i, u := range updates {
+		if u.Delay != 0 {
+			t.Errorf("update %d delay = %v, want 0", i, u.Delay)
+		}
+	}
+}
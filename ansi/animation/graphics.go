@@ -0,0 +1,96 @@
+package animation
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+	"github.com/charmbracelet/x/ansi/sixel"
+)
+
+// SixelUpdates returns updates that draw each frame as a sixel image at the
+// cursor position, leaving the cursor in place so that each frame replaces
+// the previous one. Fully transparent pixels are not drawn, so the frames
+// should be opaque where they change.
+func SixelUpdates(frames []Frame) ([]Update, error) {
+	e := &sixel.Encoder{
+		RLE:            true,
+		Dither:         sixel.DitherFloydSteinberg,
+		AlphaThreshold: 1,
+	}
+
+	updates := make([]Update, 0, len(frames))
+	var data bytes.Buffer
+	for i, f := range frames {
+		data.Reset()
+		if err := e.Encode(&data, f.Image); err != nil {
+			return nil, fmt.Errorf("failed to encode frame %d: %w", i, err)
+		}
+		updates = append(updates, Update{
+			Sequence: ansi.SaveCursor + ansi.SixelGraphics(0, 1, 0, data.Bytes()) + ansi.RestoreCursor,
+			Delay:    f.Delay,
+		})
+	}
+
+	return updates, nil
+}
+
+// KittyUpdates returns updates that transmit the frames to the terminal as a
+// Kitty graphics animation with the given image ID, display it at the cursor
+// position, and start it. The terminal plays the animation by itself the
+// given number of times, or forever if loops is zero, so the updates have no
+// delays and should be played once.
+func KittyUpdates(frames []Frame, id, loops int) ([]Update, error) {
+	if len(frames) == 0 {
+		return nil, nil
+	}
+
+	e := &kitty.Encoder{Format: kitty.PNG}
+	updates := make([]Update, 0, len(frames)+1)
+	var data bytes.Buffer
+	for i, f := range frames {
+		data.Reset()
+		if err := e.Encode(&data, f.Image); err != nil {
+			return nil, fmt.Errorf("failed to encode frame %d: %w", i, err)
+		}
+
+		o := kitty.Options{ID: id, Format: kitty.PNG, Quite: 2} //nolint:gomnd
+		var frameOpts []string
+		if i == 0 {
+			o.Action = kitty.TransmitAndPut
+			o.DoNotMoveCursor = true
+		} else {
+			o.Action = kitty.Frame
+			frameOpts = kitty.FrameOptions{Gap: gap(f.Delay)}.Options()
+		}
+
+		seq := ansi.KittyGraphicsChunks(data.Bytes(), append(o.Options(), frameOpts...)...)
+		if i == 0 {
+			// The first frame is the root frame, set its gap separately.
+			seq += ansi.KittyGraphicsAnimation(kitty.AnimationOptions{ID: id, Quite: 2, EditFrame: 1, Gap: gap(f.Delay)}) //nolint:gomnd
+		}
+		updates = append(updates, Update{Sequence: seq})
+	}
+
+	// Kitty loops forever with 1 and n-1 times with n.
+	v := 1
+	if loops > 0 {
+		v = loops + 1
+	}
+	updates = append(updates, Update{
+		Sequence: ansi.KittyGraphicsAnimation(kitty.AnimationOptions{ID: id, Quite: 2, State: kitty.AnimationRun, Loops: v}), //nolint:gomnd
+	})
+
+	return updates, nil
+}
+
+// gap returns the Kitty frame gap of a delay in milliseconds. Kitty treats a
+// zero gap as the default gap, so it's at least 1.
+func gap(d time.Duration) int {
+	if ms := int(d / time.Millisecond); ms > 0 {
+		return ms
+	}
+	return 1
+}
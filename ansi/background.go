@@ -1,8 +1,12 @@
 package ansi
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"image/color"
+	"strconv"
+	"strings"
 )
 
 // Colorizer is a [color.Color] interface that can be formatted as a string.
@@ -68,6 +72,64 @@ func (x XRGBAColorizer) String() string {
 	return fmt.Sprintf("rgba:%04x/%04x/%04x/%04x", r, g, b, a)
 }
 
+// colorString encodes a color for the color control sequences. Colors that
+// implement [fmt.Stringer], such as the [Colorizer] types, use their own
+// representation, other colors are encoded as hex strings.
+func colorString(c color.Color) string {
+	switch c := c.(type) {
+	case Colorizer:
+		return c.String()
+	case fmt.Stringer:
+		return c.String()
+	default:
+		return HexColorizer{c}.String()
+	}
+}
+
+// SetPaletteColor returns a sequence that sets the terminal palette color at
+// the given index.
+//
+//	OSC 4 ; index ; color ST
+//	OSC 4 ; index ; color BEL
+//
+// Where color is the encoded color number.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+func SetPaletteColor(i int, c color.Color) string {
+	return "\x1b]4;" + strconv.Itoa(i) + ";" + colorString(c) + "\x07"
+}
+
+// RequestPaletteColor returns a sequence that requests the terminal palette
+// color at the given index. The terminal responds with an OSC 4 sequence
+// that can be parsed using [ParsePaletteColor].
+//
+//	OSC 4 ; index ; ? ST
+//	OSC 4 ; index ; ? BEL
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+func RequestPaletteColor(i int) string {
+	return "\x1b]4;" + strconv.Itoa(i) + ";?\x07"
+}
+
+// ResetPaletteColor returns a sequence that resets the terminal palette
+// colors at the given indexes to their default values. With no indexes, the
+// whole palette is reset.
+//
+//	OSC 104 ; index ; ... ST
+//	OSC 104 ; index ; ... BEL
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
+func ResetPaletteColor(indexes ...int) string {
+	var b strings.Builder
+	b.WriteString("\x1b]104")
+	for _, idx := range indexes {
+		b.WriteByte(';')
+		b.WriteString(strconv.Itoa(idx))
+	}
+	b.WriteByte(BEL)
+	return b.String()
+}
+
 // SetForegroundColor returns a sequence that sets the default terminal
 // foreground color.
 //
@@ -78,16 +140,7 @@ func (x XRGBAColorizer) String() string {
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
 func SetForegroundColor(c color.Color) string {
-	var s string
-	switch c := c.(type) {
-	case Colorizer:
-		s = c.String()
-	case fmt.Stringer:
-		s = c.String()
-	default:
-		s = HexColorizer{c}.String()
-	}
-	return "\x1b]10;" + s + "\x07"
+	return "\x1b]10;" + colorString(c) + "\x07"
 }
 
 // RequestForegroundColor is a sequence that requests the current default
@@ -112,16 +165,7 @@ const ResetForegroundColor = "\x1b]110\x07"
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
 func SetBackgroundColor(c color.Color) string {
-	var s string
-	switch c := c.(type) {
-	case Colorizer:
-		s = c.String()
-	case fmt.Stringer:
-		s = c.String()
-	default:
-		s = HexColorizer{c}.String()
-	}
-	return "\x1b]11;" + s + "\x07"
+	return "\x1b]11;" + colorString(c) + "\x07"
 }
 
 // RequestBackgroundColor is a sequence that requests the current default
@@ -145,16 +189,7 @@ const ResetBackgroundColor = "\x1b]111\x07"
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
 func SetCursorColor(c color.Color) string {
-	var s string
-	switch c := c.(type) {
-	case Colorizer:
-		s = c.String()
-	case fmt.Stringer:
-		s = c.String()
-	default:
-		s = HexColorizer{c}.String()
-	}
-	return "\x1b]12;" + s + "\x07"
+	return "\x1b]12;" + colorString(c) + "\x07"
 }
 
 // RequestCursorColor is a sequence that requests the current terminal cursor
@@ -167,3 +202,58 @@ const RequestCursorColor = "\x1b]12;?\x07"
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Operating-System-Commands
 const ResetCursorColor = "\x1b]112\x07"
+
+// ErrInvalidColor is returned by [ParseColor] and [ParsePaletteColor] when
+// the data is not a valid color response.
+var ErrInvalidColor = errors.New("invalid color response")
+
+// ParseColor parses the response to [RequestForegroundColor],
+// [RequestBackgroundColor], or [RequestCursorColor] from the data of an OSC
+// sequence, that is the sequence without its introducer and terminator, as
+// returned by [Parser.Data]. It returns the OSC command, 10, 11, or 12, and
+// the color.
+//
+//	Ps ; rgb:RRRR/GGGG/BBBB
+//
+// Colors are parsed using [XParseColor].
+func ParseColor(data []byte) (cmd int, c color.Color, err error) {
+	ps, pc, ok := bytes.Cut(data, []byte{';'})
+	if !ok {
+		return 0, nil, ErrInvalidColor
+	}
+	switch string(ps) {
+	case "10", "11", "12":
+		cmd = 10 + int(ps[1]-'0')
+	default:
+		return 0, nil, ErrInvalidColor
+	}
+	c = XParseColor(string(pc))
+	if c == nil {
+		return 0, nil, ErrInvalidColor
+	}
+	return cmd, c, nil
+}
+
+// ParsePaletteColor parses the response to [RequestPaletteColor] from the
+// data of an OSC sequence, as returned by [Parser.Data]. It returns the
+// palette index and the color.
+//
+//	4 ; index ; rgb:RRRR/GGGG/BBBB
+func ParsePaletteColor(data []byte) (i int, c color.Color, err error) {
+	if !bytes.HasPrefix(data, []byte("4;")) {
+		return 0, nil, ErrInvalidColor
+	}
+	pi, pc, ok := bytes.Cut(data[2:], []byte{';'})
+	if !ok {
+		return 0, nil, ErrInvalidColor
+	}
+	i, err = strconv.Atoi(string(pi))
+	if err != nil || i < 0 || i > 255 {
+		return 0, nil, ErrInvalidColor
+	}
+	c = XParseColor(string(pc))
+	if c == nil {
+		return 0, nil, ErrInvalidColor
+	}
+	return i, c, nil
+}
@@ -1,6 +1,7 @@
 package ansi_test
 
 import (
+	"image/color"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
@@ -47,3 +48,85 @@ func TestColorizer(t *testing.T) {
 		t.Errorf("Unexpected sequence for XRGBAColorizer: got %q", seq)
 	}
 }
+
+func TestPaletteColor(t *testing.T) {
+	if seq := ansi.SetPaletteColor(1, ansi.TrueColor(0xff0000)); seq != "\x1b]4;1;#ff0000\x07" {
+		t.Errorf("Unexpected sequence for SetPaletteColor: got %q", seq)
+	}
+	if seq := ansi.SetPaletteColor(255, ansi.XRGBColorizer{ansi.TrueColor(0x00ff00)}); seq != "\x1b]4;255;rgb:0000/ffff/0000\x07" {
+		t.Errorf("Unexpected sequence for SetPaletteColor: got %q", seq)
+	}
+	if seq := ansi.RequestPaletteColor(12); seq != "\x1b]4;12;?\x07" {
+		t.Errorf("Unexpected sequence for RequestPaletteColor: got %q", seq)
+	}
+}
+
+func TestXParseColor(t *testing.T) {
+	cases := []struct {
+		s    string
+		want color.Color
+	}{
+		{"rgb:ffff/0000/8080", color.RGBA{0xff, 0x00, 0x80, 0xff}},
+		{"rgb:ff/00/80", color.RGBA{0xff, 0x00, 0x80, 0xff}},
+		{"rgb:f/0/8", color.RGBA{0xff, 0x00, 0x88, 0xff}},
+		{"rgb:fff/000/800", color.RGBA{0xff, 0x00, 0x80, 0xff}},
+		{"rgba:ffff/0000/0000/8080", color.RGBA{0xff, 0x00, 0x00, 0x80}},
+		{"rgb:ffff/0000", nil},
+		{"rgb:fffff/0000/0000", nil},
+		{"rgb:zz/00/00", nil},
+		{"rgb://", nil},
+		{"nope", nil},
+	}
+	for _, c := range cases {
+		if got := ansi.XParseColor(c.s); got != c.want {
+			t.Errorf("XParseColor(%q) = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	cmd, c, err := ansi.ParseColor([]byte("11;rgb:1c1c/1c1c/1c1c"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cmd != 11 || c != (color.RGBA{0x1c, 0x1c, 0x1c, 0xff}) {
+		t.Errorf("unexpected result: %d %v", cmd, c)
+	}
+
+	for _, data := range []string{"", "11", "13;rgb:0/0/0", "4;rgb:0/0/0", "10;?", "10;rgb:0/0"} {
+		if _, _, err := ansi.ParseColor([]byte(data)); err != ansi.ErrInvalidColor {
+			t.Errorf("ParseColor(%q) error = %v, want %v", data, err, ansi.ErrInvalidColor)
+		}
+	}
+}
+
+func TestParsePaletteColor(t *testing.T) {
+	i, c, err := ansi.ParsePaletteColor([]byte("4;42;rgb:0000/d7d7/8787"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if i != 42 || c != (color.RGBA{0x00, 0xd7, 0x87, 0xff}) {
+		t.Errorf("unexpected result: %d %v", i, c)
+	}
+
+	for _, data := range []string{"", "4;", "4;1", "4;x;rgb:0/0/0", "4;256;rgb:0/0/0", "10;rgb:0/0/0", "4;1;?"} {
+		if _, _, err := ansi.ParsePaletteColor([]byte(data)); err != ansi.ErrInvalidColor {
+			t.Errorf("ParsePaletteColor(%q) error = %v, want %v", data, err, ansi.ErrInvalidColor)
+		}
+	}
+}
+
+func TestResetColors(t *testing.T) {
+	for seq, want := range map[string]string{
+		ansi.ResetPaletteColor():       "\x1b]104\x07",
+		ansi.ResetPaletteColor(1):      "\x1b]104;1\x07",
+		ansi.ResetPaletteColor(1, 255): "\x1b]104;1;255\x07",
+		ansi.ResetForegroundColor:      "\x1b]110\x07",
+		ansi.ResetBackgroundColor:      "\x1b]111\x07",
+		ansi.ResetCursorColor:          "\x1b]112\x07",
+	} {
+		if seq != want {
+			t.Errorf("expected %q, got %q", want, seq)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package ansi
 
+import "strings"
+
 // C1 control characters.
 //
 // These range from (0x80-0x9F) as defined in ISO 6429 (ECMA-48).
@@ -70,3 +72,74 @@ const (
 	// APC is the application program command character.
 	APC = 0x9F
 )
+
+// To8BitControls returns s with the 7-bit C1 controls, an ESC followed by a
+// byte in the 0x40-0x5F range, replaced with their single byte 8-bit form.
+// For example, "ESC [" becomes CSI (0x9B) and "ESC \" becomes ST (0x9C). The
+// sequences are one byte shorter per control, which adds up with many small
+// sequences. Other bytes are kept as is.
+//
+// The payloads of string sequences, such as DCS, OSC and APC, are kept as is
+// up to their terminator. This keeps the sequences wrapped in a tmux or GNU
+// Screen passthrough intact. Within a payload, an ESC ESC pair is taken as an
+// escaped ESC, as in tmux passthroughs.
+//
+// 8-bit controls are only understood by terminals that don't decode their
+// input as UTF-8, or that are configured to accept them, since the 0x80-0x9F
+// bytes are also UTF-8 continuation bytes.
+//
+// Example:
+//
+//	seq := ansi.To8BitControls(ansi.SetHyperlink("https://example.com"))
+func To8BitControls(s string) string {
+	i := strings.IndexByte(s, ESC)
+	if i < 0 {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	b = append(b, s[:i]...)
+	for ; i < len(s); i++ {
+		if s[i] != ESC || i+1 >= len(s) || s[i+1] < 0x40 || s[i+1] > 0x5f {
+			b = append(b, s[i])
+			continue
+		}
+
+		c := s[i+1]
+		b = append(b, c+0x40)
+		i++
+		switch c {
+		case 'P', 'X', ']', '^', '_':
+			// DCS, SOS, OSC, PM and APC: keep the payload as is.
+			n, term := stringPayloadLen(s[i+1:], c == ']')
+			b = append(b, s[i+1:i+1+n]...)
+			i += n
+			switch term {
+			case BEL:
+				b = append(b, BEL)
+				i++
+			case ST:
+				b = append(b, ST)
+				i += 2
+			}
+		}
+	}
+	return string(b)
+}
+
+// stringPayloadLen returns the length of the payload of a string sequence at
+// the start of s, and its terminator: ST for ESC \, BEL, or 0 if the payload
+// is unterminated. BEL only terminates OSC payloads.
+func stringPayloadLen(s string, osc bool) (int, byte) {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case osc && s[i] == BEL:
+			return i, BEL
+		case s[i] == ESC && i+1 < len(s) && s[i+1] == '\\':
+			return i, ST
+		case s[i] == ESC && i+1 < len(s) && s[i+1] == ESC:
+			// An escaped ESC in a tmux passthrough.
+			i++
+		}
+	}
+	return len(s), 0
+}
@@ -0,0 +1,36 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTo8BitControls(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"hello", "hello"},
+		{"\x1b[1;2H", "\x9b1;2H"},
+		{"\x1b]8;;https://example.com\x1b\\", "\x9d8;;https://example.com\x9c"},
+		{"\x1bPq#0;2;0;0;0\x1b\\", "\x90q#0;2;0;0;0\x9c"},
+		{"\x1b_Gi=1\x1b\\", "\x9fGi=1\x9c"},
+		{"a\x1bOPb", "a\x8fPb"},
+		{"\x1b7\x1b(B\x1bc", "\x1b7\x1b(B\x1bc"},
+		{"\x1b", "\x1b"},
+		{"\x1b\x1b[m", "\x1b\x9bm"},
+		{"\x1b]2;a\x1b[b\a\x1b[m", "\x9d2;a\x1b[b\a\x9bm"},
+		{"\x1b_Ga\x07b\x1b\\", "\x9fGa\x07b\x9c"},
+		{"\x1bPq#0", "\x90q#0"},
+		{
+			"\x1bPtmux;\x1b\x1b]52;c;YQ==\x1b\x1b\\\x1b\\\x1b[m",
+			"\x90tmux;\x1b\x1b]52;c;YQ==\x1b\x1b\\\x9c\x9bm",
+		},
+		{"\x1bP\x1b]52;c;YQ==\x07\x1b\\", "\x90\x1b]52;c;YQ==\x07\x9c"},
+	}
+	for _, tc := range cases {
+		if got := ansi.To8BitControls(tc.in); got != tc.want {
+			t.Errorf("To8BitControls(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
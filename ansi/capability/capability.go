@@ -0,0 +1,189 @@
+// Package capability provides a small, terminfo-free database of terminal
+// capabilities. It maps TERM names, terminal name and version reports, and
+// device attributes to a [Set] of features that applications can use to gate
+// functionality without querying the terminal for each one.
+package capability
+
+import (
+	"strings"
+)
+
+// Set is a set of terminal capabilities.
+type Set uint32
+
+// Terminal capabilities.
+const (
+	// TrueColor indicates support for 24-bit RGB colors.
+	TrueColor Set = 1 << iota
+	// Sixel indicates support for Sixel graphics.
+	Sixel
+	// KittyGraphics indicates support for the Kitty graphics protocol.
+	KittyGraphics
+	// KittyKeyboard indicates support for the Kitty keyboard protocol.
+	KittyKeyboard
+	// SynchronizedOutput indicates support for the synchronized output mode
+	// (mode 2026).
+	SynchronizedOutput
+	// Hyperlinks indicates support for OSC 8 hyperlinks.
+	Hyperlinks
+	// ITerm2Images indicates support for the iTerm2 inline images protocol.
+	ITerm2Images
+	// StyledUnderlines indicates support for underline styles (SGR 4:x) and
+	// underline colors (SGR 58) using colon separated subparameters.
+	StyledUnderlines
+	// PlaySound indicates support for playing notes with DECPS.
+	PlaySound
+	// KittyNotifications indicates support for the kitty desktop
+	// notification protocol (OSC 99).
+	KittyNotifications
+)
+
+// None is the empty capability set.
+const None Set = 0
+
+var names = []struct {
+	cap  Set
+	name string
+}{
+	{TrueColor, "truecolor"},
+	{Sixel, "sixel"},
+	{KittyGraphics, "kitty-graphics"},
+	{KittyKeyboard, "kitty-keyboard"},
+	{SynchronizedOutput, "synchronized-output"},
+	{Hyperlinks, "hyperlinks"},
+	{ITerm2Images, "iterm2-images"},
+	{StyledUnderlines, "styled-underlines"},
+	{PlaySound, "play-sound"},
+	{KittyNotifications, "kitty-notifications"},
+}
+
+// Has returns whether the set contains all the given capabilities.
+func (s Set) Has(c Set) bool {
+	return s&c == c
+}
+
+// String returns a string representation of the set. Capabilities are
+// separated by a "|", for example "truecolor|hyperlinks".
+func (s Set) String() string {
+	if s == None {
+		return "none"
+	}
+	var parts []string
+	for _, n := range names {
+		if s.Has(n.cap) {
+			parts = append(parts, n.name)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// modern is the set of capabilities supported by most modern terminal
+// emulators.
+const modern = TrueColor | SynchronizedOutput | Hyperlinks | StyledUnderlines
+
+// terms maps TERM names to their capabilities.
+var terms = map[string]Set{
+	"alacritty":     modern | KittyKeyboard,
+	"contour":       modern | Sixel,
+	"foot":          modern | Sixel | KittyKeyboard | KittyNotifications,
+	"foot-extra":    modern | Sixel | KittyKeyboard | KittyNotifications,
+	"mlterm":        TrueColor | Sixel,
+	"rio":           modern | Sixel | KittyGraphics | KittyKeyboard,
+	"st":            TrueColor,
+	"st-256color":   TrueColor,
+	"wezterm":       modern | Sixel | KittyGraphics | KittyKeyboard | ITerm2Images,
+	"xterm-ghostty": modern | KittyGraphics | KittyKeyboard,
+	"xterm-kitty":   modern | KittyGraphics | KittyKeyboard | KittyNotifications,
+}
+
+// versions maps terminal names, as reported by XTVERSION, to their
+// capabilities. Names are lowercase.
+var versions = map[string]Set{
+	"contour": modern | Sixel,
+	"foot":    modern | Sixel | KittyKeyboard | KittyNotifications,
+	"ghostty": modern | KittyGraphics | KittyKeyboard,
+	"iterm2":  modern | Sixel | ITerm2Images,
+	"kitty":   modern | KittyGraphics | KittyKeyboard | KittyNotifications,
+	"mintty":  modern | Sixel | ITerm2Images,
+	"rio":     modern | Sixel | KittyGraphics | KittyKeyboard,
+	"wezterm": modern | Sixel | KittyGraphics | KittyKeyboard | ITerm2Images,
+	"xterm":   TrueColor | PlaySound,
+}
+
+// programs maps TERM_PROGRAM values to their capabilities.
+var programs = map[string]Set{
+	"Apple_Terminal": None,
+	"ghostty":        modern | KittyGraphics | KittyKeyboard,
+	"iTerm.app":      modern | Sixel | ITerm2Images,
+	"vscode":         modern,
+	"WezTerm":        modern | Sixel | KittyGraphics | KittyKeyboard | ITerm2Images,
+}
+
+// FromTerm returns the capabilities of the terminal with the given TERM name.
+// Terminal types ending with "-direct" or "-truecolor" are assumed to support
+// [TrueColor]. Unknown terminals and terminal multiplexers such as tmux and
+// screen return [None] since their capabilities depend on the outer terminal.
+func FromTerm(term string) Set {
+	if s, ok := terms[term]; ok {
+		return s
+	}
+	if strings.HasSuffix(term, "-direct") || strings.HasSuffix(term, "-truecolor") {
+		return TrueColor
+	}
+	return None
+}
+
+// FromVersion returns the capabilities of the terminal from its name and
+// version report, as returned by [ansi.RequestNameVersion] (XTVERSION). For
+// example, "kitty(0.36.4)", "WezTerm 20240203-110809-5046fc22", or
+// "XTerm(390)". Unknown terminals return [None].
+//
+// [ansi.RequestNameVersion]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#RequestNameVersion
+func FromVersion(version string) Set {
+	name := version
+	if i := strings.IndexAny(name, " (-"); i >= 0 {
+		name = name[:i]
+	}
+	return versions[strings.ToLower(name)]
+}
+
+// FromPrimaryDeviceAttributes returns the capabilities advertised in a
+// primary device attributes (DA1) report. Only [Sixel] (attribute 4) can be
+// detected this way.
+func FromPrimaryDeviceAttributes(attrs ...int) Set {
+	var s Set
+	for _, a := range attrs {
+		if a == 4 { //nolint:gomnd
+			s |= Sixel
+		}
+	}
+	return s
+}
+
+// FromEnviron returns the capabilities of the terminal from the given
+// environment variables in the form "key=value", such as [os.Environ]. It
+// uses TERM, TERM_PROGRAM, and COLORTERM.
+func FromEnviron(environ []string) Set {
+	var s Set
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "TERM":
+			s |= FromTerm(v)
+		case "TERM_PROGRAM":
+			if p, ok := programs[v]; ok {
+				s |= p
+			} else {
+				s |= FromVersion(v)
+			}
+		case "COLORTERM":
+			if v == "truecolor" || v == "24bit" {
+				s |= TrueColor
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package capability
+
+import "testing"
+
+func TestSetString(t *testing.T) {
+	tests := []struct {
+		set  Set
+		want string
+	}{
+		{None, "none"},
+		{TrueColor, "truecolor"},
+		{TrueColor | Hyperlinks, "truecolor|hyperlinks"},
+		{Sixel | KittyGraphics | KittyKeyboard | SynchronizedOutput, "sixel|kitty-graphics|kitty-keyboard|synchronized-output"},
+		{ITerm2Images | StyledUnderlines, "iterm2-images|styled-underlines"},
+		{PlaySound | KittyNotifications, "play-sound|kitty-notifications"},
+	}
+	for _, tt := range tests {
+		if got := tt.set.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFromTerm(t *testing.T) {
+	tests := []struct {
+		term string
+		want Set
+		not  Set
+	}{
+		{"xterm-kitty", TrueColor | KittyGraphics | KittyKeyboard, Sixel},
+		{"foot", Sixel | KittyKeyboard, KittyGraphics},
+		{"xterm-direct", TrueColor, Hyperlinks},
+		{"alacritty", StyledUnderlines, Sixel},
+		{"xterm-kitty", KittyNotifications, PlaySound},
+		{"st-256color", TrueColor, StyledUnderlines},
+		{"tmux-256color", None, TrueColor},
+		{"dumb", None, TrueColor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			got := FromTerm(tt.term)
+			if !got.Has(tt.want) || tt.not != None && got.Has(tt.not) {
+				t.Errorf("FromTerm(%q) = %v, want %v without %v", tt.term, got, tt.want, tt.not)
+			}
+		})
+	}
+}
+
+func TestFromVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    Set
+	}{
+		{"kitty(0.36.4)", TrueColor | KittyGraphics | KittyKeyboard},
+		{"WezTerm 20240203-110809-5046fc22", Sixel | KittyGraphics},
+		{"XTerm(390)", TrueColor | PlaySound},
+		{"foot(1.16.2)", Sixel | KittyKeyboard},
+		{"unknown 1.0", None},
+		{"", None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := FromVersion(tt.version); !got.Has(tt.want) {
+				t.Errorf("FromVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+	if got := FromVersion("unknown 1.0"); got != None {
+		t.Errorf("FromVersion(unknown) = %v, want none", got)
+	}
+}
+
+func TestFromPrimaryDeviceAttributes(t *testing.T) {
+	if got := FromPrimaryDeviceAttributes(62, 4, 22); got != Sixel {
+		t.Errorf("FromPrimaryDeviceAttributes() = %v, want sixel", got)
+	}
+	if got := FromPrimaryDeviceAttributes(62, 22); got != None {
+		t.Errorf("FromPrimaryDeviceAttributes() = %v, want none", got)
+	}
+}
+
+func TestFromEnviron(t *testing.T) {
+	got := FromEnviron([]string{"TERM=xterm-256color", "COLORTERM=truecolor", "TERM_PROGRAM=iTerm.app", "invalid"})
+	if want := TrueColor | Sixel | Hyperlinks; !got.Has(want) {
+		t.Errorf("FromEnviron() = %v, want %v", got, want)
+	}
+	if got := FromEnviron([]string{"TERM=xterm-256color"}); got != None {
+		t.Errorf("FromEnviron() = %v, want none", got)
+	}
+}
@@ -22,6 +22,9 @@ package ansi
 //   - A United Kingdom (UK)
 //   - B United States (USASCII)
 //
+// National replacement character sets (NRCS), such as K for German, are used
+// when [NationalReplacementCharsetMode] (DECNRCM) is set.
+//
 // Examples:
 //
 //	ESC ( B  Select character set G0 = United States (USASCII)
@@ -1,6 +1,10 @@
 package ansi
 
-import "encoding/base64"
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+)
 
 // Clipboard names.
 const (
@@ -73,3 +77,38 @@ const RequestSystemClipboard = "\x1b]52;c;?\x07"
 //
 // This is equivalent to RequestClipboard(PrimaryClipboard).
 const RequestPrimaryClipboard = "\x1b]52;p;?\x07"
+
+// ErrInvalidClipboard is returned by [ParseClipboard] when the data is not a
+// valid clipboard response.
+var ErrInvalidClipboard = errors.New("invalid clipboard response")
+
+// ParseClipboard parses the response to [RequestClipboard] from the data of
+// an OSC sequence, that is the sequence without its introducer and
+// terminator, as returned by [Parser.Data]. It returns the clipboard name and
+// the decoded content.
+//
+//	52 ; Pc ; Pd
+//
+// When the response names several clipboards, the first one is returned.
+func ParseClipboard(data []byte) (c byte, content string, err error) {
+	if !bytes.HasPrefix(data, []byte("52;")) {
+		return 0, "", ErrInvalidClipboard
+	}
+	pc, pd, ok := bytes.Cut(data[3:], []byte{';'})
+	if !ok || len(pc) == 0 {
+		return 0, "", ErrInvalidClipboard
+	}
+
+	// Some terminals leave out the base64 padding.
+	enc := base64.StdEncoding
+	if len(pd)%4 != 0 {
+		enc = base64.RawStdEncoding
+	}
+	d := make([]byte, enc.DecodedLen(len(pd)))
+	n, err := enc.Decode(d, pd)
+	if err != nil {
+		return 0, "", ErrInvalidClipboard
+	}
+
+	return pc[0], string(d[:n]), nil
+}
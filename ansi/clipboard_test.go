@@ -39,3 +39,37 @@ func TestClipboardRequest(t *testing.T) {
 		t.Errorf("Unexpected clipboard request: %q", cb)
 	}
 }
+
+func TestParseClipboard(t *testing.T) {
+	tt := []struct {
+		data    string
+		name    byte
+		content string
+		err     error
+	}{
+		{"52;c;SGVsbG8gVGVzdA==", 'c', "Hello Test", nil},
+		{"52;p;QW5zaSBUZXN0", 'p', "Ansi Test", nil},
+		{"52;cp;dGVzdA", 'c', "test", nil},
+		{"52;c;", 'c', "", nil},
+		{"52;c;!!!!", 0, "", ansi.ErrInvalidClipboard},
+		{"52;;dGVzdA==", 0, "", ansi.ErrInvalidClipboard},
+		{"52;c", 0, "", ansi.ErrInvalidClipboard},
+		{"11;rgb:0000/0000/0000", 0, "", ansi.ErrInvalidClipboard},
+	}
+	for _, tp := range tt {
+		name, content, err := ansi.ParseClipboard([]byte(tp.data))
+		if err != tp.err || name != tp.name || content != tp.content {
+			t.Errorf("ParseClipboard(%q) = %q, %q, %v, want %q, %q, %v",
+				tp.data, name, content, err, tp.name, tp.content, tp.err)
+		}
+	}
+
+	// Round trip through the decoder.
+	p := ansi.NewParser()
+	p.SetDataSize(1024)
+	seq := ansi.SetClipboard(ansi.SystemClipboard, "round trip")
+	ansi.DecodeSequence(seq, ansi.NormalState, p)
+	if name, content, err := ansi.ParseClipboard(p.Data()); err != nil || name != 'c' || content != "round trip" {
+		t.Errorf("ParseClipboard(%q) = %q, %q, %v", p.Data(), name, content, err)
+	}
+}
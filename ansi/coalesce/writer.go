@@ -0,0 +1,156 @@
+// Package coalesce provides a buffered writer that coalesces many small
+// writes, such as the escape sequences of a renderer, into fewer and larger
+// writes to the terminal. This reduces the syscall overhead and the number of
+// packets sent over slow connections.
+package coalesce
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultSize is the default flush size of a [Writer].
+const DefaultSize = 4096
+
+// Writer is an [io.Writer] that buffers writes and flushes them to the
+// underlying writer when the buffer reaches a size, or after an interval
+// since the first buffered write, whichever comes first. Use [Writer.Flush]
+// to write the buffered data right away, for example at the end of a frame.
+//
+// Flushes always happen between writes, never in the middle of one, so each
+// write should contain complete escape sequences.
+//
+// Write errors of flushes that happen in the background are returned by the
+// next call to [Writer.Write] or [Writer.Flush]. After an error, the writer
+// discards data and keeps returning the error.
+//
+// A Writer is safe for concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	w        io.Writer
+	buf      []byte
+	frame    []byte
+	size     int
+	interval time.Duration
+	sync     bool
+	timer    *time.Timer
+	gen      uint64
+	err      error
+}
+
+// NewWriter returns a new [Writer] that writes to w. The writer flushes when
+// the buffered data reaches size bytes, or interval after the first buffered
+// write. A size less than or equal to zero uses [DefaultSize], and an
+// interval of zero disables timed flushes.
+func NewWriter(w io.Writer, size int, interval time.Duration) *Writer {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Writer{
+		w:        w,
+		buf:      make([]byte, 0, size),
+		size:     size,
+		interval: interval,
+	}
+}
+
+// SetSynchronized sets whether each flush is framed with
+// [ansi.SynchronizedOutputMode], so that terminals supporting it render the
+// flushed data at once. It applies starting from the next flush.
+//
+// [ansi.SynchronizedOutputMode]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#SynchronizedOutputMode
+func (w *Writer) SetSynchronized(v bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sync = v
+}
+
+// Buffered returns the number of bytes waiting to be flushed.
+func (w *Writer) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.buf)
+}
+
+// Write implements [io.Writer]. It buffers p, flushing the buffered data
+// first if p doesn't fit. Writes larger than the flush size are written
+// through.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if len(w.buf) > 0 && len(w.buf)+len(p) > w.size {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.size {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	} else if w.interval > 0 && w.timer == nil && len(w.buf) > 0 {
+		gen := w.gen
+		w.timer = time.AfterFunc(w.interval, func() { w.flushTimer(gen) })
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	return w.flush()
+}
+
+// flushTimer flushes the buffered data when the interval elapses. gen is the
+// flush generation the timer was started in.
+func (w *Writer) flushTimer(gen uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.gen != gen {
+		// The data was flushed while the timer was firing.
+		return
+	}
+	w.timer = nil
+	if w.err == nil {
+		w.flush() //nolint:errcheck
+	}
+}
+
+// flush writes the buffered data and stops the pending timer. It must be
+// called with the lock held.
+func (w *Writer) flush() error {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.gen++
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.sync {
+		w.frame = append(w.frame[:0], ansi.SetSynchronizedOutputMode...)
+		w.frame = append(w.frame, w.buf...)
+		w.frame = append(w.frame, ansi.ResetSynchronizedOutputMode...)
+		_, err = w.w.Write(w.frame)
+	} else {
+		_, err = w.w.Write(w.buf)
+	}
+	w.buf = w.buf[:0]
+	w.err = err
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,140 @@
+package coalesce
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records the writes made to it.
+type recorder struct {
+	mu     sync.Mutex
+	writes []string
+	err    error
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.writes = append(r.writes, string(p))
+	return len(p), nil
+}
+
+func (r *recorder) Writes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.writes...)
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWriterSize(t *testing.T) {
+	var r recorder
+	w := NewWriter(&r, 8, 0)
+	for _, s := range []string{"abc", "def", "gh", "ijk", "0123456789", "x"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Writes are never split: "gh" fills the buffer, "ijk" doesn't fit after
+	// it, and the long write goes through on its own.
+	want := []string{"abcdefgh", "ijk", "0123456789"}
+	if got := r.Writes(); !equal(got, want) {
+		t.Errorf("writes = %q, want %q", got, want)
+	}
+	if got := w.Buffered(); got != 1 {
+		t.Errorf("Buffered() = %d, want 1", got)
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, "x")
+	if got := r.Writes(); !equal(got, want) {
+		t.Errorf("writes = %q, want %q", got, want)
+	}
+}
+
+func TestWriterInterval(t *testing.T) {
+	var r recorder
+	w := NewWriter(&r, 0, 10*time.Millisecond)
+	w.Write([]byte("\x1b[H"))  //nolint:errcheck
+	w.Write([]byte("\x1b[2J")) //nolint:errcheck
+	if got := r.Writes(); len(got) != 0 {
+		t.Fatalf("writes = %q before the interval", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(r.Writes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := r.Writes(), []string{"\x1b[H\x1b[2J"}; !equal(got, want) {
+		t.Errorf("writes = %q, want %q", got, want)
+	}
+}
+
+func TestWriterSynchronized(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, 0, 0)
+	w.SetSynchronized(true)
+	w.Write([]byte("\x1b[Hhello")) //nolint:errcheck
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\x1b[?2026h\x1b[Hhello\x1b[?2026l"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWriterError(t *testing.T) {
+	errWrite := errors.New("write error")
+	r := recorder{err: errWrite}
+	w := NewWriter(&r, 4, 0)
+	if _, err := w.Write([]byte("ab")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("cd")); !errors.Is(err, errWrite) {
+		t.Errorf("Write() error = %v, want %v", err, errWrite)
+	}
+	if _, err := w.Write([]byte("ef")); !errors.Is(err, errWrite) {
+		t.Errorf("Write() error = %v, want %v", err, errWrite)
+	}
+	if err := w.Flush(); !errors.Is(err, errWrite) {
+		t.Errorf("Flush() error = %v, want %v", err, errWrite)
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, 0, 0)
+	seq := []byte("\x1b[38;5;196mx")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		for j := 0; j < 1000; j++ {
+			w.Write(seq) //nolint:errcheck
+		}
+		w.Flush() //nolint:errcheck
+	}
+}
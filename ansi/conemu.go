@@ -0,0 +1,73 @@
+package ansi
+
+import "strconv"
+
+// NotifyWorkingDirectoryConEmu returns a sequence that notifies the terminal
+// of the current working directory using the ConEmu OSC 9;9 subcommand. This
+// is what Windows Terminal uses for shell integration, use
+// [NotifyWorkingDirectory] for other terminals. The path is quoted and
+// control characters are removed.
+//
+//	OSC 9 ; 9 ; "path" BEL
+//
+// See: https://learn.microsoft.com/en-us/windows/terminal/tutorials/new-tab-same-directory
+func NotifyWorkingDirectoryConEmu(path string) string {
+	return "\x1b]9;9;\"" + sanitizeTitle(path) + "\"\x07"
+}
+
+// ConEmuMessage returns a sequence that shows a GUI message box with the
+// given message using the ConEmu OSC 9;2 subcommand. Control characters are
+// removed from the message.
+//
+//	OSC 9 ; 2 ; message BEL
+//
+// See: https://conemu.github.io/en/AnsiEscapeCodes.html#ConEmu_specific_OSC
+func ConEmuMessage(msg string) string {
+	return "\x1b]9;2;" + sanitizeTitle(msg) + "\x07"
+}
+
+// ProgressState is the state of the taskbar progress indicator set with
+// [SetProgress].
+type ProgressState byte
+
+// Progress states.
+const (
+	// ProgressNone removes the progress indicator.
+	ProgressNone ProgressState = iota
+	// ProgressNormal shows the progress percentage.
+	ProgressNormal
+	// ProgressError shows the progress percentage in an error state.
+	ProgressError
+	// ProgressIndeterminate shows an indeterminate progress indicator.
+	ProgressIndeterminate
+	// ProgressPaused shows the progress percentage in a paused or warning
+	// state.
+	ProgressPaused
+)
+
+// SetProgress returns a sequence that sets the taskbar progress indicator
+// using the ConEmu OSC 9;4 subcommand, as supported by Windows Terminal. The
+// percent is clamped between 0 and 100 and ignored by the [ProgressNone] and
+// [ProgressIndeterminate] states.
+//
+//	OSC 9 ; 4 ; state ; percent BEL
+//
+// See: https://learn.microsoft.com/en-us/windows/terminal/tutorials/progress-bar-sequences
+func SetProgress(state ProgressState, percent int) string {
+	switch state {
+	case ProgressNone, ProgressIndeterminate:
+		return "\x1b]9;4;" + strconv.Itoa(int(state)) + "\x07"
+	}
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	return "\x1b]9;4;" + strconv.Itoa(int(state)) + ";" + strconv.Itoa(percent) + "\x07"
+}
+
+// ResetProgress is a sequence that removes the taskbar progress indicator.
+// This is equivalent to SetProgress(ProgressNone, 0).
+//
+// See: https://learn.microsoft.com/en-us/windows/terminal/tutorials/progress-bar-sequences
+const ResetProgress = "\x1b]9;4;0\x07"
@@ -0,0 +1,54 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestConEmu(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"cwd", ansi.NotifyWorkingDirectoryConEmu(`C:\Users\me`), "\x1b]9;9;\"C:\\Users\\me\"\x07"},
+		{"cwd spaces", ansi.NotifyWorkingDirectoryConEmu(`C:\Program Files`), "\x1b]9;9;\"C:\\Program Files\"\x07"},
+		{"cwd controls", ansi.NotifyWorkingDirectoryConEmu("C:\\a\x07b"), "\x1b]9;9;\"C:\\ab\"\x07"},
+		{"message", ansi.ConEmuMessage("Hello"), "\x1b]9;2;Hello\x07"},
+		{"message controls", ansi.ConEmuMessage("Hel\x1blo"), "\x1b]9;2;Hello\x07"},
+		{"message raw c1", ansi.ConEmuMessage("Hel\x9c\x9b2Jlo"), "\x1b]9;2;Hel2Jlo\x07"},
+		{"cwd raw c1", ansi.NotifyWorkingDirectoryConEmu("C:\\a\x9cb"), "\x1b]9;9;\"C:\\ab\"\x07"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+}
+
+func TestSetProgress(t *testing.T) {
+	tests := []struct {
+		state   ansi.ProgressState
+		percent int
+		want    string
+	}{
+		{ansi.ProgressNone, 50, "\x1b]9;4;0\x07"},
+		{ansi.ProgressNormal, 42, "\x1b]9;4;1;42\x07"},
+		{ansi.ProgressError, 100, "\x1b]9;4;2;100\x07"},
+		{ansi.ProgressIndeterminate, 10, "\x1b]9;4;3\x07"},
+		{ansi.ProgressPaused, 7, "\x1b]9;4;4;7\x07"},
+		{ansi.ProgressNormal, -5, "\x1b]9;4;1;0\x07"},
+		{ansi.ProgressNormal, 150, "\x1b]9;4;1;100\x07"},
+	}
+	for _, tt := range tests {
+		if got := ansi.SetProgress(tt.state, tt.percent); got != tt.want {
+			t.Errorf("SetProgress(%d, %d) = %q, want %q", tt.state, tt.percent, got, tt.want)
+		}
+	}
+	if ansi.ResetProgress != ansi.SetProgress(ansi.ProgressNone, 0) {
+		t.Errorf("ResetProgress = %q, want %q", ansi.ResetProgress, ansi.SetProgress(ansi.ProgressNone, 0))
+	}
+}
@@ -1,13 +1,14 @@
 package ansi
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 )
 
 // RequestNameVersion (XTVERSION) is a control sequence that requests the
-// terminal's name and version. It responds with a DSR sequence identifying the
-// terminal.
+// terminal's name and version. The terminal responds with a DCS sequence that
+// can be parsed using [ParseNameVersion].
 //
 //	CSI > 0 q
 //	DCS > | text ST
@@ -18,6 +19,39 @@ const (
 	XTVERSION          = RequestNameVersion
 )
 
+// ErrInvalidNameVersion is returned by [ParseNameVersion] when the sequence
+// is not a valid XTVERSION response.
+var ErrInvalidNameVersion = errors.New("invalid name and version response")
+
+// ParseNameVersion parses an XTVERSION response sequence, the response to
+// [RequestNameVersion]. It returns the terminal name and version.
+//
+//	DCS > | text ST
+//
+// Terminals report the text in different forms, such as "XTerm(390)",
+// "kitty(0.36.4)", or "WezTerm 20240203-110809-5046fc22". The name is the
+// text up to the first parenthesis or space, and the version is the rest of
+// the text without the parentheses. Either can be empty.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Functions-using-CSI-_-ordered-by-the-final-character_s_
+func ParseNameVersion(seq string) (name, version string, err error) {
+	text, ok := dcsData(seq)
+	if !ok || !strings.HasPrefix(text, ">|") {
+		return "", "", ErrInvalidNameVersion
+	}
+	text = text[2:]
+
+	i := strings.IndexAny(text, "( ")
+	if i < 0 {
+		return text, "", nil
+	}
+	name, version = text[:i], text[i+1:]
+	if text[i] == '(' {
+		version = strings.TrimSuffix(version, ")")
+	}
+	return name, strings.TrimSpace(version), nil
+}
+
 // RequestXTVersion is a control sequence that requests the terminal's XTVERSION. It responds with a DSR sequence identifying the version.
 //
 //	CSI > Ps q
@@ -28,6 +62,29 @@ const (
 // Deprecated: use [RequestNameVersion] instead.
 const RequestXTVersion = RequestNameVersion
 
+// Send7BitControls (S7C1T) is an escape sequence that makes the terminal send
+// its responses using 7-bit C1 controls, for example ESC [ for CSI. This is
+// the default.
+//
+//	ESC SP F
+//
+// See: https://vt100.net/docs/vt510-rm/S7C1T.html
+const (
+	Send7BitControls = "\x1b F"
+	S7C1T            = Send7BitControls
+)
+
+// Send8BitControls (S8C1T) is an escape sequence that makes the terminal send
+// its responses using 8-bit C1 controls, for example 0x9B for CSI.
+//
+//	ESC SP G
+//
+// See: https://vt100.net/docs/vt510-rm/S8C1T.html
+const (
+	Send8BitControls = "\x1b G"
+	S8C1T            = Send8BitControls
+)
+
 // PrimaryDeviceAttributes (DA1) is a control sequence that reports the
 // terminal's primary device attributes.
 //
@@ -135,3 +192,64 @@ func DA3(unitID string) string {
 //
 // See https://vt100.net/docs/vt510-rm/DA3.html
 const RequestTertiaryDeviceAttributes = "\x1b[=c"
+
+// ErrInvalidDeviceAttributes is returned by the device attributes parsers
+// when the sequence is not a valid device attributes report.
+var ErrInvalidDeviceAttributes = errors.New("invalid device attributes report")
+
+// ParsePrimaryDeviceAttributes parses a primary device attributes (DA1)
+// report, the response to [RequestPrimaryDeviceAttributes]. It returns the
+// service class, the first parameter, and the supported features, the
+// remaining parameters.
+//
+//	CSI ? Ps ; ... c
+//
+// For example, a VT220 with sixel graphics (4) and ANSI color (22) reports
+// "CSI ? 62 ; 4 ; 22 c".
+//
+// See https://vt100.net/docs/vt510-rm/DA1.html
+func ParsePrimaryDeviceAttributes(seq string) (class int, features []int, err error) {
+	cmd, params, ok := csiData(seq)
+	if !ok || cmd != Cmd(Command('?', 0, 'c')) || len(params) == 0 {
+		return 0, nil, ErrInvalidDeviceAttributes
+	}
+	class = params[0].Param(0)
+	for _, p := range params[1:] {
+		features = append(features, p.Param(0))
+	}
+	return class, features, nil
+}
+
+// ParseSecondaryDeviceAttributes parses a secondary device attributes (DA2)
+// report, the response to [RequestSecondaryDeviceAttributes]. It returns the
+// terminal identification, the firmware version, and the ROM cartridge
+// registration number, which is always 0 on most terminals.
+//
+//	CSI > Pp ; Pv ; Pc c
+//
+// See https://vt100.net/docs/vt510-rm/DA2.html
+func ParseSecondaryDeviceAttributes(seq string) (id, version, rom int, err error) {
+	cmd, params, ok := csiData(seq)
+	if !ok || cmd != Cmd(Command('>', 0, 'c')) || len(params) == 0 {
+		return 0, 0, 0, ErrInvalidDeviceAttributes
+	}
+	id, _, _ = params.Param(0, 0)
+	version, _, _ = params.Param(1, 0)
+	rom, _, _ = params.Param(2, 0)
+	return id, version, rom, nil
+}
+
+// ParseTertiaryDeviceAttributes parses a tertiary device attributes (DA3)
+// report, the response to [RequestTertiaryDeviceAttributes]. It returns the
+// terminal unit ID, usually 8 hexadecimal digits.
+//
+//	DCS ! | D...D ST
+//
+// See https://vt100.net/docs/vt510-rm/DA3.html
+func ParseTertiaryDeviceAttributes(seq string) (unitID string, err error) {
+	data, ok := dcsData(seq)
+	if !ok || !strings.HasPrefix(data, "!|") {
+		return "", ErrInvalidDeviceAttributes
+	}
+	return data[2:], nil
+}
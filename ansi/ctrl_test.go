@@ -0,0 +1,78 @@
+package ansi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseNameVersion(t *testing.T) {
+	cases := []struct {
+		seq     string
+		name    string
+		version string
+		err     error
+	}{
+		{"\x1bP>|XTerm(390)\x1b\\", "XTerm", "390", nil},
+		{"\x1bP>|kitty(0.36.4)\x1b\\", "kitty", "0.36.4", nil},
+		{"\x1bP>|WezTerm 20240203-110809-5046fc22\x1b\\", "WezTerm", "20240203-110809-5046fc22", nil},
+		{"\x90>|tmux 3.4\x9c", "tmux", "3.4", nil},
+		{"\x1bP>|foot\x07", "foot", "", nil},
+		{"\x1bP>|\x1b\\", "", "", nil},
+		{"\x1bP!|00000000\x1b\\", "", "", ansi.ErrInvalidNameVersion},
+		{"\x1bP>|kitty(0.36.4)", "", "", ansi.ErrInvalidNameVersion},
+		{"\x1b[>|kitty\x1b\\", "", "", ansi.ErrInvalidNameVersion},
+	}
+	for _, tc := range cases {
+		name, version, err := ansi.ParseNameVersion(tc.seq)
+		if err != tc.err || name != tc.name || version != tc.version {
+			t.Errorf("ParseNameVersion(%q) = %q, %q, %v, want %q, %q, %v",
+				tc.seq, name, version, err, tc.name, tc.version, tc.err)
+		}
+	}
+}
+
+func TestParsePrimaryDeviceAttributes(t *testing.T) {
+	class, features, err := ansi.ParsePrimaryDeviceAttributes(ansi.PrimaryDeviceAttributes(62, 4, 22))
+	if err != nil || class != 62 || !reflect.DeepEqual(features, []int{4, 22}) {
+		t.Errorf("ParsePrimaryDeviceAttributes() = %d, %v, %v, want 62, [4 22], nil", class, features, err)
+	}
+	class, features, err = ansi.ParsePrimaryDeviceAttributes("\x1b[?1;2c")
+	if err != nil || class != 1 || !reflect.DeepEqual(features, []int{2}) {
+		t.Errorf("ParsePrimaryDeviceAttributes() = %d, %v, %v, want 1, [2], nil", class, features, err)
+	}
+	for _, seq := range []string{"\x1b[c", "\x1b[?c", "\x1b[>1;10;0c", "\x1b[?62;4", "?62;4c"} {
+		if _, _, err := ansi.ParsePrimaryDeviceAttributes(seq); err != ansi.ErrInvalidDeviceAttributes {
+			t.Errorf("ParsePrimaryDeviceAttributes(%q) error = %v, want %v", seq, err, ansi.ErrInvalidDeviceAttributes)
+		}
+	}
+}
+
+func TestParseSecondaryDeviceAttributes(t *testing.T) {
+	id, version, rom, err := ansi.ParseSecondaryDeviceAttributes(ansi.SecondaryDeviceAttributes(1, 10, 0))
+	if err != nil || id != 1 || version != 10 || rom != 0 {
+		t.Errorf("ParseSecondaryDeviceAttributes() = %d, %d, %d, %v, want 1, 10, 0, nil", id, version, rom, err)
+	}
+	id, version, _, err = ansi.ParseSecondaryDeviceAttributes("\x1b[>41;390c")
+	if err != nil || id != 41 || version != 390 {
+		t.Errorf("ParseSecondaryDeviceAttributes() = %d, %d, %v, want 41, 390, nil", id, version, err)
+	}
+	for _, seq := range []string{"\x1b[>c", "\x1b[?62c", "\x1b[=1c"} {
+		if _, _, _, err := ansi.ParseSecondaryDeviceAttributes(seq); err != ansi.ErrInvalidDeviceAttributes {
+			t.Errorf("ParseSecondaryDeviceAttributes(%q) error = %v, want %v", seq, err, ansi.ErrInvalidDeviceAttributes)
+		}
+	}
+}
+
+func TestParseTertiaryDeviceAttributes(t *testing.T) {
+	id, err := ansi.ParseTertiaryDeviceAttributes(ansi.TertiaryDeviceAttributes("7E565445"))
+	if err != nil || id != "7E565445" {
+		t.Errorf("ParseTertiaryDeviceAttributes() = %q, %v, want %q, nil", id, err, "7E565445")
+	}
+	for _, seq := range []string{"\x1bP>|kitty\x1b\\", "\x1bP!|00000000", "\x1b[=c"} {
+		if _, err := ansi.ParseTertiaryDeviceAttributes(seq); err != ansi.ErrInvalidDeviceAttributes {
+			t.Errorf("ParseTertiaryDeviceAttributes(%q) error = %v, want %v", seq, err, ansi.ErrInvalidDeviceAttributes)
+		}
+	}
+}
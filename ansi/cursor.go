@@ -1,6 +1,9 @@
 package ansi
 
-import "strconv"
+import (
+	"errors"
+	"strconv"
+)
 
 // SaveCursor (DECSC) is an escape sequence that saves the current cursor
 // position.
@@ -525,6 +528,50 @@ const (
 // Deprecated: use [RestoreCurrentCursorPosition] instead.
 const RestoreCursorPosition = "\x1b[u"
 
+// CursorStyle is a cursor style set with [SetCursorStyle] (DECSCUSR). It
+// combines a cursor shape and whether the cursor blinks.
+type CursorStyle int
+
+// Cursor styles. The constants are untyped so they can be passed to
+// [SetCursorStyle] and compared with a [CursorStyle].
+const (
+	DefaultCursorStyle = iota
+	BlinkingBlockCursor
+	SteadyBlockCursor
+	BlinkingUnderlineCursor
+	SteadyUnderlineCursor
+	BlinkingBarCursor
+	SteadyBarCursor
+)
+
+// CursorShape is the shape of a [CursorStyle].
+type CursorShape int
+
+// Cursor shapes.
+const (
+	BlockCursorShape CursorShape = iota
+	UnderlineCursorShape
+	BarCursorShape
+)
+
+// Shape returns the shape of the cursor style. Unknown styles are reported as
+// [BlockCursorShape].
+func (s CursorStyle) Shape() CursorShape {
+	if s <= BlinkingBlockCursor || s > SteadyBarCursor {
+		return BlockCursorShape
+	}
+	return CursorShape((s - 1) / 2)
+}
+
+// Blinking returns whether the cursor style blinks. Unknown styles are
+// reported as blinking like [DefaultCursorStyle].
+func (s CursorStyle) Blinking() bool {
+	if s <= BlinkingBlockCursor || s > SteadyBarCursor {
+		return true
+	}
+	return s%2 == 1
+}
+
 // SetCursorStyle (DECSCUSR) returns a sequence for changing the cursor style.
 //
 // Default is 1.
@@ -555,6 +602,22 @@ func DECSCUSR(style int) string {
 	return SetCursorStyle(style)
 }
 
+// ErrInvalidCursorStyle is returned by [ParseCursorStyle] when the sequence
+// is not a valid DECSCUSR sequence.
+var ErrInvalidCursorStyle = errors.New("invalid cursor style sequence")
+
+// ParseCursorStyle parses a DECSCUSR sequence, as returned by
+// [SetCursorStyle], and returns the cursor style. A missing parameter is
+// reported as [DefaultCursorStyle].
+func ParseCursorStyle(seq string) (CursorStyle, error) {
+	cmd, params, ok := csiData(seq)
+	if !ok || cmd != Cmd(Command(0, ' ', 'q')) || len(params) > 1 {
+		return 0, ErrInvalidCursorStyle
+	}
+	style, _, _ := params.Param(0, 0)
+	return CursorStyle(style), nil
+}
+
 // SetPointerShape returns a sequence for changing the mouse pointer cursor
 // shape. Use "default" for the default pointer shape.
 //
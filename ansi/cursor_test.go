@@ -0,0 +1,49 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCursorStyle(t *testing.T) {
+	cases := []struct {
+		style    ansi.CursorStyle
+		shape    ansi.CursorShape
+		blinking bool
+	}{
+		{ansi.DefaultCursorStyle, ansi.BlockCursorShape, true},
+		{ansi.BlinkingBlockCursor, ansi.BlockCursorShape, true},
+		{ansi.SteadyBlockCursor, ansi.BlockCursorShape, false},
+		{ansi.BlinkingUnderlineCursor, ansi.UnderlineCursorShape, true},
+		{ansi.SteadyUnderlineCursor, ansi.UnderlineCursorShape, false},
+		{ansi.BlinkingBarCursor, ansi.BarCursorShape, true},
+		{ansi.SteadyBarCursor, ansi.BarCursorShape, false},
+		{ansi.CursorStyle(7), ansi.BlockCursorShape, true},
+	}
+	for _, tc := range cases {
+		if got := tc.style.Shape(); got != tc.shape {
+			t.Errorf("CursorStyle(%d).Shape() = %d, want %d", tc.style, got, tc.shape)
+		}
+		if got := tc.style.Blinking(); got != tc.blinking {
+			t.Errorf("CursorStyle(%d).Blinking() = %v, want %v", tc.style, got, tc.blinking)
+		}
+	}
+}
+
+func TestParseCursorStyle(t *testing.T) {
+	for style := ansi.DefaultCursorStyle; style <= ansi.SteadyBarCursor; style++ {
+		got, err := ansi.ParseCursorStyle(ansi.SetCursorStyle(style))
+		if err != nil || got != ansi.CursorStyle(style) {
+			t.Errorf("ParseCursorStyle(SetCursorStyle(%d)) = %d, %v", style, got, err)
+		}
+	}
+	if got, err := ansi.ParseCursorStyle("\x1b[ q"); err != nil || got != ansi.DefaultCursorStyle {
+		t.Errorf("ParseCursorStyle(%q) = %d, %v, want %d, nil", "\x1b[ q", got, err, ansi.DefaultCursorStyle)
+	}
+	for _, seq := range []string{"\x1b[2q", "\x1b[2;3 q", "\x1b[2 p", "\x1b[2 "} {
+		if _, err := ansi.ParseCursorStyle(seq); err != ansi.ErrInvalidCursorStyle {
+			t.Errorf("ParseCursorStyle(%q) error = %v, want %v", seq, err, ansi.ErrInvalidCursorStyle)
+		}
+	}
+}
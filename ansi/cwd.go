@@ -1,8 +1,11 @@
 package ansi
 
 import (
+	"bytes"
+	"errors"
 	"net/url"
 	"path"
+	"strings"
 )
 
 // NotifyWorkingDirectory returns a sequence that notifies the terminal
@@ -12,11 +15,16 @@ import (
 //
 // Where Pt is a URL in the format "file://[host]/[path]".
 // Set host to "localhost" if this is a path on the local computer.
+// The path is made absolute and percent-encoded. Windows Terminal uses
+// [NotifyWorkingDirectoryConEmu] instead.
 //
 // See: https://wezfurlong.org/wezterm/shell-integration.html#osc-7-escape-sequence-to-set-the-working-directory
 // See: https://iterm2.com/documentation-escape-codes.html#:~:text=RemoteHost%20and%20CurrentDir%3A-,OSC%207,-%3B%20%5BPs%5D%20ST
 func NotifyWorkingDirectory(host string, paths ...string) string {
 	path := path.Join(paths...)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
 	u := &url.URL{
 		Scheme: "file",
 		Host:   host,
@@ -24,3 +32,25 @@ func NotifyWorkingDirectory(host string, paths ...string) string {
 	}
 	return "\x1b]7;" + u.String() + "\x07"
 }
+
+// ErrInvalidWorkingDirectory is returned by [ParseWorkingDirectory] when the
+// data is not a valid working directory notification.
+var ErrInvalidWorkingDirectory = errors.New("invalid working directory")
+
+// ParseWorkingDirectory parses a working directory notification, as sent by
+// [NotifyWorkingDirectory], from the data of an OSC sequence, that is the
+// sequence without its introducer and terminator, as returned by
+// [Parser.Data]. It returns the host, which can be empty, and the decoded
+// path.
+//
+//	7 ; file://[host]/[path]
+func ParseWorkingDirectory(data []byte) (host, path string, err error) {
+	if !bytes.HasPrefix(data, []byte("7;")) {
+		return "", "", ErrInvalidWorkingDirectory
+	}
+	u, err := url.Parse(string(data[2:]))
+	if err != nil || u.Scheme != "file" || u.Opaque != "" || u.Path == "" {
+		return "", "", ErrInvalidWorkingDirectory
+	}
+	return u.Host, u.Path, nil
+}
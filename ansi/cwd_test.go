@@ -1,6 +1,7 @@
 package ansi_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
@@ -19,3 +20,54 @@ func TestNotifyWorkingDirectory_RemoteFile(t *testing.T) {
 		t.Errorf("Unexpected url: %s", h)
 	}
 }
+
+func TestNotifyWorkingDirectory_Encoding(t *testing.T) {
+	h := ansi.NotifyWorkingDirectory("localhost", "/home/me/my dir", "100%", "日本")
+	want := "\x1b]7;file://localhost/home/me/my%20dir/100%25/%E6%97%A5%E6%9C%AC\x07"
+	if h != want {
+		t.Errorf("expected %q, got %q", want, h)
+	}
+}
+
+func TestNotifyWorkingDirectory_NoHost(t *testing.T) {
+	h := ansi.NotifyWorkingDirectory("", "tmp")
+	if h != "\x1b]7;file:///tmp\x07" {
+		t.Errorf("Unexpected url: %s", h)
+	}
+}
+
+func TestParseWorkingDirectory(t *testing.T) {
+	cases := []struct {
+		data string
+		host string
+		path string
+		err  error
+	}{
+		{"7;file://localhost/path/to/file", "localhost", "/path/to/file", nil},
+		{"7;file:///tmp", "", "/tmp", nil},
+		{"7;file://example.com/my%20dir/100%25/%E6%97%A5", "example.com", "/my dir/100%/日", nil},
+		{"7;http://example.com/tmp", "", "", ansi.ErrInvalidWorkingDirectory},
+		{"7;file:tmp", "", "", ansi.ErrInvalidWorkingDirectory},
+		{"7;file://host", "", "", ansi.ErrInvalidWorkingDirectory},
+		{"7;file://host/%zz", "", "", ansi.ErrInvalidWorkingDirectory},
+		{"8;file:///tmp", "", "", ansi.ErrInvalidWorkingDirectory},
+		{"7", "", "", ansi.ErrInvalidWorkingDirectory},
+	}
+	for _, c := range cases {
+		t.Run(c.data, func(t *testing.T) {
+			host, path, err := ansi.ParseWorkingDirectory([]byte(c.data))
+			if err != c.err || host != c.host || path != c.path {
+				t.Errorf("expected (%q, %q, %v), got (%q, %q, %v)", c.host, c.path, c.err, host, path, err)
+			}
+		})
+	}
+}
+
+func TestParseWorkingDirectory_RoundTrip(t *testing.T) {
+	seq := ansi.NotifyWorkingDirectory("box", "/a b/c#d?e")
+	data := strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b]"), "\x07")
+	host, path, err := ansi.ParseWorkingDirectory([]byte(data))
+	if err != nil || host != "box" || path != "/a b/c#d?e" {
+		t.Errorf("unexpected result (%q, %q, %v)", host, path, err)
+	}
+}
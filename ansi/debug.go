@@ -0,0 +1,444 @@
+package ansi
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// DebugString returns a human readable representation of the given string or
+// byte slice for debugging. Each run of text, control character, and escape
+// sequence goes on its own line. Text is quoted, and escape sequences show
+// their parameters along with their name when known, for example:
+//
+//	CSI 38:2:255:0:0 m (SGR fg truecolor #ff0000)
+//	"hello"
+//	CSI m (SGR reset)
+//	CR
+//	LF
+//
+// This is useful for bug reports and to diff the output of programs.
+func DebugString[T string | []byte](b T) string {
+	p := GetParser()
+	defer PutParser(p)
+
+	var lines []string
+	var text string
+	flushText := func() {
+		if text != "" {
+			lines = append(lines, strconv.Quote(text))
+			text = ""
+		}
+	}
+
+	var state byte
+	for len(b) > 0 {
+		seq, _, n, newState := DecodeSequence(b, state, p)
+		s := string(seq)
+		switch {
+		case n == 0:
+			// Should not happen, but don't loop forever.
+			s = string(b)
+			n = len(b)
+			fallthrough
+		case newState != NormalState && n == len(b):
+			flushText()
+			lines = append(lines, quoteBytes(s)+" (incomplete)")
+		case isDebugText(s):
+			text += s
+		default:
+			flushText()
+			lines = append(lines, describeSequence(s, p))
+		}
+		state = newState
+		b = b[n:]
+	}
+	flushText()
+
+	return strings.Join(lines, "\n")
+}
+
+// isDebugText returns whether seq is printable text.
+func isDebugText(seq string) bool {
+	c := seq[0]
+	return c >= ' ' && c != DEL && (c < 0x80 || c > 0x9f || len(seq) > 1)
+}
+
+// describeSequence returns a human readable representation of a control
+// character or escape sequence decoded by p.
+func describeSequence(seq string, p *Parser) string {
+	if len(seq) == 1 {
+		if name := controlName(seq[0]); name != "" {
+			return name
+		}
+		return quoteBytes(seq)
+	}
+	if isCancelled(seq) {
+		return quoteBytes(seq) + " (cancelled)"
+	}
+
+	cmd := Cmd(p.Command())
+	switch {
+	case HasCsiPrefix(seq):
+		body := seq[introducerLen(seq) : len(seq)-1]
+		s := "CSI "
+		if body != "" {
+			s += quoteBytes(body) + " "
+		}
+		s += string(seq[len(seq)-1])
+		name := csiNames[int(cmd)]
+		if cmd.Prefix() == 0 && cmd.Intermediate() == 0 && cmd.Final() == 'm' {
+			name = "SGR" + describeSgr(p.Params())
+		}
+		return annotate(s, name)
+
+	case HasOscPrefix(seq):
+		return annotate("OSC "+quoteBytes(stringBody(seq)), oscNames[p.Command()])
+
+	case HasDcsPrefix(seq):
+		var name string
+		switch {
+		case cmd.Intermediate() == 0 && cmd.Final() == 'q':
+			name = "sixel graphics"
+		case cmd.Intermediate() == '$' && cmd.Final() == 'q':
+			name = "DECRQSS"
+		case cmd.Intermediate() == '+' && cmd.Final() == 'q':
+			name = "XTGETTCAP"
+		}
+		return annotate("DCS "+quoteBytes(truncateBody(stringBody(seq))), name)
+
+	case HasApcPrefix(seq):
+		body := stringBody(seq)
+		var name string
+		if strings.HasPrefix(body, "G") {
+			name = "kitty graphics"
+		}
+		return annotate("APC "+quoteBytes(truncateBody(body)), name)
+
+	case HasSosPrefix(seq):
+		return "SOS " + quoteBytes(truncateBody(stringBody(seq)))
+
+	case HasPmPrefix(seq):
+		return "PM " + quoteBytes(truncateBody(stringBody(seq)))
+
+	case HasEscPrefix(seq):
+		name := escNames[int(cmd)]
+		if name == "" && cmd.Intermediate() >= '(' && cmd.Intermediate() <= '/' {
+			name = "SCS"
+		}
+		return annotate("ESC "+quoteBytes(seq[1:]), name)
+	}
+
+	return quoteBytes(seq)
+}
+
+// annotate appends the name to s in parentheses if it's not empty.
+func annotate(s, name string) string {
+	if name == "" {
+		return s
+	}
+	return s + " (" + name + ")"
+}
+
+// isCancelled reports whether seq ends before its final byte or string
+// terminator, which happens when the sequence is cancelled with CAN or SUB or
+// interrupted by another sequence.
+func isCancelled(seq string) bool {
+	last := seq[len(seq)-1]
+	switch {
+	case HasCsiPrefix(seq):
+		return len(seq) <= introducerLen(seq) || last < 0x40 || last > 0x7e
+	case HasOscPrefix(seq), HasDcsPrefix(seq), HasApcPrefix(seq),
+		HasSosPrefix(seq), HasPmPrefix(seq):
+		body := seq[introducerLen(seq):]
+		return !strings.HasSuffix(body, "\x1b\\") && !strings.HasSuffix(body, "\a") &&
+			!strings.HasSuffix(body, "\x9c")
+	case HasEscPrefix(seq):
+		return last < 0x30 || last > 0x7e
+	}
+	return false
+}
+
+// introducerLen returns the length of the introducer of a sequence, which
+// is either a C1 control or ESC followed by a byte.
+func introducerLen(seq string) int {
+	if seq[0] == ESC {
+		return 2 //nolint:gomnd
+	}
+	return 1
+}
+
+// stringBody returns the content of a string sequence (OSC, DCS, APC, SOS,
+// PM) without its introducer and terminator.
+func stringBody(seq string) string {
+	body := seq[introducerLen(seq):]
+	switch {
+	case strings.HasSuffix(body, "\x1b\\"):
+		body = body[:len(body)-2]
+	case strings.HasSuffix(body, "\a"), strings.HasSuffix(body, "\x9c"):
+		body = body[:len(body)-1]
+	}
+	return body
+}
+
+// maxDebugBody is the number of bytes of string sequence data shown by
+// [DebugString]. Longer data, like images, is truncated.
+const maxDebugBody = 64
+
+// truncateBody truncates long string sequence data.
+func truncateBody(body string) string {
+	if len(body) <= maxDebugBody {
+		return body
+	}
+	return fmt.Sprintf("%s... %d bytes", body[:maxDebugBody], len(body))
+}
+
+// quoteBytes returns s with non-printable characters escaped.
+func quoteBytes(s string) string {
+	q := strconv.Quote(s)
+	return q[1 : len(q)-1]
+}
+
+// controlName returns the name of a C0 or C1 control character.
+func controlName(c byte) string {
+	switch {
+	case c < ' ':
+		return c0Names[c]
+	case c == DEL:
+		return "DEL"
+	case c >= 0x80 && c <= 0x9f:
+		return c1Names[c-0x80]
+	}
+	return ""
+}
+
+var c0Names = [32]string{
+	"NUL", "SOH", "STX", "ETX", "EOT", "ENQ", "ACK", "BEL",
+	"BS", "HT", "LF", "VT", "FF", "CR", "SO", "SI",
+	"DLE", "DC1", "DC2", "DC3", "DC4", "NAK", "SYN", "ETB",
+	"CAN", "EM", "SUB", "ESC", "FS", "GS", "RS", "US",
+}
+
+var c1Names = [32]string{
+	"PAD", "HOP", "BPH", "NBH", "IND", "NEL", "SSA", "ESA",
+	"HTS", "HTJ", "VTS", "PLD", "PLU", "RI", "SS2", "SS3",
+	"DCS", "PU1", "PU2", "STS", "CCH", "MW", "SPA", "EPA",
+	"SOS", "SGCI", "SCI", "CSI", "ST", "OSC", "PM", "APC",
+}
+
+// csiNames are the names of CSI sequences by packed command.
+var csiNames = map[int]string{
+	'@':                    "ICH",
+	'A':                    "CUU",
+	'B':                    "CUD",
+	'C':                    "CUF",
+	'D':                    "CUB",
+	'E':                    "CNL",
+	'F':                    "CPL",
+	'G':                    "CHA",
+	'H':                    "CUP",
+	'I':                    "CHT",
+	'J':                    "ED",
+	'K':                    "EL",
+	'L':                    "IL",
+	'M':                    "DL",
+	'P':                    "DCH",
+	'R':                    "CPR",
+	'S':                    "SU",
+	'T':                    "SD",
+	'X':                    "ECH",
+	'Z':                    "CBT",
+	'`':                    "HPA",
+	'a':                    "HPR",
+	'b':                    "REP",
+	'c':                    "DA1",
+	'd':                    "VPA",
+	'e':                    "VPR",
+	'f':                    "HVP",
+	'g':                    "TBC",
+	'h':                    "SM",
+	'l':                    "RM",
+	'n':                    "DSR",
+	'r':                    "DECSTBM",
+	's':                    "SCOSC",
+	't':                    "XTWINOPS",
+	'u':                    "SCORC",
+	Command('?', 0, 'c'):   "DA1 response",
+	Command('?', 0, 'h'):   "DECSET",
+	Command('?', 0, 'l'):   "DECRST",
+	Command('?', 0, 'n'):   "DECDSR",
+	Command('?', 0, 'J'):   "DECSED",
+	Command('?', 0, 'K'):   "DECSEL",
+	Command('?', 0, 'R'):   "DECXCPR",
+	Command('?', 0, 'u'):   "kitty keyboard query",
+	Command('>', 0, 'c'):   "DA2",
+	Command('>', 0, 'm'):   "XTMODKEYS",
+	Command('>', 0, 'q'):   "XTVERSION",
+	Command('>', 0, 'u'):   "kitty keyboard push",
+	Command('<', 0, 'u'):   "kitty keyboard pop",
+	Command('=', 0, 'c'):   "DA3",
+	Command('=', 0, 'u'):   "kitty keyboard set",
+	Command(0, '$', 'p'):   "DECRQM",
+	Command('?', '$', 'p'): "DECRQM",
+	Command(0, '$', 'y'):   "DECRPM",
+	Command('?', '$', 'y'): "DECRPM",
+	Command(0, ' ', 'q'):   "DECSCUSR",
+	Command(0, '!', 'p'):   "DECSTR",
+	Command(0, '"', 'q'):   "DECSCA",
+	Command(0, '$', 'v'):   "DECCRA",
+	Command(0, '$', 'x'):   "DECFRA",
+	Command(0, '$', 'z'):   "DECERA",
+	Command(0, '$', '{'):   "DECSERA",
+	Command(0, '$', 't'):   "DECRARA",
+	Command(0, '*', 'x'):   "DECSACE",
+}
+
+// escNames are the names of ESC sequences by packed command.
+var escNames = map[int]string{
+	'7':                  "DECSC",
+	'8':                  "DECRC",
+	'=':                  "DECKPAM",
+	'>':                  "DECKPNM",
+	'D':                  "IND",
+	'E':                  "NEL",
+	'H':                  "HTS",
+	'M':                  "RI",
+	'N':                  "SS2",
+	'O':                  "SS3",
+	'\\':                 "ST",
+	'c':                  "RIS",
+	'n':                  "LS2",
+	'o':                  "LS3",
+	Command(0, '#', '8'): "DECALN",
+}
+
+// oscNames are the names of OSC sequences by command number.
+var oscNames = map[int]string{
+	0:    "icon name and window title",
+	1:    "icon name",
+	2:    "window title",
+	4:    "palette color",
+	7:    "working directory",
+	8:    "hyperlink",
+	9:    "notification",
+	10:   "foreground color",
+	11:   "background color",
+	12:   "cursor color",
+	52:   "clipboard",
+	104:  "reset palette color",
+	110:  "reset foreground color",
+	111:  "reset background color",
+	112:  "reset cursor color",
+	133:  "shell integration",
+	777:  "notification",
+	1337: "iTerm2",
+}
+
+// basicColorNames are the names of the 16 basic colors.
+var basicColorNames = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright black", "bright red", "bright green", "bright yellow",
+	"bright blue", "bright magenta", "bright cyan", "bright white",
+}
+
+// sgrNames are the names of the SGR attributes without arguments.
+var sgrNames = map[int]string{
+	0:  "reset",
+	1:  "bold",
+	2:  "faint",
+	3:  "italic",
+	4:  "underline",
+	5:  "slow blink",
+	6:  "rapid blink",
+	7:  "reverse",
+	8:  "conceal",
+	9:  "strikethrough",
+	21: "double underline",
+	22: "normal intensity",
+	23: "no italic",
+	24: "no underline",
+	25: "no blink",
+	27: "no reverse",
+	28: "no conceal",
+	29: "no strikethrough",
+	39: "fg default",
+	49: "bg default",
+	53: "overline",
+	55: "no overline",
+	59: "underline color default",
+}
+
+// describeSgr returns the attributes set by the SGR params, each preceded by
+// a space.
+func describeSgr(params Params) string {
+	if len(params) == 0 {
+		return " reset"
+	}
+
+	var s string
+	for i := 0; i < len(params); i++ {
+		param, hasMore, _ := params.Param(i, 0)
+		switch {
+		case param == 4 && hasMore: //nolint:gomnd
+			next, _, _ := params.Param(i+1, 0)
+			s += " underline style " + strconv.Itoa(next)
+			i++
+		case param >= 30 && param <= 37:
+			s += " fg " + basicColorNames[param-30]
+		case param >= 40 && param <= 47:
+			s += " bg " + basicColorNames[param-40]
+		case param >= 90 && param <= 97:
+			s += " fg " + basicColorNames[param-90+8]
+		case param >= 100 && param <= 107:
+			s += " bg " + basicColorNames[param-100+8]
+		case param == 38 || param == 48 || param == 58:
+			prefix := " fg "
+			switch param {
+			case 48: //nolint:gomnd
+				prefix = " bg "
+			case 58: //nolint:gomnd
+				prefix = " underline color "
+			}
+			var c color.Color
+			n := ReadStyleColor(params[i:], &c)
+			if n == 0 {
+				s += prefix + "invalid"
+				// Skip the sub-parameters of the invalid color.
+				for hasMore && i+1 < len(params) {
+					i++
+					_, hasMore, _ = params.Param(i, 0)
+				}
+				continue
+			}
+			s += prefix + describeColor(c)
+			i += n - 1
+		default:
+			if name, ok := sgrNames[param]; ok {
+				s += " " + name
+			} else {
+				s += " " + strconv.Itoa(param)
+			}
+		}
+	}
+	return s
+}
+
+// describeColor returns a human readable representation of an SGR color.
+func describeColor(c color.Color) string {
+	switch c := c.(type) {
+	case nil:
+		return "default"
+	case BasicColor:
+		if int(c) < len(basicColorNames) {
+			return basicColorNames[c]
+		}
+	case ExtendedColor:
+		return "256-color " + strconv.Itoa(int(c))
+	}
+	if c == color.Transparent {
+		return "transparent"
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("truecolor #%02x%02x%02x", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,127 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugString(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "text",
+			input: "hello, 世界!",
+			want:  []string{`"hello, 世界!"`},
+		},
+		{
+			name:  "sgr",
+			input: "\x1b[38:2:255:0:0mhello\x1b[m\r\n",
+			want: []string{
+				"CSI 38:2:255:0:0 m (SGR fg truecolor #ff0000)",
+				`"hello"`,
+				"CSI m (SGR reset)",
+				"CR",
+				"LF",
+			},
+		},
+		{
+			name:  "sgr attributes",
+			input: "\x1b[1;4:3;31;48;5;234;58;2;0;128;255m",
+			want:  []string{"CSI 1;4:3;31;48;5;234;58;2;0;128;255 m (SGR bold underline style 3 fg red bg 256-color 234 underline color truecolor #0080ff)"},
+		},
+		{
+			name:  "csi",
+			input: "\x1b[?25l\x1b[2J\x1b[10;20H\x1b[2 q\x1b[>1u\x1b[1z",
+			want: []string{
+				"CSI ?25 l (DECRST)",
+				"CSI 2 J (ED)",
+				"CSI 10;20 H (CUP)",
+				"CSI 2  q (DECSCUSR)",
+				"CSI >1 u (kitty keyboard push)",
+				"CSI 1 z",
+			},
+		},
+		{
+			name:  "esc",
+			input: "\x1b7\x1b(B\x1b8",
+			want: []string{
+				"ESC 7 (DECSC)",
+				"ESC (B (SCS)",
+				"ESC 8 (DECRC)",
+			},
+		},
+		{
+			name:  "osc",
+			input: "\x1b]8;;https://charm.sh\x07link\x1b]8;;\x1b\\\x1b]2;title\x07",
+			want: []string{
+				"OSC 8;;https://charm.sh (hyperlink)",
+				`"link"`,
+				"OSC 8;; (hyperlink)",
+				"OSC 2;title (window title)",
+			},
+		},
+		{
+			name:  "string sequences",
+			input: "\x1bP0;1q#0!80~\x1b\\\x1b_Ga=d\x1b\\",
+			want: []string{
+				"DCS 0;1q#0!80~ (sixel graphics)",
+				"APC Ga=d (kitty graphics)",
+			},
+		},
+		{
+			name:  "long data",
+			input: "\x1b_G" + strings.Repeat("A", 100) + "\x1b\\",
+			want:  []string{"APC G" + strings.Repeat("A", 63) + "... 101 bytes (kitty graphics)"},
+		},
+		{
+			name:  "controls",
+			input: "a\x07\x7f\x1b",
+			want:  []string{`"a"`, "BEL", "DEL", `\x1b (incomplete)`},
+		},
+		{
+			name:  "incomplete",
+			input: "\x1b[31",
+			want:  []string{`\x1b[31 (incomplete)`},
+		},
+		{
+			name:  "cancelled",
+			input: "\x1b[\x9c\x9b\x9c\x1b[1\x18\x1b]2;t\x1a\x1b(\x18a",
+			want: []string{
+				`\x1b[ (cancelled)`,
+				"ST",
+				"CSI",
+				"ST",
+				`\x1b[1 (cancelled)`,
+				"CAN",
+				`\x1b]2;t (cancelled)`,
+				"SUB",
+				`\x1b( (cancelled)`,
+				"CAN",
+				`"a"`,
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := DebugString(c.input)
+			if want := strings.Join(c.want, "\n"); got != want {
+				t.Errorf("DebugString() =\n%s\nwant\n%s", got, want)
+			}
+			if got2 := DebugString([]byte(c.input)); got2 != got {
+				t.Errorf("DebugString([]byte) = %q, want %q", got2, got)
+			}
+		})
+	}
+}
+
+func FuzzDebugString(f *testing.F) {
+	f.Add("\x1b[38:2:255:0:0mhello\x1b[m\r\n")
+	f.Add("\x1b[\x9c")
+	f.Add("\x9b\x18\x1b]8;;\x1a")
+	f.Fuzz(func(t *testing.T, s string) {
+		DebugString(s)
+	})
+}
@@ -0,0 +1,64 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FinalTerm returns a sequence that uses the FinalTerm OSC 133 shell
+// integration protocol, also known as semantic prompts. Shells and prompt
+// frameworks use it to mark the prompt, the command line, and the command
+// output so terminals can navigate between prompts and select command
+// outputs.
+//
+//	OSC 133 ; Ps ; Pm BEL
+//
+// See: https://iterm2.com/documentation-shell-integration.html
+// See: https://gitlab.freedesktop.org/Per_Bothner/specifications/blob/master/proposals/semantic-prompts.md
+func FinalTerm(pm ...string) string {
+	return "\x1b]133;" + strings.Join(pm, ";") + "\x07"
+}
+
+// FinalTermPrompt returns a sequence that marks the start of the shell
+// prompt. The optional params are key=value options such as "aid=1".
+//
+//	OSC 133 ; A ; Pm BEL
+//
+// See: https://iterm2.com/documentation-shell-integration.html
+func FinalTermPrompt(pm ...string) string {
+	return FinalTerm(append([]string{"A"}, pm...)...)
+}
+
+// FinalTermCmdStart returns a sequence that marks the end of the shell
+// prompt and the start of the command line typed by the user.
+//
+//	OSC 133 ; B ; Pm BEL
+//
+// See: https://iterm2.com/documentation-shell-integration.html
+func FinalTermCmdStart(pm ...string) string {
+	return FinalTerm(append([]string{"B"}, pm...)...)
+}
+
+// FinalTermCmdExecuted returns a sequence that marks the end of the command
+// line and the start of the command output.
+//
+//	OSC 133 ; C ; Pm BEL
+//
+// See: https://iterm2.com/documentation-shell-integration.html
+func FinalTermCmdExecuted(pm ...string) string {
+	return FinalTerm(append([]string{"C"}, pm...)...)
+}
+
+// FinalTermCmdFinished returns a sequence that marks the end of the command
+// output with the given exit code. A negative exit code is omitted, which
+// means the command was aborted or the status is unknown.
+//
+//	OSC 133 ; D ; exit code ; Pm BEL
+//
+// See: https://iterm2.com/documentation-shell-integration.html
+func FinalTermCmdFinished(exitCode int, pm ...string) string {
+	if exitCode < 0 {
+		return FinalTerm(append([]string{"D"}, pm...)...)
+	}
+	return FinalTerm(append([]string{"D", strconv.Itoa(exitCode)}, pm...)...)
+}
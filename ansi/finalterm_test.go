@@ -0,0 +1,31 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFinalTerm(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"generic", ansi.FinalTerm("P", "k=i"), "\x1b]133;P;k=i\x07"},
+		{"prompt", ansi.FinalTermPrompt(), "\x1b]133;A\x07"},
+		{"prompt params", ansi.FinalTermPrompt("aid=1", "cl=m"), "\x1b]133;A;aid=1;cl=m\x07"},
+		{"cmd start", ansi.FinalTermCmdStart(), "\x1b]133;B\x07"},
+		{"cmd executed", ansi.FinalTermCmdExecuted(), "\x1b]133;C\x07"},
+		{"cmd finished", ansi.FinalTermCmdFinished(0), "\x1b]133;D;0\x07"},
+		{"cmd failed", ansi.FinalTermCmdFinished(127, "aid=1"), "\x1b]133;D;127;aid=1\x07"},
+		{"cmd aborted", ansi.FinalTermCmdFinished(-1), "\x1b]133;D\x07"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"image"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/x/ansi/kitty"
+	"github.com/charmbracelet/x/ansi/sixel"
 )
 
 // KittyGraphics returns a sequence that encodes the given image in the Kitty
@@ -31,6 +33,105 @@ func KittyGraphics(payload []byte, opts ...string) string {
 	return buf.String()
 }
 
+// KittyGraphicsChunks returns the Kitty graphics sequences that transmit the
+// given raw data with the given options. The data is base64 encoded and split
+// into chunks of [kitty.MaxChunkSize] bytes, each in its own sequence. The
+// options go in the first sequence, while the following ones only repeat the
+// quiet (q=) option and the frame action (a=f), as the protocol requires.
+//
+// See https://sw.kovidgoyal.net/kitty/graphics-protocol/#remote-client
+func KittyGraphicsChunks(data []byte, opts ...string) string {
+	payload := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(payload, data)
+	if len(payload) <= kitty.MaxChunkSize {
+		return KittyGraphics(payload, opts...)
+	}
+
+	var more []string
+	for _, opt := range opts {
+		if strings.HasPrefix(opt, "q=") || opt == "a=f" {
+			more = append(more, opt)
+		}
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(payload); i += kitty.MaxChunkSize {
+		end := i + kitty.MaxChunkSize
+		m := "m=1"
+		if end >= len(payload) {
+			end = len(payload)
+			m = "m=0"
+		}
+		chunkOpts := more
+		if i == 0 {
+			chunkOpts = opts
+		}
+		buf.WriteString(KittyGraphics(payload[i:end], append(chunkOpts[:len(chunkOpts):len(chunkOpts)], m)...))
+	}
+	return buf.String()
+}
+
+// KittyGraphicsAnimation returns a Kitty graphics sequence that controls the
+// animation of an image with the given options.
+//
+// See https://sw.kovidgoyal.net/kitty/graphics-protocol/#controlling-animations
+func KittyGraphicsAnimation(o kitty.AnimationOptions) string {
+	return KittyGraphics(nil, o.Options()...)
+}
+
+// SixelGraphics returns a sequence that encodes the given sixel image payload
+// to a DCS sixel sequence.
+//
+//	DCS p1; p2; p3; q [sixel payload] ST
+//
+// p1 = pixel aspect ratio, deprecated and replaced by pixel metrics in the
+// payload. Use 0 for the default.
+//
+// p2 = This is supposed to be 0 for transparency, but terminals don't seem to
+// use it properly. Value 0 leaves an unsightly black bar on all terminals
+// we've tried and looks correct with value 1.
+//
+// p3 = Horizontal grid size parameter. Everyone ignores this and uses a fixed
+// grid size, as far as we can tell. Use 0.
+//
+// Use the [sixel.Encoder] to encode images to the sixel payload.
+//
+// See https://shuford.invisible-island.net/all_about_sixels.txt
+func SixelGraphics(p1, p2, p3 int, payload []byte) string {
+	var buf bytes.Buffer
+
+	buf.WriteString("\x1bP")
+	if p1 >= 0 {
+		buf.WriteString(strconv.Itoa(p1))
+	}
+	buf.WriteByte(';')
+	if p2 >= 0 {
+		buf.WriteString(strconv.Itoa(p2))
+	}
+	if p3 > 0 {
+		buf.WriteByte(';')
+		buf.WriteString(strconv.Itoa(p3))
+	}
+	buf.WriteByte('q')
+	buf.Write(payload)
+	buf.WriteString("\x1b\\")
+
+	return buf.String()
+}
+
+// WriteSixelGraphics encodes the given image using the [sixel.Encoder] with
+// run-length encoding and Floyd-Steinberg dithering, and writes the resulting
+// DCS sixel sequence to w. Fully transparent pixels are not drawn.
+func WriteSixelGraphics(w io.Writer, m image.Image) error {
+	var data bytes.Buffer
+	if err := (&sixel.Encoder{RLE: true, Dither: sixel.DitherFloydSteinberg, AlphaThreshold: 1}).Encode(&data, m); err != nil {
+		return fmt.Errorf("failed to encode sixel image: %w", err)
+	}
+
+	_, err := io.WriteString(w, SixelGraphics(0, 1, 0, data.Bytes()))
+	return err
+}
+
 var (
 	// KittyGraphicsTempDir is the directory where temporary files are stored.
 	// This is used in [WriteKittyGraphics] along with [os.CreateTemp].
@@ -53,6 +154,10 @@ var (
 // the image is written to a temporary file that is automatically deleted by
 // the terminal.
 //
+// With o.Transmission = [kitty.SharedMemory], o.File is the name of a shared
+// memory object that the caller has created and filled with the image data.
+// The terminal reads the data and unlinks the object.
+//
 // See https://sw.kovidgoyal.net/kitty/graphics-protocol/
 func WriteKittyGraphics(w io.Writer, m image.Image, o *kitty.Options) error {
 	if o == nil {
@@ -76,8 +181,12 @@ func WriteKittyGraphics(w io.Writer, m image.Image, o *kitty.Options) error {
 		}
 
 	case kitty.SharedMemory:
-		// TODO: Implement shared memory
-		return fmt.Errorf("shared memory transmission is not yet implemented")
+		if len(o.File) == 0 {
+			return kitty.ErrMissingFile
+		}
+
+		// Write the shared memory object name to the buffer
+		data.WriteString(o.File)
 
 	case kitty.File:
 		if len(o.File) == 0 {
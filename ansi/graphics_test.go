@@ -62,6 +62,33 @@ func TestKittyGraphics(t *testing.T) {
 	}
 }
 
+func TestKittyGraphicsChunks(t *testing.T) {
+	if got, want := KittyGraphicsChunks([]byte("test"), "a=T", "f=100"), "\x1b_Ga=T,f=100;dGVzdA==\x1b\\"; got != want {
+		t.Errorf("KittyGraphicsChunks() = %q, want %q", got, want)
+	}
+
+	// 3 bytes encode to 4 base64 bytes, so this is one chunk and a half.
+	data := bytes.Repeat([]byte{0xff}, kitty.MaxChunkSize/4*3+3)
+	opts := []string{"a=f", "i=1", "q=2"}
+	got := KittyGraphicsChunks(data, opts...)
+	payload := strings.Repeat("/", kitty.MaxChunkSize)
+	want := "\x1b_Ga=f,i=1,q=2,m=1;" + payload + "\x1b\\" +
+		"\x1b_Ga=f,q=2,m=0;////\x1b\\"
+	if got != want {
+		t.Errorf("KittyGraphicsChunks() = %q, want %q", got, want)
+	}
+	if len(opts) != 3 {
+		t.Errorf("KittyGraphicsChunks() modified the options: %q", opts)
+	}
+}
+
+func TestKittyGraphicsAnimation(t *testing.T) {
+	got := KittyGraphicsAnimation(kitty.AnimationOptions{ID: 3, State: kitty.AnimationRun, Loops: 1})
+	if want := "\x1b_Ga=a,i=3,s=3,v=1\x1b\\"; got != want {
+		t.Errorf("KittyGraphicsAnimation() = %q, want %q", got, want)
+	}
+}
+
 func TestWriteKittyGraphics(t *testing.T) {
 	// Create a test image
 	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
@@ -253,7 +280,16 @@ func TestWriteKittyGraphicsEdgeCases(t *testing.T) {
 			opts: &kitty.Options{
 				Transmission: kitty.SharedMemory,
 			},
-			wantError: true, // Not implemented
+			wantError: true, // Missing shared memory name
+		},
+		{
+			name: "shared memory transmission with name",
+			img:  image.NewRGBA(image.Rect(0, 0, 1, 1)),
+			opts: &kitty.Options{
+				Transmission: kitty.SharedMemory,
+				File:         "/kitty-shm",
+			},
+			wantError: false,
 		},
 		{
 			name: "file transmission without file path",
@@ -276,3 +312,62 @@ func TestWriteKittyGraphicsEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestSixelGraphics(t *testing.T) {
+	tests := []struct {
+		p1, p2, p3 int
+		payload    string
+		want       string
+	}{
+		{0, 1, 0, "#0~", "\x1bP0;1q#0~\x1b\\"},
+		{-1, -1, 0, "", "\x1bP;q\x1b\\"},
+		{2, 0, 5, "-", "\x1bP2;0;5q-\x1b\\"},
+	}
+	for _, tt := range tests {
+		if got := SixelGraphics(tt.p1, tt.p2, tt.p3, []byte(tt.payload)); got != tt.want {
+			t.Errorf("SixelGraphics(%d, %d, %d) = %q, want %q", tt.p1, tt.p2, tt.p3, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSixelGraphics(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.NRGBA{G: 0xff, A: 0xff})
+
+	var buf bytes.Buffer
+	if err := WriteSixelGraphics(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\x1bP0;1q\"1;1;1;1#0;2;0;100;0#0@\x1b\\"; buf.String() != want {
+		t.Errorf("WriteSixelGraphics() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDecodeKittyGraphicsResponse(t *testing.T) {
+	p := NewParser()
+	p.SetDataSize(1024)
+	input := "\x1b_Gi=31;OK\x1b\\\x1b_Gi=32;ENOENT:Unknown image\x1b\\"
+
+	var got []kitty.Response
+	var state byte
+	for len(input) > 0 {
+		seq, _, n, newState := DecodeSequence(input, state, p)
+		if HasApcPrefix(seq) {
+			r, err := kitty.ParseResponse(p.Data())
+			if err != nil {
+				t.Fatalf("ParseResponse(%q) error = %v", p.Data(), err)
+			}
+			got = append(got, r)
+		}
+		state = newState
+		input = input[n:]
+	}
+
+	want := []kitty.Response{
+		{ID: 31, Code: "OK"},
+		{ID: 32, Code: "ENOENT", Message: "Unknown image"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("responses = %+v, want %+v", got, want)
+	}
+}
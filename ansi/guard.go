@@ -0,0 +1,183 @@
+package ansi
+
+import (
+	"io"
+	"sync"
+)
+
+// Guard records the terminal state changed by an application and restores it
+// on exit or panic. Changes made through the guard are written to the
+// underlying writer as they happen, and [Guard.Restore] writes the exact
+// sequence that undoes them, in reverse order.
+//
+// A mode set through the guard is assumed to be reset before, and a mode
+// reset through the guard is assumed to be set before. Use
+// [Guard.RecordModeSetting] with the setting reported by the terminal, for
+// example with [RequestMode], to restore the actual previous setting instead.
+//
+// A Guard is safe for concurrent use.
+//
+// Example:
+//
+//	g := ansi.NewGuard(os.Stdout)
+//	defer g.Recover()
+//	g.SetMode(ansi.AltScreenSaveCursorMode, ansi.BracketedPasteMode)
+//	g.ResetMode(ansi.TextCursorEnableMode) // hide the cursor
+//	g.PushKittyKeyboard(ansi.KittyDisambiguateEscapeCodes)
+//	// ...
+//	g.Restore()
+type Guard struct {
+	mu      sync.Mutex
+	w       io.Writer
+	changes []guardChange
+	prior   map[Mode]bool // whether the mode was set before the first change
+	set     map[Mode]bool // whether the mode is currently set
+	styled  bool
+}
+
+// guardChange is a recorded change, either a mode or a number of Kitty
+// Keyboard flags pushed in a row.
+type guardChange struct {
+	mode  Mode
+	kitty int
+}
+
+// NewGuard returns a new [Guard] that writes to w.
+func NewGuard(w io.Writer) *Guard {
+	return &Guard{
+		w:     w,
+		prior: make(map[Mode]bool),
+		set:   make(map[Mode]bool),
+	}
+}
+
+// Write writes p to the underlying writer. Text written through the guard may
+// change the current style, so [Guard.Restore] resets it.
+func (g *Guard) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.styled = true
+	return g.w.Write(p) //nolint:wrapcheck
+}
+
+// SetMode sets the given modes and records them to be reset on restore.
+func (g *Guard) SetMode(modes ...Mode) error {
+	return g.setMode(true, modes)
+}
+
+// ResetMode resets the given modes and records them to be set on restore.
+// For example, resetting [TextCursorEnableMode] hides the cursor until the
+// guard is restored.
+func (g *Guard) ResetMode(modes ...Mode) error {
+	return g.setMode(false, modes)
+}
+
+func (g *Guard) setMode(set bool, modes []Mode) error {
+	if len(modes) == 0 {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, m := range modes {
+		if _, ok := g.set[m]; !ok {
+			if _, ok := g.prior[m]; !ok {
+				g.prior[m] = !set
+			}
+			g.changes = append(g.changes, guardChange{mode: m})
+		}
+		g.set[m] = set
+	}
+
+	seq := ResetMode(modes...)
+	if set {
+		seq = SetMode(modes...)
+	}
+	_, err := io.WriteString(g.w, seq)
+	return err //nolint:wrapcheck
+}
+
+// RecordModeSetting records the setting of the given mode before the guard
+// changes it, usually the setting reported by the terminal in response to
+// [RequestMode]. [Guard.Restore] then brings the mode back to that setting.
+// Permanently set modes are recorded as set, and permanently reset and
+// unrecognized modes as reset.
+func (g *Guard) RecordModeSetting(mode Mode, setting ModeSetting) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.prior[mode] = setting.IsSet()
+}
+
+// PushKittyKeyboard pushes the given flags to the terminal Kitty Keyboard
+// stack and records them to be popped on restore.
+func (g *Guard) PushKittyKeyboard(flags int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if n := len(g.changes); n > 0 && g.changes[n-1].kitty > 0 {
+		g.changes[n-1].kitty++
+	} else {
+		g.changes = append(g.changes, guardChange{kitty: 1})
+	}
+
+	_, err := io.WriteString(g.w, PushKittyKeyboard(flags))
+	return err //nolint:wrapcheck
+}
+
+// RestoreSequence returns the sequence that restores the recorded changes,
+// or an empty string if there is nothing to restore.
+func (g *Guard) RestoreSequence() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.restoreSequence()
+}
+
+func (g *Guard) restoreSequence() string {
+	var seq string
+	if g.styled {
+		seq += ResetStyle
+	}
+	for i := len(g.changes) - 1; i >= 0; i-- {
+		c := g.changes[i]
+		switch {
+		case c.kitty > 0:
+			seq += PopKittyKeyboard(c.kitty)
+		case g.set[c.mode] == g.prior[c.mode]:
+			// The mode is already back to its prior setting.
+		case g.prior[c.mode]:
+			seq += SetMode(c.mode)
+		default:
+			seq += ResetMode(c.mode)
+		}
+	}
+	return seq
+}
+
+// Restore writes the sequence that restores the recorded changes and clears
+// them. It's safe to call Restore more than once.
+func (g *Guard) Restore() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	seq := g.restoreSequence()
+	g.changes = g.changes[:0]
+	g.prior = make(map[Mode]bool)
+	g.set = make(map[Mode]bool)
+	g.styled = false
+	if seq == "" {
+		return nil
+	}
+
+	_, err := io.WriteString(g.w, seq)
+	return err //nolint:wrapcheck
+}
+
+// Recover restores the recorded changes if the calling goroutine is
+// panicking, then continues panicking. It must be called directly by a
+// deferred statement.
+//
+//	defer g.Recover()
+func (g *Guard) Recover() {
+	if r := recover(); r != nil {
+		g.Restore() //nolint:errcheck
+		panic(r)
+	}
+}
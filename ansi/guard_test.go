@@ -0,0 +1,79 @@
+package ansi
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	var buf bytes.Buffer
+	g := NewGuard(&buf)
+	g.SetMode(AltScreenSaveCursorMode)                  //nolint:errcheck
+	g.PushKittyKeyboard(KittyDisambiguateEscapeCodes)   //nolint:errcheck
+	g.PushKittyKeyboard(KittyAllFlags)                  //nolint:errcheck
+	g.SetMode(BracketedPasteMode, ButtonEventMouseMode) //nolint:errcheck
+	g.ResetMode(TextCursorEnableMode)                   //nolint:errcheck
+	g.Write([]byte("\x1b[1mhello"))                     //nolint:errcheck
+	g.ResetMode(ButtonEventMouseMode)                   //nolint:errcheck
+
+	want := "\x1b[?1049h\x1b[>1u\x1b[>31u\x1b[?2004;1002h\x1b[?25l\x1b[1mhello\x1b[?1002l"
+	if got := buf.String(); got != want {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+
+	// The mouse mode is already back to its prior setting, so it's not
+	// restored.
+	restore := "\x1b[m\x1b[?25h\x1b[?2004l\x1b[<2u\x1b[?1049l"
+	if got := g.RestoreSequence(); got != restore {
+		t.Errorf("RestoreSequence() = %q, want %q", got, restore)
+	}
+
+	buf.Reset()
+	if err := g.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != restore {
+		t.Errorf("Restore() = %q, want %q", got, restore)
+	}
+}
+
+func TestGuardRecordModeSetting(t *testing.T) {
+	var buf bytes.Buffer
+	g := NewGuard(&buf)
+	g.RecordModeSetting(BracketedPasteMode, ModeSet)
+	g.RecordModeSetting(TextCursorEnableMode, ModeReset)
+	g.SetMode(BracketedPasteMode, AltScreenSaveCursorMode) //nolint:errcheck
+	g.ResetMode(TextCursorEnableMode)                      //nolint:errcheck
+	g.ResetMode(BracketedPasteMode)                        //nolint:errcheck
+
+	// The alternate screen mode is assumed to be reset before, the bracketed
+	// paste mode was set, and the cursor was already hidden.
+	restore := "\x1b[?1049l\x1b[?2004h"
+	if got := g.RestoreSequence(); got != restore {
+		t.Errorf("RestoreSequence() = %q, want %q", got, restore)
+	}
+}
+
+func TestGuardRecover(t *testing.T) {
+	var buf bytes.Buffer
+	g := NewGuard(&buf)
+	g.SetMode(AltScreenSaveCursorMode) //nolint:errcheck
+	buf.Reset()
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want boom", r)
+		}
+		if got, want := buf.String(), "\x1b[?1049l"; got != want {
+			t.Errorf("restore = %q, want %q", got, want)
+		}
+	}()
+
+	func() {
+		defer g.Recover()
+		panic("boom")
+	}()
+}
@@ -1,6 +1,9 @@
 package ansi
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+)
 
 // Kitty keyboard protocol progressive enhancement flags.
 // See: https://sw.kovidgoyal.net/kitty/keyboard-protocol/#progressive-enhancement
@@ -15,6 +18,51 @@ const (
 		KittyReportAlternateKeys | KittyReportAllKeysAsEscapeCodes | KittyReportAssociatedKeys
 )
 
+// KittyFlags is a bitmask of the Kitty keyboard protocol progressive
+// enhancement flags, such as [KittyDisambiguateEscapeCodes]. The flags
+// constants are untyped, so they can be used as KittyFlags or as the int
+// flags of [KittyKeyboard] and [PushKittyKeyboard].
+type KittyFlags int
+
+var kittyFlagNames = []string{
+	"disambiguate",
+	"eventtypes",
+	"alternatekeys",
+	"allkeys",
+	"associatedtext",
+}
+
+// Contains reports whether f contains all the given flags.
+func (f KittyFlags) Contains(flags KittyFlags) bool {
+	return f&flags == flags
+}
+
+// String returns the names of the flags separated by "|", for example
+// "disambiguate|allkeys", or "none" when no flags are set. Unknown flags are
+// written as numbers.
+func (f KittyFlags) String() string {
+	if f == 0 {
+		return "none"
+	}
+	var b strings.Builder
+	for i, name := range kittyFlagNames {
+		if f&(1<<i) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('|')
+		}
+		b.WriteString(name)
+	}
+	if rest := f &^ KittyAllFlags; rest != 0 {
+		if b.Len() > 0 {
+			b.WriteByte('|')
+		}
+		b.WriteString(strconv.Itoa(int(rest)))
+	}
+	return b.String()
+}
+
 // RequestKittyKeyboard is a sequence to request the terminal Kitty keyboard
 // protocol enabled flags.
 //
@@ -0,0 +1,130 @@
+package kitty
+
+import "strconv"
+
+// Animation states.
+const (
+	// AnimationStop stops the animation.
+	AnimationStop = 1
+	// AnimationLoading runs the animation, waiting for new frames at the
+	// last one.
+	AnimationLoading = 2
+	// AnimationRun runs the animation, looping back to the first frame after
+	// the last one.
+	AnimationRun = 3
+)
+
+// FrameOptions are the options of an animation frame, transmitted with the
+// [Frame] action or composed with the [Compose] action. Use them along with
+// [Options] for the image ID and data format, leaving the [Options] position
+// and size fields empty since the frame options reuse their keys.
+type FrameOptions struct {
+	// X (x=) and Y (y=) are the position of the frame data in the frame, in
+	// pixels.
+	X, Y int
+
+	// BaseFrame (c=) is the 1-based number of the frame to use as the
+	// background of the new frame.
+	BaseFrame int
+
+	// EditFrame (r=) is the 1-based number of an existing frame to edit
+	// instead of creating a new one.
+	EditFrame int
+
+	// Gap (z=) is the time to show the frame in milliseconds. A negative gap
+	// makes a gapless frame that is skipped.
+	Gap int
+
+	// Replace (X=1) replaces the pixels of the base frame instead of alpha
+	// blending them.
+	Replace bool
+
+	// Background (Y=) is the RGBA background color of the frame when there
+	// is no base frame.
+	Background uint32
+}
+
+// Options returns the frame options as a slice of key-value pairs.
+func (f FrameOptions) Options() (opts []string) {
+	opts = []string{}
+	if f.X > 0 {
+		opts = append(opts, "x="+strconv.Itoa(f.X))
+	}
+	if f.Y > 0 {
+		opts = append(opts, "y="+strconv.Itoa(f.Y))
+	}
+	if f.BaseFrame > 0 {
+		opts = append(opts, "c="+strconv.Itoa(f.BaseFrame))
+	}
+	if f.EditFrame > 0 {
+		opts = append(opts, "r="+strconv.Itoa(f.EditFrame))
+	}
+	if f.Gap != 0 {
+		opts = append(opts, "z="+strconv.Itoa(f.Gap))
+	}
+	if f.Replace {
+		opts = append(opts, "X=1")
+	}
+	if f.Background > 0 {
+		opts = append(opts, "Y="+strconv.FormatUint(uint64(f.Background), 10))
+	}
+	return
+}
+
+// AnimationOptions are the options of the [Animate] action, which controls
+// the animation of an image.
+type AnimationOptions struct {
+	// ID (i=) and Number (I=) identify the image.
+	ID, Number int
+
+	// Quite (q=) suppresses the terminal responses, see [Options.Quite].
+	Quite byte
+
+	// State (s=) is one of [AnimationStop], [AnimationLoading], or
+	// [AnimationRun].
+	State int
+
+	// Loops (v=) is the number of times to loop the animation. 1 loops
+	// forever, and any other number loops that number minus one times.
+	Loops int
+
+	// Frame (c=) is the 1-based number of the frame to make current.
+	Frame int
+
+	// EditFrame (r=) is the 1-based number of the frame whose gap is set.
+	EditFrame int
+
+	// Gap (z=) is the new gap of EditFrame in milliseconds.
+	Gap int
+}
+
+// Options returns the animation options as a slice of key-value pairs,
+// including the [Animate] action.
+func (a AnimationOptions) Options() (opts []string) {
+	opts = []string{"a=a"}
+	if a.ID > 0 {
+		opts = append(opts, "i="+strconv.Itoa(a.ID))
+	}
+	if a.Number > 0 {
+		opts = append(opts, "I="+strconv.Itoa(a.Number))
+	}
+	if a.Quite > 0 {
+		opts = append(opts, "q="+strconv.Itoa(int(a.Quite)))
+	}
+	if a.State > 0 {
+		opts = append(opts, "s="+strconv.Itoa(a.State))
+	}
+	if a.Loops > 0 {
+		opts = append(opts, "v="+strconv.Itoa(a.Loops))
+	}
+	if a.Frame > 0 {
+		opts = append(opts, "c="+strconv.Itoa(a.Frame))
+	}
+	if a.EditFrame > 0 {
+		opts = append(opts, "r="+strconv.Itoa(a.EditFrame))
+	}
+	if a.Gap != 0 {
+		opts = append(opts, "z="+strconv.Itoa(a.Gap))
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package kitty
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrameOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		o    FrameOptions
+		want []string
+	}{
+		{"empty", FrameOptions{}, []string{}},
+		{
+			name: "all",
+			o: FrameOptions{
+				X: 10, Y: 20, BaseFrame: 1, EditFrame: 2, Gap: 40,
+				Replace: true, Background: 0xff0000ff,
+			},
+			want: []string{"x=10", "y=20", "c=1", "r=2", "z=40", "X=1", "Y=4278190335"},
+		},
+		{"gapless", FrameOptions{Gap: -1}, []string{"z=-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.Options(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Options() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnimationOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		o    AnimationOptions
+		want []string
+	}{
+		{"empty", AnimationOptions{}, []string{"a=a"}},
+		{
+			name: "all",
+			o: AnimationOptions{
+				ID: 1, Number: 2, Quite: 2, State: AnimationLoading,
+				Loops: 3, Frame: 4, EditFrame: 5, Gap: 100,
+			},
+			want: []string{"a=a", "i=1", "I=2", "q=2", "s=2", "v=3", "c=4", "r=5", "z=100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.Options(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Options() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -56,9 +56,10 @@ type Options struct {
 	// [TempFile], or[SharedMemory].
 	Transmission byte
 
-	// File is the file path to be used when the transmission type is [File].
-	// If [Options.Transmission] is omitted i.e. zero and this is non-empty,
-	// the transmission type is set to [File].
+	// File is the file path to be used when the transmission type is [File],
+	// or the shared memory object name when it's [SharedMemory]. If
+	// [Options.Transmission] is omitted i.e. zero and this is non-empty, the
+	// transmission type is set to [File].
 	File string
 
 	// Size (S=0) is the size to be read from the transmission medium.
@@ -0,0 +1,122 @@
+package kitty
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidResponse is returned by [ParseResponse] when the data is not a
+// Kitty graphics response.
+var ErrInvalidResponse = errors.New("invalid kitty graphics response")
+
+// Response is a terminal response to a Kitty graphics command.
+//
+//	APC G i=ID,I=NUMBER,p=PLACEMENT ; OK ST
+//	APC G i=ID ; ENOENT:message ST
+//
+// See https://sw.kovidgoyal.net/kitty/graphics-protocol/#display-images-on-screen
+type Response struct {
+	// ID (i=) is the image ID. When the command used an image number, this
+	// is the ID assigned by the terminal.
+	ID int
+
+	// Number (I=) is the image number of the command, if any.
+	Number int
+
+	// PlacementID (p=) is the placement ID of the command, if any.
+	PlacementID int
+
+	// Code is "OK" when the command succeeded, or an error code such as
+	// "ENOENT" or "EINVAL".
+	Code string
+
+	// Message is the error message, if any.
+	Message string
+}
+
+// OK reports whether the command succeeded.
+func (r Response) OK() bool {
+	return r.Code == "OK"
+}
+
+// Err returns nil if the command succeeded, or a [*ResponseError] with the
+// error code and message.
+func (r Response) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return &ResponseError{ID: r.ID, Code: r.Code, Message: r.Message}
+}
+
+// ResponseError is an error reported by the terminal for a Kitty graphics
+// command.
+type ResponseError struct {
+	ID      int
+	Code    string
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ResponseError) Error() string {
+	s := "kitty graphics: " + e.Code
+	if e.Message != "" {
+		s += ": " + e.Message
+	}
+	if e.ID > 0 {
+		s += " (image " + strconv.Itoa(e.ID) + ")"
+	}
+	return s
+}
+
+// ParseResponse parses a Kitty graphics response from the data of an APC
+// sequence, that is the sequence without its introducer and terminator,
+// starting with 'G'. This is what [ansi.Parser.Data] returns after decoding
+// the sequence with [ansi.DecodeSequence] or the parser handler.
+//
+// Example:
+//
+//	seq, _, n, state := ansi.DecodeSequence(buf, state, p)
+//	if ansi.HasApcPrefix(seq) {
+//	  if r, err := kitty.ParseResponse(p.Data()); err == nil && r.OK() {
+//	    // The image was transmitted with ID r.ID.
+//	  }
+//	}
+//
+// [ansi.Parser.Data]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#Parser.Data
+// [ansi.DecodeSequence]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#DecodeSequence
+func ParseResponse(data []byte) (Response, error) {
+	var r Response
+	if len(data) == 0 || data[0] != 'G' {
+		return r, ErrInvalidResponse
+	}
+
+	keys, msg, ok := bytes.Cut(data[1:], []byte{';'})
+	if !ok || len(msg) == 0 {
+		return r, ErrInvalidResponse
+	}
+
+	for _, kv := range bytes.Split(keys, []byte{','}) {
+		k, v, ok := bytes.Cut(kv, []byte{'='})
+		if !ok || len(k) != 1 {
+			continue
+		}
+		n, err := strconv.Atoi(string(v))
+		if err != nil {
+			continue
+		}
+		switch k[0] {
+		case 'i':
+			r.ID = n
+		case 'I':
+			r.Number = n
+		case 'p':
+			r.PlacementID = n
+		}
+	}
+
+	code, message, _ := bytes.Cut(msg, []byte{':'})
+	r.Code = string(code)
+	r.Message = string(message)
+	return r, nil
+}
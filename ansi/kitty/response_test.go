@@ -0,0 +1,62 @@
+package kitty
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Response
+		wantErr error
+		errMsg  string
+	}{
+		{
+			name: "ok",
+			data: "Gi=31;OK",
+			want: Response{ID: 31, Code: "OK"},
+		},
+		{
+			name: "assigned id",
+			data: "Gi=99,I=13,p=2;OK",
+			want: Response{ID: 99, Number: 13, PlacementID: 2, Code: "OK"},
+		},
+		{
+			name:   "error",
+			data:   "Gi=31;ENOENT:Unknown image id",
+			want:   Response{ID: 31, Code: "ENOENT", Message: "Unknown image id"},
+			errMsg: "kitty graphics: ENOENT: Unknown image id (image 31)",
+		},
+		{
+			name:   "error without message",
+			data:   "G;EINVAL",
+			want:   Response{Code: "EINVAL"},
+			errMsg: "kitty graphics: EINVAL",
+		},
+		{name: "not graphics", data: "Pi=1;OK", wantErr: ErrInvalidResponse},
+		{name: "no message", data: "Gi=1", wantErr: ErrInvalidResponse},
+		{name: "empty", data: "", wantErr: ErrInvalidResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResponse([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseResponse() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseResponse() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.OK() != (tt.errMsg == "") {
+				t.Errorf("OK() = %v", got.OK())
+			}
+			if err := got.Err(); err == nil && tt.errMsg != "" || err != nil && err.Error() != tt.errMsg {
+				t.Errorf("Err() = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
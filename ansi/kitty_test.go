@@ -0,0 +1,48 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestKittyFlagsString(t *testing.T) {
+	cases := []struct {
+		flags ansi.KittyFlags
+		want  string
+	}{
+		{0, "none"},
+		{ansi.KittyDisambiguateEscapeCodes, "disambiguate"},
+		{ansi.KittyDisambiguateEscapeCodes | ansi.KittyReportAllKeysAsEscapeCodes, "disambiguate|allkeys"},
+		{ansi.KittyAllFlags, "disambiguate|eventtypes|alternatekeys|allkeys|associatedtext"},
+		{ansi.KittyReportEventTypes | 64, "eventtypes|64"},
+	}
+	for _, tc := range cases {
+		if got := tc.flags.String(); got != tc.want {
+			t.Errorf("KittyFlags(%d).String() = %q, want %q", int(tc.flags), got, tc.want)
+		}
+	}
+
+	f := ansi.KittyFlags(ansi.KittyReportEventTypes | ansi.KittyReportAlternateKeys)
+	if !f.Contains(ansi.KittyReportEventTypes) || f.Contains(ansi.KittyReportEventTypes|ansi.KittyReportAssociatedKeys) {
+		t.Errorf("KittyFlags(%s).Contains() mismatch", f)
+	}
+}
+
+func TestKittyKeyboardSequences(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{ansi.PushKittyKeyboard(ansi.KittyDisambiguateEscapeCodes), "\x1b[>1u"},
+		{ansi.PushKittyKeyboard(0), ansi.DisableKittyKeyboard},
+		{ansi.PopKittyKeyboard(0), "\x1b[<u"},
+		{ansi.PopKittyKeyboard(2), "\x1b[<2u"},
+		{ansi.KittyKeyboard(ansi.KittyAllFlags, 1), "\x1b[=31;1u"},
+		{ansi.RequestKittyKeyboard, "\x1b[?u"},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("expected: %q, got: %q", tc.want, tc.got)
+		}
+	}
+}
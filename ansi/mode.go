@@ -346,6 +346,19 @@ const (
 	RequestX10MouseMode = "\x1b[?9$p"
 )
 
+// Cursor Blink Mode (ATT610) is a mode that starts and stops blinking the
+// cursor. When set, the cursor blinks.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Functions-using-CSI-_-ordered-by-the-final-character_s_
+const (
+	CursorBlinkMode = DECMode(12)
+	ATT610          = CursorBlinkMode
+
+	SetCursorBlinkMode     = "\x1b[?12h"
+	ResetCursorBlinkMode   = "\x1b[?12l"
+	RequestCursorBlinkMode = "\x1b[?12$p"
+)
+
 // Text Cursor Enable Mode (DECTCEM) is a mode that shows/hides the cursor.
 //
 // See: https://vt100.net/docs/vt510-rm/DECTCEM.html
@@ -374,6 +387,20 @@ const (
 	RequestCursorVisibility = "\x1b[?25$p"
 )
 
+// National Replacement Character Set Mode (DECNRCM) is a mode that determines
+// whether the terminal uses 7-bit national replacement character sets (NRCS)
+// designated with [SCS] or 8-bit multinational character sets.
+//
+// See: https://vt100.net/docs/vt510-rm/DECNRCM.html
+const (
+	NationalReplacementCharsetMode = DECMode(42)
+	DECNRCM                        = NationalReplacementCharsetMode
+
+	SetNationalReplacementCharsetMode     = "\x1b[?42h"
+	ResetNationalReplacementCharsetMode   = "\x1b[?42l"
+	RequestNationalReplacementCharsetMode = "\x1b[?42$p"
+)
+
 // Numeric Keypad Mode (DECNKM) is a mode that determines whether the keypad
 // sends application sequences or numeric sequences.
 //
@@ -742,6 +769,25 @@ const (
 	RequestGraphemeClustering = "\x1b[?2027$p"
 )
 
+// InBandResizeMode is a mode that reports terminal resize events as escape
+// sequences. This is useful for environments where [SIGWINCH] is not
+// available or reliable, such as SSH sessions and terminal multiplexers.
+//
+// When enabled, the terminal reports its size on every resize as:
+//
+//	CSI 48 ; height ; width ; height_pixels ; width_pixels t
+//
+// See: https://gist.github.com/rockorager/e695fb2924d36b2bcf1fff4a3704bd83
+//
+// [SIGWINCH]: https://man7.org/linux/man-pages/man7/signal.7.html
+const (
+	InBandResizeMode = DECMode(2048)
+
+	SetInBandResizeMode     = "\x1b[?2048h"
+	ResetInBandResizeMode   = "\x1b[?2048l"
+	RequestInBandResizeMode = "\x1b[?2048$p"
+)
+
 // Win32Input is a mode that determines whether input is processed by the
 // Win32 console and Conpty.
 //
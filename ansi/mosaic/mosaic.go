@@ -0,0 +1,257 @@
+// Package mosaic renders images in the terminal. It picks the best image
+// protocol available given a set of terminal capabilities: the Kitty graphics
+// protocol, Sixel, the iTerm2 inline images protocol, or colored half-block
+// cells as a fallback that works everywhere.
+package mosaic
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/capability"
+	"github.com/charmbracelet/x/ansi/iterm2"
+	"github.com/charmbracelet/x/ansi/kitty"
+)
+
+// Protocol is an image rendering protocol.
+type Protocol uint8
+
+// Image protocols.
+const (
+	// Auto picks the best protocol from the capabilities.
+	Auto Protocol = iota
+	// Kitty uses the Kitty graphics protocol.
+	Kitty
+	// Sixel uses the DEC Sixel graphics format.
+	Sixel
+	// ITerm2 uses the iTerm2 inline images protocol.
+	ITerm2
+	// HalfBlocks uses upper half block characters with foreground and
+	// background colors. Each cell represents two vertical pixels.
+	HalfBlocks
+)
+
+// String returns the name of the protocol.
+func (p Protocol) String() string {
+	switch p {
+	case Auto:
+		return "auto"
+	case Kitty:
+		return "kitty"
+	case Sixel:
+		return "sixel"
+	case ITerm2:
+		return "iterm2"
+	case HalfBlocks:
+		return "half-blocks"
+	}
+	return fmt.Sprintf("Protocol(%d)", p)
+}
+
+// Default cell size in pixels used to compute the size of the image in cells.
+const (
+	DefaultCellWidth  = 10
+	DefaultCellHeight = 20
+)
+
+// ErrInvalidProtocol is returned when rendering with an unknown protocol.
+var ErrInvalidProtocol = errors.New("mosaic: invalid protocol")
+
+// Options are the rendering options.
+type Options struct {
+	// Protocol is the protocol to use. The default [Auto] picks the best
+	// protocol from [Options.Capabilities].
+	Protocol Protocol
+
+	// Capabilities are the terminal capabilities. See the
+	// [capability] package to detect them.
+	Capabilities capability.Set
+
+	// Width and Height are the size of the rendered image in cells. When
+	// zero, they are computed from the image size and the cell size keeping
+	// the aspect ratio.
+	Width, Height int
+
+	// CellWidth and CellHeight are the size of a terminal cell in pixels.
+	// They default to [DefaultCellWidth] and [DefaultCellHeight].
+	CellWidth, CellHeight int
+}
+
+// Select returns the best protocol supported by the given capabilities.
+func Select(caps capability.Set) Protocol {
+	switch {
+	case caps.Has(capability.KittyGraphics):
+		return Kitty
+	case caps.Has(capability.Sixel):
+		return Sixel
+	case caps.Has(capability.ITerm2Images):
+		return ITerm2
+	default:
+		return HalfBlocks
+	}
+}
+
+// Render renders the image for the terminal. The returned string contains the
+// escape sequences or the styled half-block cells to print.
+func Render(img image.Image, opts Options) (string, error) {
+	if img == nil {
+		return "", nil
+	}
+
+	opts = opts.normalize(img.Bounds())
+	protocol := opts.Protocol
+	if protocol == Auto {
+		protocol = Select(opts.Capabilities)
+	}
+
+	switch protocol {
+	case Kitty:
+		return renderKitty(img, opts)
+	case Sixel:
+		return renderSixel(img, opts)
+	case ITerm2:
+		return renderITerm2(img, opts)
+	case HalfBlocks:
+		return renderHalfBlocks(img, opts), nil
+	}
+
+	return "", ErrInvalidProtocol
+}
+
+// normalize fills the default options for an image with the given bounds.
+func (o Options) normalize(bounds image.Rectangle) Options {
+	if o.CellWidth <= 0 {
+		o.CellWidth = DefaultCellWidth
+	}
+	if o.CellHeight <= 0 {
+		o.CellHeight = DefaultCellHeight
+	}
+
+	w, h := bounds.Dx(), bounds.Dy()
+	switch {
+	case o.Width <= 0 && o.Height <= 0:
+		o.Width = ceilDiv(w, o.CellWidth)
+		o.Height = ceilDiv(h, o.CellHeight)
+	case o.Width <= 0:
+		o.Width = ceilDiv(o.Height*o.CellHeight*w, h*o.CellWidth)
+	case o.Height <= 0:
+		o.Height = ceilDiv(o.Width*o.CellWidth*h, w*o.CellHeight)
+	}
+	if o.Width < 1 {
+		o.Width = 1
+	}
+	if o.Height < 1 {
+		o.Height = 1
+	}
+
+	return o
+}
+
+// renderKitty renders the image using the Kitty graphics protocol.
+func renderKitty(img image.Image, opts Options) (string, error) {
+	var buf bytes.Buffer
+	if err := ansi.WriteKittyGraphics(&buf, img, &kitty.Options{
+		Action:       kitty.TransmitAndPut,
+		Transmission: kitty.Direct,
+		Format:       kitty.PNG,
+		Quite:        2, //nolint:gomnd
+		Chunk:        true,
+		Columns:      opts.Width,
+		Rows:         opts.Height,
+	}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderSixel renders the image using the Sixel graphics format. Since
+// sixels are drawn pixel by pixel, the image is scaled to the target size.
+func renderSixel(img image.Image, opts Options) (string, error) {
+	var buf bytes.Buffer
+	scaled := scale(img, opts.Width*opts.CellWidth, opts.Height*opts.CellHeight)
+	if err := ansi.WriteSixelGraphics(&buf, scaled); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderITerm2 renders the image using the iTerm2 inline images protocol.
+func renderITerm2(img image.Image, opts Options) (string, error) {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return "", fmt.Errorf("mosaic: failed to encode image: %w", err)
+	}
+
+	content := make([]byte, base64.StdEncoding.EncodedLen(data.Len()))
+	base64.StdEncoding.Encode(content, data.Bytes())
+
+	return ansi.ITerm2(iterm2.File{
+		Size:    int64(data.Len()),
+		Width:   iterm2.Cells(opts.Width),
+		Height:  iterm2.Cells(opts.Height),
+		Inline:  true,
+		Content: content,
+	}), nil
+}
+
+// renderHalfBlocks renders the image using upper half blocks. The foreground
+// color is the top pixel and the background color is the bottom pixel.
+func renderHalfBlocks(img image.Image, opts Options) string {
+	scaled := scale(img, opts.Width, opts.Height*2) //nolint:gomnd
+	trueColor := opts.Capabilities.Has(capability.TrueColor)
+
+	var b strings.Builder
+	for y := 0; y < opts.Height; y++ {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := 0; x < opts.Width; x++ {
+			fg := scaled.At(x, y*2)   //nolint:gomnd
+			bg := scaled.At(x, y*2+1) //nolint:gomnd
+			var style ansi.Style
+			if trueColor {
+				style = style.ForegroundColor(fg).BackgroundColor(bg)
+			} else {
+				style = style.ForegroundColor(ansi.ClosestExtendedColor(fg)).BackgroundColor(ansi.ClosestExtendedColor(bg))
+			}
+			b.WriteString(style.String())
+			b.WriteString("▀")
+		}
+		b.WriteString(ansi.ResetStyle)
+	}
+
+	return b.String()
+}
+
+// scale resizes the image to the given size using nearest neighbor sampling.
+func scale(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return img
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+
+	return dst
+}
+
+// ceilDiv returns the ceiling of a / b.
+func ceilDiv(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
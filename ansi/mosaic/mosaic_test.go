@@ -0,0 +1,125 @@
+package mosaic
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/capability"
+)
+
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 2; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 0xff, A: 0xff})
+		}
+	}
+	for x := 0; x < 2; x++ {
+		img.SetNRGBA(x, 1, color.NRGBA{B: 0xff, A: 0xff})
+	}
+	return img
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		caps capability.Set
+		want Protocol
+	}{
+		{capability.None, HalfBlocks},
+		{capability.TrueColor, HalfBlocks},
+		{capability.ITerm2Images, ITerm2},
+		{capability.Sixel | capability.ITerm2Images, Sixel},
+		{capability.KittyGraphics | capability.Sixel, Kitty},
+	}
+	for _, tt := range tests {
+		if got := Select(tt.caps); got != tt.want {
+			t.Errorf("Select(%v) = %v, want %v", tt.caps, got, tt.want)
+		}
+	}
+}
+
+func TestRenderProtocols(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		prefix string
+	}{
+		{"kitty", Options{Capabilities: capability.KittyGraphics}, "\x1b_Gf=100,"},
+		{"sixel", Options{Capabilities: capability.Sixel}, "\x1bP0;1q"},
+		{"iterm2", Options{Capabilities: capability.ITerm2Images}, "\x1b]1337;File="},
+		{"half-blocks", Options{}, "\x1b["},
+		{"forced", Options{Protocol: Sixel, Capabilities: capability.KittyGraphics}, "\x1bP0;1q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Render(testImage(), tt.opts)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.HasPrefix(out, tt.prefix) {
+				t.Errorf("Render() = %q, want prefix %q", out, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestRenderInvalidProtocol(t *testing.T) {
+	if _, err := Render(testImage(), Options{Protocol: Protocol(42)}); err != ErrInvalidProtocol {
+		t.Errorf("Render() error = %v, want %v", err, ErrInvalidProtocol)
+	}
+}
+
+func TestRenderHalfBlocks(t *testing.T) {
+	out, err := Render(testImage(), Options{
+		Protocol:     HalfBlocks,
+		Capabilities: capability.TrueColor,
+		Width:        2,
+		Height:       2,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	top := "\x1b[38;2;255;0;0;48;2;0;0;255m▀"
+	bottom := "\x1b[38;2;255;0;0;48;2;255;0;0m▀"
+	want := top + top + ansi.ResetStyle + "\n" + bottom + bottom + ansi.ResetStyle
+	if out != want {
+		t.Errorf("Render() = %q, want %q", out, want)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 100)
+	tests := []struct {
+		name          string
+		opts          Options
+		width, height int
+	}{
+		{"default", Options{}, 10, 5},
+		{"width", Options{Width: 20}, 20, 10},
+		{"height", Options{Height: 10}, 20, 10},
+		{"both", Options{Width: 3, Height: 4}, 3, 4},
+		{"cell size", Options{CellWidth: 5, CellHeight: 5}, 20, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := tt.opts.normalize(bounds)
+			if o.Width != tt.width || o.Height != tt.height {
+				t.Errorf("normalize() = %dx%d, want %dx%d", o.Width, o.Height, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestRenderKittyPayload(t *testing.T) {
+	out, err := Render(testImage(), Options{Protocol: Kitty})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(out, "a=T") || !strings.Contains(out, ";iVBOR") {
+		t.Errorf("Render() = %q, want transmit and put with a PNG payload", out)
+	}
+}
@@ -0,0 +1,109 @@
+package ansi
+
+import "io"
+
+// mouseTrackingModes are the mouse tracking modes from the most to the least
+// capable. Terminals only use one tracking mode at a time.
+var mouseTrackingModes = []DECMode{
+	AnyEventMouseMode,
+	ButtonEventMouseMode,
+	NormalMouseMode,
+	X10MouseMode,
+}
+
+// RequestMouseModes is a sequence that requests the settings of the mouse
+// tracking modes and the SGR mouse encoding using [DECRQM]. The terminal
+// replies with a [DECRPM] report for each mode, which can be collected into
+// [Modes] and passed to [StartMouseTracking].
+const RequestMouseModes = RequestAnyEventMouseMode +
+	RequestButtonEventMouseMode +
+	RequestNormalMouseMode +
+	RequestX10MouseMode +
+	RequestSgrExtMouseMode
+
+// MouseTracking is the mouse reporting enabled by [StartMouseTracking]. Use
+// [MouseTracking.Close] to restore the prior modes.
+type MouseTracking struct {
+	w       io.Writer
+	restore string
+
+	// Mode is the enabled mouse tracking mode, or zero if the terminal
+	// doesn't support any.
+	Mode DECMode
+
+	// Sgr reports whether the SGR mouse encoding is enabled.
+	Sgr bool
+}
+
+// StartMouseTracking enables the best mouse reporting supported by the
+// terminal according to modes, the [DECRPM] reports of [RequestMouseModes].
+// It picks [AnyEventMouseMode] with [SgrExtMouseMode] encoding when
+// supported, falling back to [ButtonEventMouseMode], [NormalMouseMode], and
+// [X10MouseMode] tracking, and to the default encoding. Modes that are not
+// recognized or are permanently reset are never used, and modes that are
+// already set are left alone.
+//
+// Close the returned [MouseTracking] to restore the prior modes.
+//
+// Example:
+//
+//	// Write [RequestMouseModes], then collect the [DECRPM] reports.
+//	mt, err := ansi.StartMouseTracking(os.Stdout, modes)
+//	if err != nil {
+//	  return err
+//	}
+//	defer mt.Close()
+func StartMouseTracking(w io.Writer, modes Modes) (*MouseTracking, error) {
+	mt := &MouseTracking{w: w}
+	usable := func(m DECMode) bool {
+		s := modes.Get(m)
+		return !s.IsNotRecognized() && !s.IsPermanentlyReset()
+	}
+
+	var set, reset, prior []Mode
+	for _, m := range mouseTrackingModes {
+		if mt.Mode == 0 && usable(m) {
+			mt.Mode = m
+			if !modes.IsSet(m) {
+				set = append(set, m)
+				reset = append(reset, m)
+			}
+		} else if modes.Get(m) == ModeSet {
+			// Enabling a tracking mode replaces the current one, so we
+			// need to set it back when done.
+			prior = append(prior, m)
+		}
+	}
+
+	if mt.Mode != 0 && usable(SgrExtMouseMode) {
+		mt.Sgr = true
+		if !modes.IsSet(SgrExtMouseMode) {
+			set = append(set, SgrExtMouseMode)
+			reset = append(reset, SgrExtMouseMode)
+		}
+	}
+
+	if mt.Mode == 0 || len(set) == 0 {
+		return mt, nil
+	}
+
+	mt.restore = ResetMode(reset...)
+	if len(prior) > 0 {
+		mt.restore += SetMode(prior...)
+	}
+
+	_, err := io.WriteString(w, SetMode(set...))
+	return mt, err //nolint:wrapcheck
+}
+
+// Close restores the mouse modes changed by [StartMouseTracking]. It's safe
+// to call Close more than once.
+func (m *MouseTracking) Close() error {
+	if m.restore == "" {
+		return nil
+	}
+	restore := m.restore
+	m.restore = ""
+	_, err := io.WriteString(m.w, restore)
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,105 @@
+package ansi
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStartMouseTracking(t *testing.T) {
+	cases := []struct {
+		name    string
+		modes   Modes
+		mode    DECMode
+		sgr     bool
+		enable  string
+		restore string
+	}{
+		{
+			name:  "none",
+			modes: Modes{},
+		},
+		{
+			name: "any_event_sgr",
+			modes: Modes{
+				AnyEventMouseMode:    ModeReset,
+				ButtonEventMouseMode: ModeReset,
+				NormalMouseMode:      ModeReset,
+				X10MouseMode:         ModeReset,
+				SgrExtMouseMode:      ModeReset,
+			},
+			mode:    AnyEventMouseMode,
+			sgr:     true,
+			enable:  "\x1b[?1003;1006h",
+			restore: "\x1b[?1003;1006l",
+		},
+		{
+			name: "fallback_normal",
+			modes: Modes{
+				AnyEventMouseMode: ModePermanentlyReset,
+				NormalMouseMode:   ModeReset,
+				X10MouseMode:      ModeReset,
+			},
+			mode:    NormalMouseMode,
+			enable:  "\x1b[?1000h",
+			restore: "\x1b[?1000l",
+		},
+		{
+			name: "fallback_x10",
+			modes: Modes{
+				X10MouseMode:    ModeReset,
+				SgrExtMouseMode: ModeReset,
+			},
+			mode:    X10MouseMode,
+			sgr:     true,
+			enable:  "\x1b[?9;1006h",
+			restore: "\x1b[?9;1006l",
+		},
+		{
+			name: "restore_prior_tracking",
+			modes: Modes{
+				AnyEventMouseMode: ModeReset,
+				NormalMouseMode:   ModeSet,
+				SgrExtMouseMode:   ModeSet,
+			},
+			mode:    AnyEventMouseMode,
+			sgr:     true,
+			enable:  "\x1b[?1003h",
+			restore: "\x1b[?1003l\x1b[?1000h",
+		},
+		{
+			name: "already_set",
+			modes: Modes{
+				AnyEventMouseMode: ModeSet,
+				SgrExtMouseMode:   ModePermanentlySet,
+			},
+			mode: AnyEventMouseMode,
+			sgr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			mt, err := StartMouseTracking(&buf, c.modes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, c.mode, mt.Mode)
+			assertEqual(t, c.sgr, mt.Sgr)
+			assertEqual(t, c.enable, buf.String())
+
+			buf.Reset()
+			if err := mt.Close(); err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, c.restore, buf.String())
+
+			// Closing again is a no-op.
+			buf.Reset()
+			if err := mt.Close(); err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, "", buf.String())
+		})
+	}
+}
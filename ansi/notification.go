@@ -1,5 +1,7 @@
 package ansi
 
+import "strings"
+
 // Notify sends a desktop notification using iTerm's OSC 9.
 //
 //	OSC 9 ; Mc ST
@@ -7,7 +9,96 @@ package ansi
 //
 // Where Mc is the notification body.
 //
+// ConEmu and Windows Terminal use OSC 9 subcommands, such as [SetProgress].
+// They take a body that starts with a number followed by a semicolon as a
+// subcommand instead of a notification.
+//
 // See: https://iterm2.com/documentation-escape-codes.html
 func Notify(s string) string {
 	return "\x1b]9;" + s + "\x07"
 }
+
+// NotifyWithTitle returns a sequence that sends a desktop notification with
+// the given title and body using iTerm's OSC 9. OSC 9 notifications don't
+// have a title, so a non-empty title is prepended to the body, separated by
+// ": ". Control characters are removed from the title and body.
+//
+// A notification that starts with a number followed by a semicolon, like
+// "4;3", would be taken as a ConEmu subcommand such as [SetProgress], so it's
+// prefixed with a space.
+//
+//	OSC 9 ; Mc BEL
+//
+// See: https://iterm2.com/documentation-escape-codes.html
+func NotifyWithTitle(title, body string) string {
+	title, body = sanitizeTitle(title), sanitizeTitle(body)
+	if title != "" {
+		body = title + ": " + body
+	}
+	if isOsc9Subcommand(body) {
+		body = " " + body
+	}
+	return Notify(body)
+}
+
+// isOsc9Subcommand returns whether the OSC 9 data starts with a number
+// followed by a semicolon, which ConEmu and Windows Terminal read as a
+// subcommand.
+func isOsc9Subcommand(s string) bool {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i > 0 && i < len(s) && s[i] == ';'
+}
+
+// NotifyURxvt returns a sequence that sends a desktop notification with the
+// given title and body using the rxvt-unicode notify extension OSC 777. It's
+// also supported by terminals such as foot, Ghostty, WezTerm, and VTE based
+// terminals. Control characters are removed from the title and body, and
+// semicolons are removed from the title since they separate the fields.
+//
+//	OSC 777 ; notify ; title ; body BEL
+//
+// See: https://github.com/exg/rxvt-unicode/blob/master/src/perl/notify
+func NotifyURxvt(title, body string) string {
+	title = strings.ReplaceAll(sanitizeTitle(title), ";", "")
+	return "\x1b]777;notify;" + title + ";" + sanitizeTitle(body) + "\x07"
+}
+
+// NotifyKitty returns a sequence that sends a desktop notification with the
+// given title and body using the kitty desktop notification protocol OSC 99.
+// The id identifies the notification and is used to update it or match the
+// terminal responses, it can be empty. Characters other than letters,
+// digits, and "-_+." are removed from the id, and control characters are
+// removed from the title and body.
+//
+//	OSC 99 ; i=id : d=0 : p=title ; title BEL
+//	OSC 99 ; i=id : p=body ; body BEL
+//
+// Only use this when the terminal supports [capability.KittyNotifications].
+//
+// See: https://sw.kovidgoyal.net/kitty/desktop-notifications/
+//
+// [capability.KittyNotifications]: https://pkg.go.dev/github.com/charmbracelet/x/ansi/capability#KittyNotifications
+func NotifyKitty(id, title, body string) string {
+	id = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_', r == '+', r == '.':
+			return r
+		}
+		return -1
+	}, id)
+
+	var meta string
+	if id != "" {
+		meta = "i=" + id + ":"
+	}
+	title, body = sanitizeTitle(title), sanitizeTitle(body)
+	if body == "" {
+		return "\x1b]99;" + strings.TrimSuffix(meta, ":") + ";" + title + "\x07"
+	}
+	return "\x1b]99;" + meta + "d=0:p=title;" + title + "\x07" +
+		"\x1b]99;" + meta + "p=body;" + body + "\x07"
+}
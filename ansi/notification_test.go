@@ -0,0 +1,46 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"body", ansi.Notify("Build done"), "\x1b]9;Build done\x07"},
+		{"title", ansi.NotifyWithTitle("make", "Build done"), "\x1b]9;make: Build done\x07"},
+		{"no title", ansi.NotifyWithTitle("", "Build done"), "\x1b]9;Build done\x07"},
+		{"title controls", ansi.NotifyWithTitle("ma\x07ke", "Build\x1b]0;x done"), "\x1b]9;make: Build]0;x done\x07"},
+		{"urxvt", ansi.NotifyURxvt("make", "Build done"), "\x1b]777;notify;make;Build done\x07"},
+		{"urxvt empty", ansi.NotifyURxvt("", ""), "\x1b]777;notify;;\x07"},
+		{"urxvt separators", ansi.NotifyURxvt("a;b", "c;d\u009c"), "\x1b]777;notify;ab;c;d\x07"},
+		{"urxvt raw c1", ansi.NotifyURxvt("a\x9cb", "c\x9b2J"), "\x1b]777;notify;ab;c2J\x07"},
+		{"subcommand body", ansi.NotifyWithTitle("", "4;3"), "\x1b]9; 4;3\x07"},
+		{"subcommand title", ansi.NotifyWithTitle("12;a", "b"), "\x1b]9; 12;a: b\x07"},
+		{"number body", ansi.NotifyWithTitle("", "42 done; ok"), "\x1b]9;42 done; ok\x07"},
+		{"title raw c1", ansi.NotifyWithTitle("a\x9c", "b\x9b2J"), "\x1b]9;a: b2J\x07"},
+		{"kitty raw c1", ansi.NotifyKitty("", "a\x9cb", ""), "\x1b]99;;ab\x07"},
+		{"kitty title", ansi.NotifyKitty("", "Build done", ""), "\x1b]99;;Build done\x07"},
+		{"kitty id", ansi.NotifyKitty("build:1;", "Build done", ""), "\x1b]99;i=build1;Build done\x07"},
+		{
+			"kitty body", ansi.NotifyKitty("b1", "make", "Build\x07 done"),
+			"\x1b]99;i=b1:d=0:p=title;make\x07\x1b]99;i=b1:p=body;Build done\x07",
+		},
+		{
+			"kitty no id", ansi.NotifyKitty("", "make", "done"),
+			"\x1b]99;d=0:p=title;make\x07\x1b]99;p=body;done\x07",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package ansi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestPaletteMatchesColors(t *testing.T) {
+	p := Palette()
+	if len(p) != 256 {
+		t.Fatalf("len(Palette()) = %d, want 256", len(p))
+	}
+	for i, c := range p {
+		r1, g1, b1, a1 := c.RGBA()
+		r2, g2, b2, a2 := ExtendedColor(i).RGBA()
+		if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+			t.Errorf("Palette()[%d] = %v, want ExtendedColor(%d) RGBA", i, c, i)
+		}
+	}
+
+	// The returned palette is a copy.
+	p[1] = TrueColor(0x123456)
+	if r, _, _, _ := ExtendedColor(1).RGBA(); r != 0x8080 {
+		t.Errorf("modifying Palette() changed ExtendedColor(1)")
+	}
+}
+
+func TestPaletteNames(t *testing.T) {
+	tests := []struct {
+		c    ExtendedColor
+		name string
+	}{
+		{0, "Black"},
+		{1, "Maroon"},
+		{15, "White"},
+		{16, "Grey0"},
+		{24, "DeepSkyBlue4"},
+		{196, "Red1"},
+		{231, "Grey100"},
+		{255, "Grey93"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Name(); got != tt.name {
+			t.Errorf("ExtendedColor(%d).Name() = %q, want %q", tt.c, got, tt.name)
+		}
+	}
+
+	if got := Red.Name(); got != "Maroon" {
+		t.Errorf("Red.Name() = %q, want %q", got, "Maroon")
+	}
+	if got := BasicColor(16).Name(); got != "" {
+		t.Errorf("BasicColor(16).Name() = %q, want empty", got)
+	}
+}
+
+func TestExtendedColorByName(t *testing.T) {
+	tests := []struct {
+		name string
+		want ExtendedColor
+		ok   bool
+	}{
+		{"Maroon", 1, true},
+		{"deepskyblue4", 23, true},
+		{"GREY93", 255, true},
+		{"NotAColor", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ExtendedColorByName(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ExtendedColorByName(%q) = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// Every name maps back to a color with the same name.
+	for i := 0; i < 256; i++ {
+		c, ok := ExtendedColorByName(ExtendedColor(i).Name())
+		if !ok || c.Name() != ExtendedColor(i).Name() {
+			t.Errorf("ExtendedColorByName(%q) = %d, %v", ExtendedColor(i).Name(), c, ok)
+		}
+	}
+}
+
+func TestClosestExtendedColor(t *testing.T) {
+	tests := []struct {
+		c    color.Color
+		want ExtendedColor
+	}{
+		{color.Black, 16},
+		{color.White, 231},
+		{color.RGBA{R: 0xff, A: 0xff}, 196},
+		{color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}, 244},
+		{color.RGBA{R: 0x5f, G: 0x87, B: 0xaf, A: 0xff}, 67},
+		{color.RGBA{R: 0x09, G: 0x09, B: 0x09, A: 0xff}, 232},
+		{TrueColor(0x5f5f87), 60},
+	}
+	for _, tt := range tests {
+		if got := ClosestExtendedColor(tt.c); got != tt.want {
+			t.Errorf("ClosestExtendedColor(%v) = %d, want %d", tt.c, got, tt.want)
+		}
+	}
+
+	// Every palette color maps to itself.
+	for i := 16; i < 256; i++ {
+		if got := ClosestExtendedColor(ExtendedColor(i)); got != ExtendedColor(i) {
+			t.Errorf("ClosestExtendedColor(ExtendedColor(%d)) = %d", i, got)
+		}
+	}
+}
+
+func TestClosestBasicColor(t *testing.T) {
+	tests := []struct {
+		c    color.Color
+		want BasicColor
+	}{
+		{color.Black, Black},
+		{color.White, BrightWhite},
+		{color.RGBA{R: 0x90, A: 0xff}, Red},
+		{color.RGBA{R: 0xf0, A: 0xff}, BrightRed},
+		{color.RGBA{R: 0xb0, G: 0xb0, B: 0xb0, A: 0xff}, White},
+	}
+	for _, tt := range tests {
+		if got := ClosestBasicColor(tt.c); got != tt.want {
+			t.Errorf("ClosestBasicColor(%v) = %d, want %d", tt.c, got, tt.want)
+		}
+	}
+}
@@ -14,8 +14,6 @@ import (
 // similar application that needs to parse ANSI escape sequences and control
 // characters.
 // See package [parser] for more information.
-//
-//go:generate go run ./gen.go
 type Parser struct {
 	handler Handler
 
@@ -50,8 +48,41 @@ type Parser struct {
 
 	// state is the current state of the parser.
 	state byte
+
+	// c1Text treats raw C1 bytes as text instead of control characters.
+	c1Text bool
+
+	// sixel reports whether the current DCS sequence is a SIXEL sequence
+	// streamed to [Handler.HandleSixel].
+	sixel bool
+
+	// hooked reports whether the current DCS sequence is streamed to
+	// [Handler.HandleDcsPut], see [Handler.HandleDcsHook].
+	hooked bool
+
+	// limits are the size and flood limits of the parser.
+	limits Limits
+
+	// overflow reports whether the current sequence exceeded a size limit and
+	// must be discarded.
+	overflow bool
+
+	// putLen is the number of data bytes of the current string sequence,
+	// including bytes that didn't fit in the data buffer.
+	putLen int
+
+	// seqCount is the number of consecutive dispatched sequences since the
+	// last printable rune.
+	seqCount int
+
+	// graphemes caches grapheme cluster segmentation results.
+	graphemes *graphemeCache
 }
 
+// streamChunkSize is the size of the chunks passed to [Handler.HandleSixel]
+// and [Handler.HandleDcsPut] when the data buffer is unlimited.
+const streamChunkSize = 4096
+
 // NewParser returns a new parser with the default settings.
 // The [Parser] uses a default size of 32 for the parameters and 64KB for the
 // data buffer. Use [Parser.SetParamsSize] and [Parser.SetDataSize] to set the
@@ -81,6 +112,19 @@ func (p *Parser) SetDataSize(size int) {
 	p.data = make([]byte, size)
 }
 
+// SetC1Controls sets whether raw 8-bit C1 bytes (0x80-0x9F) are recognized
+// as control characters and sequence introducers such as CSI (0x9B), OSC
+// (0x9D), and ST (0x9C). This is the default.
+//
+// When disabled, C1 bytes are treated as text: they're printed as Latin-1
+// runes in the ground state and collected as data in string sequences. This
+// avoids misinterpreting streams that never use 8-bit controls, for example,
+// a UTF-8 OSC payload containing a 0x9C continuation byte. This also applies
+// to [DecodeSequence] and [DecodeSequenceWc] when given this parser.
+func (p *Parser) SetC1Controls(enabled bool) {
+	p.c1Text = !enabled
+}
+
 // Params returns the list of parsed packed parameters.
 func (p *Parser) Params() Params {
 	return unsafe.Slice((*Param)(unsafe.Pointer(&p.params[0])), p.paramsLen)
@@ -126,6 +170,7 @@ func (p *Parser) Data() []byte {
 func (p *Parser) Reset() {
 	p.clear()
 	p.state = parser.GroundState
+	p.seqCount = 0
 }
 
 // clear clears the parser parameters and command.
@@ -135,6 +180,54 @@ func (p *Parser) clear() {
 	}
 	p.paramsLen = 0
 	p.cmd = 0
+	p.sixel = false
+	p.hooked = false
+	p.overflow = false
+	p.putLen = 0
+}
+
+// finishParams counts the last parameter of the sequence if there is one.
+func (p *Parser) finishParams() {
+	if p.paramsLen > 0 && p.paramsLen < len(p.params)-1 ||
+		p.paramsLen == 0 && len(p.params) > 0 && p.params[0] != parser.MissingParam {
+		p.paramsLen++
+	}
+}
+
+// seqLimited reports whether the consecutive sequences limit is reached.
+func (p *Parser) seqLimited() bool {
+	return p.limits.MaxSequences > 0 && p.seqCount >= p.limits.MaxSequences
+}
+
+// flushStream passes the collected data of a streamed DCS sequence to its
+// handler.
+func (p *Parser) flushStream(done bool) {
+	data := p.data
+	if p.dataLen >= 0 {
+		data = data[:p.dataLen]
+	}
+	p.stream(data, done)
+	if p.dataLen < 0 {
+		p.data = p.data[:0]
+	} else {
+		p.dataLen = 0
+	}
+}
+
+// stream passes a chunk of data of a streamed DCS sequence to
+// [Handler.HandleSixel] or [Handler.HandleDcsPut]. When done, the sequence
+// has ended.
+func (p *Parser) stream(data []byte, done bool) {
+	if p.sixel {
+		p.handler.HandleSixel(p.Params(), data, done)
+		return
+	}
+	if len(data) > 0 && p.handler.HandleDcsPut != nil {
+		p.handler.HandleDcsPut(data)
+	}
+	if done && p.handler.HandleDcsUnhook != nil {
+		p.handler.HandleDcsUnhook(false)
+	}
 }
 
 // State returns the current state of the parser.
@@ -193,6 +286,7 @@ func (p *Parser) advanceUtf8(b byte) parser.Action {
 	}
 
 	// We have enough bytes to decode the rune using unsafe
+	p.seqCount = 0
 	if p.handler.Print != nil {
 		p.handler.Print(p.Rune())
 	}
@@ -204,6 +298,10 @@ func (p *Parser) advanceUtf8(b byte) parser.Action {
 }
 
 func (p *Parser) advance(b byte) parser.Action {
+	if p.c1Text && b >= PAD && b <= APC {
+		return p.advanceC1Text(b)
+	}
+
 	state, action := parser.Table.Transition(p.state, b)
 
 	// We need to clear the parser state if the state changes from EscapeState.
@@ -225,6 +323,14 @@ func (p *Parser) advance(b byte) parser.Action {
 		}
 	}
 
+	if p.hooked && state == parser.GroundState && action == parser.IgnoreAction {
+		// CAN or SUB canceled the hooked DCS sequence.
+		p.hooked = false
+		if p.handler.HandleDcsUnhook != nil {
+			p.handler.HandleDcsUnhook(true)
+		}
+	}
+
 	// Handle special cases
 	switch {
 	case b == ESC && p.state == parser.EscapeState:
@@ -239,6 +345,20 @@ func (p *Parser) advance(b byte) parser.Action {
 	return action
 }
 
+// advanceC1Text handles a raw C1 byte as text when C1 controls are disabled.
+func (p *Parser) advanceC1Text(b byte) parser.Action {
+	switch p.state {
+	case parser.GroundState:
+		p.performAction(parser.PrintAction, p.state, b)
+		return parser.PrintAction
+	case parser.DcsStringState, parser.OscStringState, parser.SosStringState,
+		parser.PmStringState, parser.ApcStringState:
+		p.performAction(parser.PutAction, p.state, b)
+		return parser.PutAction
+	}
+	return parser.IgnoreAction
+}
+
 func (p *Parser) parseStringCmd() {
 	// Try to parse the command
 	datalen := len(p.data)
@@ -268,6 +388,7 @@ func (p *Parser) performAction(action parser.Action, state parser.State, b byte)
 
 	case parser.PrintAction:
 		p.cmd = int(b)
+		p.seqCount = 0
 		if p.handler.Print != nil {
 			p.handler.Print(rune(b))
 		}
@@ -316,6 +437,11 @@ func (p *Parser) performAction(action parser.Action, state parser.State, b byte)
 		}
 
 		if b == ';' || b == ':' {
+			if p.limits.MaxParams > 0 && p.paramsLen+1 >= p.limits.MaxParams {
+				// The separator starts a parameter beyond the limit.
+				p.overflow = true
+				break
+			}
 			p.paramsLen++
 			if p.paramsLen < len(p.params) {
 				p.params[p.paramsLen] = parser.MissingParam
@@ -323,6 +449,10 @@ func (p *Parser) performAction(action parser.Action, state parser.State, b byte)
 		}
 
 	case parser.StartAction:
+		p.putLen = 0
+		if p.state < parser.DcsEntryState || p.state > parser.DcsStringState {
+			p.overflow = false
+		}
 		if p.dataLen < 0 && p.data != nil {
 			p.data = p.data[:0]
 		} else {
@@ -331,11 +461,36 @@ func (p *Parser) performAction(action parser.Action, state parser.State, b byte)
 		if p.state >= parser.DcsEntryState && p.state <= parser.DcsStringState {
 			// Collect the command byte for DCS
 			p.cmd |= int(b)
+			if p.cmd == 'q' && p.handler.HandleSixel != nil &&
+				!p.overflow && !p.seqLimited() {
+				// Stream SIXEL data instead of buffering it.
+				p.sixel = true
+				p.finishParams()
+			} else if p.handler.HandleDcsHook != nil && !p.overflow && !p.seqLimited() {
+				n := p.paramsLen
+				p.finishParams()
+				p.hooked = p.handler.HandleDcsHook(Cmd(p.cmd), p.Params())
+				if !p.hooked {
+					// The parameters are finished again on dispatch.
+					p.paramsLen = n
+				}
+			}
 		} else {
 			p.cmd = parser.MissingCommand
 		}
 
 	case parser.PutAction:
+		if p.overflow {
+			break
+		}
+		if !p.sixel && !p.hooked && p.limits.MaxDataLen > 0 {
+			if p.putLen >= p.limits.MaxDataLen {
+				p.overflow = true
+				break
+			}
+			p.putLen++
+		}
+
 		switch p.state {
 		case parser.OscStringState:
 			if b == ';' && p.cmd == parser.MissingCommand {
@@ -343,6 +498,18 @@ func (p *Parser) performAction(action parser.Action, state parser.State, b byte)
 			}
 		}
 
+		if p.sixel || p.hooked {
+			if p.dataLen >= 0 && len(p.data) == 0 {
+				// There is no data buffer, pass the byte as is.
+				p.stream([]byte{b}, false)
+				break
+			}
+			if p.dataLen < 0 && len(p.data) >= streamChunkSize ||
+				p.dataLen >= 0 && p.dataLen >= len(p.data) {
+				p.flushStream(false)
+			}
+		}
+
 		if p.dataLen < 0 {
 			p.data = append(p.data, b)
 		} else {
@@ -353,12 +520,28 @@ func (p *Parser) performAction(action parser.Action, state parser.State, b byte)
 		}
 
 	case parser.DispatchAction:
-		// Increment the last parameter
-		if p.paramsLen > 0 && p.paramsLen < len(p.params)-1 ||
-			p.paramsLen == 0 && len(p.params) > 0 && p.params[0] != parser.MissingParam {
-			p.paramsLen++
+		if p.sixel || p.hooked {
+			p.flushStream(true)
+			p.sixel = false
+			p.hooked = false
+			p.seqCount++
+			break
 		}
 
+		// The string terminator (ESC \) of a dispatched string sequence
+		// doesn't count as a sequence of its own.
+		if p.state != parser.EscapeState || b != '\\' {
+			if p.overflow || p.seqLimited() {
+				// Discard the sequence.
+				p.overflow = false
+				break
+			}
+			p.seqCount++
+		}
+
+		// Increment the last parameter
+		p.finishParams()
+
 		if p.state == parser.OscStringState && p.cmd == parser.MissingCommand {
 			// Ensure we have a command for OSC
 			p.parseStringCmd()
@@ -0,0 +1,58 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+
+	. "github.com/charmbracelet/x/ansi/parser"
+)
+
+func main() {
+	var f bytes.Buffer
+	table := GenerateTransitionTable()
+	_, _ = f.WriteString(`// Code generated by gen.go. DO NOT EDIT.
+
+package parser
+
+// Table is a DEC ANSI transition table generated from [Spec].
+//
+// Each row is a state and each value is
+//
+//	action << TransitionActionShift | nextState
+var Table = TransitionTable{
+`)
+	for i := 0; i < len(table); i += 256 {
+		state := i >> IndexStateShift
+		name := fmt.Sprintf("State(%d)", state)
+		if state < len(StateNames) {
+			name = StateNames[state]
+		}
+		fmt.Fprintf(&f, "\t// %s\n", name)
+		for j := 0; j < 256; j += 16 {
+			f.WriteString("\t")
+			for k, v := range table[i+j : i+j+16] {
+				if k > 0 {
+					f.WriteString(" ")
+				}
+				fmt.Fprintf(&f, "0x%02x,", v)
+			}
+			fmt.Fprintf(&f, " // 0x%02x-0x%02x\n", j, j+15)
+		}
+	}
+
+	fmt.Fprintln(&f, "}")
+	content, err := format.Source(f.Bytes())
+	if err != nil {
+		log.Fatalf("formatting source: %v", err)
+	}
+
+	if err := os.WriteFile("table.go", content, 0o644); err != nil { //nolint:gosec
+		log.Fatalf("writing file: %v", err)
+	}
+}
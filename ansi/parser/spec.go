@@ -0,0 +1,200 @@
+package parser
+
+// SameState is a [Rule] next state that keeps the current state.
+const SameState State = 0xFF
+
+// Rule is a declarative transition rule. It maps each of the Codes bytes in
+// each of the States to the given Action and Next state. When rules overlap,
+// the last one wins.
+type Rule struct {
+	States []State
+	Codes  []byte
+	Action Action
+	Next   State
+}
+
+// Apply adds the given rules to the transition table in order.
+func (t TransitionTable) Apply(rules ...Rule) {
+	for _, rule := range rules {
+		for _, state := range rule.States {
+			next := rule.Next
+			if next == SameState {
+				next = state
+			}
+			t.AddMany(rule.Codes, state, rule.Action, next)
+		}
+	}
+}
+
+// codes returns the given bytes.
+func codes(b ...byte) []byte {
+	return b
+}
+
+// states returns the given states.
+func states(s ...State) []State {
+	return s
+}
+
+// c0 are the C0 control characters that are executed, ignored, or put
+// depending on the state. CAN (0x18), SUB (0x1A), and ESC (0x1B) are handled
+// by the anywhere rules.
+var c0 = append(append(r(0x00, 0x17), 0x19), r(0x1C, 0x1F)...)
+
+// Spec is the declarative specification of the DEC ANSI transition table
+// generated by [GenerateTransitionTable]. Rules are applied in order on a
+// table defaulting to [NoneAction] and [GroundState].
+//
+// After changing the spec, run go generate to regenerate [Table].
+var Spec = []Rule{
+	// Anywhere -> Ground
+	{r(GroundState, Utf8State), codes(0x18, 0x1A, 0x99, 0x9A), ExecuteAction, GroundState},
+	{r(GroundState, Utf8State), r(0x80, 0x8F), ExecuteAction, GroundState},
+	{r(GroundState, Utf8State), r(0x90, 0x97), ExecuteAction, GroundState},
+	{r(GroundState, Utf8State), codes(0x9C), ExecuteAction, GroundState},
+	// Anywhere -> Escape
+	{r(GroundState, Utf8State), codes(0x1B), ClearAction, EscapeState},
+	// Anywhere -> SosStringState
+	{r(GroundState, Utf8State), codes(0x98), StartAction, SosStringState},
+	// Anywhere -> PmStringState
+	{r(GroundState, Utf8State), codes(0x9E), StartAction, PmStringState},
+	// Anywhere -> ApcStringState
+	{r(GroundState, Utf8State), codes(0x9F), StartAction, ApcStringState},
+	// Anywhere -> CsiEntry
+	{r(GroundState, Utf8State), codes(0x9B), ClearAction, CsiEntryState},
+	// Anywhere -> DcsEntry
+	{r(GroundState, Utf8State), codes(0x90), ClearAction, DcsEntryState},
+	// Anywhere -> OscString
+	{r(GroundState, Utf8State), codes(0x9D), StartAction, OscStringState},
+	// Anywhere -> Utf8
+	{r(GroundState, Utf8State), r(0xC2, 0xDF), CollectAction, Utf8State}, // UTF8 2 byte sequence
+	{r(GroundState, Utf8State), r(0xE0, 0xEF), CollectAction, Utf8State}, // UTF8 3 byte sequence
+	{r(GroundState, Utf8State), r(0xF0, 0xF4), CollectAction, Utf8State}, // UTF8 4 byte sequence
+
+	// Ground
+	{states(GroundState), c0, ExecuteAction, GroundState},
+	{states(GroundState), r(0x20, 0x7E), PrintAction, GroundState},
+	{states(GroundState), codes(0x7F), ExecuteAction, GroundState},
+
+	// EscapeIntermediate
+	{states(EscapeIntermediateState), c0, ExecuteAction, SameState},
+	{states(EscapeIntermediateState), r(0x20, 0x2F), CollectAction, SameState},
+	{states(EscapeIntermediateState), codes(0x7F), IgnoreAction, SameState},
+	// EscapeIntermediate -> Ground
+	{states(EscapeIntermediateState), r(0x30, 0x7E), DispatchAction, GroundState},
+
+	// Escape
+	{states(EscapeState), c0, ExecuteAction, SameState},
+	{states(EscapeState), codes(0x7F), IgnoreAction, SameState},
+	// Escape -> Ground
+	{states(EscapeState), r(0x30, 0x4F), DispatchAction, GroundState},
+	{states(EscapeState), r(0x51, 0x57), DispatchAction, GroundState},
+	{states(EscapeState), codes(0x59, 0x5A, 0x5C), DispatchAction, GroundState},
+	{states(EscapeState), r(0x60, 0x7E), DispatchAction, GroundState},
+	// Escape -> Escape_intermediate
+	{states(EscapeState), r(0x20, 0x2F), CollectAction, EscapeIntermediateState},
+	// Escape -> Sos_pm_apc_string
+	{states(EscapeState), codes('X'), StartAction, SosStringState}, // SOS
+	{states(EscapeState), codes('^'), StartAction, PmStringState},  // PM
+	{states(EscapeState), codes('_'), StartAction, ApcStringState}, // APC
+	// Escape -> Dcs_entry
+	{states(EscapeState), codes('P'), ClearAction, DcsEntryState},
+	// Escape -> Csi_entry
+	{states(EscapeState), codes('['), ClearAction, CsiEntryState},
+	// Escape -> Osc_string
+	{states(EscapeState), codes(']'), StartAction, OscStringState},
+
+	// Sos_pm_apc_string
+	{r(SosStringState, ApcStringState), c0, PutAction, SameState},
+	{r(SosStringState, ApcStringState), r(0x20, 0x7F), PutAction, SameState},
+	// ESC, ST, CAN, and SUB terminate the sequence
+	{r(SosStringState, ApcStringState), codes(0x1B), DispatchAction, EscapeState},
+	{r(SosStringState, ApcStringState), codes(0x9C), DispatchAction, GroundState},
+	{r(SosStringState, ApcStringState), codes(0x18, 0x1A), IgnoreAction, GroundState},
+
+	// Dcs_entry
+	{states(DcsEntryState), r(0x00, 0x07), IgnoreAction, SameState},
+	{states(DcsEntryState), r(0x0E, 0x17), IgnoreAction, SameState},
+	{states(DcsEntryState), codes(0x19), IgnoreAction, SameState},
+	{states(DcsEntryState), r(0x1C, 0x1F), IgnoreAction, SameState},
+	{states(DcsEntryState), codes(0x7F), IgnoreAction, SameState},
+	// Dcs_entry -> Dcs_intermediate
+	{states(DcsEntryState), r(0x20, 0x2F), CollectAction, DcsIntermediateState},
+	// Dcs_entry -> Dcs_param
+	{states(DcsEntryState), r(0x30, 0x3B), ParamAction, DcsParamState},
+	{states(DcsEntryState), r(0x3C, 0x3F), PrefixAction, DcsParamState},
+	// Dcs_entry -> Dcs_passthrough
+	{states(DcsEntryState), r(0x08, 0x0D), PutAction, DcsStringState}, // Follows ECMA-48 § 8.3.27
+	// XXX: allows passing ESC (not a ECMA-48 standard) this to allow for
+	// passthrough of ANSI sequences like in Screen or Tmux passthrough mode.
+	{states(DcsEntryState), codes(0x1B), PutAction, DcsStringState},
+	{states(DcsEntryState), r(0x40, 0x7E), StartAction, DcsStringState},
+
+	// Dcs_intermediate
+	{states(DcsIntermediateState), c0, IgnoreAction, SameState},
+	{states(DcsIntermediateState), r(0x20, 0x2F), CollectAction, SameState},
+	{states(DcsIntermediateState), codes(0x7F), IgnoreAction, SameState},
+	// Dcs_intermediate -> Dcs_passthrough
+	{states(DcsIntermediateState), r(0x30, 0x7E), StartAction, DcsStringState},
+
+	// Dcs_param
+	{states(DcsParamState), c0, IgnoreAction, SameState},
+	{states(DcsParamState), r(0x30, 0x3B), ParamAction, SameState},
+	{states(DcsParamState), codes(0x7F), IgnoreAction, SameState},
+	{states(DcsParamState), r(0x3C, 0x3F), IgnoreAction, SameState},
+	// Dcs_param -> Dcs_intermediate
+	{states(DcsParamState), r(0x20, 0x2F), CollectAction, DcsIntermediateState},
+	// Dcs_param -> Dcs_passthrough
+	{states(DcsParamState), r(0x40, 0x7E), StartAction, DcsStringState},
+
+	// Dcs_passthrough
+	{states(DcsStringState), c0, PutAction, SameState},
+	{states(DcsStringState), r(0x20, 0x7F), PutAction, SameState},
+	{states(DcsStringState), r(0x80, 0xFF), PutAction, SameState}, // Allow Utf8 characters by extending the printable range from 0x7F to 0xFF
+	// ST, CAN, SUB, and ESC terminate the sequence
+	{states(DcsStringState), codes(0x1B), DispatchAction, EscapeState},
+	{states(DcsStringState), codes(0x9C), DispatchAction, GroundState},
+	{states(DcsStringState), codes(0x18, 0x1A), IgnoreAction, GroundState},
+
+	// Csi_param
+	{states(CsiParamState), c0, ExecuteAction, SameState},
+	{states(CsiParamState), r(0x30, 0x3B), ParamAction, SameState},
+	{states(CsiParamState), codes(0x7F), IgnoreAction, SameState},
+	{states(CsiParamState), r(0x3C, 0x3F), IgnoreAction, SameState},
+	// Csi_param -> Ground
+	{states(CsiParamState), r(0x40, 0x7E), DispatchAction, GroundState},
+	// Csi_param -> Csi_intermediate
+	{states(CsiParamState), r(0x20, 0x2F), CollectAction, CsiIntermediateState},
+
+	// Csi_intermediate
+	{states(CsiIntermediateState), c0, ExecuteAction, SameState},
+	{states(CsiIntermediateState), r(0x20, 0x2F), CollectAction, SameState},
+	{states(CsiIntermediateState), codes(0x7F), IgnoreAction, SameState},
+	// Csi_intermediate -> Ground
+	{states(CsiIntermediateState), r(0x40, 0x7E), DispatchAction, GroundState},
+	// Csi_intermediate -> Csi_ignore
+	{states(CsiIntermediateState), r(0x30, 0x3F), IgnoreAction, GroundState},
+
+	// Csi_entry
+	{states(CsiEntryState), c0, ExecuteAction, SameState},
+	{states(CsiEntryState), codes(0x7F), IgnoreAction, SameState},
+	// Csi_entry -> Ground
+	{states(CsiEntryState), r(0x40, 0x7E), DispatchAction, GroundState},
+	// Csi_entry -> Csi_intermediate
+	{states(CsiEntryState), r(0x20, 0x2F), CollectAction, CsiIntermediateState},
+	// Csi_entry -> Csi_param
+	{states(CsiEntryState), r(0x30, 0x3B), ParamAction, CsiParamState},
+	{states(CsiEntryState), r(0x3C, 0x3F), PrefixAction, CsiParamState},
+
+	// Osc_string
+	{states(OscStringState), r(0x00, 0x06), IgnoreAction, SameState},
+	{states(OscStringState), r(0x08, 0x17), IgnoreAction, SameState},
+	{states(OscStringState), codes(0x19), IgnoreAction, SameState},
+	{states(OscStringState), r(0x1C, 0x1F), IgnoreAction, SameState},
+	{states(OscStringState), r(0x20, 0xFF), PutAction, SameState}, // Allow Utf8 characters by extending the printable range from 0x7F to 0xFF
+	// ST, CAN, SUB, ESC, and BEL terminate the sequence
+	{states(OscStringState), codes(0x1B), DispatchAction, EscapeState},
+	{states(OscStringState), codes(0x07), DispatchAction, GroundState},
+	{states(OscStringState), codes(0x9C), DispatchAction, GroundState},
+	{states(OscStringState), codes(0x18, 0x1A), IgnoreAction, GroundState},
+}
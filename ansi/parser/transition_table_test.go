@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableIsGenerated(t *testing.T) {
+	if !bytes.Equal(Table, GenerateTransitionTable()) {
+		t.Fatal("Table is out of date with Spec, run go generate")
+	}
+}
+
+func TestApply(t *testing.T) {
+	table := NewTransitionTable(DefaultTableSize)
+	table.SetDefault(NoneAction, GroundState)
+	table.Apply(
+		Rule{States: []State{CsiEntryState, CsiParamState}, Codes: []byte{'a'}, Action: PrintAction, Next: SameState},
+		Rule{States: []State{CsiParamState}, Codes: []byte{'a', 'b'}, Action: ExecuteAction, Next: EscapeState},
+	)
+
+	tests := []struct {
+		state  State
+		code   byte
+		next   State
+		action Action
+	}{
+		{CsiEntryState, 'a', CsiEntryState, PrintAction},
+		{CsiParamState, 'a', EscapeState, ExecuteAction},
+		{CsiParamState, 'b', EscapeState, ExecuteAction},
+		{CsiEntryState, 'b', GroundState, NoneAction},
+	}
+	for _, tt := range tests {
+		next, action := table.Transition(tt.state, tt.code)
+		if next != tt.next || action != tt.action {
+			t.Errorf("Transition(%s, %q) = %s, %s, want %s, %s",
+				StateNames[tt.state], tt.code, StateNames[next], ActionNames[action],
+				StateNames[tt.next], ActionNames[tt.action])
+		}
+	}
+}
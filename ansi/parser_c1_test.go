@@ -0,0 +1,176 @@
+package ansi
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+func TestC1Sequence(t *testing.T) {
+	cases := []testCase{
+		{
+			name:  "csi",
+			input: "\x9b1;31mA\x9b?25l",
+			expected: []any{
+				csiSequence{Cmd: 'm', Params: Params{1, 31}},
+				rune('A'),
+				csiSequence{Cmd: 'l' | '?'<<parser.PrefixShift, Params: Params{25}},
+			},
+		},
+		{
+			name:  "osc",
+			input: "\x9d2;title\x9c",
+			expected: []any{
+				[]byte("2;title"),
+			},
+		},
+		{
+			name:  "dcs",
+			input: "\x90+q544e\x9c",
+			expected: []any{
+				dcsSequence{Cmd: 'q' | '+'<<parser.IntermedShift, Params: Params{}, Data: []byte("544e")},
+			},
+		},
+		{
+			name:  "apc",
+			input: "\x9fGi=1\x9c",
+			expected: []any{
+				[]byte("Gi=1"),
+			},
+		},
+		{
+			name:  "execute",
+			input: "a\x84b\x85c\x88",
+			expected: []any{
+				rune('a'),
+				byte(0x84),
+				rune('b'),
+				byte(0x85),
+				rune('c'),
+				byte(0x88),
+			},
+		},
+		{
+			name:  "cancel",
+			input: "\x9b1;\x9b2J",
+			expected: []any{
+				csiSequence{Cmd: 'J', Params: Params{2}},
+			},
+		},
+		{
+			name:  "st in ground",
+			input: "\x9c",
+			expected: []any{
+				byte(0x9c),
+			},
+		},
+		{
+			name:  "mixed 7-bit and 8-bit",
+			input: "\x1b]0;a\x9c\x9d1;b\x1b\\",
+			expected: []any{
+				[]byte("0;a"),
+				[]byte("1;b"),
+				Cmd('\\'),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dispatcher := &testDispatcher{}
+			parser := testParser(dispatcher)
+			parser.Parse([]byte(c.input))
+			assertEqual(t, len(c.expected), len(dispatcher.dispatched))
+			for i := range c.expected {
+				assertEqual(t, c.expected[i], dispatcher.dispatched[i])
+			}
+		})
+	}
+}
+
+func TestC1Text(t *testing.T) {
+	cases := []testCase{
+		{
+			name:  "csi",
+			input: "\x9b1m",
+			expected: []any{
+				rune(0x9b),
+				rune('1'),
+				rune('m'),
+			},
+		},
+		{
+			name:  "utf8 osc",
+			input: "\x1b]2;\xe6\x9c\xab\x1b\\",
+			expected: []any{
+				[]byte("2;\xe6\x9c\xab"),
+				Cmd('\\'),
+			},
+		},
+		{
+			name:  "st in osc",
+			input: "\x1b]2;a\x9cb\x07",
+			expected: []any{
+				[]byte("2;a\x9cb"),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dispatcher := &testDispatcher{}
+			parser := testParser(dispatcher)
+			parser.SetC1Controls(false)
+			parser.Parse([]byte(c.input))
+			assertEqual(t, len(c.expected), len(dispatcher.dispatched))
+			for i := range c.expected {
+				assertEqual(t, c.expected[i], dispatcher.dispatched[i])
+			}
+		})
+	}
+}
+
+func TestDecodeSequenceC1(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		controls []string
+		text     []string
+	}{
+		{
+			name:     "csi",
+			input:    "\x9b31mA",
+			controls: []string{"\x9b31m", "A"},
+			text:     []string{"\x9b", "3", "1", "m", "A"},
+		},
+		{
+			name:     "utf8 osc",
+			input:    "\x1b]2;\xe6\x9c\xab\x1b\\",
+			controls: []string{"\x1b]2;\xe6\x9c", "\xab", "\x1b\\"},
+			text:     []string{"\x1b]2;\xe6\x9c\xab\x1b\\"},
+		},
+		{
+			name:     "apc",
+			input:    "\x9fGa=q\x9c",
+			controls: []string{"\x9fGa=q\x9c"},
+			text:     []string{"\x9f", "G", "a", "=", "q", "\x9c"},
+		},
+	}
+	decode := func(input string, p *Parser) []string {
+		var seqs []string
+		var state byte
+		for len(input) > 0 {
+			seq, _, n, newState := DecodeSequence(input, state, p)
+			seqs = append(seqs, seq)
+			state = newState
+			input = input[n:]
+		}
+		return seqs
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewParser()
+			assertEqual(t, c.controls, decode(c.input, p))
+			p.SetC1Controls(false)
+			assertEqual(t, c.text, decode(c.input, p))
+		})
+	}
+}
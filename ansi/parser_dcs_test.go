@@ -80,3 +80,184 @@ func TestDcsSequence(t *testing.T) {
 		})
 	}
 }
+
+type sixelChunk struct {
+	Params Params
+	Data   string
+	Done   bool
+}
+
+func TestDcsSixelStreaming(t *testing.T) {
+	cases := []struct {
+		name     string
+		dataSize int
+		input    string
+		chunks   []sixelChunk
+		dcs      int
+	}{
+		{
+			name:     "chunked",
+			dataSize: 4,
+			input:    "\x1bP0;1;0q#0;2;0;0;0\x1b\\",
+			chunks: []sixelChunk{
+				{Params{0, 1, 0}, "#0;2", false},
+				{Params{0, 1, 0}, ";0;0", false},
+				{Params{0, 1, 0}, ";0", true},
+			},
+		},
+		{
+			name:     "unlimited",
+			dataSize: -1,
+			input:    "\x1bPq#0!5~\x9c",
+			chunks: []sixelChunk{
+				{Params{}, "#0!5~", true},
+			},
+		},
+		{
+			name:     "no_buffer",
+			dataSize: 0,
+			input:    "\x1bP;1q~-\x1b\\",
+			chunks: []sixelChunk{
+				{Params{parser.MissingParam, 1}, "~", false},
+				{Params{parser.MissingParam, 1}, "-", false},
+				{Params{parser.MissingParam, 1}, "", true},
+			},
+		},
+		{
+			name:     "not_sixel",
+			dataSize: 64,
+			input:    "\x1bP$qm\x1b\\\x1bP?qx\x1b\\",
+			dcs:      2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var chunks []sixelChunk
+			var dcs int
+			p := new(Parser)
+			p.SetParamsSize(parser.MaxParamsSize)
+			if c.dataSize != 0 {
+				p.SetDataSize(c.dataSize)
+			}
+			p.SetHandler(Handler{
+				HandleDcs: func(Cmd, Params, []byte) { dcs++ },
+				HandleSixel: func(params Params, data []byte, done bool) {
+					chunks = append(chunks, sixelChunk{
+						Params: append(Params{}, params...),
+						Data:   string(data),
+						Done:   done,
+					})
+				},
+			})
+			p.Parse([]byte(c.input))
+			assertEqual(t, c.chunks, chunks)
+			assertEqual(t, c.dcs, dcs)
+		})
+	}
+}
+
+func TestDcsSixelWithoutHandler(t *testing.T) {
+	dispatcher := &testDispatcher{}
+	p := testParser(dispatcher)
+	p.Parse([]byte("\x1bP0;1q#0~\x1b\\"))
+	assertEqual(t, []any{
+		dcsSequence{Cmd: 'q', Params: Params{0, 1}, Data: []byte("#0~")},
+		Cmd('\\'),
+	}, dispatcher.dispatched)
+}
+
+func TestDcsHook(t *testing.T) {
+	cases := []struct {
+		name     string
+		dataSize int
+		input    string
+		events   []string
+		dcs      int
+	}{
+		{
+			name:     "chunked",
+			dataSize: 4,
+			input:    "\x1bP1+q544e;636f6c73\x1b\\",
+			events:   []string{"hook +q [1]", "put 544e", "put ;636", "put f6c7", "put 3", "unhook"},
+		},
+		{
+			name:     "unlimited",
+			dataSize: -1,
+			input:    "\x1bP$qm\x9c",
+			events:   []string{"hook $q []", "put m", "unhook"},
+		},
+		{
+			name:     "no_buffer",
+			dataSize: 0,
+			input:    "\x1bP$qr\x1b\\",
+			events:   []string{"hook $q []", "put r", "unhook"},
+		},
+		{
+			name:     "empty",
+			dataSize: 64,
+			input:    "\x1bP$q\x1b\\",
+			events:   []string{"hook $q []", "unhook"},
+		},
+		{
+			name:     "canceled",
+			dataSize: 64,
+			input:    "\x1bP$qm\x18",
+			events:   []string{"hook $q []", "unhook canceled"},
+		},
+		{
+			name:     "not_hooked",
+			dataSize: 64,
+			input:    "\x1bP1;2|x\x1b\\",
+			dcs:      1,
+		},
+		{
+			name:     "sixel",
+			dataSize: 64,
+			input:    "\x1bPq~\x1b\\",
+			dcs:      1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var events []string
+			var dcs int
+			p := new(Parser)
+			p.SetParamsSize(parser.MaxParamsSize)
+			if c.dataSize != 0 {
+				p.SetDataSize(c.dataSize)
+			}
+			p.SetHandler(Handler{
+				HandleDcs: func(cmd Cmd, params Params, _ []byte) {
+					dcs++
+					if cmd == '|' && len(params) != 2 {
+						t.Errorf("params = %v, want 2 parameters", params)
+					}
+				},
+				HandleDcsHook: func(cmd Cmd, params Params) bool {
+					if cmd.Final() == '|' || cmd.Final() == 'q' && cmd.Intermediate() == 0 {
+						return false
+					}
+					var ps []int
+					params.ForEach(0, func(_, p int, _ bool) { ps = append(ps, p) })
+					events = append(events, fmt.Sprintf("hook %c%c %v", cmd.Intermediate(), cmd.Final(), ps))
+					return true
+				},
+				HandleDcsPut: func(data []byte) {
+					events = append(events, "put "+string(data))
+				},
+				HandleDcsUnhook: func(canceled bool) {
+					if canceled {
+						events = append(events, "unhook canceled")
+						return
+					}
+					events = append(events, "unhook")
+				},
+			})
+			p.Parse([]byte(c.input))
+			assertEqual(t, c.events, events)
+			assertEqual(t, c.dcs, dcs)
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package ansi
 
 import (
-	"unicode/utf8"
-
 	"github.com/charmbracelet/x/ansi/parser"
 	"github.com/mattn/go-runewidth"
 	"github.com/rivo/uniseg"
@@ -39,6 +37,9 @@ const (
 // [Cmd] and [Param] types to unpack command intermediates and prefixes as well
 // as parameters.
 //
+// Raw 8-bit C1 bytes are recognized as control characters and sequence
+// introducers unless disabled with [Parser.SetC1Controls].
+//
 // Zero [Cmd] means the CSI, DCS, or ESC sequence is invalid. Moreover, checking the
 // validity of other data sequences, OSC, DCS, etc, will require checking for
 // the returned sequence terminator bytes such as ST (ESC \\) and BEL).
@@ -89,6 +90,9 @@ func DecodeSequence[T string | []byte](b T, state byte, p *Parser) (seq T, width
 // [Cmd] and [Param] types to unpack command intermediates and prefixes as well
 // as parameters.
 //
+// Raw 8-bit C1 bytes are recognized as control characters and sequence
+// introducers unless disabled with [Parser.SetC1Controls].
+//
 // Zero [Cmd] means the CSI, DCS, or ESC sequence is invalid. Moreover, checking the
 // validity of other data sequences, OSC, DCS, etc, will require checking for
 // the returned sequence terminator bytes such as ST (ESC \\) and BEL).
@@ -122,14 +126,137 @@ func DecodeSequenceWc[T string | []byte](b T, state byte, p *Parser) (seq T, wid
 	return decodeSequence(WcWidth, b, state, p)
 }
 
+// Byte classes used by [decodeSequence] to dispatch bytes with a single table
+// lookup per state.
+const (
+	printableClass byte = iota
+	controlClass
+	escapeClass
+	introducerClass // CSI and DCS, or their ESC forms
+	stringClass     // OSC, APC, SOS, and PM, or their ESC forms
+	utf8Class
+	invalidClass
+	prefixClass   // <=>?
+	digitClass    // 0-9
+	subParamClass // :
+	paramSepClass // ;
+	intermedClass
+	finalClass
+	belClass
+	stClass
+	cancelClass // CAN and SUB
+	dataClass
+)
+
+// normalClasses maps each byte to its class in the [NormalState].
+var normalClasses = func() (t [256]byte) {
+	for i := range t {
+		c := byte(i)
+		switch {
+		case c == ESC:
+			t[i] = escapeClass
+		case c == CSI || c == DCS:
+			t[i] = introducerClass
+		case c == OSC || c == APC || c == SOS || c == PM:
+			t[i] = stringClass
+		case c > US && c < DEL:
+			t[i] = printableClass
+		case c < 0xC0: // C0 & C1 control characters & DEL
+			t[i] = controlClass
+		default:
+			t[i] = utf8Class
+		}
+	}
+	return
+}()
+
+// escapeClasses maps each byte to its class in the [EscapeState].
+var escapeClasses = func() (t [256]byte) {
+	for i := range t {
+		c := byte(i)
+		switch {
+		case c == '[' || c == 'P':
+			t[i] = introducerClass
+		case c == ']' || c == 'X' || c == '^' || c == '_':
+			t[i] = stringClass
+		case c >= ' ' && c <= '/':
+			t[i] = intermedClass
+		case c >= '0' && c <= '~':
+			t[i] = finalClass
+		default:
+			t[i] = invalidClass
+		}
+	}
+	return
+}()
+
+// csiClasses maps each byte to its class in the [PrefixState],
+// [ParamsState], and [IntermedState] of CSI and DCS sequences.
+var csiClasses = func() (t [256]byte) {
+	for i := range t {
+		c := byte(i)
+		switch {
+		case c >= '<' && c <= '?':
+			t[i] = prefixClass
+		case c >= '0' && c <= '9':
+			t[i] = digitClass
+		case c == ':':
+			t[i] = subParamClass
+		case c == ';':
+			t[i] = paramSepClass
+		case c >= ' ' && c <= '/':
+			t[i] = intermedClass
+		case c >= '@' && c <= '~':
+			t[i] = finalClass
+		default:
+			t[i] = invalidClass
+		}
+	}
+	return
+}()
+
+// stringClasses maps each byte to its class in the [StringState].
+var stringClasses = func() (t [256]byte) {
+	for i := range t {
+		switch c := byte(i); c {
+		case BEL:
+			t[i] = belClass
+		case CAN, SUB:
+			t[i] = cancelClass
+		case ST:
+			t[i] = stClass
+		case ESC:
+			t[i] = escapeClass
+		default:
+			t[i] = dataClass
+		}
+	}
+	return
+}()
+
 func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (seq T, width int, n int, newState byte) {
+	// Fast path for printable ASCII characters, the most common case.
+	if state == NormalState && len(b) > 0 && normalClasses[b[0]] == printableClass {
+		if p != nil {
+			p.dataLen = 0
+			p.paramsLen = 0
+			p.cmd = 0
+		}
+		return b[:1], 1, 1, NormalState
+	}
+
 	for i := 0; i < len(b); i++ {
 		c := b[i]
 
 		switch state {
 		case NormalState:
-			switch c {
-			case ESC:
+			class := normalClasses[c]
+			if p != nil && p.c1Text && c >= PAD && c <= APC {
+				class = controlClass
+			}
+
+			switch class {
+			case escapeClass:
 				if p != nil {
 					if len(p.params) > 0 {
 						p.params[0] = parser.MissingParam
@@ -140,7 +267,7 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 				}
 				state = EscapeState
 				continue
-			case CSI, DCS:
+			case introducerClass:
 				if p != nil {
 					if len(p.params) > 0 {
 						p.params[0] = parser.MissingParam
@@ -151,7 +278,7 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 				}
 				state = PrefixState
 				continue
-			case OSC, APC, SOS, PM:
+			case stringClass:
 				if p != nil {
 					p.cmd = parser.MissingCommand
 					p.dataLen = 0
@@ -165,77 +292,67 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 				p.paramsLen = 0
 				p.cmd = 0
 			}
-			if c > US && c < DEL {
-				// ASCII printable characters
-				return b[i : i+1], 1, 1, NormalState
-			}
 
-			if c <= US || c == DEL || c < 0xC0 {
-				// C0 & C1 control characters & DEL
+			if class == controlClass {
+				// C0 & C1 control characters & DEL, or C1 text
 				return b[i : i+1], 0, 1, NormalState
 			}
 
-			if utf8.RuneStart(c) {
-				seq, _, width, _ = FirstGraphemeCluster(b, -1)
-				if m == WcWidth {
-					width = runewidth.StringWidth(string(seq))
-				}
-				i += len(seq)
-				return b[:i], width, i, NormalState
+			// A UTF-8 sequence. This is always at the start of b since
+			// we return from the normal state on the first byte.
+			if p != nil && p.graphemes != nil {
+				return decodeCachedGrapheme(p.graphemes, m, b)
 			}
+			return decodeGrapheme(m, b)
+		case PrefixState, ParamsState, IntermedState:
+			class := csiClasses[c]
 
-			// Invalid UTF-8 sequence
-			return b[:i], 0, i, NormalState
-		case PrefixState:
-			if c >= '<' && c <= '?' {
-				if p != nil {
-					// We only collect the last prefix character.
-					p.cmd &^= 0xff << parser.PrefixShift
-					p.cmd |= int(c) << parser.PrefixShift
-				}
-				break
-			}
-
-			state = ParamsState
-			fallthrough
-		case ParamsState:
-			if c >= '0' && c <= '9' {
-				if p != nil {
-					if p.params[p.paramsLen] == parser.MissingParam {
-						p.params[p.paramsLen] = 0
+			if state == PrefixState {
+				if class == prefixClass {
+					if p != nil {
+						// We only collect the last prefix character.
+						p.cmd &^= 0xff << parser.PrefixShift
+						p.cmd |= int(c) << parser.PrefixShift
 					}
-
-					p.params[p.paramsLen] *= 10
-					p.params[p.paramsLen] += int(c - '0')
-				}
-				break
-			}
-
-			if c == ':' {
-				if p != nil {
-					p.params[p.paramsLen] |= parser.HasMoreFlag
+					continue
 				}
+				state = ParamsState
 			}
 
-			if c == ';' || c == ':' {
-				if p != nil {
-					p.paramsLen++
-					if p.paramsLen < len(p.params) {
-						p.params[p.paramsLen] = parser.MissingParam
+			if state == ParamsState {
+				switch class {
+				case digitClass:
+					// Parameters past the params buffer are ignored.
+					if p != nil && p.paramsLen < len(p.params) {
+						if p.params[p.paramsLen] == parser.MissingParam {
+							p.params[p.paramsLen] = 0
+						}
+
+						p.params[p.paramsLen] *= 10
+						p.params[p.paramsLen] += int(c - '0')
 					}
+					continue
+				case subParamClass, paramSepClass:
+					if p != nil && p.paramsLen < len(p.params) {
+						if class == subParamClass {
+							p.params[p.paramsLen] |= parser.HasMoreFlag
+						}
+						p.paramsLen++
+						if p.paramsLen < len(p.params) {
+							p.params[p.paramsLen] = parser.MissingParam
+						}
+					}
+					continue
 				}
-				break
+				state = IntermedState
 			}
 
-			state = IntermedState
-			fallthrough
-		case IntermedState:
-			if c >= ' ' && c <= '/' {
+			if class == intermedClass {
 				if p != nil {
 					p.cmd &^= 0xff << parser.IntermedShift
 					p.cmd |= int(c) << parser.IntermedShift
 				}
-				break
+				continue
 			}
 
 			if p != nil {
@@ -246,7 +363,7 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 				}
 			}
 
-			if c >= '@' && c <= '~' {
+			if class == finalClass {
 				if p != nil {
 					p.cmd &^= 0xff
 					p.cmd |= int(c)
@@ -267,8 +384,8 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 			// Invalid CSI/DCS sequence
 			return b[:i], 0, i, NormalState
 		case EscapeState:
-			switch c {
-			case '[', 'P':
+			switch escapeClasses[c] {
+			case introducerClass:
 				if p != nil {
 					if len(p.params) > 0 {
 						p.params[0] = parser.MissingParam
@@ -278,22 +395,20 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 				}
 				state = PrefixState
 				continue
-			case ']', 'X', '^', '_':
+			case stringClass:
 				if p != nil {
 					p.cmd = parser.MissingCommand
 					p.dataLen = 0
 				}
 				state = StringState
 				continue
-			}
-
-			if c >= ' ' && c <= '/' {
+			case intermedClass:
 				if p != nil {
 					p.cmd &^= 0xff << parser.IntermedShift
 					p.cmd |= int(c) << parser.IntermedShift
 				}
 				continue
-			} else if c >= '0' && c <= '~' {
+			case finalClass:
 				if p != nil {
 					p.cmd &^= 0xff
 					p.cmd |= int(c)
@@ -304,13 +419,13 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 			// Invalid escape sequence
 			return b[:i], 0, i, NormalState
 		case StringState:
-			switch c {
-			case BEL:
+			switch stringClasses[c] {
+			case belClass:
 				if HasOscPrefix(b) {
 					parseOscCmd(p)
 					return b[:i+1], 0, i + 1, NormalState
 				}
-			case CAN, SUB:
+			case cancelClass:
 				if HasOscPrefix(b) {
 					// Ensure we parse the OSC command number
 					parseOscCmd(p)
@@ -318,14 +433,18 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 
 				// Cancel the sequence
 				return b[:i], 0, i, NormalState
-			case ST:
+			case stClass:
+				if p != nil && p.c1Text {
+					// ST is data when C1 controls are disabled.
+					break
+				}
 				if HasOscPrefix(b) {
 					// Ensure we parse the OSC command number
 					parseOscCmd(p)
 				}
 
 				return b[:i+1], 0, i + 1, NormalState
-			case ESC:
+			case escapeClass:
 				if HasStPrefix(b[i:]) {
 					if HasOscPrefix(b) {
 						// Ensure we parse the OSC command number
@@ -355,6 +474,16 @@ func decodeSequence[T string | []byte](m Method, b T, state State, p *Parser) (s
 	return b, 0, len(b), state
 }
 
+// decodeGrapheme decodes the first grapheme cluster of b, which must start
+// with a UTF-8 sequence.
+func decodeGrapheme[T string | []byte](m Method, b T) (seq T, width int, n int, newState byte) {
+	seq, _, width, _ = FirstGraphemeCluster(b, -1)
+	if m == WcWidth {
+		width = runewidth.StringWidth(string(seq))
+	}
+	return seq, width, len(seq), NormalState
+}
+
 func parseOscCmd(p *Parser) {
 	if p == nil || p.cmd != parser.MissingCommand {
 		return
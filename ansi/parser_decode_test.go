@@ -1,6 +1,7 @@
 package ansi
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/x/ansi/parser"
@@ -355,6 +356,19 @@ func TestDecodeSequence(t *testing.T) {
 	}
 }
 
+func TestDecodeSequenceTooManyParams(t *testing.T) {
+	p := NewParser()
+	p.SetParamsSize(32)
+	seq := "\x1b[" + strings.Repeat("1;", 40) + "H"
+	_, _, n, state := DecodeSequence(seq, NormalState, p)
+	if n != len(seq) || state != NormalState || p.Command() != 'H' {
+		t.Fatalf("DecodeSequence() = %d, %d, %q, want %d, %d, %q", n, state, p.Command(), len(seq), NormalState, 'H')
+	}
+	if got := len(p.Params()); got != 32 {
+		t.Errorf("params = %d, want 32", got)
+	}
+}
+
 func FuzzDecodeSequence(f *testing.F) {
 	var b byte
 	for b < 0x80 {
@@ -403,6 +417,40 @@ func BenchmarkDecodeSequence(b *testing.B) {
 	}
 }
 
+func benchmarkDecodeSequence(b *testing.B, input string, p *Parser) {
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		in := input
+		var state byte
+		for len(in) > 0 {
+			_, _, n, newState := DecodeSequence(in, state, p)
+			state = newState
+			in = in[n:]
+		}
+	}
+}
+
+func BenchmarkDecodeSequenceASCII(b *testing.B) {
+	input := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	benchmarkDecodeSequence(b, input, nil)
+}
+
+func BenchmarkDecodeSequenceSGR(b *testing.B) {
+	input := strings.Repeat("\x1b[1;38;2;255;0;0mred\x1b[m \x1b[4:3mcurly\x1b[24m ", 20)
+	b.Run("nil parser", func(b *testing.B) {
+		benchmarkDecodeSequence(b, input, nil)
+	})
+	b.Run("parser", func(b *testing.B) {
+		benchmarkDecodeSequence(b, input, NewParser())
+	})
+}
+
+func BenchmarkDecodeSequenceUnicode(b *testing.B) {
+	input := strings.Repeat("héllo wörld 世界 👋🏽 ", 20)
+	benchmarkDecodeSequence(b, input, nil)
+}
+
 func BenchmarkDecodeParser(b *testing.B) {
 	p := NewParser()
 	p.SetParamsSize(32)
@@ -0,0 +1,101 @@
+package ansi
+
+// graphemeEntry is a cached grapheme cluster segmentation result.
+type graphemeEntry struct {
+	// cluster is the grapheme cluster.
+	cluster string
+
+	// next is the rune that followed the cluster when it was segmented.
+	next string
+
+	method Method
+	width  int
+}
+
+// graphemeCache is a small direct-mapped cache of grapheme cluster
+// segmentation results indexed by the first rune of the cluster.
+//
+// Whether a cluster ends at a given position only depends on the cluster
+// itself and the rune that follows it. An entry is therefore only used when
+// the input starts with the cached cluster followed by the same rune. This
+// includes ASCII runes, which extend clusters that end with a Prepend
+// character like U+0600.
+type graphemeCache struct {
+	entries []graphemeEntry
+	mask    uint32
+}
+
+// SetGraphemeCache enables caching grapheme cluster segmentation results in
+// [DecodeSequence] and [DecodeSequenceWc] when given this parser. The cache
+// holds up to size entries, rounded up to a power of two, and speeds up
+// decoding content where the same emoji and CJK clusters repeat. A size less
+// than or equal to 0 disables the cache, which is the default.
+func (p *Parser) SetGraphemeCache(size int) {
+	if size <= 0 {
+		p.graphemes = nil
+		return
+	}
+
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	p.graphemes = &graphemeCache{
+		entries: make([]graphemeEntry, n),
+		mask:    uint32(n - 1), //nolint:gosec
+	}
+}
+
+// graphemeSlot returns the cache entry for clusters starting with the given
+// rune.
+func graphemeSlot[T string | []byte](c *graphemeCache, r T) *graphemeEntry {
+	// FNV-1a
+	var h uint32 = 2166136261 //nolint:gomnd
+	for i := 0; i < len(r); i++ {
+		h ^= uint32(r[i])
+		h *= 16777619 //nolint:gomnd
+	}
+	return &c.entries[h&c.mask]
+}
+
+// runeLen returns the length of the UTF-8 sequence at the start of b, or 0 if
+// it's invalid or incomplete.
+func runeLen[T string | []byte](b T) int {
+	if len(b) == 0 {
+		return 0
+	}
+	rw := utf8ByteLen(b[0])
+	if rw <= 0 || rw > len(b) {
+		return 0
+	}
+	return rw
+}
+
+// decodeCachedGrapheme is like decodeGrapheme but uses the cache to avoid
+// segmenting repeated clusters.
+func decodeCachedGrapheme[T string | []byte](c *graphemeCache, m Method, b T) (seq T, width int, n int, newState byte) {
+	rw := runeLen(b)
+	if rw == 0 {
+		return decodeGrapheme(m, b)
+	}
+
+	e := graphemeSlot(c, b[:rw])
+	if l := len(e.cluster); l > 0 && e.method == m && l <= len(b) && string(b[:l]) == e.cluster {
+		if rest := b[l:]; len(rest) >= len(e.next) && string(rest[:len(e.next)]) == e.next {
+			return b[:l], e.width, l, NormalState
+		}
+	}
+
+	seq, width, n, newState = decodeGrapheme(m, b)
+
+	// We can only cache the result if we know what follows the cluster.
+	rest := b[n:]
+	nw := runeLen(rest)
+	if nw == 0 {
+		return
+	}
+
+	*e = graphemeEntry{cluster: string(seq), next: string(rest[:nw]), method: m, width: width}
+
+	return
+}
@@ -0,0 +1,105 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+)
+
+type decoded struct {
+	seq   string
+	width int
+}
+
+func decodeAll(m Method, s string, p *Parser) (out []decoded) {
+	var state byte
+	for len(s) > 0 {
+		var seq string
+		var width, n int
+		if m == WcWidth {
+			seq, width, n, state = DecodeSequenceWc(s, state, p)
+		} else {
+			seq, width, n, state = DecodeSequence(s, state, p)
+		}
+		out = append(out, decoded{seq, width})
+		s = s[n:]
+	}
+	return
+}
+
+func TestGraphemeCache(t *testing.T) {
+	inputs := []string{
+		"héllo wörld 世界",
+		"👋a👋🏽👋 👋",
+		"😀😀😀😀",
+		"🇯🇵🇯🇵🇯",
+		"👨‍👩‍👧👨‍👩‍👧 👨‍👩",
+		"é́x é",
+		"\xe4ab\xe4\xe4",
+		"世界\x1b[1m世界\x1b[m世",
+		"👋",
+		// Prepend characters are extended by the ASCII that follows.
+		"\u0600\x1b[m",
+		"\u0600b",
+		"\u0600\x1b[m\u0600b",
+	}
+
+	for _, m := range []Method{WcWidth, GraphemeWidth} {
+		for _, in := range inputs {
+			want := decodeAll(m, in, nil)
+
+			p := NewParser()
+			p.SetGraphemeCache(4)
+			for i := 0; i < 3; i++ {
+				// Decode the input a few times to hit the cache, and
+				// decode all inputs in between to exercise collisions.
+				got := decodeAll(m, in, p)
+				assertEqual(t, want, got)
+				for _, other := range inputs {
+					decodeAll(m, other, p)
+				}
+			}
+		}
+	}
+}
+
+func TestGraphemeCacheByteSlice(t *testing.T) {
+	p := NewParser()
+	p.SetGraphemeCache(16)
+	in := []byte("😀😀👋🏽 👋a")
+	for i := 0; i < 2; i++ {
+		var state byte
+		var got []string
+		b := in
+		for len(b) > 0 {
+			seq, _, n, newState := DecodeSequence(b, state, p)
+			state = newState
+			got = append(got, string(seq))
+			b = b[n:]
+		}
+		assertEqual(t, []string{"😀", "😀", "👋🏽", " ", "👋", "a"}, got)
+	}
+}
+
+func BenchmarkDecodeSequenceEmoji(b *testing.B) {
+	input := strings.Repeat("😀😀👋🏽 🇯🇵 世界 👨‍👩‍👧 ❤️ ", 20)
+	b.Run("no cache", func(b *testing.B) {
+		benchmarkDecodeSequence(b, input, NewParser())
+	})
+	b.Run("cache", func(b *testing.B) {
+		p := NewParser()
+		p.SetGraphemeCache(256)
+		benchmarkDecodeSequence(b, input, p)
+	})
+}
+
+func BenchmarkDecodeSequenceCJK(b *testing.B) {
+	input := strings.Repeat("日本語のテキストと中文文本 ", 20)
+	b.Run("no cache", func(b *testing.B) {
+		benchmarkDecodeSequence(b, input, NewParser())
+	})
+	b.Run("cache", func(b *testing.B) {
+		p := NewParser()
+		p.SetGraphemeCache(256)
+		benchmarkDecodeSequence(b, input, p)
+	})
+}
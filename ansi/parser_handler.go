@@ -26,6 +26,39 @@ func (p Params) ForEach(def int, f func(i, param int, hasMore bool)) {
 	}
 }
 
+// Group returns the parameter at the given index along with its
+// sub-parameters, the parameters that follow it separated by colons (:), for
+// example "4:3" in "1;4:3". If the index is out of bounds, it returns nil.
+// The length of the group is the offset to the next parameter.
+//
+//	for i := 0; i < len(params); {
+//		g := params.Group(i)
+//		i += len(g)
+//	}
+func (p Params) Group(i int) Params {
+	if i < 0 || i >= len(p) {
+		return nil
+	}
+	j := i
+	for j < len(p)-1 && p[j].HasMore() {
+		j++
+	}
+	return p[i : j+1]
+}
+
+// ForEachGroup iterates over the parameters and calls the given function for
+// each parameter with its sub-parameters, see [Params.Group]. The index i is
+// the index of the parameter and sub is empty when it has no
+// sub-parameters.
+// Use def to set a default value for missing parameters.
+func (p Params) ForEachGroup(def int, f func(i, param int, sub Params)) {
+	for i := 0; i < len(p); {
+		g := p.Group(i)
+		f(i, g[0].Param(def), g[1:])
+		i += len(g)
+	}
+}
+
 // ToParams converts a list of integers to a list of parameters.
 func ToParams(params []int) Params {
 	return unsafe.Slice((*Param)(unsafe.Pointer(&params[0])), len(params))
@@ -44,6 +77,30 @@ type Handler struct {
 	HandleEsc func(cmd Cmd)
 	// HandleDcs is called when a DCS sequence is encountered.
 	HandleDcs func(cmd Cmd, params Params, data []byte)
+	// HandleSixel is called with chunks of SIXEL graphics (DCS q) data as they
+	// arrive instead of buffering the whole sequence. The params are the DCS
+	// parameters P1;P2;P3 and done reports whether this is the last chunk of
+	// the sequence, which might be empty. When set, [Handler.HandleDcs] is not
+	// called for SIXEL sequences. The data slice is only valid until the
+	// function returns.
+	//
+	// Sequences canceled by CAN or SUB don't get a final call with done set.
+	HandleSixel func(params Params, data []byte, done bool)
+	// HandleDcsHook is called when a DCS sequence starts, once its command
+	// and parameters are known, and reports whether to hook the sequence.
+	// The data of a hooked sequence is passed in chunks to
+	// [Handler.HandleDcsPut] as it arrives and [Handler.HandleDcsUnhook] is
+	// called at its end, instead of buffering the whole sequence for
+	// [Handler.HandleDcs]. SIXEL sequences streamed to [Handler.HandleSixel]
+	// are not hooked.
+	HandleDcsHook func(cmd Cmd, params Params) bool
+	// HandleDcsPut is called with chunks of the data of a hooked DCS
+	// sequence. The data slice is only valid until the function returns.
+	HandleDcsPut func(data []byte)
+	// HandleDcsUnhook is called at the end of a hooked DCS sequence. The
+	// canceled argument reports whether the sequence was canceled by CAN or
+	// SUB instead of terminated by ST.
+	HandleDcsUnhook func(canceled bool)
 	// HandleOsc is called when an OSC sequence is encountered.
 	HandleOsc func(cmd int, data []byte)
 	// HandlePm is called when a PM sequence is encountered.
@@ -0,0 +1,46 @@
+package ansi
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+func TestParamsGroup(t *testing.T) {
+	// 1;4:3;38:2::1:2:3;;5
+	params := Params{
+		1,
+		4 | parser.HasMoreFlag, 3,
+		38 | parser.HasMoreFlag, 2 | parser.HasMoreFlag, parser.MissingParam | parser.HasMoreFlag,
+		1 | parser.HasMoreFlag, 2 | parser.HasMoreFlag, 3,
+		parser.MissingParam,
+		5,
+	}
+
+	type group struct {
+		I     int
+		Param int
+		Sub   []int
+	}
+	var got []group
+	params.ForEachGroup(0, func(i, param int, sub Params) {
+		g := group{I: i, Param: param}
+		sub.ForEach(-1, func(_, p int, _ bool) { g.Sub = append(g.Sub, p) })
+		got = append(got, g)
+	})
+	assertEqual(t, []group{
+		{0, 1, nil},
+		{1, 4, []int{3}},
+		{3, 38, []int{2, -1, 1, 2, 3}},
+		{9, 0, nil},
+		{10, 5, nil},
+	}, got)
+
+	if g := params.Group(len(params)); g != nil {
+		t.Errorf("Group(%d) = %v, want nil", len(params), g)
+	}
+	// A trailing sub-parameter flag doesn't go out of bounds.
+	if g := (Params{4 | parser.HasMoreFlag}).Group(0); len(g) != 1 {
+		t.Errorf("Group(0) = %v, want a single parameter", g)
+	}
+}
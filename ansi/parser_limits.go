@@ -0,0 +1,36 @@
+package ansi
+
+// Limits configures the maximum size of sequences and the number of
+// consecutive sequences a parser accepts. These protect hosts from floods of
+// malicious or broken terminal output and input. A zero value means no limit.
+//
+// A sequence that exceeds a size limit is consumed until its end and then
+// discarded as a whole, it is never dispatched truncated. Sequences exceeding
+// the consecutive sequence limit are discarded until printable text is seen.
+type Limits struct {
+	// MaxParams is the maximum number of CSI and DCS parameters. Note that
+	// the number of parameters is also bounded by the parameters buffer size,
+	// see [Parser.SetParamsSize].
+	MaxParams int
+
+	// MaxDataLen is the maximum length in bytes of the data of OSC, DCS, SOS,
+	// PM, and APC sequences. SIXEL data streamed to [Handler.HandleSixel] is
+	// not buffered and is not subject to this limit.
+	MaxDataLen int
+
+	// MaxSequences is the maximum number of consecutive escape, control, and
+	// string sequences without any printable text in between. Control
+	// characters such as LF don't count as sequences.
+	MaxSequences int
+}
+
+// SetLimits sets the limits of the parser. See [Limits] for more
+// information.
+func (p *Parser) SetLimits(l Limits) {
+	p.limits = l
+}
+
+// Limits returns the limits of the parser.
+func (p *Parser) Limits() Limits {
+	return p.limits
+}
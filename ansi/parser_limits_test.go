@@ -0,0 +1,103 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParserLimits(t *testing.T) {
+	cases := []struct {
+		name     string
+		limits   Limits
+		input    string
+		expected []any
+	}{
+		{
+			name:   "params_within_limit",
+			limits: Limits{MaxParams: 2},
+			input:  "\x1b[1;2H",
+			expected: []any{
+				csiSequence{Cmd: 'H', Params: Params{1, 2}},
+			},
+		},
+		{
+			name:   "params_overflow",
+			limits: Limits{MaxParams: 2},
+			input:  "\x1b[1;2;3Hx\x1b[4m",
+			expected: []any{
+				'x',
+				csiSequence{Cmd: 'm', Params: Params{4}},
+			},
+		},
+		{
+			name:   "dcs_params_overflow",
+			limits: Limits{MaxParams: 1},
+			input:  "\x1bP1;2|data\x1b\\",
+			expected: []any{
+				Cmd('\\'),
+			},
+		},
+		{
+			name:   "data_within_limit",
+			limits: Limits{MaxDataLen: 6},
+			input:  "\x1b]2;abcd\x07",
+			expected: []any{
+				[]byte("2;abcd"),
+			},
+		},
+		{
+			name:   "data_overflow",
+			limits: Limits{MaxDataLen: 6},
+			input:  "\x1b]2;abcde\x07\x1b_Gok\x1b\\",
+			expected: []any{
+				[]byte("Gok"),
+				Cmd('\\'),
+			},
+		},
+		{
+			name:   "consecutive_sequences",
+			limits: Limits{MaxSequences: 2},
+			input:  "\x1b[1m\x1b[2m\n\x1b[3m\x1b]0;t\x1b\\a\x1b[4m",
+			expected: []any{
+				csiSequence{Cmd: 'm', Params: Params{1}},
+				csiSequence{Cmd: 'm', Params: Params{2}},
+				byte('\n'),
+				Cmd('\\'),
+				'a',
+				csiSequence{Cmd: 'm', Params: Params{4}},
+			},
+		},
+		{
+			name:   "string_terminator_not_counted",
+			limits: Limits{MaxSequences: 2},
+			input:  "\x1b]0;a\x1b\\\x1b]0;b\x1b\\",
+			expected: []any{
+				[]byte("0;a"),
+				Cmd('\\'),
+				[]byte("0;b"),
+				Cmd('\\'),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dispatcher := &testDispatcher{}
+			parser := testParser(dispatcher)
+			parser.SetLimits(c.limits)
+			parser.Parse([]byte(c.input))
+			assertEqual(t, c.expected, dispatcher.dispatched)
+		})
+	}
+}
+
+func TestParserLimitsUnlimitedData(t *testing.T) {
+	dispatcher := &testDispatcher{}
+	parser := testParser(dispatcher)
+	parser.SetLimits(Limits{MaxDataLen: 1024})
+	parser.Parse([]byte("\x1b]2;" + strings.Repeat("x", 1<<20) + "\x07"))
+	assertEqual(t, 0, len(dispatcher.dispatched))
+	if len(parser.data) > 1024 {
+		t.Errorf("data buffer grew to %d bytes, want at most 1024", len(parser.data))
+	}
+}
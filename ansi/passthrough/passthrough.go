@@ -0,0 +1,102 @@
+// Package passthrough wraps escape sequences that terminal multiplexers don't
+// understand in the multiplexer passthrough framing, so that they reach the
+// outer terminal. This is needed for graphics, clipboard, and notification
+// sequences when running inside tmux or GNU Screen.
+package passthrough
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Multiplexer is a terminal multiplexer.
+type Multiplexer uint8
+
+// Terminal multiplexers.
+const (
+	// None means no terminal multiplexer. Sequences are written as is.
+	None Multiplexer = iota
+	// Tmux is tmux. Sequences are wrapped with [ansi.TmuxPassthrough]. This
+	// requires the tmux allow-passthrough option to be on.
+	//
+	// [ansi.TmuxPassthrough]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#TmuxPassthrough
+	Tmux
+	// Screen is GNU Screen. Sequences are wrapped with
+	// [ansi.ScreenPassthrough] using the [ScreenLimit] chunk size.
+	//
+	// [ansi.ScreenPassthrough]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#ScreenPassthrough
+	Screen
+	// Zellij is Zellij. Zellij doesn't support passthrough and handles
+	// supported sequences itself, so sequences are written as is.
+	Zellij
+)
+
+// String returns the name of the multiplexer.
+func (m Multiplexer) String() string {
+	switch m {
+	case None:
+		return "none"
+	case Tmux:
+		return "tmux"
+	case Screen:
+		return "screen"
+	case Zellij:
+		return "zellij"
+	}
+	return "Multiplexer(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Detect returns the terminal multiplexer from the given environment
+// variables in the form "key=value", such as [os.Environ]. It uses TMUX,
+// ZELLIJ, STY, and TERM, in that order of precedence.
+func Detect(environ []string) Multiplexer {
+	var tmux, zellij, screen bool
+	var term string
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "TMUX":
+			tmux = v != ""
+		case "ZELLIJ":
+			zellij = true
+		case "STY":
+			screen = v != ""
+		case "TERM":
+			term = v
+		}
+	}
+
+	switch {
+	case tmux:
+		return Tmux
+	case zellij:
+		return Zellij
+	case screen:
+		return Screen
+	case strings.HasPrefix(term, "tmux"):
+		return Tmux
+	case strings.HasPrefix(term, "screen"):
+		return Screen
+	}
+	return None
+}
+
+// FromSecondaryDeviceAttributes returns the terminal multiplexer from a
+// secondary device attributes (DA2) report. tmux reports itself as terminal
+// type 84 ('T') and GNU Screen as terminal type 83 ('S'). This is useful when
+// the environment isn't available, for example over SSH.
+func FromSecondaryDeviceAttributes(attrs ...int) Multiplexer {
+	if len(attrs) == 0 {
+		return None
+	}
+	switch attrs[0] {
+	case 'T':
+		return Tmux
+	case 'S':
+		return Screen
+	}
+	return None
+}
@@ -0,0 +1,156 @@
+package passthrough
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    Multiplexer
+	}{
+		{"none", []string{"TERM=xterm-256color"}, None},
+		{"tmux", []string{"TERM=screen-256color", "TMUX=/tmp/tmux-1000/default,1,0"}, Tmux},
+		{"tmux term", []string{"TERM=tmux-256color"}, Tmux},
+		{"screen", []string{"TERM=screen", "STY=1234.pts-0.host"}, Screen},
+		{"screen term", []string{"TERM=screen.xterm-256color"}, Screen},
+		{"zellij", []string{"TERM=xterm-256color", "ZELLIJ=0"}, Zellij},
+		{"empty tmux", []string{"TMUX="}, None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.environ); got != tt.want {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSecondaryDeviceAttributes(t *testing.T) {
+	if got := FromSecondaryDeviceAttributes(84, 0, 0); got != Tmux {
+		t.Errorf("FromSecondaryDeviceAttributes(84) = %v, want %v", got, Tmux)
+	}
+	if got := FromSecondaryDeviceAttributes(83, 40800, 0); got != Screen {
+		t.Errorf("FromSecondaryDeviceAttributes(83) = %v, want %v", got, Screen)
+	}
+	if got := FromSecondaryDeviceAttributes(1, 10, 0); got != None {
+		t.Errorf("FromSecondaryDeviceAttributes(1) = %v, want %v", got, None)
+	}
+	if got := FromSecondaryDeviceAttributes(); got != None {
+		t.Errorf("FromSecondaryDeviceAttributes() = %v, want %v", got, None)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	clipboard := ansi.SetSystemClipboard("hello")
+	kitty := "\x1b_Ga=T,f=100;AAAA\x1b\\"
+	sixel := "\x1bP0;1q#0;2;0;0;0~-\x1b\\"
+	notify := "\x1b]9;done\x07"
+	title := ansi.SetWindowTitle("title")
+	style := "\x1b[31mred\x1b[m"
+
+	tests := []struct {
+		name string
+		m    Multiplexer
+		in   string
+		want string
+	}{
+		{"none", None, "text" + clipboard, "text" + clipboard},
+		{"zellij", Zellij, kitty + sixel, kitty + sixel},
+		{"text", Tmux, "plain " + style + "\r\n", "plain " + style + "\r\n"},
+		{"title", Tmux, title, title},
+		{"clipboard", Tmux, "a" + clipboard + "b", "a" + ansi.TmuxPassthrough(clipboard) + "b"},
+		{"kitty", Tmux, kitty, ansi.TmuxPassthrough(kitty)},
+		{"sixel", Tmux, sixel, ansi.TmuxPassthrough(sixel)},
+		{"notification", Tmux, notify, ansi.TmuxPassthrough(notify)},
+		{"wrapped", Tmux, ansi.TmuxPassthrough(kitty), ansi.TmuxPassthrough(kitty)},
+		{"screen", Screen, style + clipboard, style + ansi.ScreenPassthrough(clipboard, ScreenLimit)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf, tt.m)
+			if _, err := w.Write([]byte(tt.in)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterSplitWrites(t *testing.T) {
+	in := "a\x1b[1mb" + ansi.SetSystemClipboard("hello") + "c"
+	want := "a\x1b[1mb" + ansi.TmuxPassthrough(ansi.SetSystemClipboard("hello")) + "c"
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Tmux)
+	for i := 0; i < len(in); i++ {
+		if _, err := w.Write([]byte{in[i]}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Write() = %q, want %q", got, want)
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Tmux)
+	if _, err := w.Write([]byte("a\x1b]52;c;")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := buf.String(); got != "a" {
+		t.Errorf("Write() = %q, want %q", got, "a")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := buf.String(); got != "a\x1b]52;c;" {
+		t.Errorf("Flush() = %q, want %q", got, "a\x1b]52;c;")
+	}
+}
+
+func TestWriterLongSequence(t *testing.T) {
+	// The payload has an ESC to check that it's escaped across chunks.
+	seq := "\x1b_Ga=T;" + strings.Repeat("A", 5000) + "\x1b" + strings.Repeat("B", 5000) + "\x1b\\"
+	cases := []struct {
+		name string
+		m    Multiplexer
+		seq  string
+		want string
+	}{
+		{"tmux", Tmux, seq, ansi.TmuxPassthrough(seq)},
+		{"screen", Screen, seq, ansi.ScreenPassthrough(seq, ScreenLimit)},
+		{"not wrapped", Tmux, "\x1b]2;" + strings.Repeat("t", 5000) + "\a", "\x1b]2;" + strings.Repeat("t", 5000) + "\a"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf, tt.m)
+			in := "a" + tt.seq + "b"
+			for i := 0; i < len(in); i += 1000 {
+				end := i + 1000
+				if end > len(in) {
+					end = len(in)
+				}
+				if _, err := w.Write([]byte(in[i:end])); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				if len(w.buf) > maxPending {
+					t.Fatalf("Write() buffered %d bytes, want at most %d", len(w.buf), maxPending)
+				}
+			}
+			if got, want := buf.String(), "a"+tt.want+"b"; got != want {
+				t.Errorf("Write() = %q, want %q", got, want)
+			}
+		})
+	}
+}
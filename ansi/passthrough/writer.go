@@ -0,0 +1,287 @@
+package passthrough
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ScreenLimit is the maximum length of a string sequence in GNU Screen. Longer
+// sequences are split into multiple passthrough sequences.
+const ScreenLimit = 768
+
+// maxPending is the maximum number of bytes of a string sequence a [Writer]
+// buffers.
+const maxPending = 4096
+
+// Writer is an [io.Writer] that wraps graphics, clipboard, and notification
+// sequences in the passthrough framing of a terminal multiplexer. Text and
+// other sequences are written untouched.
+//
+// The wrapped sequences are the Kitty graphics protocol (APC G), Sixel
+// graphics (DCS q), clipboard (OSC 52), iTerm2 images (OSC 1337), and
+// notifications (OSC 9, OSC 99, and OSC 777). Sequences that are already
+// wrapped in a tmux passthrough are left as is.
+//
+// String sequences are buffered until they're terminated, so they can be
+// split across writes. Sequences longer than 4096 bytes, such as large
+// images, are written as they arrive instead. Use [Writer.Flush] to write any
+// pending data.
+type Writer struct {
+	w     io.Writer
+	m     Multiplexer
+	buf   []byte
+	state byte
+	intro byte
+
+	// stream is set when the pending string sequence grew beyond maxPending
+	// and is written as it arrives. wrap is whether it's wrapped in a
+	// passthrough, and chunk is the length of the current GNU Screen
+	// passthrough chunk.
+	stream bool
+	wrap   bool
+	chunk  int
+}
+
+// Writer states.
+const (
+	normalState byte = iota
+	escapeState
+	stringState
+	stringEscapeState
+)
+
+// NewWriter returns a new [Writer] that writes to w for the given terminal
+// multiplexer. Use [Detect] to find the multiplexer.
+func NewWriter(w io.Writer, m Multiplexer) *Writer {
+	return &Writer{w: w, m: m}
+}
+
+// Multiplexer returns the terminal multiplexer of the writer.
+func (w *Writer) Multiplexer() Multiplexer {
+	return w.m
+}
+
+// Write implements [io.Writer].
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.m != Tmux && w.m != Screen {
+		return w.w.Write(p) //nolint:wrapcheck
+	}
+
+	start := 0
+	for i := 0; i < len(p); i++ {
+		b := p[i]
+		switch w.state {
+		case normalState:
+			if b == ansi.ESC {
+				if err := w.write(p[start:i]); err != nil {
+					return 0, err
+				}
+				w.buf = append(w.buf[:0], b)
+				w.state = escapeState
+			}
+
+		case escapeState:
+			switch b {
+			case ']', 'P', '_':
+				w.buf = append(w.buf, b)
+				w.intro = b
+				w.state = stringState
+			default:
+				// Not a string sequence, write the escape and handle the
+				// current byte as text.
+				if err := w.write(w.buf); err != nil {
+					return 0, err
+				}
+				w.buf = w.buf[:0]
+				w.state = normalState
+				start = i
+				i--
+			}
+
+		case stringState:
+			w.buf = append(w.buf, b)
+			switch {
+			case b == ansi.ESC:
+				w.state = stringEscapeState
+			case b == ansi.BEL && w.intro == ']':
+				if err := w.flushSequence(); err != nil {
+					return 0, err
+				}
+				start = i + 1
+			case len(w.buf) >= maxPending:
+				if err := w.streamPending(); err != nil {
+					return 0, err
+				}
+			}
+
+		case stringEscapeState:
+			w.buf = append(w.buf, b)
+			switch b {
+			case '\\':
+				if err := w.flushSequence(); err != nil {
+					return 0, err
+				}
+				start = i + 1
+			default:
+				// Escaped ESC in a tmux passthrough or a stray ESC.
+				w.state = stringState
+				if len(w.buf) >= maxPending {
+					if err := w.streamPending(); err != nil {
+						return 0, err
+					}
+				}
+			}
+		}
+	}
+
+	if w.state == normalState {
+		if err := w.write(p[start:]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any pending data, such as an unterminated sequence, as is.
+func (w *Writer) Flush() error {
+	if w.stream {
+		return w.flushSequence()
+	}
+	err := w.write(w.buf)
+	w.buf = w.buf[:0]
+	w.state = normalState
+	return err
+}
+
+// flushSequence writes the buffered string sequence, wrapping it if needed.
+func (w *Writer) flushSequence() error {
+	seq := w.buf
+	w.buf = w.buf[:0]
+	w.state = normalState
+
+	if w.stream {
+		w.stream = false
+		if err := w.writeData(seq); err != nil {
+			return err
+		}
+		if w.wrap {
+			return w.write([]byte("\x1b\\"))
+		}
+		return nil
+	}
+
+	if !shouldWrap(seq) {
+		return w.write(seq)
+	}
+
+	switch w.m {
+	case Tmux:
+		_, err := io.WriteString(w.w, ansi.TmuxPassthrough(string(seq)))
+		return err //nolint:wrapcheck
+	case Screen:
+		_, err := io.WriteString(w.w, ansi.ScreenPassthrough(string(seq), ScreenLimit))
+		return err //nolint:wrapcheck
+	}
+	return w.write(seq)
+}
+
+// streamPending writes the buffered part of a long string sequence, starting
+// its passthrough if needed, so that the rest of it is written as it arrives.
+func (w *Writer) streamPending() error {
+	if !w.stream {
+		w.stream = true
+		w.wrap = shouldWrap(w.buf)
+		w.chunk = 0
+		if w.wrap {
+			intro := "\x1bP"
+			if w.m == Tmux {
+				intro = "\x1bPtmux;"
+			}
+			if _, err := io.WriteString(w.w, intro); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+	}
+	err := w.writeData(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// writeData writes part of a streamed string sequence, escaping it for the
+// passthrough like [ansi.TmuxPassthrough] and [ansi.ScreenPassthrough] do.
+func (w *Writer) writeData(p []byte) error {
+	if !w.wrap {
+		return w.write(p)
+	}
+
+	var b bytes.Buffer
+	for _, c := range p {
+		switch w.m {
+		case Tmux:
+			if c == ansi.ESC {
+				b.WriteByte(ansi.ESC)
+			}
+		case Screen:
+			if w.chunk == ScreenLimit {
+				b.WriteString("\x1b\\\x1bP")
+				w.chunk = 0
+			}
+			w.chunk++
+		}
+		b.WriteByte(c)
+	}
+	return w.write(b.Bytes())
+}
+
+// write writes p to the underlying writer.
+func (w *Writer) write(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(p)
+	return err //nolint:wrapcheck
+}
+
+// wrappedOSC are the OSC commands that need a passthrough.
+var wrappedOSC = map[string]bool{
+	"9":    true, // notifications
+	"52":   true, // clipboard
+	"99":   true, // kitty notifications
+	"777":  true, // notifications
+	"1337": true, // iTerm2 images
+}
+
+// shouldWrap returns whether the given complete string sequence needs a
+// passthrough.
+func shouldWrap(seq []byte) bool {
+	if len(seq) < 3 { //nolint:gomnd
+		return false
+	}
+
+	data := seq[2:]
+	switch seq[1] {
+	case '_':
+		// Kitty graphics protocol.
+		return data[0] == 'G'
+	case 'P':
+		// Sixel graphics have numeric parameters followed by 'q'.
+		for _, c := range data {
+			switch {
+			case c == 'q':
+				return true
+			case c != ';' && (c < '0' || c > '9'):
+				return false
+			}
+		}
+	case ']':
+		cmd := data
+		if i := bytes.IndexAny(data, ";\x07\x1b"); i >= 0 {
+			cmd = data[:i]
+		}
+		return wrappedOSC[string(cmd)]
+	}
+	return false
+}
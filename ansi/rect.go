@@ -0,0 +1,136 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Rectangle is a rectangular area of the screen used by the rectangular area
+// operations, such as [CopyRectangularArea] and [FillRectangularArea]. The
+// coordinates are 1-based and inclusive, and a zero value uses the default:
+// the first line or column for Top and Left, and the last line or column for
+// Bottom and Right.
+//
+// The coordinates are relative to the page, or to the margins when
+// [OriginMode] is set.
+type Rectangle struct {
+	Top, Left, Bottom, Right int
+}
+
+// params returns the parameters of the rectangle.
+func (r Rectangle) params() []string {
+	return []string{
+		rectParam(r.Top, 1),
+		rectParam(r.Left, 1),
+		rectParam(r.Bottom, 0),
+		rectParam(r.Right, 0),
+	}
+}
+
+// rectParam returns a rectangle parameter, or an empty string when it's the
+// default value.
+func rectParam(n, def int) string {
+	if n <= def {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// rectSeq returns a rectangular area operation sequence. Trailing default
+// parameters are omitted.
+func rectSeq(params []string, final string) string {
+	return "\x1b[" + strings.TrimRight(strings.Join(params, ";"), ";") + final
+}
+
+// CopyRectangularArea (DECCRA) copies the characters and attributes of the
+// source rectangle on the source page to the destination position, the top
+// left corner of the copy, on the destination page. Pages are 1-based and 0
+// uses the default, the first page.
+//
+//	CSI Pts ; Pls ; Pbs ; Prs ; Pps ; Ptd ; Pld ; Ppd $ v
+//
+// See: https://vt100.net/docs/vt510-rm/DECCRA.html
+func CopyRectangularArea(src Rectangle, srcPage, dstTop, dstLeft, dstPage int) string {
+	params := append(src.params(),
+		rectParam(srcPage, 1),
+		rectParam(dstTop, 1),
+		rectParam(dstLeft, 1),
+		rectParam(dstPage, 1),
+	)
+	return rectSeq(params, "$v")
+}
+
+// DECCRA is an alias for [CopyRectangularArea].
+func DECCRA(src Rectangle, srcPage, dstTop, dstLeft, dstPage int) string {
+	return CopyRectangularArea(src, srcPage, dstTop, dstLeft, dstPage)
+}
+
+// FillRectangularArea (DECFRA) fills the rectangle with the given character
+// using the current graphic rendition.
+//
+//	CSI Pch ; Pt ; Pl ; Pb ; Pr $ x
+//
+// Where Pch is the decimal code of the character.
+//
+// See: https://vt100.net/docs/vt510-rm/DECFRA.html
+func FillRectangularArea(c rune, r Rectangle) string {
+	params := append([]string{strconv.Itoa(int(c))}, r.params()...)
+	return rectSeq(params, "$x")
+}
+
+// DECFRA is an alias for [FillRectangularArea].
+func DECFRA(c rune, r Rectangle) string {
+	return FillRectangularArea(c, r)
+}
+
+// EraseRectangularArea (DECERA) erases the characters and attributes of the
+// rectangle.
+//
+//	CSI Pt ; Pl ; Pb ; Pr $ z
+//
+// See: https://vt100.net/docs/vt510-rm/DECERA.html
+func EraseRectangularArea(r Rectangle) string {
+	return rectSeq(r.params(), "$z")
+}
+
+// DECERA is an alias for [EraseRectangularArea].
+func DECERA(r Rectangle) string {
+	return EraseRectangularArea(r)
+}
+
+// SelectiveEraseRectangularArea (DECSERA) erases the characters of the
+// rectangle that are not protected by [SelectCharacterProtection] (DECSCA).
+//
+//	CSI Pt ; Pl ; Pb ; Pr $ {
+//
+// See: https://vt100.net/docs/vt510-rm/DECSERA.html
+func SelectiveEraseRectangularArea(r Rectangle) string {
+	return rectSeq(r.params(), "${")
+}
+
+// DECSERA is an alias for [SelectiveEraseRectangularArea].
+func DECSERA(r Rectangle) string {
+	return SelectiveEraseRectangularArea(r)
+}
+
+// ReverseAttributesInRectangularArea (DECRARA) reverses the given graphic
+// rendition attributes of the characters in the rectangle.
+//
+//	CSI Pt ; Pl ; Pb ; Pr ; Ps... $ t
+//
+// Where Ps are the attributes to reverse: 0 for all attributes (the default),
+// 1 for bold, 4 for underline, 5 for blink, and 7 for negative image.
+//
+// See: https://vt100.net/docs/vt510-rm/DECRARA.html
+func ReverseAttributesInRectangularArea(r Rectangle, attrs ...int) string {
+	params := r.params()
+	for _, a := range attrs {
+		params = append(params, strconv.Itoa(a))
+	}
+	return rectSeq(params, "$t")
+}
+
+// DECRARA is an alias for [ReverseAttributesInRectangularArea].
+func DECRARA(r Rectangle, attrs ...int) string {
+	return ReverseAttributesInRectangularArea(r, attrs...)
+}
@@ -0,0 +1,29 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRectangularAreaOperations(t *testing.T) {
+	r := ansi.Rectangle{Top: 2, Left: 3, Bottom: 10, Right: 20}
+	cases := []struct {
+		got, want string
+	}{
+		{ansi.CopyRectangularArea(r, 0, 5, 6, 0), "\x1b[2;3;10;20;;5;6$v"},
+		{ansi.CopyRectangularArea(r, 1, 5, 6, 2), "\x1b[2;3;10;20;;5;6;2$v"},
+		{ansi.FillRectangularArea('X', r), "\x1b[88;2;3;10;20$x"},
+		{ansi.FillRectangularArea('X', ansi.Rectangle{}), "\x1b[88$x"},
+		{ansi.EraseRectangularArea(r), "\x1b[2;3;10;20$z"},
+		{ansi.EraseRectangularArea(ansi.Rectangle{Bottom: 5}), "\x1b[;;5$z"},
+		{ansi.SelectiveEraseRectangularArea(ansi.Rectangle{}), "\x1b[${"},
+		{ansi.ReverseAttributesInRectangularArea(r), "\x1b[2;3;10;20$t"},
+		{ansi.ReverseAttributesInRectangularArea(ansi.Rectangle{}, 1, 7), "\x1b[;;;;1;7$t"},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("expected: %q, got: %q", tc.want, tc.got)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package ansi
 
-// ResetInitialState (RIS) resets the terminal to its initial state.
+// ResetInitialState (RIS) resets the terminal to its initial state. This is a
+// hard reset: it clears the screen and the scrollback, and resets the modes,
+// character sets, tab stops, margins, colors, and cursor to their power-up
+// defaults. Some terminals also discard their input buffer.
 //
 //	ESC c
 //
@@ -9,3 +12,18 @@ const (
 	ResetInitialState = "\x1bc"
 	RIS               = ResetInitialState
 )
+
+// SoftTerminalReset (DECSTR) resets the terminal modes and settings to their
+// defaults without clearing the screen. It shows the cursor, resets the
+// insert, origin, auto wrap, and keyboard modes, the character sets, the
+// margins, the graphic rendition, the character protection, and the saved
+// cursor state. Unlike [ResetInitialState], the screen content and the
+// cursor position are kept.
+//
+//	CSI ! p
+//
+// See: https://vt100.net/docs/vt510-rm/DECSTR.html
+const (
+	SoftTerminalReset = "\x1b[!p"
+	DECSTR            = SoftTerminalReset
+)
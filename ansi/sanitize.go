@@ -0,0 +1,179 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// Sanitizer removes escape sequences and control characters that can change
+// the terminal state from untrusted text, such as remote or user-provided
+// content. Window title changes, clipboard writes, mode toggles, device
+// queries, cursor movements, and other sequences are removed, while text and
+// SGR styling are preserved.
+//
+// The only kept control characters are backspace, tab, line feed, and
+// carriage return. C1 controls are removed both as single bytes and encoded
+// as UTF-8 (U+0080 to U+009F), since some terminals interpret the latter.
+type Sanitizer struct {
+	// Hyperlinks keeps OSC 8 hyperlinks.
+	Hyperlinks bool
+}
+
+// Sanitize removes escape sequences that can change the terminal state from
+// the given string, preserving text and SGR styling. See [Sanitizer].
+func Sanitize(s string) string {
+	return Sanitizer{}.Sanitize(s)
+}
+
+// Sanitize removes escape sequences that can change the terminal state from
+// the given string, preserving text and SGR styling.
+func (s Sanitizer) Sanitize(str string) string {
+	var buf bytes.Buffer
+	p := newSanitizeParser()
+	for len(str) > 0 {
+		seq, _, n, state := DecodeSequence(str, NormalState, p)
+		if state != NormalState {
+			// Drop unterminated sequences.
+			break
+		}
+		if s.keep(seq, p) {
+			buf.WriteString(seq)
+		}
+		str = str[n:]
+	}
+	return buf.String()
+}
+
+// keep returns whether the given decoded sequence is safe to keep.
+func (s Sanitizer) keep(seq string, p *Parser) bool {
+	if len(seq) == 0 {
+		return false
+	}
+
+	c := seq[0]
+	switch {
+	case HasCsiPrefix(seq):
+		cmd := Cmd(p.Command())
+		return cmd.Final() == 'm' && cmd.Prefix() == 0 && cmd.Intermediate() == 0
+	case HasOscPrefix(seq):
+		return s.Hyperlinks && p.Command() == 8 //nolint:gomnd
+	case c == ESC || c == DCS || c == SOS || c == PM || c == APC:
+		return false
+	case len(seq) == 1 && (c <= US || c == DEL || c >= PAD && c <= APC):
+		return c == BS || c == HT || c == LF || c == CR
+	}
+	return strings.IndexFunc(seq, isC1Rune) < 0
+}
+
+// isC1Rune returns whether r is a C1 control character.
+func isC1Rune(r rune) bool {
+	return r >= PAD && r <= APC
+}
+
+// newSanitizeParser returns a parser with a small data buffer since only the
+// commands are needed to sanitize sequences.
+func newSanitizeParser() *Parser {
+	p := new(Parser)
+	p.SetParamsSize(parser.MaxParamsSize)
+	p.SetDataSize(16) //nolint:gomnd
+	return p
+}
+
+// maxSanitizePending is the maximum number of bytes of an incomplete
+// sequence a [SanitizeWriter] buffers.
+const maxSanitizePending = 4096
+
+// SanitizeWriter is an [io.Writer] that sanitizes the data written to it
+// using a [Sanitizer] before writing it to the underlying writer. Sequences
+// can be split across writes. A sequence that grows beyond 4096 bytes before
+// it's complete is dropped, even if it would be kept.
+type SanitizeWriter struct {
+	s   Sanitizer
+	w   io.Writer
+	p   *Parser
+	buf []byte
+
+	// skip is the 8-bit introducer of a dropped sequence whose remaining
+	// bytes are discarded, or 0. skipEsc is set when the last discarded byte
+	// is an ESC, which either starts ST or a new sequence.
+	skip    byte
+	skipEsc bool
+}
+
+// NewSanitizeWriter returns a new [SanitizeWriter] that writes the sanitized
+// data to w.
+func NewSanitizeWriter(w io.Writer, s Sanitizer) *SanitizeWriter {
+	return &SanitizeWriter{s: s, w: w, p: newSanitizeParser()}
+}
+
+// Write implements [io.Writer]. Incomplete sequences and runes at the end of
+// p are buffered until the next write.
+func (w *SanitizeWriter) Write(p []byte) (int, error) {
+	b := p
+	if w.skip != 0 {
+		b = w.discard(b)
+	}
+	w.buf = append(w.buf, b...)
+
+	var out bytes.Buffer
+	b = w.buf
+	for len(b) > 0 {
+		seq, _, n, newState := DecodeSequence(b, NormalState, w.p)
+		if newState != NormalState || !utf8.FullRune(b) {
+			// Wait for the rest of the sequence or rune.
+			break
+		}
+		if w.s.keep(string(seq), w.p) {
+			out.Write(seq)
+		}
+		b = b[n:]
+	}
+	w.buf = append(w.buf[:0], b...)
+	if len(w.buf) > maxSanitizePending {
+		// Drop the incomplete sequence instead of buffering it forever.
+		w.skip = w.buf[0]
+		if w.skip == ESC {
+			w.skip = w.buf[1] + 0x40 //nolint:gomnd
+		}
+		w.skipEsc = w.buf[len(w.buf)-1] == ESC
+		w.buf = w.buf[:0]
+	}
+
+	if out.Len() > 0 {
+		if _, err := w.w.Write(out.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// discard discards the bytes of a dropped sequence up to its end and returns
+// the rest of b.
+func (w *SanitizeWriter) discard(b []byte) []byte {
+	for i, c := range b {
+		if w.skipEsc {
+			w.skipEsc = false
+			w.skip = 0
+			if c == '\\' {
+				return b[i+1:]
+			}
+			// The ESC starts a new sequence.
+			w.buf = append(w.buf, ESC)
+			return b[i:]
+		}
+		switch {
+		case c == ESC:
+			w.skipEsc = true
+		case c == CAN || c == SUB || c == ST,
+			c == BEL && w.skip == OSC,
+			w.skip == CSI && c >= 0x40 && c <= 0x7e:
+			w.skip = 0
+			return b[i+1:]
+		}
+	}
+	return nil
+}
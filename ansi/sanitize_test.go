@@ -0,0 +1,91 @@
+package ansi
+
+import (
+	"bytes"
+	"testing"
+)
+
+var sanitizeCases = []struct {
+	name string
+	in   string
+	want string
+}{
+	{"empty", "", ""},
+	{"text", "hello, 世界 👋🏽", "hello, 世界 👋🏽"},
+	{"controls", "a\tb\r\nc\x07d\x0ee\x0ff\x7f\bg", "a\tb\r\ncdef\bg"},
+	{"sgr", "\x1b[1;38;2;255;0;0mred\x1b[m", "\x1b[1;38;2;255;0;0mred\x1b[m"},
+	{"8-bit sgr", "\x9b31mred\x9bm", "\x9b31mred\x9bm"},
+	{"title", "a\x1b]2;pwned\x07b\x1b]0;pwned\x1b\\c", "abc"},
+	{"clipboard", "a\x1b]52;c;aGVsbG8=\x07b", "ab"},
+	{"modes", "\x1b[?1049h\x1b[?25lhidden\x1b[4h", "hidden"},
+	{"queries", "\x1b[c\x1b[>c\x1b[6n\x1b[?u\x1bP+q544e\x1b\\", ""},
+	{"cursor", "a\x1b[2Jb\x1b[10;10Hc\x1b7\x1b8\x1bc", "abc"},
+	{"private sgr", "\x1b[>4;2m\x1b[?m", ""},
+	{"apc", "\x1b_Gi=1;AAAA\x1b\\x", "x"},
+	{"hyperlink", "\x1b]8;;https://example.com\x07link\x1b]8;;\x07", "link"},
+	{"unterminated", "a\x1b]2;pwned", "a"},
+	{"c1 controls", "a\x85b\x8dc", "abc"},
+	{"utf-8 c1 controls", "a\xc2\x9b2Jb\xc2\x85c", "a2Jbc"},
+}
+
+func TestSanitize(t *testing.T) {
+	for _, tt := range sanitizeCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizerHyperlinks(t *testing.T) {
+	in := "\x1b]8;;https://example.com\x07link\x1b]8;;\x07\x1b]2;title\x07"
+	want := "\x1b]8;;https://example.com\x07link\x1b]8;;\x07"
+	if got := (Sanitizer{Hyperlinks: true}).Sanitize(in); got != want {
+		t.Errorf("Sanitize(%q) = %q, want %q", in, got, want)
+	}
+}
+
+func TestSanitizeWriter(t *testing.T) {
+	for _, tt := range sanitizeCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewSanitizeWriter(&buf, Sanitizer{})
+			// Write a byte at a time to split sequences and runes.
+			for i := 0; i < len(tt.in); i++ {
+				if _, err := w.Write([]byte{tt.in[i]}); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Write(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeWriterLongSequence(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSanitizeWriter(&buf, Sanitizer{})
+	chunk := bytes.Repeat([]byte("A"), 1000)
+	w.Write([]byte("a\x1b]2;")) //nolint:errcheck
+	for i := 0; i < 10; i++ {
+		w.Write(chunk) //nolint:errcheck
+	}
+	if len(w.buf) > maxSanitizePending {
+		t.Errorf("pending = %d bytes, want at most %d", len(w.buf), maxSanitizePending)
+	}
+	w.Write([]byte("\x07b\x1b["))             //nolint:errcheck
+	w.Write(bytes.Repeat([]byte("1;"), 3000)) //nolint:errcheck
+	w.Write([]byte("Hc\x1bP"))                //nolint:errcheck
+	w.Write(chunk)                            //nolint:errcheck
+	w.Write(chunk)                            //nolint:errcheck
+	w.Write(chunk)                            //nolint:errcheck
+	w.Write(chunk)                            //nolint:errcheck
+	w.Write(chunk)                            //nolint:errcheck
+	w.Write([]byte("\x1b"))                   //nolint:errcheck
+	w.Write([]byte("\\d\x1b[1md"))            //nolint:errcheck
+	if got, want := buf.String(), "abcd\x1b[1md"; got != want {
+		t.Errorf("Write() = %q, want %q", got, want)
+	}
+}
@@ -73,6 +73,94 @@ const (
 	EraseEntireLine = "\x1b[2K"
 )
 
+// SelectiveEraseDisplay (DECSED) clears the screen or parts of the screen
+// like [EraseDisplay] but only erases the characters that are not protected
+// with [SelectCharacterProtection]. Possible values:
+//
+// Default is 0.
+//
+//	0: Clear from cursor to end of screen.
+//	1: Clear from cursor to beginning of the screen.
+//	2: Clear entire screen.
+//
+//	CSI ? <n> J
+//
+// See: https://vt100.net/docs/vt510-rm/DECSED.html
+func SelectiveEraseDisplay(n int) string {
+	var s string
+	if n > 0 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[?" + s + "J"
+}
+
+// DECSED is an alias for [SelectiveEraseDisplay].
+func DECSED(n int) string {
+	return SelectiveEraseDisplay(n)
+}
+
+// SelectiveEraseLine (DECSEL) clears the current line or parts of the line
+// like [EraseLine] but only erases the characters that are not protected with
+// [SelectCharacterProtection]. Possible values:
+//
+//	0: Clear from cursor to end of line.
+//	1: Clear from cursor to beginning of the line.
+//	2: Clear entire line.
+//
+// The cursor position is not affected.
+//
+//	CSI ? <n> K
+//
+// See: https://vt100.net/docs/vt510-rm/DECSEL.html
+func SelectiveEraseLine(n int) string {
+	var s string
+	if n > 0 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[?" + s + "K"
+}
+
+// DECSEL is an alias for [SelectiveEraseLine].
+func DECSEL(n int) string {
+	return SelectiveEraseLine(n)
+}
+
+// SelectCharacterProtection (DECSCA) sets whether the characters written
+// after it are protected from the selective erase operations
+// [SelectiveEraseDisplay] and [SelectiveEraseLine]. Possible values:
+//
+// Default is 0.
+//
+//	0: Characters are not protected.
+//	1: Characters are protected.
+//	2: Characters are not protected.
+//
+//	CSI <n> " q
+//
+// See: https://vt100.net/docs/vt510-rm/DECSCA.html
+func SelectCharacterProtection(n int) string {
+	var s string
+	if n > 0 {
+		s = strconv.Itoa(n)
+	}
+	return "\x1b[" + s + "\"q"
+}
+
+// DECSCA is an alias for [SelectCharacterProtection].
+func DECSCA(n int) string {
+	return SelectCharacterProtection(n)
+}
+
+// Character protection sequences. Characters written after
+// [SetCharacterProtection] are protected from selective erases until
+// [ResetCharacterProtection].
+//
+// See: https://vt100.net/docs/vt510-rm/DECSCA.html
+const (
+	SetCharacterProtection   = "\x1b[1\"q"
+	ResetCharacterProtection = "\x1b[0\"q"
+)
+
 // ScrollUp (SU) scrolls the screen up n lines. New lines are added at the
 // bottom of the screen.
 //
@@ -166,16 +254,19 @@ func DL(n int) string {
 //
 //	CSI Pt ; Pb r
 //
+// Defaulted parameters, a top of 1 or less and a bottom of 0 or less, are
+// omitted from the sequence.
+//
 // See: https://vt100.net/docs/vt510-rm/DECSTBM.html
 func SetTopBottomMargins(top, bot int) string {
-	var t, b string
-	if top > 0 {
-		t = strconv.Itoa(top)
+	var s string
+	if top > 1 {
+		s = strconv.Itoa(top)
 	}
 	if bot > 0 {
-		b = strconv.Itoa(bot)
+		s += ";" + strconv.Itoa(bot)
 	}
-	return "\x1b[" + t + ";" + b + "r"
+	return "\x1b[" + s + "r"
 }
 
 // DECSTBM is an alias for [SetTopBottomMargins].
@@ -183,17 +274,30 @@ func DECSTBM(top, bot int) string {
 	return SetTopBottomMargins(top, bot)
 }
 
+// ResetTopBottomMargins is a sequence that resets the top and bottom margins
+// to the entire screen. This is equivalent to SetTopBottomMargins(0, 0).
+//
+//	CSI r
+//
+// See: https://vt100.net/docs/vt510-rm/DECSTBM.html
+const ResetTopBottomMargins = "\x1b[r"
+
 // SetLeftRightMargins (DECSLRM) sets the left and right margins for the scrolling
-// region.
+// region. The margins only take effect while [LeftRightMarginMode] (DECLRMM)
+// is set.
 //
 // Default is 1 and the right of the screen.
 //
 //	CSI Pl ; Pr s
 //
+// Defaulted parameters, a left of 1 or less and a right of 0 or less, are
+// omitted from the sequence. The separator is always kept so that the
+// sequence isn't mistaken for [SaveCurrentCursorPosition] (SCOSC).
+//
 // See: https://vt100.net/docs/vt510-rm/DECSLRM.html
 func SetLeftRightMargins(left, right int) string {
 	var l, r string
-	if left > 0 {
+	if left > 1 {
 		l = strconv.Itoa(left)
 	}
 	if right > 0 {
@@ -207,6 +311,14 @@ func DECSLRM(left, right int) string {
 	return SetLeftRightMargins(left, right)
 }
 
+// ResetLeftRightMargins is a sequence that resets the left and right margins
+// to the entire screen. This is equivalent to SetLeftRightMargins(0, 0).
+//
+//	CSI ; s
+//
+// See: https://vt100.net/docs/vt510-rm/DECSLRM.html
+const ResetLeftRightMargins = "\x1b[;s"
+
 // SetScrollingRegion (DECSTBM) sets the top and bottom margins for the scrolling
 // region. The default is the entire screen.
 //
@@ -313,6 +425,19 @@ func TBC(n int) string {
 	return TabClear(n)
 }
 
+// Tab clear sequences.
+//
+// See: https://vt100.net/docs/vt510-rm/TBC.html
+const (
+	// ClearTabStop clears the tab stop at the current column. This is
+	// equivalent to TabClear(0).
+	ClearTabStop = "\x1b[g"
+
+	// ClearAllTabStops clears all the tab stops. This is equivalent to
+	// TabClear(3).
+	ClearAllTabStops = "\x1b[3g"
+)
+
 // RequestPresentationStateReport (DECRQPSR) requests the terminal to send a
 // report of the presentation state. This includes the cursor information [DECCIR],
 // and tab stop [DECTABSR] reports.
@@ -0,0 +1,90 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSetTopBottomMargins(t *testing.T) {
+	cases := []struct {
+		top, bot int
+		want     string
+	}{
+		{0, 0, "\x1b[r"},
+		{1, 0, "\x1b[r"},
+		{2, 0, "\x1b[2r"},
+		{0, 24, "\x1b[;24r"},
+		{1, 24, "\x1b[;24r"},
+		{5, 10, "\x1b[5;10r"},
+	}
+	for _, tc := range cases {
+		if got := ansi.SetTopBottomMargins(tc.top, tc.bot); got != tc.want {
+			t.Errorf("SetTopBottomMargins(%d, %d) = %q, want %q", tc.top, tc.bot, got, tc.want)
+		}
+	}
+	if ansi.SetTopBottomMargins(0, 0) != ansi.ResetTopBottomMargins {
+		t.Errorf("SetTopBottomMargins(0, 0) != ResetTopBottomMargins")
+	}
+}
+
+func TestSetLeftRightMargins(t *testing.T) {
+	cases := []struct {
+		left, right int
+		want        string
+	}{
+		{0, 0, "\x1b[;s"},
+		{1, 0, "\x1b[;s"},
+		{2, 0, "\x1b[2;s"},
+		{0, 80, "\x1b[;80s"},
+		{10, 40, "\x1b[10;40s"},
+	}
+	for _, tc := range cases {
+		if got := ansi.SetLeftRightMargins(tc.left, tc.right); got != tc.want {
+			t.Errorf("SetLeftRightMargins(%d, %d) = %q, want %q", tc.left, tc.right, got, tc.want)
+		}
+	}
+	if ansi.SetLeftRightMargins(0, 0) != ansi.ResetLeftRightMargins {
+		t.Errorf("SetLeftRightMargins(0, 0) != ResetLeftRightMargins")
+	}
+}
+
+func TestSelectiveErase(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{ansi.SelectiveEraseDisplay(0), "\x1b[?J"},
+		{ansi.SelectiveEraseDisplay(2), "\x1b[?2J"},
+		{ansi.SelectiveEraseLine(0), "\x1b[?K"},
+		{ansi.SelectiveEraseLine(1), "\x1b[?1K"},
+		{ansi.SelectCharacterProtection(0), "\x1b[\"q"},
+		{ansi.SelectCharacterProtection(1), ansi.SetCharacterProtection},
+		{ansi.ResetCharacterProtection, "\x1b[0\"q"},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("expected: %q, got: %q", tc.want, tc.got)
+		}
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{ansi.HorizontalTabSet, "\x1bH"},
+		{ansi.TabClear(0), ansi.ClearTabStop},
+		{ansi.TabClear(3), ansi.ClearAllTabStops},
+		{ansi.ClearAllTabStops, "\x1b[3g"},
+		{ansi.CursorHorizontalForwardTab(0), "\x1b[I"},
+		{ansi.CursorHorizontalForwardTab(1), "\x1b[I"},
+		{ansi.CursorHorizontalForwardTab(4), "\x1b[4I"},
+		{ansi.CursorBackwardTab(1), "\x1b[Z"},
+		{ansi.CursorBackwardTab(2), "\x1b[2Z"},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("expected: %q, got: %q", tc.want, tc.got)
+		}
+	}
+}
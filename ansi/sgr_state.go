@@ -0,0 +1,348 @@
+package ansi
+
+import (
+	"image/color"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// SgrState is the effective Select Graphic Rendition (SGR) state of a
+// terminal. It consumes successive SGR sequences and emits the minimal
+// sequence to transition from one state to another. The zero value is the
+// default state with no attributes or colors.
+//
+// This is useful to keep track of the current style when wrapping,
+// truncating, or rendering styled text.
+type SgrState struct {
+	// Foreground, Background, and UnderlineColor are the colors. A nil color
+	// is the terminal default color.
+	Foreground     Color
+	Background     Color
+	UnderlineColor Color
+
+	// Underline is the underline style.
+	Underline UnderlineStyle
+
+	Bold          bool
+	Faint         bool
+	Italic        bool
+	SlowBlink     bool
+	RapidBlink    bool
+	Reverse       bool
+	Conceal       bool
+	Strikethrough bool
+}
+
+// Reset resets the state to the default state.
+func (s *SgrState) Reset() {
+	*s = SgrState{}
+}
+
+// IsZero returns whether the state is the default state.
+func (s SgrState) IsZero() bool {
+	return s.Equal(SgrState{})
+}
+
+// Equal returns whether both states are equal. Colors are equal when they
+// are of the same kind, basic, extended, or other, and have the same RGBA
+// values.
+func (s SgrState) Equal(o SgrState) bool {
+	return colorEqual(s.Foreground, o.Foreground) &&
+		colorEqual(s.Background, o.Background) &&
+		colorEqual(s.UnderlineColor, o.UnderlineColor) &&
+		s.Underline == o.Underline &&
+		s.Bold == o.Bold &&
+		s.Faint == o.Faint &&
+		s.Italic == o.Italic &&
+		s.SlowBlink == o.SlowBlink &&
+		s.RapidBlink == o.RapidBlink &&
+		s.Reverse == o.Reverse &&
+		s.Conceal == o.Conceal &&
+		s.Strikethrough == o.Strikethrough
+}
+
+// Consume applies the given sequence to the state if it's an SGR sequence
+// and reports whether it was one.
+func (s *SgrState) Consume(seq string) bool {
+	if !HasCsiPrefix(seq) || !HasSuffix(seq, "m") {
+		return false
+	}
+
+	p := new(Parser)
+	p.SetParamsSize(parser.MaxParamsSize)
+	_, _, n, _ := DecodeSequence(seq, NormalState, p)
+	if n != len(seq) || Cmd(p.Command()) != 'm' {
+		return false
+	}
+
+	s.Apply(p.Params())
+	return true
+}
+
+// Apply applies the given SGR parameters to the state.
+func (s *SgrState) Apply(params Params) {
+	if len(params) == 0 {
+		s.Reset()
+		return
+	}
+
+	for i := 0; i < len(params); {
+		g := params.Group(i)
+		param, n := g[0].Param(0), len(g)
+		switch param {
+		case 0:
+			s.Reset()
+		case 1:
+			s.Bold = true
+		case 2: //nolint:gomnd
+			s.Faint = true
+		case 3: //nolint:gomnd
+			s.Italic = true
+		case 4: //nolint:gomnd
+			if u, ok := ReadUnderlineStyle(g); ok {
+				s.Underline = u
+			}
+		case 5: //nolint:gomnd
+			s.SlowBlink = true
+		case 6: //nolint:gomnd
+			s.RapidBlink = true
+		case 7: //nolint:gomnd
+			s.Reverse = true
+		case 8: //nolint:gomnd
+			s.Conceal = true
+		case 9: //nolint:gomnd
+			s.Strikethrough = true
+		case 22: //nolint:gomnd
+			s.Bold, s.Faint = false, false
+		case 23: //nolint:gomnd
+			s.Italic = false
+		case 24: //nolint:gomnd
+			s.Underline = NoUnderlineStyle
+		case 25: //nolint:gomnd
+			s.SlowBlink, s.RapidBlink = false, false
+		case 27: //nolint:gomnd
+			s.Reverse = false
+		case 28: //nolint:gomnd
+			s.Conceal = false
+		case 29: //nolint:gomnd
+			s.Strikethrough = false
+		case 30, 31, 32, 33, 34, 35, 36, 37: //nolint:gomnd
+			s.Foreground = Black + BasicColor(param-30) //nolint:gosec
+		case 38, 48, 58: //nolint:gomnd
+			var c color.Color
+			if m := ReadStyleColor(params[i:], &c); m > 0 {
+				switch param {
+				case 38: //nolint:gomnd
+					s.Foreground = c
+				case 48: //nolint:gomnd
+					s.Background = c
+				case 58: //nolint:gomnd
+					s.UnderlineColor = c
+				}
+				n = max(n, m)
+			}
+		case 39: //nolint:gomnd
+			s.Foreground = nil
+		case 40, 41, 42, 43, 44, 45, 46, 47: //nolint:gomnd
+			s.Background = Black + BasicColor(param-40) //nolint:gosec
+		case 49: //nolint:gomnd
+			s.Background = nil
+		case 59: //nolint:gomnd
+			s.UnderlineColor = nil
+		case 90, 91, 92, 93, 94, 95, 96, 97: //nolint:gomnd
+			s.Foreground = BrightBlack + BasicColor(param-90) //nolint:gosec
+		case 100, 101, 102, 103, 104, 105, 106, 107: //nolint:gomnd
+			s.Background = BrightBlack + BasicColor(param-100) //nolint:gosec
+		}
+		i += n
+	}
+}
+
+// Style returns the style attributes that set the state from the default
+// state.
+func (s SgrState) Style() Style {
+	var b Style
+	if s.Bold {
+		b = b.Bold()
+	}
+	if s.Faint {
+		b = b.Faint()
+	}
+	if s.Italic {
+		b = b.Italic()
+	}
+	if s.Underline != NoUnderlineStyle {
+		b = b.UnderlineStyle(s.Underline)
+	}
+	if s.SlowBlink {
+		b = b.SlowBlink()
+	}
+	if s.RapidBlink {
+		b = b.RapidBlink()
+	}
+	if s.Reverse {
+		b = b.Reverse()
+	}
+	if s.Conceal {
+		b = b.Conceal()
+	}
+	if s.Strikethrough {
+		b = b.Strikethrough()
+	}
+	if s.Foreground != nil {
+		b = b.ForegroundColor(s.Foreground)
+	}
+	if s.Background != nil {
+		b = b.BackgroundColor(s.Background)
+	}
+	if s.UnderlineColor != nil {
+		b = b.UnderlineColor(s.UnderlineColor)
+	}
+	return b
+}
+
+// Sequence returns the SGR sequence that sets the state from the default
+// state. The default state returns [ResetStyle].
+func (s SgrState) Sequence() string {
+	return s.Style().String()
+}
+
+// Transition returns the shortest SGR sequence that changes the state s to
+// the state to. It's either the attributes that differ or a reset followed by
+// the attributes of the new state. An empty string is returned when both
+// states are equal.
+func (s SgrState) Transition(to SgrState) string {
+	if s.Equal(to) {
+		return ""
+	}
+	if to.IsZero() {
+		return ResetStyle
+	}
+
+	diff := s.diff(to).String()
+	reset := append(Style{resetAttr}, to.Style()...).String()
+	if len(reset) < len(diff) {
+		return reset
+	}
+	return diff
+}
+
+// diff returns the style attributes that change the state s to the state to.
+func (s SgrState) diff(to SgrState) Style {
+	var b Style
+
+	// Normal intensity resets both bold and faint.
+	if s.Bold && !to.Bold || s.Faint && !to.Faint {
+		b = b.NormalIntensity()
+		if to.Bold {
+			b = b.Bold()
+		}
+		if to.Faint {
+			b = b.Faint()
+		}
+	} else {
+		if to.Bold && !s.Bold {
+			b = b.Bold()
+		}
+		if to.Faint && !s.Faint {
+			b = b.Faint()
+		}
+	}
+	if s.Italic != to.Italic {
+		if to.Italic {
+			b = b.Italic()
+		} else {
+			b = b.NoItalic()
+		}
+	}
+	if s.Underline != to.Underline {
+		b = b.UnderlineStyle(to.Underline)
+	}
+	// No blink resets both slow and rapid blink.
+	if s.SlowBlink && !to.SlowBlink || s.RapidBlink && !to.RapidBlink {
+		b = b.NoBlink()
+		if to.SlowBlink {
+			b = b.SlowBlink()
+		}
+		if to.RapidBlink {
+			b = b.RapidBlink()
+		}
+	} else {
+		if to.SlowBlink && !s.SlowBlink {
+			b = b.SlowBlink()
+		}
+		if to.RapidBlink && !s.RapidBlink {
+			b = b.RapidBlink()
+		}
+	}
+	if s.Reverse != to.Reverse {
+		if to.Reverse {
+			b = b.Reverse()
+		} else {
+			b = b.NoReverse()
+		}
+	}
+	if s.Conceal != to.Conceal {
+		if to.Conceal {
+			b = b.Conceal()
+		} else {
+			b = b.NoConceal()
+		}
+	}
+	if s.Strikethrough != to.Strikethrough {
+		if to.Strikethrough {
+			b = b.Strikethrough()
+		} else {
+			b = b.NoStrikethrough()
+		}
+	}
+	if !colorEqual(s.Foreground, to.Foreground) {
+		if to.Foreground == nil {
+			b = b.DefaultForegroundColor()
+		} else {
+			b = b.ForegroundColor(to.Foreground)
+		}
+	}
+	if !colorEqual(s.Background, to.Background) {
+		if to.Background == nil {
+			b = b.DefaultBackgroundColor()
+		} else {
+			b = b.BackgroundColor(to.Background)
+		}
+	}
+	if !colorEqual(s.UnderlineColor, to.UnderlineColor) {
+		if to.UnderlineColor == nil {
+			b = b.DefaultUnderlineColor()
+		} else {
+			b = b.UnderlineColor(to.UnderlineColor)
+		}
+	}
+
+	return b
+}
+
+// colorEqual returns whether both colors are of the same kind and have the
+// same RGBA values. Basic and extended colors are different kinds since they
+// are encoded differently even if they have the same values.
+func colorEqual(a, b Color) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if colorKind(a) != colorKind(b) {
+		return false
+	}
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+// colorKind returns the kind of the color for comparison.
+func colorKind(c Color) int {
+	switch c.(type) {
+	case BasicColor:
+		return 1
+	case ExtendedColor:
+		return 2 //nolint:gomnd
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package ansi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestSgrStateConsume(t *testing.T) {
+	var s SgrState
+	for _, seq := range []string{
+		"\x1b[1;3;4:3m",
+		"\x1b[38;2;255;0;0;44m",
+		"\x1b[58:5:196m",
+		"\x1b[5;6m",
+		"\x1b[25m",
+	} {
+		if !s.Consume(seq) {
+			t.Fatalf("Consume(%q) = false, want true", seq)
+		}
+	}
+
+	want := SgrState{
+		Foreground:     color.RGBA{R: 0xff, A: 0xff},
+		Background:     Blue,
+		UnderlineColor: ExtendedColor(196),
+		Underline:      CurlyUnderlineStyle,
+		Bold:           true,
+		Italic:         true,
+	}
+	if !s.Equal(want) {
+		t.Errorf("Consume() = %+v, want %+v", s, want)
+	}
+
+	for _, seq := range []string{"\x1b[2J", "\x1b[?1m", "hello", "\x1b]0;m\x07"} {
+		if s.Consume(seq) {
+			t.Errorf("Consume(%q) = true, want false", seq)
+		}
+	}
+
+	if !s.Consume("\x1b[m") || !s.IsZero() {
+		t.Errorf("Consume(reset) = %+v, want zero state", s)
+	}
+}
+
+func TestSgrStateSubParams(t *testing.T) {
+	var s SgrState
+	// Invalid underline styles are ignored along with the rest of their
+	// group instead of being read as attributes.
+	s.Consume("\x1b[4:9;38:2::1:2:3:7m")
+	want := SgrState{
+		Foreground: color.RGBA{R: 1, G: 2, B: 3, A: 0xff},
+	}
+	if !s.Equal(want) {
+		t.Errorf("Consume() = %+v, want %+v", s, want)
+	}
+}
+
+func TestSgrStateSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		s    SgrState
+		want string
+	}{
+		{"zero", SgrState{}, "\x1b[m"},
+		{"bold", SgrState{Bold: true}, "\x1b[1m"},
+		{"all", SgrState{
+			Bold: true, Faint: true, Italic: true, Underline: DoubleUnderlineStyle,
+			SlowBlink: true, Reverse: true, Strikethrough: true,
+			Foreground: Red, Background: ExtendedColor(200), UnderlineColor: color.RGBA{G: 0xff, A: 0xff},
+		}, "\x1b[1;2;3;4:2;5;7;9;31;48;5;200;58:2::0:255:0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Sequence(); got != tt.want {
+				t.Errorf("Sequence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSgrStateTransition(t *testing.T) {
+	bold := SgrState{Bold: true}
+	boldFaint := SgrState{Bold: true, Faint: true}
+	red := SgrState{Foreground: Red}
+	styled := SgrState{Bold: true, Italic: true, Underline: SingleUnderlineStyle, Foreground: Red, Background: Blue}
+
+	tests := []struct {
+		name     string
+		from, to SgrState
+		want     string
+	}{
+		{"equal", bold, bold, ""},
+		{"to zero", styled, SgrState{}, "\x1b[m"},
+		{"from zero", SgrState{}, bold, "\x1b[1m"},
+		{"add", bold, boldFaint, "\x1b[2m"},
+		{"remove faint keeps bold", boldFaint, bold, "\x1b[0;1m"},
+		{"normal intensity", SgrState{Bold: true, Faint: true, Foreground: Red}, SgrState{Bold: true, Foreground: Red}, "\x1b[22;1m"},
+		{"blink", SgrState{SlowBlink: true, RapidBlink: true, Reverse: true}, SgrState{RapidBlink: true, Reverse: true}, "\x1b[25;6m"},
+		{"color", red, SgrState{Foreground: Green}, "\x1b[32m"},
+		{"default color", SgrState{Foreground: Red, Bold: true}, bold, "\x1b[39m"},
+		{"basic vs extended", red, SgrState{Foreground: ExtendedColor(1)}, "\x1b[38;5;1m"},
+		{"underline", styled, SgrState{Bold: true, Italic: true, Underline: CurlyUnderlineStyle, Foreground: Red, Background: Blue}, "\x1b[4:3m"},
+		{"reset is shorter", styled, SgrState{Reverse: true}, "\x1b[0;7m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.from.Transition(tt.to)
+			if got != tt.want {
+				t.Errorf("Transition() = %q, want %q", got, tt.want)
+			}
+
+			// Applying the transition must result in the target state.
+			s := tt.from
+			if got != "" && !s.Consume(got) {
+				t.Fatalf("Consume(%q) = false", got)
+			}
+			if !s.Equal(tt.to) {
+				t.Errorf("Consume(Transition()) = %+v, want %+v", s, tt.to)
+			}
+		})
+	}
+}
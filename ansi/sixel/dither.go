@@ -0,0 +1,91 @@
+package sixel
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Dither is the dithering method used when an image has more than
+// [MaxColors] colors and has to be quantized to a fixed palette.
+type Dither int
+
+// Dithering methods.
+const (
+	// DitherNone maps each pixel to the closest palette color. It's the
+	// fastest method, but gradients and photographs show visible banding.
+	DitherNone Dither = iota
+
+	// DitherFloydSteinberg diffuses the quantization error of each pixel to
+	// its neighbors. It gives the best looking results for photographs.
+	DitherFloydSteinberg
+
+	// DitherOrdered offsets each pixel by a threshold from an 8x8 Bayer
+	// matrix before mapping it to the closest palette color. The pattern is
+	// stable, which suits animations and images that are redrawn often.
+	DitherOrdered
+)
+
+// String returns the name of the dithering method.
+func (d Dither) String() string {
+	switch d {
+	case DitherNone:
+		return "none"
+	case DitherFloydSteinberg:
+		return "floyd-steinberg"
+	case DitherOrdered:
+		return "ordered"
+	default:
+		return "unknown"
+	}
+}
+
+// bayer is the 8x8 Bayer threshold matrix.
+var bayer = [8][8]int{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// orderedSpread is the range of the ordered dithering offsets in 8-bit
+// channel units. It roughly matches the distance between the levels of the
+// fixed palette.
+const orderedSpread = 64
+
+// drawDithered draws m into p using the dithering method d.
+func drawDithered(p *image.Paletted, m image.Image, d Dither) {
+	bounds := m.Bounds()
+	switch d {
+	case DitherFloydSteinberg:
+		draw.FloydSteinberg.Draw(p, bounds, m, bounds.Min)
+	case DitherOrdered:
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA) //nolint:forcetypeassert
+				t := bayer[y&7][x&7]*orderedSpread/64 - orderedSpread/2 //nolint:gomnd
+				c.R = clamp(int(c.R) + t)
+				c.G = clamp(int(c.G) + t)
+				c.B = clamp(int(c.B) + t)
+				p.SetColorIndex(x, y, uint8(p.Palette.Index(c))) //nolint:gosec
+			}
+		}
+	default:
+		draw.Draw(p, bounds, m, bounds.Min, draw.Src)
+	}
+}
+
+// clamp clamps v to a color channel value.
+func clamp(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xff { //nolint:gomnd
+		return 0xff
+	}
+	return uint8(v)
+}
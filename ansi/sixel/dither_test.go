@@ -0,0 +1,102 @@
+package sixel
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient returns an image with a horizontal gradient that doesn't fit in
+// the sixel palette.
+func gradient() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 256, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 256; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y * 8), B: uint8(255 - x), A: 0xff})
+		}
+	}
+	return img
+}
+
+// blockError returns the average difference between the mean color of each
+// 8x8 block of the quantized image and of the source image. Dithering keeps
+// the local average close to the source, so it lowers the error.
+func blockError(src image.Image, p *image.Paletted) float64 {
+	var total float64
+	var blocks int
+	b := src.Bounds()
+	for by := b.Min.Y; by < b.Max.Y; by += 8 {
+		for bx := b.Min.X; bx < b.Max.X; bx += 8 {
+			var want, got [3]float64
+			for y := by; y < by+8; y++ {
+				for x := bx; x < bx+8; x++ {
+					r, g, b, _ := src.At(x, y).RGBA()
+					want[0], want[1], want[2] = want[0]+float64(r), want[1]+float64(g), want[2]+float64(b)
+					r, g, b, _ = p.At(x, y).RGBA()
+					got[0], got[1], got[2] = got[0]+float64(r), got[1]+float64(g), got[2]+float64(b)
+				}
+			}
+			for i := range want {
+				d := (want[i] - got[i]) / 64
+				if d < 0 {
+					d = -d
+				}
+				total += d
+			}
+			blocks++
+		}
+	}
+	return total / float64(blocks)
+}
+
+func TestDither(t *testing.T) {
+	img := gradient()
+	none := blockError(img, quantize(img, DitherNone, 0))
+	for _, d := range []Dither{DitherFloydSteinberg, DitherOrdered} {
+		t.Run(d.String(), func(t *testing.T) {
+			p := quantize(img, d, 0)
+			if len(p.Palette) > MaxColors {
+				t.Fatalf("palette has %d colors, want at most %d", len(p.Palette), MaxColors)
+			}
+			if got := blockError(img, p); got >= none*3/4 {
+				t.Errorf("block error = %.0f, want less than 3/4 of %.0f without dithering", got, none)
+			}
+		})
+	}
+}
+
+func TestDitherExactColors(t *testing.T) {
+	// Images that fit in the palette are never dithered.
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 30), G: uint8(y * 30), A: 0xff})
+		}
+	}
+
+	var want bytes.Buffer
+	if err := (&Encoder{}).Encode(&want, img); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []Dither{DitherFloydSteinberg, DitherOrdered} {
+		var got bytes.Buffer
+		if err := (&Encoder{Dither: d}).Encode(&got, img); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%s: Encode() = %q, want %q", d, got.String(), want.String())
+		}
+	}
+}
+
+func BenchmarkDither(b *testing.B) {
+	img := gradient()
+	for _, d := range []Dither{DitherNone, DitherFloydSteinberg, DitherOrdered} {
+		b.Run(d.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				quantize(img, d, 0)
+			}
+		})
+	}
+}
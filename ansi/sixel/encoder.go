@@ -0,0 +1,268 @@
+package sixel
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"io"
+	"strconv"
+)
+
+// Encoder encodes images to the Sixel format. The output is the sixel data
+// that goes inside a DCS sixel sequence, see [ansi.SixelGraphics].
+//
+// [ansi.SixelGraphics]: https://pkg.go.dev/github.com/charmbracelet/x/ansi#SixelGraphics
+type Encoder struct {
+	// Uses run-length encoding for repeated sixels. This makes images with
+	// large areas of flat color much smaller at a small encoding cost.
+	RLE bool
+
+	// Dither is the dithering method used when the image has more than
+	// [MaxColors] colors and is quantized to a fixed palette. The default is
+	// [DitherNone].
+	Dither Dither
+
+	// AlphaThreshold makes pixels with an alpha value below it transparent.
+	// Transparent pixels are not drawn, leaving the background visible, and
+	// their colors are left out of the palette. The default of zero draws
+	// every pixel.
+	AlphaThreshold uint8
+
+	// Palette is a fixed palette of at most [MaxColors] colors to map images
+	// to instead of picking one per image. Paletted images that already use
+	// it are encoded without quantization. Sharing a palette across the
+	// frames of an animation saves the work of finding one for each frame.
+	// An empty palette is the same as nil.
+	Palette color.Palette
+
+	// OmitPalette skips writing the color register definitions, leaving the
+	// registers as defined by a previous image. Use it along with Palette
+	// for all the frames of an animation but the first, on terminals that
+	// share color registers between images.
+	OmitPalette bool
+}
+
+// ErrPaletteSize is returned by [Encoder.Encode] when [Encoder.Palette] has
+// more than [MaxColors] colors.
+var ErrPaletteSize = errors.New("sixel: palette has too many colors")
+
+// Encode encodes the image as sixel data and writes it to w. Images with more
+// than [MaxColors] colors are quantized to a fixed palette using the
+// encoder's [Dither] method, or to [Encoder.Palette] if it isn't empty.
+func (e *Encoder) Encode(w io.Writer, m image.Image) error {
+	if m == nil {
+		return nil
+	}
+	if len(e.Palette) > MaxColors {
+		return ErrPaletteSize
+	}
+
+	bw := bufio.NewWriter(w)
+	var p *image.Paletted
+	if len(e.Palette) > 0 {
+		p = mapToPalette(m, e.Palette, e.Dither)
+	} else {
+		p = quantize(m, e.Dither, e.AlphaThreshold)
+	}
+	bounds := p.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	hidden := transparent(m, e.AlphaThreshold)
+	drawn := func(x, y int) bool {
+		return hidden == nil || !hidden[y*width+x]
+	}
+
+	// Raster attributes: 1:1 aspect ratio and the image size.
+	writeInts(bw, RasterAttribute, 1, 1, width, height)
+
+	if !e.OmitPalette {
+		for i, c := range p.Palette {
+			r, g, b, _ := c.RGBA()
+			bw.WriteByte(ColorIntroducer)                             //nolint:errcheck
+			bw.WriteString(strconv.Itoa(i))                           //nolint:errcheck
+			writeInts(bw, ';', 2, percent(r), percent(g), percent(b)) //nolint:gomnd
+		}
+	}
+
+	used := make([]bool, len(p.Palette))
+	row := make([]byte, width)
+	for y := 0; y < height; y += 6 {
+		for i := range used {
+			used[i] = false
+		}
+		for dy := 0; dy < 6 && y+dy < height; dy++ {
+			for x := 0; x < width; x++ {
+				if drawn(x, y+dy) {
+					used[p.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y+dy)] = true
+				}
+			}
+		}
+
+		first := true
+		for ci := range p.Palette {
+			if !used[ci] {
+				continue
+			}
+			for x := range row {
+				var bits byte
+				for dy := 0; dy < 6 && y+dy < height; dy++ {
+					if drawn(x, y+dy) && int(p.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y+dy)) == ci {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+
+			if !first {
+				bw.WriteByte(CarriageReturn) //nolint:errcheck
+			}
+			first = false
+			bw.WriteByte(ColorIntroducer)    //nolint:errcheck
+			bw.WriteString(strconv.Itoa(ci)) //nolint:errcheck
+			if e.RLE {
+				writeRLE(bw, row)
+			} else {
+				bw.Write(row) //nolint:errcheck
+			}
+		}
+
+		if y+6 < height {
+			bw.WriteByte(LineBreak) //nolint:errcheck
+		}
+	}
+
+	return bw.Flush()
+}
+
+// minRepeat is the shortest run of sixels worth encoding with the
+// [RepeatIntroducer]. Shorter runs are not longer when written as is.
+const minRepeat = 4
+
+// writeRLE writes the sixels replacing runs of the same sixel with the
+// [RepeatIntroducer].
+func writeRLE(w *bufio.Writer, row []byte) {
+	for i := 0; i < len(row); {
+		n := 1
+		for i+n < len(row) && row[i+n] == row[i] {
+			n++
+		}
+		if n >= minRepeat {
+			w.WriteByte(RepeatIntroducer)  //nolint:errcheck
+			w.WriteString(strconv.Itoa(n)) //nolint:errcheck
+			w.WriteByte(row[i])            //nolint:errcheck
+		} else {
+			w.Write(row[i : i+n]) //nolint:errcheck
+		}
+		i += n
+	}
+}
+
+// quantize returns a paletted version of m with at most [MaxColors] colors.
+// Images that already fit in the palette keep their exact colors, others are
+// dithered with d. Pixels with an alpha value below threshold are left out
+// of the palette.
+func quantize(m image.Image, d Dither, threshold uint8) *image.Paletted {
+	if p, ok := m.(*image.Paletted); ok && len(p.Palette) <= MaxColors {
+		return p
+	}
+
+	pal, ok := exactPalette(m, threshold)
+	if !ok {
+		pal = palette.Plan9
+	}
+
+	p := image.NewPaletted(m.Bounds(), pal)
+	if ok {
+		// Exact colors don't need dithering.
+		d = DitherNone
+	}
+	drawDithered(p, m, d)
+	return p
+}
+
+// mapToPalette returns a version of m that uses the given palette, dithered
+// with d. Paletted images that already use the palette are returned as is.
+func mapToPalette(m image.Image, pal color.Palette, d Dither) *image.Paletted {
+	if p, ok := m.(*image.Paletted); ok && samePalette(p.Palette, pal) {
+		return p
+	}
+
+	p := image.NewPaletted(m.Bounds(), pal)
+	drawDithered(p, m, d)
+	return p
+}
+
+// samePalette reports whether the palettes have the same colors.
+func samePalette(a, b color.Palette) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		r1, g1, b1, a1 := a[i].RGBA()
+		r2, g2, b2, a2 := b[i].RGBA()
+		if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+			return false
+		}
+	}
+	return true
+}
+
+// exactPalette returns the colors of the pixels of m with an alpha value of
+// at least threshold if there are at most [MaxColors] of them.
+func exactPalette(m image.Image, threshold uint8) (color.Palette, bool) {
+	var pal color.Palette
+	seen := make(map[color.Color]struct{})
+	bounds := m.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(m.At(x, y))
+			if c.(color.NRGBA).A < threshold { //nolint:forcetypeassert
+				continue
+			}
+			if _, ok := seen[c]; ok {
+				continue
+			}
+			if len(pal) == MaxColors {
+				return nil, false
+			}
+			seen[c] = struct{}{}
+			pal = append(pal, c)
+		}
+	}
+	return pal, true
+}
+
+// transparent returns which pixels of m, row by row, have an alpha value
+// below threshold. It returns nil if threshold is zero.
+func transparent(m image.Image, threshold uint8) []bool {
+	if threshold == 0 {
+		return nil
+	}
+
+	bounds := m.Bounds()
+	hidden := make([]bool, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := m.At(x, y).RGBA()
+			hidden = append(hidden, a>>8 < uint32(threshold))
+		}
+	}
+	return hidden
+}
+
+// percent converts a 16-bit color channel to a percentage.
+func percent(c uint32) int {
+	return int((c*100 + 0x7fff) / 0xffff) //nolint:gomnd
+}
+
+// writeInts writes the prefix followed by the semicolon separated integers.
+func writeInts(w *bufio.Writer, prefix byte, ints ...int) {
+	w.WriteByte(prefix) //nolint:errcheck
+	for i, n := range ints {
+		if i > 0 {
+			w.WriteByte(';') //nolint:errcheck
+		}
+		w.WriteString(strconv.Itoa(n)) //nolint:errcheck
+	}
+}
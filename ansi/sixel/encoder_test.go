@@ -0,0 +1,171 @@
+package sixel
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 7))
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	blue := color.NRGBA{B: 0xff, A: 0xff}
+	for y := 0; y < 7; y++ {
+		img.Set(0, y, red)
+		img.Set(1, y, blue)
+	}
+	img.Set(1, 0, red)
+
+	var buf bytes.Buffer
+	if err := (&Encoder{}).Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// Red is register 0 and blue is register 1. The first sixel line has six
+	// rows, red covers the first column and the top pixel of the second
+	// column.
+	want := `"1;1;2;7` +
+		`#0;2;100;0;0#1;2;0;0;100` +
+		`#0~@$#1?}` +
+		`-#0@?$#1?@`
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestEncoderQuantize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 8), G: uint8(y * 8), B: 0x80, A: 0xff})
+		}
+	}
+
+	p := quantize(img, DitherNone, 0)
+	if len(p.Palette) > MaxColors {
+		t.Errorf("palette has %d colors, want at most %d", len(p.Palette), MaxColors)
+	}
+
+	var buf bytes.Buffer
+	if err := (&Encoder{}).Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte(`"1;1;32;32#`)) {
+		t.Errorf("Encode() = %q..., want raster attributes", buf.Bytes()[:20])
+	}
+}
+
+func TestEncoderRLE(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 6))
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	blue := color.NRGBA{B: 0xff, A: 0xff}
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 10; x++ {
+			if x < 7 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (&Encoder{RLE: true}).Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// Runs of at least 4 sixels are compressed, shorter ones are not.
+	want := `"1;1;10;6` +
+		`#0;2;100;0;0#1;2;0;0;100` +
+		`#0!7~???$#1!7?~~~`
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestEncoderTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 6))
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	for y := 0; y < 6; y++ {
+		img.Set(0, y, red)
+		img.Set(2, y, red)
+	}
+	img.Set(1, 0, color.NRGBA{B: 0xff, A: 0x40})
+
+	cases := []struct {
+		name      string
+		threshold uint8
+		want      string
+	}{
+		{
+			// Transparent pixels are drawn over black.
+			name: "opaque",
+			want: `"1;1;3;6` +
+				`#0;2;100;0;0#1;2;0;0;25#2;2;0;0;0` +
+				`#0~?~$#1?@?$#2?}?`,
+		},
+		{
+			// The translucent blue pixel is kept, the others are skipped.
+			name:      "fully_transparent",
+			threshold: 1,
+			want: `"1;1;3;6` +
+				`#0;2;100;0;0#1;2;0;0;25` +
+				`#0~?~$#1?@?`,
+		},
+		{
+			name:      "half_transparent",
+			threshold: 0x80,
+			want: `"1;1;3;6` +
+				`#0;2;100;0;0` +
+				`#0~?~`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (&Encoder{AlphaThreshold: c.threshold}).Encode(&buf, img); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != c.want {
+				t.Errorf("Encode() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func flatImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: 0xff, A: 0xff}
+			if x >= w/2 {
+				c = color.NRGBA{B: 0xff, A: 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	img := flatImage(640, 480)
+	for _, rle := range []bool{false, true} {
+		name := "raw"
+		if rle {
+			name = "rle"
+		}
+		b.Run(name, func(b *testing.B) {
+			var buf bytes.Buffer
+			e := &Encoder{RLE: rle}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := e.Encode(&buf, img); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(buf.Len()), "bytes/image")
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package sixel
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"strings"
+	"testing"
+)
+
+func TestEncoderPalette(t *testing.T) {
+	pal := color.Palette{
+		color.NRGBA{A: 0xff},
+		color.NRGBA{R: 0xff, A: 0xff},
+		color.NRGBA{B: 0xff, A: 0xff},
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 0xf0, A: 0xff})
+	img.Set(1, 0, color.NRGBA{B: 0xf0, A: 0xff})
+
+	var buf bytes.Buffer
+	if err := (&Encoder{Palette: pal}).Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// The colors are mapped to the closest ones of the palette, and the
+	// whole palette is defined.
+	want := `"1;1;2;1` +
+		`#0;2;0;0;0#1;2;100;0;0#2;2;0;0;100` +
+		`#1@?$#2?@`
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := (&Encoder{Palette: pal, OmitPalette: true}).Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	want = `"1;1;2;1#1@?$#2?@`
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestEncoderPaletteFrames(t *testing.T) {
+	// Frames that already use the shared palette are not quantized again.
+	frame := image.NewPaletted(image.Rect(0, 0, 4, 6), palette.Plan9)
+	for i := range frame.Pix {
+		frame.Pix[i] = uint8(i)
+	}
+	if p := mapToPalette(frame, palette.Plan9, DitherFloydSteinberg); p != frame {
+		t.Error("mapToPalette() copied an image that uses the palette")
+	}
+
+	var buf bytes.Buffer
+	e := &Encoder{Palette: palette.Plan9, OmitPalette: true}
+	if err := e.Encode(&buf, frame); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), ";2;") {
+		t.Errorf("Encode() = %q, want no color definitions", buf.String())
+	}
+}
+
+func TestEncoderPaletteSize(t *testing.T) {
+	pal := make(color.Palette, MaxColors+1)
+	for i := range pal {
+		pal[i] = color.Gray16{Y: uint16(i)}
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	err := (&Encoder{Palette: pal}).Encode(&bytes.Buffer{}, img)
+	if !errors.Is(err, ErrPaletteSize) {
+		t.Errorf("Encode() error = %v, want %v", err, ErrPaletteSize)
+	}
+}
+
+func TestEncoderEmptyPalette(t *testing.T) {
+	// An empty palette is the same as none.
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	img.Set(1, 0, color.NRGBA{B: 0xff, A: 0xff})
+
+	var got, want bytes.Buffer
+	if err := (&Encoder{Palette: color.Palette{}}).Encode(&got, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Encoder{}).Encode(&want, img); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("Encode() = %q, want %q", got.String(), want.String())
+	}
+}
+
+func BenchmarkEncoderPalette(b *testing.B) {
+	img := gradient()
+	frame := quantize(img, DitherNone, 0)
+	cases := []struct {
+		name string
+		e    *Encoder
+		m    image.Image
+	}{
+		{"quantize", &Encoder{RLE: true}, img},
+		{"shared", &Encoder{RLE: true, Palette: frame.Palette, OmitPalette: true}, frame},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := c.e.Encode(&buf, c.m); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(buf.Len()), "bytes/frame")
+		})
+	}
+}
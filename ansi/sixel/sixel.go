@@ -0,0 +1,31 @@
+// Package sixel implements an encoder for the DEC Sixel graphics format.
+//
+// See https://vt100.net/docs/vt3xx-gp/chapter14.html
+package sixel
+
+// MaxColors is the maximum number of color registers used by the encoder.
+const MaxColors = 256
+
+// Sixel control characters.
+const (
+	// ColorIntroducer selects or defines a color register.
+	//
+	//	# Pc ; Pu ; Px ; Py ; Pz
+	ColorIntroducer = '#'
+
+	// RasterAttribute defines the aspect ratio and size of the image.
+	//
+	//	" Pan ; Pad ; Ph ; Pv
+	RasterAttribute = '"'
+
+	// RepeatIntroducer repeats the next sixel character a number of times.
+	//
+	//	! Pn character
+	RepeatIntroducer = '!'
+
+	// CarriageReturn moves back to the beginning of the current sixel line.
+	CarriageReturn = '$'
+
+	// LineBreak moves to the beginning of the next sixel line.
+	LineBreak = '-'
+)
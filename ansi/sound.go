@@ -0,0 +1,48 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PlaySound returns a sequence that plays a sequence of notes using the
+// DECPS (Play Sound) control function. The volume is between 0 (off) and 7
+// (loudest), the duration of each note is in 1/32 of a second between 0 and
+// 255, and the notes are between 0 (silent) and 25, where 1 is C5 and each
+// step is a semitone. Out of range values are clamped.
+//
+//	CSI Pv ; Pd ; Pn ... SP ~
+//
+// Only use this when the terminal supports [capability.PlaySound], other
+// terminals might ignore it or display garbage.
+//
+// See: https://vt100.net/docs/vt520-rm/chapter4.html#S4.10.2
+//
+// [capability.PlaySound]: https://pkg.go.dev/github.com/charmbracelet/x/ansi/capability#PlaySound
+func PlaySound(volume, duration int, notes ...int) string {
+	clamp := func(v, hi int) string {
+		if v < 0 {
+			v = 0
+		} else if v > hi {
+			v = hi
+		}
+		return strconv.Itoa(v)
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[")
+	b.WriteString(clamp(volume, 7)) //nolint:gomnd
+	b.WriteByte(';')
+	b.WriteString(clamp(duration, 255)) //nolint:gomnd
+	for _, n := range notes {
+		b.WriteByte(';')
+		b.WriteString(clamp(n, 25)) //nolint:gomnd
+	}
+	b.WriteString(" ~")
+	return b.String()
+}
+
+// DECPS is an alias for [PlaySound].
+func DECPS(volume, duration int, notes ...int) string {
+	return PlaySound(volume, duration, notes...)
+}
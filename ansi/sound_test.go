@@ -0,0 +1,27 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPlaySound(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"no notes", ansi.PlaySound(3, 8), "\x1b[3;8 ~"},
+		{"notes", ansi.PlaySound(7, 4, 1, 5, 8), "\x1b[7;4;1;5;8 ~"},
+		{"clamped", ansi.PlaySound(9, 300, -1, 30), "\x1b[7;255;0;25 ~"},
+		{"alias", ansi.DECPS(1, 2, 3), "\x1b[1;2;3 ~"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package ansi
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 )
@@ -142,3 +143,79 @@ func ExtendedCursorPositionReport(line, column, page int) string {
 func DECXCPR(line, column, page int) string {
 	return ExtendedCursorPositionReport(line, column, page)
 }
+
+// RequestOperatingStatus is an escape sequence that requests the terminal
+// operating status.
+//
+//	CSI 5 n
+//
+// The terminal responds with "CSI 0 n" when it's ready, or "CSI 3 n" when it
+// has a malfunction. The response can be parsed using
+// [ParseOperatingStatusReport].
+//
+// See: https://vt100.net/docs/vt510-rm/DSR-OS.html
+const RequestOperatingStatus = "\x1b[5n"
+
+// ErrInvalidStatusReport is returned by the status report parsers when the
+// sequence is not a valid status report.
+var ErrInvalidStatusReport = errors.New("invalid status report")
+
+// ParseOperatingStatusReport parses an operating status report, the response
+// to [RequestOperatingStatus]. It returns whether the terminal reported no
+// malfunction.
+//
+//	CSI 0 n
+//	CSI 3 n
+//
+// See: https://vt100.net/docs/vt510-rm/DSR-OS.html
+func ParseOperatingStatusReport(seq string) (ok bool, err error) {
+	cmd, params, valid := csiData(seq)
+	if !valid || cmd != 'n' || len(params) > 1 {
+		return false, ErrInvalidStatusReport
+	}
+	switch status, _, _ := params.Param(0, 0); status {
+	case 0:
+		return true, nil
+	case 3:
+		return false, nil
+	}
+	return false, ErrInvalidStatusReport
+}
+
+// ParseCursorPositionReport parses a cursor position report (CPR) or an
+// extended cursor position report (DECXCPR), the responses to
+// [RequestCursorPositionReport] and [RequestExtendedCursorPositionReport]. It
+// returns the 1-based line and column numbers, and the page number, which is
+// 0 when the report doesn't include it.
+//
+//	CSI Pl ; Pc R
+//	CSI ? Pl ; Pc R
+//	CSI ? Pl ; Pc ; Pp R
+//
+// Note that a CPR report is indistinguishable from a modified F3 key press,
+// "CSI 1 ; Pm R", in some terminals.
+//
+// See: https://vt100.net/docs/vt510-rm/CPR.html
+// See: https://vt100.net/docs/vt510-rm/DECXCPR.html
+func ParseCursorPositionReport(seq string) (line, column, page int, err error) {
+	cmd, params, ok := csiData(seq)
+	if !ok || cmd.Final() != 'R' || cmd.Intermediate() != 0 {
+		return 0, 0, 0, ErrInvalidStatusReport
+	}
+	switch cmd.Prefix() {
+	case 0:
+		if len(params) != 2 {
+			return 0, 0, 0, ErrInvalidStatusReport
+		}
+	case '?':
+		if len(params) != 2 && len(params) != 3 {
+			return 0, 0, 0, ErrInvalidStatusReport
+		}
+		page, _, _ = params.Param(2, 0)
+	default:
+		return 0, 0, 0, ErrInvalidStatusReport
+	}
+	line, _, _ = params.Param(0, 1)
+	column, _, _ = params.Param(1, 1)
+	return line, column, page, nil
+}
@@ -0,0 +1,148 @@
+package ansi
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/x/ansi/parser"
+)
+
+// RequestStatusString (DECRQSS) returns a sequence that requests the current
+// setting of a control function. The terminal responds with a DECRPSS
+// sequence that can be parsed using [ParseStatusString].
+//
+//	DCS $ q Pt ST
+//
+// Where Pt is the intermediate and final characters of the control function,
+// for example "m" for SGR or " q" for DECSCUSR.
+//
+// See: https://vt100.net/docs/vt510-rm/DECRQSS.html
+func RequestStatusString(pt string) string {
+	return "\x1bP$q" + pt + "\x1b\\"
+}
+
+// DECRQSS is an alias for [RequestStatusString].
+func DECRQSS(pt string) string {
+	return RequestStatusString(pt)
+}
+
+// Status string requests for common settings.
+const (
+	// RequestSelectGraphicRendition requests the current graphic rendition
+	// (SGR) attributes.
+	RequestSelectGraphicRendition = "\x1bP$qm\x1b\\"
+
+	// RequestCursorStyle requests the current cursor style (DECSCUSR).
+	RequestCursorStyle = "\x1bP$q q\x1b\\"
+
+	// RequestTopBottomMargins requests the current top and bottom margins
+	// (DECSTBM).
+	RequestTopBottomMargins = "\x1bP$qr\x1b\\"
+
+	// RequestLeftRightMargins requests the current left and right margins
+	// (DECSLRM).
+	RequestLeftRightMargins = "\x1bP$qs\x1b\\"
+)
+
+// ErrInvalidStatusString is returned by [ParseStatusString] when the sequence
+// is not a valid DECRPSS response.
+var ErrInvalidStatusString = errors.New("invalid status string report")
+
+// StatusStringReport is a decoded DECRPSS response. See [ParseStatusString].
+type StatusStringReport struct {
+	// Valid reports whether the terminal recognized the request.
+	Valid bool
+
+	// Cmd is the control function of the setting. Use [Cmd.Final] and
+	// [Cmd.Intermediate] to identify it.
+	Cmd Cmd
+
+	// Params are the parameters of the setting.
+	Params Params
+}
+
+// ParseStatusString parses a DECRPSS response sequence, the response to
+// [RequestStatusString].
+//
+//	DCS 1 $ r Pt ST
+//	DCS 0 $ r ST
+//
+// Where Pt is the setting written as the control function that would set it
+// without its introducer, for example "0;1m" for bold SGR attributes. A "0"
+// means the request was invalid.
+//
+// See: https://vt100.net/docs/vt510-rm/DECRPSS.html
+func ParseStatusString(seq string) (StatusStringReport, error) {
+	var r StatusStringReport
+	if !HasDcsPrefix(seq) ||
+		!(HasSuffix(seq, "\x1b\\") || HasSuffix(seq, "\x9c") || HasSuffix(seq, "\x07")) {
+		return r, ErrInvalidStatusString
+	}
+
+	p := new(Parser)
+	p.SetParamsSize(parser.MaxParamsSize)
+	p.SetDataSize(len(seq))
+	_, _, n, _ := DecodeSequence(seq, NormalState, p)
+	cmd := Cmd(p.Command())
+	if n != len(seq) || cmd.Intermediate() != '$' || cmd.Final() != 'r' {
+		return r, ErrInvalidStatusString
+	}
+	valid, _, _ := p.Params().Param(0, 0)
+	if valid != 1 {
+		return r, nil
+	}
+
+	// Decode the setting as a control sequence.
+	data := "\x1b[" + string(p.Data())
+	p = new(Parser)
+	p.SetParamsSize(parser.MaxParamsSize)
+	_, _, n, _ = DecodeSequence(data, NormalState, p)
+	if n != len(data) || !HasCsiPrefix(data) || p.Command() == 0 {
+		return r, ErrInvalidStatusString
+	}
+
+	r.Valid = true
+	r.Cmd = Cmd(p.Command())
+	r.Params = append(Params(nil), p.Params()...)
+	return r, nil
+}
+
+// SGR returns the graphic rendition of an SGR report.
+func (r StatusStringReport) SGR() (SgrState, bool) {
+	var s SgrState
+	if !r.Valid || r.Cmd != 'm' {
+		return s, false
+	}
+	s.Apply(r.Params)
+	return s, true
+}
+
+// CursorStyle returns the cursor style of a DECSCUSR report.
+func (r StatusStringReport) CursorStyle() (CursorStyle, bool) {
+	if !r.Valid || r.Cmd.Final() != 'q' || r.Cmd.Intermediate() != ' ' {
+		return 0, false
+	}
+	style, _, _ := r.Params.Param(0, 1)
+	return CursorStyle(style), true
+}
+
+// TopBottomMargins returns the 1-based top and bottom margins of a DECSTBM
+// report.
+func (r StatusStringReport) TopBottomMargins() (top, bottom int, ok bool) {
+	if !r.Valid || r.Cmd != 'r' {
+		return 0, 0, false
+	}
+	top, _, _ = r.Params.Param(0, 1)
+	bottom, _, _ = r.Params.Param(1, 0)
+	return top, bottom, true
+}
+
+// LeftRightMargins returns the 1-based left and right margins of a DECSLRM
+// report.
+func (r StatusStringReport) LeftRightMargins() (left, right int, ok bool) {
+	if !r.Valid || r.Cmd != 's' {
+		return 0, 0, false
+	}
+	left, _, _ = r.Params.Param(0, 1)
+	right, _, _ = r.Params.Param(1, 0)
+	return left, right, true
+}
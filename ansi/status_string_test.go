@@ -0,0 +1,90 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRequestStatusString(t *testing.T) {
+	if got, want := ansi.RequestStatusString("m"), ansi.RequestSelectGraphicRendition; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := ansi.DECRQSS(" q"), ansi.RequestCursorStyle; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestParseStatusString(t *testing.T) {
+	t.Run("sgr", func(t *testing.T) {
+		r, err := ansi.ParseStatusString("\x1bP1$r0;1;4:3;38;2;255;0;0m\x1b\\")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s, ok := r.SGR()
+		want := ansi.SgrState{Bold: true, Underline: ansi.CurlyUnderlineStyle, Foreground: ansi.XParseColor("#ff0000")}
+		if !ok || !s.Equal(want) {
+			t.Errorf("expected %+v, got %+v (%v)", want, s, ok)
+		}
+		if _, ok := r.CursorStyle(); ok {
+			t.Error("expected not a cursor style report")
+		}
+	})
+
+	t.Run("cursor style", func(t *testing.T) {
+		r, err := ansi.ParseStatusString("\x1bP1$r2 q\x1b\\")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if style, ok := r.CursorStyle(); !ok || style != 2 {
+			t.Errorf("expected cursor style 2, got %d (%v)", style, ok)
+		}
+	})
+
+	t.Run("top bottom margins", func(t *testing.T) {
+		r, err := ansi.ParseStatusString("\x1bP1$r3;20r\x1b\\")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if top, bottom, ok := r.TopBottomMargins(); !ok || top != 3 || bottom != 20 {
+			t.Errorf("expected margins 3;20, got %d;%d (%v)", top, bottom, ok)
+		}
+		if _, _, ok := r.LeftRightMargins(); ok {
+			t.Error("expected not a left right margins report")
+		}
+	})
+
+	t.Run("left right margins", func(t *testing.T) {
+		r, err := ansi.ParseStatusString("\x901$r1;80s\x9c")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if left, right, ok := r.LeftRightMargins(); !ok || left != 1 || right != 80 {
+			t.Errorf("expected margins 1;80, got %d;%d (%v)", left, right, ok)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		r, err := ansi.ParseStatusString("\x1bP0$r\x1b\\")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Valid {
+			t.Error("expected an invalid request report")
+		}
+		if _, ok := r.SGR(); ok {
+			t.Error("expected no SGR state")
+		}
+	})
+
+	for _, seq := range []string{
+		"\x1bP1+r5463\x1b\\", // XTGETTCAP
+		"\x1b[1$r",
+		"\x1bP1$r\x1b\\",
+		"\x1bP1$r0;1m",
+	} {
+		if _, err := ansi.ParseStatusString(seq); err != ansi.ErrInvalidStatusString {
+			t.Errorf("ParseStatusString(%q) error = %v, want %v", seq, err, ansi.ErrInvalidStatusString)
+		}
+	}
+}
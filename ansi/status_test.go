@@ -0,0 +1,53 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseCursorPositionReport(t *testing.T) {
+	cases := []struct {
+		seq                string
+		line, column, page int
+		err                error
+	}{
+		{ansi.CursorPositionReport(3, 12), 3, 12, 0, nil},
+		{ansi.ExtendedCursorPositionReport(3, 12, 0), 3, 12, 0, nil},
+		{ansi.ExtendedCursorPositionReport(3, 12, 2), 3, 12, 2, nil},
+		{"\x1b[;R", 1, 1, 0, nil},
+		{"\x1b[3R", 0, 0, 0, ansi.ErrInvalidStatusReport},
+		{"\x1b[3;12;1R", 0, 0, 0, ansi.ErrInvalidStatusReport},
+		{"\x1b[>3;12R", 0, 0, 0, ansi.ErrInvalidStatusReport},
+		{"\x1b[3;12", 0, 0, 0, ansi.ErrInvalidStatusReport},
+		{"\x1b[0n", 0, 0, 0, ansi.ErrInvalidStatusReport},
+	}
+	for _, tc := range cases {
+		line, column, page, err := ansi.ParseCursorPositionReport(tc.seq)
+		if err != tc.err || line != tc.line || column != tc.column || page != tc.page {
+			t.Errorf("ParseCursorPositionReport(%q) = %d, %d, %d, %v, want %d, %d, %d, %v",
+				tc.seq, line, column, page, err, tc.line, tc.column, tc.page, tc.err)
+		}
+	}
+}
+
+func TestParseOperatingStatusReport(t *testing.T) {
+	cases := []struct {
+		seq string
+		ok  bool
+		err error
+	}{
+		{"\x1b[0n", true, nil},
+		{"\x1b[3n", false, nil},
+		{"\x1b[n", true, nil},
+		{"\x1b[5n", false, ansi.ErrInvalidStatusReport},
+		{"\x1b[?0n", false, ansi.ErrInvalidStatusReport},
+		{"\x1b[1;1R", false, ansi.ErrInvalidStatusReport},
+	}
+	for _, tc := range cases {
+		ok, err := ansi.ParseOperatingStatusReport(tc.seq)
+		if ok != tc.ok || err != tc.err {
+			t.Errorf("ParseOperatingStatusReport(%q) = %v, %v, want %v, %v", tc.seq, ok, err, tc.ok, tc.err)
+		}
+	}
+}
@@ -459,28 +459,59 @@ func backgroundColorString(c Color) string {
 // color.
 // See: https://en.wikipedia.org/wiki/ANSI_escape_code#SGR_(Select_Graphic_Rendition)_parameters
 func underlineColorString(c Color) string {
+	// NOTE: underline colors use colon separated subparameters so that
+	// terminals that don't support them ignore the whole attribute instead of
+	// interpreting the color values as other attributes.
 	switch c := c.(type) {
 	// NOTE: we can't use 3-bit and 4-bit ANSI color codes with underline
 	// color, use 256-color instead.
 	//
 	// 256-color ANSI underline color
-	// "58;5;<n>"
+	// "58:5:<n>"
 	case BasicColor:
-		return "58;5;" + strconv.FormatUint(uint64(c), 10)
+		return "58:5:" + strconv.FormatUint(uint64(c), 10)
 	case ExtendedColor:
-		return "58;5;" + strconv.FormatUint(uint64(c), 10)
+		return "58:5:" + strconv.FormatUint(uint64(c), 10)
 	case TrueColor, color.Color:
-		// 24-bit "true color" foreground
-		// "38;2;<r>;<g>;<b>"
+		// 24-bit "true color" underline color with an empty color space id
+		// "58:2::<r>:<g>:<b>"
 		r, g, b, _ := c.RGBA()
-		return "58;2;" +
-			strconv.FormatUint(uint64(shift(r)), 10) + ";" +
-			strconv.FormatUint(uint64(shift(g)), 10) + ";" +
+		return "58:2::" +
+			strconv.FormatUint(uint64(shift(r)), 10) + ":" +
+			strconv.FormatUint(uint64(shift(g)), 10) + ":" +
 			strconv.FormatUint(uint64(shift(b)), 10)
 	}
 	return defaultUnderlineColorAttr
 }
 
+// DecodeSgrColor decodes the color of an SGR 38, 48, or 58 parameter group
+// at the start of params, for example "38;2;r;g;b" or "38:2::r:g:b". It
+// returns the color and the number of parameters read, or a nil color and 0
+// if the parameters are not a valid color. An implementation defined color,
+// "38;0", is read as a nil color.
+//
+// See [ReadStyleColor] for the supported color types and forms.
+func DecodeSgrColor(params Params) (color.Color, int) {
+	var c color.Color
+	n := ReadStyleColor(params, &c)
+	return c, n
+}
+
+// ReadUnderlineStyle reads the underline style of an SGR 4 parameter group,
+// see [Params.Group]. A group without sub-parameters is a single underline,
+// and "4:n" selects the underline style n. It returns false for an unknown
+// style, which must be ignored.
+func ReadUnderlineStyle(g Params) (UnderlineStyle, bool) {
+	if len(g) < 2 { //nolint:gomnd
+		return SingleUnderlineStyle, true
+	}
+	style := g[1].Param(0)
+	if style < int(NoUnderlineStyle) || style > int(DashedUnderlineStyle) {
+		return NoUnderlineStyle, false
+	}
+	return UnderlineStyle(style), true
+}
+
 // ReadStyleColor decodes a color from a slice of parameters. It returns the
 // number of parameters read and the color. This function is used to read SGR
 // color parameters following the ITU T.416 standard.
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/parser"
 )
 
 func TestReset(t *testing.T) {
@@ -46,6 +47,32 @@ func TestColorColor(t *testing.T) {
 	}
 }
 
+func TestUnderlineColor(t *testing.T) {
+	tests := []struct {
+		c    ansi.Color
+		want string
+	}{
+		{ansi.Red, "\x1b[4:3;58:5:1m"},
+		{ansi.ExtendedColor(196), "\x1b[4:3;58:5:196m"},
+		{color.RGBA{R: 0xff, G: 0x80, A: 0xff}, "\x1b[4:3;58:2::255:128:0m"},
+	}
+	for _, tt := range tests {
+		s := ansi.Style{}.CurlyUnderline().UnderlineColor(tt.c)
+		if s.String() != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, s)
+		}
+
+		// The colon form must round-trip through the SGR state parser.
+		var st ansi.SgrState
+		if !st.Consume(s.String()) || st.Underline != ansi.CurlyUnderlineStyle || st.UnderlineColor == nil {
+			t.Fatalf("Consume(%q) = %+v", s, st)
+		}
+		if st.Sequence() != tt.want {
+			t.Errorf("expected round-trip %q, got %q", tt.want, st.Sequence())
+		}
+	}
+}
+
 func BenchmarkStyle(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -56,3 +83,56 @@ func BenchmarkStyle(b *testing.B) {
 			String()
 	}
 }
+
+func TestDecodeSgrColor(t *testing.T) {
+	cases := []struct {
+		seq  string
+		want color.Color
+		n    int
+	}{
+		{"\x1b[38;2;1;2;3m", color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, 5},
+		{"\x1b[38:2:1:2:3m", color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, 5},
+		{"\x1b[38:2::1:2:3m", color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, 6},
+		{"\x1b[48:2:7:1:2:3m", color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, 6},
+		{"\x1b[58:2::1:2:3::0:0m", color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, 9},
+		{"\x1b[38;5;196m", ansi.ExtendedColor(196), 3},
+		{"\x1b[38:5:196m", ansi.ExtendedColor(196), 3},
+		{"\x1b[48:3::10:20:30m", color.CMYK{C: 10, M: 20, Y: 30}, 6},
+		{"\x1b[48:4::10:20:30:40m", color.CMYK{C: 10, M: 20, Y: 30, K: 40}, 7},
+		{"\x1b[38:6::1:2:3:4m", color.RGBA{R: 1, G: 2, B: 3, A: 4}, 7},
+		{"\x1b[38;1m", color.Transparent, 2},
+		{"\x1b[38;0m", nil, 2},
+		{"\x1b[38;2;1;2m", nil, 0},
+		{"\x1b[38;5:196m", nil, 0},
+		{"\x1b[38;9;1m", nil, 0},
+		{"\x1b[38m", nil, 0},
+	}
+	for _, tc := range cases {
+		p := ansi.NewParser()
+		ansi.DecodeSequence(tc.seq, ansi.NormalState, p)
+		c, n := ansi.DecodeSgrColor(p.Params())
+		if c != tc.want || n != tc.n {
+			t.Errorf("DecodeSgrColor(%q) = %v, %d, want %v, %d", tc.seq, c, n, tc.want, tc.n)
+		}
+	}
+}
+
+func TestReadUnderlineStyle(t *testing.T) {
+	tests := []struct {
+		params ansi.Params
+		want   ansi.UnderlineStyle
+		ok     bool
+	}{
+		{ansi.Params{4}, ansi.SingleUnderlineStyle, true},
+		{ansi.Params{4 | parser.HasMoreFlag, 0}, ansi.NoUnderlineStyle, true},
+		{ansi.Params{4 | parser.HasMoreFlag, 3}, ansi.CurlyUnderlineStyle, true},
+		{ansi.Params{4 | parser.HasMoreFlag, 5}, ansi.DashedUnderlineStyle, true},
+		{ansi.Params{4 | parser.HasMoreFlag, 9}, ansi.NoUnderlineStyle, false},
+	}
+	for _, tt := range tests {
+		got, ok := ansi.ReadUnderlineStyle(tt.params)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ReadUnderlineStyle(%v) = %d, %v, want %d, %v", tt.params, got, ok, tt.want, tt.ok)
+		}
+	}
+}
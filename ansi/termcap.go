@@ -2,6 +2,7 @@ package ansi
 
 import (
 	"encoding/hex"
+	"errors"
 	"strings"
 )
 
@@ -39,3 +40,56 @@ func RequestTermcap(caps ...string) string {
 func RequestTerminfo(caps ...string) string {
 	return XTGETTCAP(caps...)
 }
+
+// ErrInvalidTermcap is returned by [ParseTermcap] when the sequence is not a
+// valid XTGETTCAP response.
+var ErrInvalidTermcap = errors.New("invalid termcap response")
+
+// ParseTermcap parses an XTGETTCAP response sequence, the response to
+// [RequestTermcap]. It returns the capabilities, mapping their names to their
+// values, and whether the terminal recognized them. Boolean capabilities have
+// an empty value.
+//
+//	DCS 1 + r <Pt> ST
+//	DCS 0 + r <Pt> ST
+//
+// Where <Pt> is a list of "name=value" pairs encoded in hexadecimal and
+// separated by semicolons. A "1" means the capabilities are valid, a "0"
+// means the terminal doesn't recognize them, in which case <Pt> only lists
+// their names.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Device-Control-functions
+func ParseTermcap(seq string) (caps map[string]string, valid bool, err error) {
+	seq, ok := dcsData(seq)
+	if !ok {
+		return nil, false, ErrInvalidTermcap
+	}
+
+	switch {
+	case strings.HasPrefix(seq, "1+r"):
+		valid = true
+	case strings.HasPrefix(seq, "0+r"):
+	default:
+		return nil, false, ErrInvalidTermcap
+	}
+	seq = seq[3:]
+
+	caps = make(map[string]string)
+	if seq == "" {
+		return caps, valid, nil
+	}
+	for _, pair := range strings.Split(seq, ";") {
+		hname, hvalue, _ := strings.Cut(pair, "=")
+		name, err := hex.DecodeString(hname)
+		if err != nil || len(name) == 0 {
+			return nil, false, ErrInvalidTermcap
+		}
+		value, err := hex.DecodeString(hvalue)
+		if err != nil {
+			return nil, false, ErrInvalidTermcap
+		}
+		caps[string(name)] = string(value)
+	}
+
+	return caps, valid, nil
+}
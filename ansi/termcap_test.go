@@ -0,0 +1,84 @@
+package ansi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRequestTermcap(t *testing.T) {
+	if got, want := ansi.RequestTermcap("Tc", "Smulx"), "\x1bP+q5463;536D756C78\x1b\\"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := ansi.RequestTermcap(); got != "" {
+		t.Errorf("expected empty request, got %q", got)
+	}
+}
+
+func TestParseTermcap(t *testing.T) {
+	cases := []struct {
+		name  string
+		seq   string
+		caps  map[string]string
+		valid bool
+		err   error
+	}{
+		{
+			name:  "boolean",
+			seq:   "\x1bP1+r5463\x1b\\",
+			caps:  map[string]string{"Tc": ""},
+			valid: true,
+		},
+		{
+			name:  "values",
+			seq:   "\x1bP1+r636f=3830;524742=382f382f38\x1b\\",
+			caps:  map[string]string{"co": "80", "RGB": "8/8/8"},
+			valid: true,
+		},
+		{
+			name:  "lowercase hex and C1",
+			seq:   "\x901+r736d756c78=1b5b343a25703125646d\x9c",
+			caps:  map[string]string{"smulx": "\x1b[4:%p1%dm"},
+			valid: true,
+		},
+		{
+			name: "unknown",
+			seq:  "\x1bP0+r78797a\x1b\\",
+			caps: map[string]string{"xyz": ""},
+		},
+		{
+			name: "empty unknown",
+			seq:  "\x1bP0+r\x1b\\",
+			caps: map[string]string{},
+		},
+		{name: "not termcap", seq: "\x1bP1$r0m\x1b\\", err: ansi.ErrInvalidTermcap},
+		{name: "bad hex", seq: "\x1bP1+r5x63\x1b\\", err: ansi.ErrInvalidTermcap},
+		{name: "unterminated", seq: "\x1bP1+r5463", err: ansi.ErrInvalidTermcap},
+		{name: "not dcs", seq: "\x1b]1+r5463\x07", err: ansi.ErrInvalidTermcap},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			caps, valid, err := ansi.ParseTermcap(c.seq)
+			if err != c.err {
+				t.Fatalf("expected error %v, got %v", c.err, err)
+			}
+			if valid != c.valid || !reflect.DeepEqual(caps, c.caps) {
+				t.Errorf("expected (%v, %v), got (%v, %v)", c.caps, c.valid, caps, valid)
+			}
+		})
+	}
+}
+
+func TestParseTermcapRoundTrip(t *testing.T) {
+	req := ansi.RequestTermcap("Tc", "RGB")
+	// A terminal echoes the requested names with their values.
+	resp := "\x1bP1+r" + req[4:len(req)-2] + "\x1b\\"
+	caps, valid, err := ansi.ParseTermcap(resp)
+	if err != nil || !valid {
+		t.Fatalf("unexpected result (%v, %v)", valid, err)
+	}
+	if want := map[string]string{"Tc": "", "RGB": ""}; !reflect.DeepEqual(caps, want) {
+		t.Errorf("expected %v, got %v", want, caps)
+	}
+}
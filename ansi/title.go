@@ -1,32 +1,81 @@
 package ansi
 
+import "unicode/utf8"
+
 // SetIconNameWindowTitle returns a sequence for setting the icon name and
-// window title.
+// window title. Control characters are removed from the title, since they
+// would end the sequence or be interpreted by the terminal.
 //
 //	OSC 0 ; title ST
 //	OSC 0 ; title BEL
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Operating-System-Commands
 func SetIconNameWindowTitle(s string) string {
-	return "\x1b]0;" + s + "\x07"
+	return "\x1b]0;" + sanitizeTitle(s) + "\x07"
 }
 
-// SetIconName returns a sequence for setting the icon name.
+// SetIconName returns a sequence for setting the icon name. Control
+// characters are removed from the name.
 //
 //	OSC 1 ; title ST
 //	OSC 1 ; title BEL
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Operating-System-Commands
 func SetIconName(s string) string {
-	return "\x1b]1;" + s + "\x07"
+	return "\x1b]1;" + sanitizeTitle(s) + "\x07"
 }
 
-// SetWindowTitle returns a sequence for setting the window title.
+// SetWindowTitle returns a sequence for setting the window title. Control
+// characters are removed from the title.
 //
 //	OSC 2 ; title ST
 //	OSC 2 ; title BEL
 //
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h2-Operating-System-Commands
 func SetWindowTitle(s string) string {
-	return "\x1b]2;" + s + "\x07"
+	return "\x1b]2;" + sanitizeTitle(s) + "\x07"
+}
+
+// Title stack sequences. They save and restore the icon name, the window
+// title, or both, using the [PushTitleWinOp] and [PopTitleWinOp] window
+// operations. Push the title before changing it, and pop it on exit to
+// restore it.
+//
+//	CSI 22 ; Ps t
+//	CSI 23 ; Ps t
+//
+// Where Ps is 0 for both, 1 for the icon name, and 2 for the window title.
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h4-Functions-using-CSI-_-ordered-by-the-final-character-lparen-s-rparen:CSI-Ps;Ps;Ps-t.1EB0
+const (
+	PushIconNameWindowTitle = "\x1b[22;0t"
+	PushIconName            = "\x1b[22;1t"
+	PushWindowTitle         = "\x1b[22;2t"
+	PopIconNameWindowTitle  = "\x1b[23;0t"
+	PopIconName             = "\x1b[23;1t"
+	PopWindowTitle          = "\x1b[23;2t"
+)
+
+// sanitizeTitle removes the C0, DEL, and C1 control characters from s, both
+// as raw bytes and as UTF-8 encoded runes, along with any invalid UTF-8. A raw
+// 8-bit C1 byte such as ST (0x9C) would otherwise end the sequence on
+// terminals that accept 8-bit controls.
+func sanitizeTitle(s string) string {
+	var b []byte
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && n == 1) || r < ' ' || (r >= DEL && r <= 0x9f) {
+			if b == nil {
+				b = make([]byte, i, len(s))
+				copy(b, s[:i])
+			}
+		} else if b != nil {
+			b = append(b, s[i:i+n]...)
+		}
+		i += n
+	}
+	if b == nil {
+		return s
+	}
+	return string(b)
 }
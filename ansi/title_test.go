@@ -23,3 +23,47 @@ func TestSetWindowTitle(t *testing.T) {
 		t.Errorf("expected: %q, got: %q", "\x1b]2;hello\x07", ansi.SetWindowTitle("hello"))
 	}
 }
+
+func TestSetWindowTitleControls(t *testing.T) {
+	// Controls would end the sequence early or be interpreted.
+	got := ansi.SetWindowTitle("evil\x07\x1b]0;pwned\x1b\\ \u009dtitle\t✓")
+	if want := "\x1b]2;evil]0;pwned\\ title✓\x07"; got != want {
+		t.Errorf("expected: %q, got: %q", want, got)
+	}
+	if got, want := ansi.SetIconName("a\nb"), "\x1b]1;ab\x07"; got != want {
+		t.Errorf("expected: %q, got: %q", want, got)
+	}
+	if got, want := ansi.SetIconNameWindowTitle("a\x7fb"), "\x1b]0;ab\x07"; got != want {
+		t.Errorf("expected: %q, got: %q", want, got)
+	}
+}
+
+func TestSetWindowTitleRawC1(t *testing.T) {
+	// Raw 8-bit C1 bytes and invalid UTF-8 are removed too, since terminals
+	// that accept 8-bit controls would read ST and CSI here.
+	for s, want := range map[string]string{
+		"a\x9c\x9b2J":      "\x1b]2;a2J\x07",
+		"a\x90b\x9dc\x9fd": "\x1b]2;abcd\x07",
+		"\xc3\x9c\xff✓":    "\x1b]2;Ü✓\x07",
+		"\xe4\xb8":         "\x1b]2;\x07",
+	} {
+		if got := ansi.SetWindowTitle(s); got != want {
+			t.Errorf("SetWindowTitle(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestTitleStack(t *testing.T) {
+	for seq, want := range map[string]string{
+		ansi.PushIconNameWindowTitle: ansi.WindowOp(ansi.PushTitleWinOp, 0),
+		ansi.PushIconName:            ansi.WindowOp(ansi.PushTitleWinOp, 1),
+		ansi.PushWindowTitle:         ansi.WindowOp(ansi.PushTitleWinOp, 2),
+		ansi.PopIconNameWindowTitle:  ansi.WindowOp(ansi.PopTitleWinOp, 0),
+		ansi.PopIconName:             ansi.WindowOp(ansi.PopTitleWinOp, 1),
+		ansi.PopWindowTitle:          ansi.WindowOp(ansi.PopTitleWinOp, 2),
+	} {
+		if seq != want {
+			t.Errorf("expected: %q, got: %q", want, seq)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/x/ansi/parser"
 	"github.com/lucasb-eyer/go-colorful"
 )
 
@@ -65,32 +66,48 @@ func XParseColor(s string) color.Color {
 
 		return c
 	case strings.HasPrefix(s, "rgb:"):
-		parts := strings.Split(s[4:], "/")
-		if len(parts) != 3 {
+		c, ok := parseXColorComponents(s[4:], 3)
+		if !ok {
 			return nil
 		}
 
-		r, _ := strconv.ParseUint(parts[0], 16, 32)
-		g, _ := strconv.ParseUint(parts[1], 16, 32)
-		b, _ := strconv.ParseUint(parts[2], 16, 32)
-
-		return color.RGBA{uint8(shift(r)), uint8(shift(g)), uint8(shift(b)), 255} //nolint:gosec
+		return color.RGBA{c[0], c[1], c[2], 255}
 	case strings.HasPrefix(s, "rgba:"):
-		parts := strings.Split(s[5:], "/")
-		if len(parts) != 4 {
+		c, ok := parseXColorComponents(s[5:], 4)
+		if !ok {
 			return nil
 		}
 
-		r, _ := strconv.ParseUint(parts[0], 16, 32)
-		g, _ := strconv.ParseUint(parts[1], 16, 32)
-		b, _ := strconv.ParseUint(parts[2], 16, 32)
-		a, _ := strconv.ParseUint(parts[3], 16, 32)
-
-		return color.RGBA{uint8(shift(r)), uint8(shift(g)), uint8(shift(b)), uint8(shift(a))} //nolint:gosec
+		return color.RGBA{c[0], c[1], c[2], c[3]}
 	}
 	return nil
 }
 
+// parseXColorComponents parses n slash separated hexadecimal color
+// components. Each component has 1 to 4 hex digits and is scaled to 8 bits,
+// so "f", "ff", and "ffff" all mean full intensity.
+func parseXColorComponents(s string, n int) ([]uint8, bool) {
+	parts := strings.Split(s, "/")
+	if len(parts) != n {
+		return nil, false
+	}
+
+	c := make([]uint8, n)
+	for i, p := range parts {
+		if len(p) < 1 || len(p) > 4 {
+			return nil, false
+		}
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil {
+			return nil, false
+		}
+		maxv := uint64(1)<<(4*len(p)) - 1
+		c[i] = uint8((v*0xff + maxv/2) / maxv) //nolint:gosec
+	}
+
+	return c, true
+}
+
 type ordered interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 |
 		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
@@ -104,3 +121,39 @@ func max[T ordered](a, b T) T { //nolint:predeclared
 	}
 	return b
 }
+
+// dcsData returns the data of a DCS sequence, that is the sequence without its
+// introducer and terminator. It reports false when the sequence is not a DCS
+// sequence.
+func dcsData(seq string) (string, bool) {
+	switch {
+	case strings.HasPrefix(seq, "\x1bP"):
+		seq = seq[2:]
+	case strings.HasPrefix(seq, "\x90"):
+		seq = seq[1:]
+	default:
+		return "", false
+	}
+	switch {
+	case strings.HasSuffix(seq, "\x1b\\"):
+		return seq[:len(seq)-2], true
+	case strings.HasSuffix(seq, "\x9c"), strings.HasSuffix(seq, "\x07"):
+		return seq[:len(seq)-1], true
+	}
+	return "", false
+}
+
+// csiData decodes a CSI sequence and returns its command and parameters. It
+// reports false when the sequence is not a single complete CSI sequence.
+func csiData(seq string) (Cmd, Params, bool) {
+	if !HasCsiPrefix(seq) {
+		return 0, nil, false
+	}
+	p := new(Parser)
+	p.SetParamsSize(parser.MaxParamsSize)
+	_, _, n, _ := DecodeSequence(seq, NormalState, p)
+	if n != len(seq) || p.Command() == 0 {
+		return 0, nil, false
+	}
+	return Cmd(p.Command()), p.Params(), true
+}
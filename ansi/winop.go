@@ -1,15 +1,28 @@
 package ansi
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 )
 
 const (
+	// DeiconifyWindowWinOp is a window operation that de-iconifies
+	// (restores) the terminal window.
+	DeiconifyWindowWinOp = 1
+
+	// IconifyWindowWinOp is a window operation that iconifies (minimizes)
+	// the terminal window.
+	IconifyWindowWinOp = 2
+
 	// ResizeWindowWinOp is a window operation that resizes the terminal
 	// window.
 	ResizeWindowWinOp = 4
 
+	// ResizeTextAreaWinOp is a window operation that resizes the terminal
+	// text area in characters.
+	ResizeTextAreaWinOp = 8
+
 	// RequestWindowSizeWinOp is a window operation that requests a report of
 	// the size of the terminal window in pixels. The response is in the form:
 	//  CSI 4 ; height ; width t
@@ -19,6 +32,22 @@ const (
 	// the size of the terminal cell size in pixels. The response is in the form:
 	//  CSI 6 ; height ; width t
 	RequestCellSizeWinOp = 16
+
+	// RequestTextAreaSizeWinOp is a window operation that requests a report
+	// of the size of the terminal text area in characters. The response is in
+	// the form:
+	//  CSI 8 ; height ; width t
+	RequestTextAreaSizeWinOp = 18
+
+	// PushTitleWinOp is a window operation that saves the icon name, the
+	// window title, or both on a stack. The second parameter is 0 for both,
+	// 1 for the icon name, and 2 for the window title.
+	PushTitleWinOp = 22
+
+	// PopTitleWinOp is a window operation that restores the icon name, the
+	// window title, or both from the stack. It takes the same parameter as
+	// [PushTitleWinOp].
+	PopTitleWinOp = 23
 )
 
 // WindowOp (XTWINOPS) is a sequence that manipulates the terminal window.
@@ -51,3 +80,102 @@ func WindowOp(p int, ps ...int) string {
 func XTWINOPS(p int, ps ...int) string {
 	return WindowOp(p, ps...)
 }
+
+// Window operations.
+const (
+	// DeiconifyWindow de-iconifies (restores) the terminal window.
+	DeiconifyWindow = "\x1b[1t"
+
+	// IconifyWindow iconifies (minimizes) the terminal window.
+	IconifyWindow = "\x1b[2t"
+
+	// RequestTextAreaPixelSize requests the size of the terminal text area in
+	// pixels. The response can be parsed using [ParseTextAreaPixelSizeReport].
+	RequestTextAreaPixelSize = "\x1b[14t"
+
+	// RequestCellSize requests the size of a terminal cell in pixels. The
+	// response can be parsed using [ParseCellSizeReport].
+	RequestCellSize = "\x1b[16t"
+
+	// RequestTextAreaSize requests the size of the terminal text area in
+	// characters. The response can be parsed using
+	// [ParseTextAreaSizeReport].
+	RequestTextAreaSize = "\x1b[18t"
+)
+
+// ResizeWindow returns a sequence that resizes the terminal window to the
+// given width and height in pixels. A negative value keeps the current size
+// and 0 uses the size of the display.
+//
+//	CSI 4 ; height ; width t
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h4-Functions-using-CSI-_-ordered-by-the-final-character-lparen-s-rparen:CSI-Ps;Ps;Ps-t.1EB0
+func ResizeWindow(width, height int) string {
+	return resizeWinOp(ResizeWindowWinOp, width, height)
+}
+
+// ResizeTextArea returns a sequence that resizes the terminal text area to
+// the given width and height in characters. A negative value keeps the
+// current size and 0 uses the size of the display.
+//
+//	CSI 8 ; height ; width t
+//
+// See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h4-Functions-using-CSI-_-ordered-by-the-final-character-lparen-s-rparen:CSI-Ps;Ps;Ps-t.1EB0
+func ResizeTextArea(width, height int) string {
+	return resizeWinOp(ResizeTextAreaWinOp, width, height)
+}
+
+// resizeWinOp returns a resize window operation sequence. Negative sizes are
+// omitted from the sequence.
+func resizeWinOp(op, width, height int) string {
+	var h, w string
+	if height >= 0 {
+		h = strconv.Itoa(height)
+	}
+	if width >= 0 {
+		w = strconv.Itoa(width)
+	}
+	return "\x1b[" + strconv.Itoa(op) + ";" + h + ";" + w + "t"
+}
+
+// ErrInvalidWindowReport is returned when a sequence is not a valid window
+// operation report.
+var ErrInvalidWindowReport = errors.New("invalid window report")
+
+// ParseTextAreaPixelSizeReport parses the response to
+// [RequestTextAreaPixelSize]. It returns the text area size in pixels.
+//
+//	CSI 4 ; height ; width t
+func ParseTextAreaPixelSizeReport(seq string) (width, height int, err error) {
+	return parseWindowReport(seq, 4)
+}
+
+// ParseCellSizeReport parses the response to [RequestCellSize]. It returns
+// the cell size in pixels.
+//
+//	CSI 6 ; height ; width t
+func ParseCellSizeReport(seq string) (width, height int, err error) {
+	return parseWindowReport(seq, 6)
+}
+
+// ParseTextAreaSizeReport parses the response to [RequestTextAreaSize]. It
+// returns the text area size in characters.
+//
+//	CSI 8 ; height ; width t
+func ParseTextAreaSizeReport(seq string) (width, height int, err error) {
+	return parseWindowReport(seq, 8)
+}
+
+// parseWindowReport parses a "CSI op ; height ; width t" window report.
+func parseWindowReport(seq string, op int) (width, height int, err error) {
+	cmd, params, ok := csiData(seq)
+	if !ok || cmd != 't' || len(params) != 3 {
+		return 0, 0, ErrInvalidWindowReport
+	}
+	if p, _, _ := params.Param(0, 0); p != op {
+		return 0, 0, ErrInvalidWindowReport
+	}
+	height, _, _ = params.Param(1, 0)
+	width, _, _ = params.Param(2, 0)
+	return width, height, nil
+}
@@ -0,0 +1,49 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestResizeWindow(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{ansi.ResizeWindow(800, 600), "\x1b[4;600;800t"},
+		{ansi.ResizeWindow(-1, 600), "\x1b[4;600;t"},
+		{ansi.ResizeTextArea(80, 24), "\x1b[8;24;80t"},
+		{ansi.ResizeTextArea(0, -1), "\x1b[8;;0t"},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("expected: %q, got: %q", tc.want, tc.got)
+		}
+	}
+}
+
+func TestParseWindowReports(t *testing.T) {
+	cases := []struct {
+		name          string
+		parse         func(string) (int, int, error)
+		seq           string
+		width, height int
+		err           error
+	}{
+		{"text area pixels", ansi.ParseTextAreaPixelSizeReport, "\x1b[4;600;800t", 800, 600, nil},
+		{"cell size", ansi.ParseCellSizeReport, "\x1b[6;18;9t", 9, 18, nil},
+		{"text area", ansi.ParseTextAreaSizeReport, "\x1b[8;24;80t", 80, 24, nil},
+		{"wrong report", ansi.ParseCellSizeReport, "\x1b[4;600;800t", 0, 0, ansi.ErrInvalidWindowReport},
+		{"missing width", ansi.ParseTextAreaSizeReport, "\x1b[8;24t", 0, 0, ansi.ErrInvalidWindowReport},
+		{"wrong final", ansi.ParseTextAreaSizeReport, "\x1b[8;24;80T", 0, 0, ansi.ErrInvalidWindowReport},
+		{"trailing data", ansi.ParseTextAreaSizeReport, "\x1b[8;24;80tx", 0, 0, ansi.ErrInvalidWindowReport},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, h, err := tc.parse(tc.seq)
+			if err != tc.err || w != tc.width || h != tc.height {
+				t.Errorf("parse(%q) = %d, %d, %v, want %d, %d, %v", tc.seq, w, h, err, tc.width, tc.height, tc.err)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package ansi
 
-import "strconv"
+import (
+	"errors"
+	"strconv"
+)
 
 // KeyModifierOptions (XTMODKEYS) sets/resets xterm key modifier options.
 //
@@ -80,6 +83,48 @@ const (
 	QueryModifyOtherKeys = "\x1b[?4m"
 )
 
+// ModifyOtherKeysResource is the [KeyModifierOptions] resource of the
+// modifyOtherKeys feature.
+const ModifyOtherKeysResource = 4
+
+// SetModifyOtherKeys returns a sequence that sets the xterm modifyOtherKeys
+// level. A negative level resets modifyOtherKeys to its initial value, like
+// [ResetModifyOtherKeys].
+//
+//	0: Disable modifyOtherKeys.
+//	1: Modify the keys that don't have a well-known behavior.
+//	2: Modify all keys, including the ones with a well-known behavior.
+//
+//	CSI > 4 ; level m
+//
+// See: https://invisible-island.net/xterm/manpage/xterm.html#VT100-Widget-Resources:modifyOtherKeys
+func SetModifyOtherKeys(level int) string {
+	if level < 0 {
+		return ResetModifyOtherKeys
+	}
+	return "\x1b[>4;" + strconv.Itoa(level) + "m"
+}
+
+// ErrInvalidModifyOtherKeysReport is returned by [ParseModifyOtherKeysReport]
+// when the sequence is not a valid modifyOtherKeys report.
+var ErrInvalidModifyOtherKeysReport = errors.New("invalid modifyOtherKeys report")
+
+// ParseModifyOtherKeysReport parses the response to [QueryModifyOtherKeys].
+// It returns the modifyOtherKeys level.
+//
+//	CSI > 4 ; level m
+func ParseModifyOtherKeysReport(seq string) (level int, err error) {
+	cmd, params, ok := csiData(seq)
+	if !ok || cmd != Cmd(Command('>', 0, 'm')) || len(params) > 2 {
+		return 0, ErrInvalidModifyOtherKeysReport
+	}
+	if p, _, _ := params.Param(0, 0); p != ModifyOtherKeysResource {
+		return 0, ErrInvalidModifyOtherKeysReport
+	}
+	level, _, _ = params.Param(1, 0)
+	return level, nil
+}
+
 // ModifyOtherKeys returns a sequence that sets XTerm modifyOtherKeys mode.
 // The mode argument specifies the mode to set.
 //
@@ -92,7 +137,7 @@ const (
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Functions-using-CSI-_-ordered-by-the-final-character_s_
 // See: https://invisible-island.net/xterm/manpage/xterm.html#VT100-Widget-Resources:modifyOtherKeys
 //
-// Deprecated: use [SetModifyOtherKeys1] or [SetModifyOtherKeys2] instead.
+// Deprecated: use [SetModifyOtherKeys] instead.
 func ModifyOtherKeys(mode int) string {
 	return "\x1b[>4;" + strconv.Itoa(mode) + "m"
 }
@@ -0,0 +1,46 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSetModifyOtherKeys(t *testing.T) {
+	cases := []struct {
+		level int
+		want  string
+	}{
+		{0, "\x1b[>4;0m"},
+		{1, ansi.SetModifyOtherKeys1},
+		{2, ansi.SetModifyOtherKeys2},
+		{-1, ansi.ResetModifyOtherKeys},
+	}
+	for _, tc := range cases {
+		if got := ansi.SetModifyOtherKeys(tc.level); got != tc.want {
+			t.Errorf("SetModifyOtherKeys(%d) = %q, want %q", tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestParseModifyOtherKeysReport(t *testing.T) {
+	cases := []struct {
+		seq   string
+		level int
+		err   error
+	}{
+		{"\x1b[>4;2m", 2, nil},
+		{"\x1b[>4;0m", 0, nil},
+		{"\x1b[>4m", 0, nil},
+		{"\x1b[>1;2m", 0, ansi.ErrInvalidModifyOtherKeysReport},
+		{"\x1b[4;2m", 0, ansi.ErrInvalidModifyOtherKeysReport},
+		{"\x1b[?4m", 0, ansi.ErrInvalidModifyOtherKeysReport},
+		{"\x1b[>4;2", 0, ansi.ErrInvalidModifyOtherKeysReport},
+	}
+	for _, tc := range cases {
+		level, err := ansi.ParseModifyOtherKeysReport(tc.seq)
+		if level != tc.level || err != tc.err {
+			t.Errorf("ParseModifyOtherKeysReport(%q) = %d, %v, want %d, %v", tc.seq, level, err, tc.level, tc.err)
+		}
+	}
+}
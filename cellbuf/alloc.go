@@ -0,0 +1,66 @@
+package cellbuf
+
+// CellAllocator allocates the cells stored in a [Buffer]. Use it to control
+// where cell storage comes from, for example, to pre-allocate it and avoid
+// garbage collection pauses while rendering.
+type CellAllocator interface {
+	// NewCell returns a new zero value cell.
+	NewCell() *Cell
+}
+
+// CellArena is a [CellAllocator] that allocates cells in blocks. Allocating a
+// block of cells at once amortizes the cost of allocations and keeps the
+// cells close together in memory.
+//
+// A CellArena is not safe for concurrent use.
+type CellArena struct {
+	cells []Cell
+	block int
+}
+
+var _ CellAllocator = (*CellArena)(nil)
+
+// NewCellArena creates a new cell arena that pre-allocates n cells. When
+// these are used up, the arena allocates new blocks of n cells. Use the
+// screen size, or a multiple of it, to pre-allocate the cells of a full
+// screen. An n less than 1 uses a block size of 1024 cells.
+func NewCellArena(n int) *CellArena {
+	if n < 1 {
+		n = 1024
+	}
+	return &CellArena{
+		cells: make([]Cell, n),
+		block: n,
+	}
+}
+
+// NewCell returns a new zero value cell from the arena.
+func (a *CellArena) NewCell() *Cell {
+	if len(a.cells) == 0 {
+		a.cells = make([]Cell, a.block)
+	}
+	c := &a.cells[0]
+	a.cells = a.cells[1:]
+	return c
+}
+
+// Available returns the number of cells left in the current block.
+func (a *CellArena) Available() int {
+	return len(a.cells)
+}
+
+// allocCell returns a new zero value cell using the given allocator, or the
+// heap when it's nil.
+func allocCell(alloc CellAllocator) *Cell {
+	if alloc == nil {
+		return new(Cell)
+	}
+	return alloc.NewCell()
+}
+
+// cloneCell returns a copy of the cell allocated using the given allocator.
+func cloneCell(alloc CellAllocator, c *Cell) *Cell {
+	n := allocCell(alloc)
+	*n = *c
+	return n
+}
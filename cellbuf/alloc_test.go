@@ -0,0 +1,214 @@
+package cellbuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCellArena(t *testing.T) {
+	a := NewCellArena(2)
+	c1, c2 := a.NewCell(), a.NewCell()
+	if c1 == c2 {
+		t.Fatal("expected distinct cells")
+	}
+	if a.Available() != 0 {
+		t.Errorf("expected no cells left, got %d", a.Available())
+	}
+	c3 := a.NewCell()
+	if c3 == c1 || c3 == c2 || a.Available() != 1 {
+		t.Errorf("expected a new block of cells")
+	}
+	if !c3.Empty() {
+		t.Errorf("expected an empty cell, got %#v", c3)
+	}
+}
+
+// randomBufferOps applies the same random operations to all the buffers.
+func randomBufferOps(r *rand.Rand, n int, bufs ...*Buffer) {
+	cells := []*Cell{
+		nil,
+		NewCell('a'),
+		NewCell('b'),
+		NewCell('世'),
+		{Rune: 'c', Width: 1, Style: Style{Fg: ansi.Red}},
+		{Rune: ' ', Width: 1, Style: Style{Bg: ansi.Blue}},
+	}
+	for i := 0; i < n; i++ {
+		b0 := bufs[0]
+		w, h := b0.Width(), b0.Height()
+		x, y := r.Intn(w), r.Intn(h)
+		c := cells[r.Intn(len(cells))]
+		k := 1 + r.Intn(3)
+		rx, ry := r.Intn(w), r.Intn(h)
+		rect := Rect(rx, ry, 1+r.Intn(w-rx), 1+r.Intn(h-ry))
+		op := r.Intn(10)
+		for _, b := range bufs {
+			switch op {
+			case 0, 1, 2, 3:
+				b.SetCell(x, y, c)
+			case 4:
+				b.FillRect(c, rect)
+			case 5:
+				b.InsertLineRect(y, k, c, rect)
+			case 6:
+				b.DeleteLineRect(y, k, c, rect)
+			case 7:
+				b.InsertCell(x, y, k, c)
+			case 8:
+				b.DeleteCell(x, y, k, c)
+			case 9:
+				b.SetCells(x, y, []*Cell{c, cells[1], c})
+			}
+		}
+	}
+}
+
+func TestBufferAllocator(t *testing.T) {
+	const width, height = 12, 6
+	heap := NewBuffer(width, height)
+	arena := NewBuffer(width, height)
+	arena.SetAllocator(NewCellArena(width * height))
+
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		randomBufferOps(r, 20, heap, arena)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if !cellEqual(heap.Cell(x, y), arena.Cell(x, y)) {
+					t.Fatalf("round %d: cell (%d,%d) = %#v, want %#v",
+						round, x, y, arena.Cell(x, y), heap.Cell(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestBufferAllocatorReusesCells(t *testing.T) {
+	b := NewBuffer(80, 24)
+	b.SetAllocator(NewCellArena(80 * 24))
+	cells := []*Cell{NewCell('a'), NewCell('b')}
+	draw := func(i int) {
+		for y := 0; y < b.Height(); y++ {
+			for x := 0; x < b.Width(); x++ {
+				b.SetCell(x, y, cells[(i+x+y)%len(cells)])
+			}
+		}
+	}
+
+	draw(0)
+	var i int
+	if n := testing.AllocsPerRun(10, func() {
+		i++
+		draw(i)
+		b.Clear()
+	}); n != 0 {
+		t.Errorf("expected no allocations, got %v", n)
+	}
+}
+
+// drawFrame draws an animation frame filling the whole screen.
+func drawFrame(s *Screen, frame int) {
+	w, h := s.Width(), s.Height()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := Cell{Rune: rune('a' + (x+y+frame)%26), Width: 1}
+			c.Style.Fg = ansi.ExtendedColor((x + frame) % 256)
+			s.SetCell(x, y, &c)
+		}
+	}
+}
+
+func TestScreenAllocator(t *testing.T) {
+	var heapOut, arenaOut bytes.Buffer
+	opts := ScreenOptions{Term: "xterm-256color", Width: 20, Height: 5, AltScreen: true}
+	heap := NewScreen(&heapOut, &opts)
+	opts.Allocator = NewCellArena(20 * 5)
+	arena := NewScreen(&arenaOut, &opts)
+
+	for frame := 0; frame < 10; frame++ {
+		for _, s := range []*Screen{heap, arena} {
+			drawFrame(s, frame)
+			if frame%3 == 0 {
+				s.FillRect(nil, Rect(2, 1, 5, 3))
+			}
+			if frame%4 == 0 {
+				s.InsertAbove(fmt.Sprintf("frame %d", frame))
+			}
+			if err := s.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+		}
+		if heapOut.String() != arenaOut.String() {
+			t.Fatalf("frame %d: output = %q, want %q", frame, arenaOut.String(), heapOut.String())
+		}
+		heapOut.Reset()
+		arenaOut.Reset()
+	}
+}
+
+func TestScreenAllocatorResize(t *testing.T) {
+	var heapOut, arenaOut bytes.Buffer
+	opts := ScreenOptions{Term: "xterm-256color", Width: 10, Height: 2}
+	heap := NewScreen(&heapOut, &opts)
+	opts.Allocator = NewCellArena(10 * 4)
+	arena := NewScreen(&arenaOut, &opts)
+
+	steps := []func(s *Screen){
+		func(s *Screen) { s.SetCell(0, 0, &Cell{Rune: 'a', Width: 1}) },
+		func(s *Screen) {
+			s.Resize(10, 4)
+			s.SetCell(4, 2, &Cell{Rune: 'Y', Width: 1})
+		},
+		// The cells of the new lines must not be shared between the buffers,
+		// or the update is lost when the cell is reused.
+		func(s *Screen) { s.SetCell(4, 2, &Cell{Rune: 'Z', Width: 1}) },
+	}
+	for i, step := range steps {
+		for _, s := range []*Screen{heap, arena} {
+			step(s)
+			if err := s.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+		}
+		if heapOut.String() != arenaOut.String() {
+			t.Fatalf("step %d: output = %q, want %q", i, arenaOut.String(), heapOut.String())
+		}
+		heapOut.Reset()
+		arenaOut.Reset()
+	}
+}
+
+// benchmarkScreenFrames renders full screen animation frames, the kind of
+// updates a 60fps animation does.
+func benchmarkScreenFrames(b *testing.B, width, height int, alloc CellAllocator) {
+	s := NewScreen(io.Discard, &ScreenOptions{
+		Term:      "xterm-256color",
+		Width:     width,
+		Height:    height,
+		AltScreen: true,
+		Allocator: alloc,
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		drawFrame(s, i)
+		s.Flush() //nolint:errcheck
+	}
+}
+
+func BenchmarkScreenFrames(b *testing.B) {
+	for _, size := range []struct{ w, h int }{{80, 24}, {200, 60}} {
+		name := fmt.Sprintf("%dx%d", size.w, size.h)
+		b.Run(name+"/heap", func(b *testing.B) {
+			benchmarkScreenFrames(b, size.w, size.h, nil)
+		})
+		b.Run(name+"/arena", func(b *testing.B) {
+			benchmarkScreenFrames(b, size.w, size.h, NewCellArena(size.w*size.h*2))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cellbuf
+
+import (
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+// Annotation represents styled text that is drawn above the screen contents
+// at render time. Annotations don't modify the screen buffer and are useful
+// for debug overlays, IME pre-edit strings, and collaborator cursors.
+type Annotation struct {
+	// Text is the text of the annotation. It shouldn't contain any escape
+	// sequences or control characters. Text that exceeds the screen width is
+	// truncated.
+	Text string
+
+	// Style is the style of the annotation text.
+	Style Style
+
+	// Link is the hyperlink of the annotation text.
+	Link Link
+
+	// Position is the position of the first cell of the annotation.
+	Position
+}
+
+// SetAnnotation adds or replaces the annotation with the given id. The
+// annotation is drawn above the screen contents on the next render.
+func (s *Screen) SetAnnotation(id string, a Annotation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.annotations == nil {
+		s.annotations = make(map[string]Annotation)
+	}
+	if old, ok := s.annotations[id]; ok {
+		s.touchAnnotation(old)
+	}
+	s.annotations[id] = a
+	s.touchAnnotation(a)
+}
+
+// Annotation returns the annotation with the given id and whether it exists.
+func (s *Screen) Annotation(id string) (a Annotation, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok = s.annotations[id]
+	return
+}
+
+// RemoveAnnotation removes the annotation with the given id. The screen
+// contents below the annotation are restored on the next render.
+func (s *Screen) RemoveAnnotation(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.annotations[id]; ok {
+		s.touchAnnotation(a)
+		delete(s.annotations, id)
+	}
+}
+
+// ClearAnnotations removes all the annotations.
+func (s *Screen) ClearAnnotations() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, a := range s.annotations {
+		s.touchAnnotation(a)
+		delete(s.annotations, id)
+	}
+}
+
+// touchAnnotation marks the line of the given annotation as changed.
+func (s *Screen) touchAnnotation(a Annotation) {
+	if a.Y < 0 || a.Y >= s.newbuf.Height() {
+		return
+	}
+	s.touch[a.Y] = lineData{firstCell: 0, lastCell: s.newbuf.Width()}
+}
+
+// applyAnnotations draws the annotations on the new buffer and returns a
+// function that restores the buffer to its previous state.
+func (s *Screen) applyAnnotations() (restore func()) {
+	if len(s.annotations) == 0 {
+		return func() {}
+	}
+
+	saved := make(map[int]Line)
+	for _, a := range s.annotations {
+		line := s.newbuf.Line(a.Y)
+		if line == nil {
+			continue
+		}
+		if _, ok := saved[a.Y]; !ok {
+			saved[a.Y] = append(Line(nil), line...)
+		}
+
+		x := a.X
+		text := a.Text
+		state := -1
+		for len(text) > 0 && x < s.newbuf.Width() {
+			var g string
+			var w int
+			g, text, w, state = uniseg.FirstGraphemeClusterInString(text, state)
+
+			var cell *Cell
+			if s.method == ansi.WcWidth {
+				cell = NewCellString(g)
+			} else {
+				cell = newGraphemeCell(g, w)
+			}
+			if cell.Width == 0 {
+				continue
+			}
+
+			cell.Style = a.Style
+			cell.Link = a.Link
+			s.newbuf.setCell(x, a.Y, cell, false)
+			x += cell.Width
+		}
+	}
+
+	return func() {
+		for y, line := range saved {
+			copy(s.newbuf.Line(y), line)
+		}
+	}
+}
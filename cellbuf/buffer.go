@@ -20,7 +20,7 @@ func NewCell(r rune, comb ...rune) (c *Cell) {
 
 // NewCellString returns a new cell with the given string content. This is a
 // convenience function that initializes a new cell with the given content. The
-// cell's width is determined by the content using [wcwidth.StringWidth].
+// cell's width is determined by the content using [runewidth.StringWidth].
 // This will only use the first combined rune in the string. If the string is
 // empty, it will return an empty cell with a width of 0.
 func NewCellString(s string) (c *Cell) {
@@ -114,10 +114,12 @@ func (l Line) At(x int) *Cell {
 // set the cell and the following cells to [EmptyCell]. It returns true if the
 // cell was set.
 func (l Line) Set(x int, c *Cell) bool {
-	return l.set(x, c, true)
+	return l.set(x, c, true, nil)
 }
 
-func (l Line) set(x int, c *Cell, clone bool) bool {
+// set sets the cell at the given x position. New cells are allocated using
+// alloc when it's not nil.
+func (l Line) set(x int, c *Cell, clone bool, alloc CellAllocator) bool {
 	width := l.Width()
 	if x < 0 || x >= width {
 		return false
@@ -130,7 +132,7 @@ func (l Line) set(x int, c *Cell, clone bool) bool {
 	if prev != nil && prev.Width > 1 {
 		// Writing to the first wide cell
 		for j := 0; j < prev.Width && x+j < l.Width(); j++ {
-			l[x+j] = prev.Clone().Blank()
+			l[x+j] = cloneCell(alloc, prev).Blank()
 		}
 	} else if prev != nil && prev.Width == 0 {
 		// Writing to wide cell placeholders
@@ -138,7 +140,7 @@ func (l Line) set(x int, c *Cell, clone bool) bool {
 			wide := l.At(x - j)
 			if wide != nil && wide.Width > 1 && j < wide.Width {
 				for k := 0; k < wide.Width; k++ {
-					l[x-j+k] = wide.Clone().Blank()
+					l[x-j+k] = cloneCell(alloc, wide).Blank()
 				}
 				break
 			}
@@ -147,13 +149,13 @@ func (l Line) set(x int, c *Cell, clone bool) bool {
 
 	if clone && c != nil {
 		// Clone the cell if not nil.
-		c = c.Clone()
+		c = cloneCell(alloc, c)
 	}
 
 	if c != nil && x+c.Width > width {
 		// If the cell is too wide, we write blanks with the same style.
 		for i := 0; i < c.Width && x+i < width; i++ {
-			l[x+i] = c.Clone().Blank()
+			l[x+i] = cloneCell(alloc, c).Blank()
 		}
 	} else {
 		l[x] = c
@@ -162,8 +164,7 @@ func (l Line) set(x int, c *Cell, clone bool) bool {
 		// We set the wide cell down below
 		if c != nil && c.Width > 1 {
 			for j := 1; j < c.Width && x+j < l.Width(); j++ {
-				var wide Cell
-				l[x+j] = &wide
+				l[x+j] = allocCell(alloc)
 			}
 		}
 	}
@@ -175,6 +176,13 @@ func (l Line) set(x int, c *Cell, clone bool) bool {
 type Buffer struct {
 	// Lines holds the lines of the buffer.
 	Lines []Line
+
+	// wrapped holds whether each line continues on the next line i.e. it was
+	// soft wrapped by a writer.
+	wrapped []bool
+
+	// alloc is the cell allocator. See [Buffer.SetAllocator].
+	alloc CellAllocator
 }
 
 // NewBuffer creates a new buffer with the given width and height.
@@ -185,6 +193,25 @@ func NewBuffer(width int, height int) *Buffer {
 	return b
 }
 
+// SetAllocator sets the allocator used to allocate the cells of the buffer.
+// A nil allocator allocates cells on the heap, which is the default.
+//
+// With an allocator, the buffer owns its cells and reuses them: setting or
+// filling a narrow cell over a narrow cell copies the new cell into the
+// existing one, so repeatedly updating the buffer doesn't allocate.
+// Therefore, the cells returned by [Buffer.Cell] and [Buffer.Line] are only
+// valid until their position changes, and cells must not be shared by
+// assigning them to [Buffer.Lines] directly.
+func (b *Buffer) SetAllocator(alloc CellAllocator) {
+	b.alloc = alloc
+}
+
+// Allocator returns the allocator used to allocate the cells of the buffer.
+// See [Buffer.SetAllocator].
+func (b *Buffer) Allocator() CellAllocator {
+	return b.alloc
+}
+
 // String returns the string representation of the buffer.
 func (b *Buffer) String() (s string) {
 	for i, l := range b.Lines {
@@ -218,7 +245,101 @@ const maxCellWidth = 4
 
 // SetCell sets the cell at the given x, y position.
 func (b *Buffer) SetCell(x, y int, c *Cell) bool {
-	return b.setCell(x, y, c, true)
+	return b.putCell(x, y, c)
+}
+
+// BoundsMode determines how positions outside of the buffer bounds are
+// handled by [Buffer.CellAt] and [Buffer.SetCellAt].
+type BoundsMode uint8
+
+// These are the available bounds modes.
+const (
+	// BoundsError reports positions outside of the buffer bounds with
+	// [ErrOutOfBounds].
+	BoundsError BoundsMode = iota
+
+	// BoundsClamp clamps positions to the nearest position within the buffer
+	// bounds.
+	BoundsClamp
+
+	// BoundsWrap wraps positions around the buffer edges. For example, a
+	// position one column past the right edge maps to the first column.
+	BoundsWrap
+)
+
+// bounded returns the position within the buffer bounds for the given x, y
+// position using the given bounds mode.
+func (b *Buffer) bounded(x, y int, mode BoundsMode) (int, int, error) {
+	width, height := b.Width(), b.Height()
+	if width == 0 || height == 0 {
+		return x, y, ErrOutOfBounds
+	}
+
+	switch mode {
+	case BoundsClamp:
+		x = clamp(x, 0, width-1)
+		y = clamp(y, 0, height-1)
+	case BoundsWrap:
+		x = ((x % width) + width) % width
+		y = ((y % height) + height) % height
+	default:
+		if x < 0 || x >= width || y < 0 || y >= height {
+			return x, y, ErrOutOfBounds
+		}
+	}
+
+	return x, y, nil
+}
+
+// CellAt returns the cell at the given x, y position using the given bounds
+// mode to handle positions outside of the buffer bounds.
+func (b *Buffer) CellAt(x, y int, mode BoundsMode) (*Cell, error) {
+	x, y, err := b.bounded(x, y, mode)
+	if err != nil {
+		return nil, err
+	}
+	return b.Lines[y].At(x), nil
+}
+
+// SetCellAt sets the cell at the given x, y position using the given bounds
+// mode to handle positions outside of the buffer bounds.
+func (b *Buffer) SetCellAt(x, y int, c *Cell, mode BoundsMode) error {
+	x, y, err := b.bounded(x, y, mode)
+	if err != nil {
+		return err
+	}
+	b.putCell(x, y, c)
+	return nil
+}
+
+// SetCells sets a row of cells starting at the given x, y position. Each
+// element of cells occupies one column, the same way as a [Line], and
+// wide cell placeholders i.e. [EmptyCell] are skipped. Cells that don't fit
+// within the buffer width are ignored. It returns the number of columns
+// written.
+func (b *Buffer) SetCells(x, y int, cells []*Cell) (n int) {
+	if y < 0 || y >= b.Height() {
+		return 0
+	}
+
+	width := b.Width()
+	for i, c := range cells {
+		col := x + i
+		if col >= width {
+			break
+		}
+		if col < 0 {
+			continue
+		}
+		n++
+		if c != nil && c.Width == 0 && c.Empty() {
+			// Wide cell placeholder, this is set by the wide cell.
+			continue
+		}
+		b.putCell(col, y, c)
+	}
+
+	return n
 }
 
 // setCell sets the cell at the given x, y position. This will always clone and
@@ -227,7 +348,145 @@ func (b *Buffer) setCell(x, y int, c *Cell, clone bool) bool {
 	if y < 0 || y >= len(b.Lines) {
 		return false
 	}
-	return b.Lines[y].set(x, c, clone)
+	return b.Lines[y].set(x, c, clone, b.alloc)
+}
+
+// putCell sets the cell at the given x, y position. When the buffer has an
+// allocator, a narrow cell replacing a narrow cell is copied into the
+// existing cell instead of allocating a new one.
+func (b *Buffer) putCell(x, y int, c *Cell) bool {
+	if b.alloc != nil && b.reuseCell(x, y, c) {
+		return true
+	}
+	return b.setCell(x, y, c, true)
+}
+
+// reuseCell copies c into the cell at the given x, y position when both are
+// narrow cells. A nil c is copied as a [BlankCell]. It returns false if the
+// cell can't be reused.
+//
+// This must only be used when the buffer owns its cells i.e. when it has an
+// allocator, and not while cells are being moved.
+func (b *Buffer) reuseCell(x, y int, c *Cell) bool {
+	if y < 0 || y >= len(b.Lines) || x < 0 || x >= len(b.Lines[y]) {
+		return false
+	}
+	old := b.Lines[y][x]
+	if old == nil || old.Width != 1 || (c != nil && c.Width != 1) {
+		return false
+	}
+	if c == nil {
+		*old = BlankCell
+	} else {
+		*old = *c
+	}
+	return true
+}
+
+// IsWrapped returns whether the line at the given y position was soft wrapped
+// and continues on the next line. Lines that were wrapped belong to the same
+// logical line as the line below them.
+func (b *Buffer) IsWrapped(y int) bool {
+	if y < 0 || y >= len(b.wrapped) || y >= len(b.Lines) {
+		return false
+	}
+	return b.wrapped[y]
+}
+
+// SetWrapped sets whether the line at the given y position was soft wrapped
+// and continues on the next line. Writers that wrap text at the buffer width
+// should mark the wrapped lines so that logical lines can be reconstructed.
+func (b *Buffer) SetWrapped(y int, v bool) {
+	if y < 0 || y >= len(b.Lines) {
+		return
+	}
+	if len(b.wrapped) < len(b.Lines) {
+		b.wrapped = append(b.wrapped, make([]bool, len(b.Lines)-len(b.wrapped))...)
+	}
+	b.wrapped[y] = v
+}
+
+// LogicalLine returns the first and last line positions, inclusive, of the
+// logical line containing the line at the given y position. A logical line is
+// a run of soft wrapped lines ending with a line that is not wrapped.
+func (b *Buffer) LogicalLine(y int) (top, bottom int) {
+	if y < 0 || y >= len(b.Lines) {
+		return -1, -1
+	}
+
+	top, bottom = y, y
+	for top > 0 && b.IsWrapped(top-1) {
+		top--
+	}
+	for bottom < len(b.Lines)-1 && b.IsWrapped(bottom) {
+		bottom++
+	}
+	return
+}
+
+// LogicalLines returns the string representation of the buffer logical lines.
+// Soft wrapped lines are joined together with their continuation lines
+// instead of being broken at the buffer width. Any trailing spaces are
+// removed from each logical line.
+func (b *Buffer) LogicalLines() (lines []string) {
+	var sb strings.Builder
+	for y, l := range b.Lines {
+		for _, c := range l {
+			if c == nil {
+				sb.WriteByte(' ')
+			} else if !c.Empty() {
+				sb.WriteString(c.String())
+			}
+		}
+		if !b.IsWrapped(y) {
+			lines = append(lines, strings.TrimRight(sb.String(), " "))
+			sb.Reset()
+		}
+	}
+	return
+}
+
+// shiftWrapped moves the wrapped line flags within the given rows by n lines.
+// A positive n moves the flags down while a negative n moves them up. Lines
+// shifted in are not wrapped.
+func (b *Buffer) shiftWrapped(top, bottom, n int) {
+	if len(b.wrapped) == 0 {
+		return
+	}
+
+	bottom = min(bottom, len(b.wrapped))
+	if n > 0 {
+		for y := bottom - 1; y >= top; y-- {
+			b.wrapped[y] = y-n >= top && b.wrapped[y-n]
+		}
+	} else if n < 0 {
+		for y := top; y < bottom; y++ {
+			b.wrapped[y] = y-n < bottom && b.wrapped[y-n]
+		}
+	}
+}
+
+// moveWrapped moves the wrapped line flags of the lines inserted or deleted at
+// the given y position within the rectangle bounds. Flags only move along with
+// their lines when the rectangle covers the whole buffer width, otherwise the
+// flags of the affected lines are reset.
+func (b *Buffer) moveWrapped(y, n int, rect Rectangle) {
+	if rect.Min.X > 0 || rect.Max.X < b.Width() {
+		b.resetWrapped(Rect(0, y, b.Width(), rect.Max.Y-y))
+		return
+	}
+	b.shiftWrapped(y, rect.Max.Y, n)
+}
+
+// resetWrapped resets the wrapped line flags of the lines fully covered by the
+// given rectangle. Lines that are only partially covered keep their flags.
+func (b *Buffer) resetWrapped(rect Rectangle) {
+	if rect.Min.X > 0 || rect.Max.X < b.Width() {
+		return
+	}
+	for y := max(rect.Min.Y, 0); y < rect.Max.Y && y < len(b.wrapped); y++ {
+		b.wrapped[y] = false
+	}
 }
 
 // Height implements Screen.
@@ -252,6 +511,7 @@ func (b *Buffer) Bounds() Rectangle {
 func (b *Buffer) Resize(width int, height int) {
 	if width == 0 || height == 0 {
 		b.Lines = nil
+		b.wrapped = nil
 		return
 	}
 
@@ -273,6 +533,10 @@ func (b *Buffer) Resize(width int, height int) {
 	} else if height < len(b.Lines) {
 		b.Lines = b.Lines[:height]
 	}
+
+	if len(b.wrapped) > height {
+		b.wrapped = b.wrapped[:height]
+	}
 }
 
 // FillRect fills the buffer with the given cell and rectangle.
@@ -283,7 +547,32 @@ func (b *Buffer) FillRect(c *Cell, rect Rectangle) {
 	}
 	for y := rect.Min.Y; y < rect.Max.Y; y++ {
 		for x := rect.Min.X; x < rect.Max.X; x += cellWidth {
-			b.setCell(x, y, c, false) //nolint:errcheck
+			if b.alloc != nil {
+				// The buffer owns its cells, don't share c between them.
+				b.putCell(x, y, c)
+			} else {
+				b.setCell(x, y, c, false) //nolint:errcheck
+			}
+		}
+	}
+	b.resetWrapped(rect)
+}
+
+// EraseRect fills the rectangle with the given cell like [Buffer.FillRect]
+// but leaves the protected cells, see [Cell.Protected], untouched. This
+// follows terminal selective erase behavior such as [ansi.DECSED] and
+// [ansi.DECSEL].
+func (b *Buffer) EraseRect(c *Cell, rect Rectangle) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if o, _ := b.ownerCell(x, y); o != nil && o.Protected {
+				continue
+			}
+			if b.alloc != nil {
+				b.putCell(x, y, c)
+			} else {
+				b.setCell(x, y, c, false) //nolint:errcheck
+			}
 		}
 	}
 }
@@ -328,6 +617,8 @@ func (b *Buffer) InsertLineRect(y, n int, c *Cell, rect Rectangle) {
 		n = rect.Max.Y - y
 	}
 
+	b.moveWrapped(y, n, rect)
+
 	// Move existing lines down within the bounds
 	for i := rect.Max.Y - 1; i >= y+n; i-- {
 		for x := rect.Min.X; x < rect.Max.X; x++ {
@@ -359,6 +650,8 @@ func (b *Buffer) DeleteLineRect(y, n int, c *Cell, rect Rectangle) {
 		n = rect.Max.Y - y
 	}
 
+	b.moveWrapped(y, -n, rect)
+
 	// Shift cells up within the bounds
 	for dst := y; dst < rect.Max.Y-n; dst++ {
 		src := dst + n
@@ -225,3 +225,137 @@ func TestBufferBounds(t *testing.T) {
 		t.Errorf("Buffer bounds max = (%d,%d), want (4,3)", bounds.Max.X, bounds.Max.Y)
 	}
 }
+
+func TestBufferWrapped(t *testing.T) {
+	b := NewBuffer(3, 4)
+	for x, r := range "abc" {
+		b.SetCell(x, 0, NewCell(r))
+	}
+	b.SetCell(0, 1, NewCell('d'))
+	b.SetWrapped(0, true)
+	b.SetCell(0, 2, NewCell('e'))
+
+	if !b.IsWrapped(0) || b.IsWrapped(1) {
+		t.Fatalf("IsWrapped() = %v, %v, want true, false", b.IsWrapped(0), b.IsWrapped(1))
+	}
+
+	if top, bottom := b.LogicalLine(1); top != 0 || bottom != 1 {
+		t.Errorf("LogicalLine(1) = %d, %d, want 0, 1", top, bottom)
+	}
+	if top, bottom := b.LogicalLine(2); top != 2 || bottom != 2 {
+		t.Errorf("LogicalLine(2) = %d, %d, want 2, 2", top, bottom)
+	}
+
+	want := []string{"abcd", "e", ""}
+	got := b.LogicalLines()
+	if len(got) != len(want) {
+		t.Fatalf("LogicalLines() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("LogicalLines()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// Inserting a line moves the flags down.
+	b.InsertLine(0, 1, nil)
+	if b.IsWrapped(0) || !b.IsWrapped(1) {
+		t.Errorf("after InsertLine IsWrapped() = %v, %v, want false, true", b.IsWrapped(0), b.IsWrapped(1))
+	}
+
+	// Deleting a line moves the flags up.
+	b.DeleteLine(0, 1, nil)
+	if !b.IsWrapped(0) {
+		t.Error("after DeleteLine IsWrapped(0) = false, want true")
+	}
+
+	// Clearing a line resets its flag.
+	b.ClearRect(Rect(0, 0, 3, 1))
+	if b.IsWrapped(0) {
+		t.Error("after ClearRect IsWrapped(0) = true, want false")
+	}
+}
+
+func TestBufferBoundsModes(t *testing.T) {
+	b := NewBuffer(3, 2)
+	b.SetCell(0, 0, NewCell('a'))
+	b.SetCell(2, 1, NewCell('z'))
+
+	if _, err := b.CellAt(3, 0, BoundsError); err != ErrOutOfBounds {
+		t.Errorf("CellAt(3, 0, BoundsError) error = %v, want %v", err, ErrOutOfBounds)
+	}
+	if c, err := b.CellAt(5, 9, BoundsClamp); err != nil || c.Rune != 'z' {
+		t.Errorf("CellAt(5, 9, BoundsClamp) = %v, %v, want 'z'", c, err)
+	}
+	if c, err := b.CellAt(-1, -1, BoundsWrap); err != nil || c.Rune != 'z' {
+		t.Errorf("CellAt(-1, -1, BoundsWrap) = %v, %v, want 'z'", c, err)
+	}
+	if c, err := b.CellAt(3, 2, BoundsWrap); err != nil || c.Rune != 'a' {
+		t.Errorf("CellAt(3, 2, BoundsWrap) = %v, %v, want 'a'", c, err)
+	}
+
+	if err := b.SetCellAt(-1, 0, NewCell('b'), BoundsError); err != ErrOutOfBounds {
+		t.Errorf("SetCellAt(-1, 0, BoundsError) error = %v, want %v", err, ErrOutOfBounds)
+	}
+	if err := b.SetCellAt(-1, 0, NewCell('b'), BoundsClamp); err != nil || b.Cell(0, 0).Rune != 'b' {
+		t.Errorf("SetCellAt(-1, 0, BoundsClamp) error = %v, cell = %v", err, b.Cell(0, 0))
+	}
+	if err := b.SetCellAt(4, 0, NewCell('c'), BoundsWrap); err != nil || b.Cell(1, 0).Rune != 'c' {
+		t.Errorf("SetCellAt(4, 0, BoundsWrap) error = %v, cell = %v", err, b.Cell(1, 0))
+	}
+
+	var empty Buffer
+	if _, err := empty.CellAt(0, 0, BoundsWrap); err != ErrOutOfBounds {
+		t.Errorf("empty CellAt(0, 0, BoundsWrap) error = %v, want %v", err, ErrOutOfBounds)
+	}
+}
+
+func TestBufferSetCells(t *testing.T) {
+	b := NewBuffer(4, 1)
+	line := Line{NewCell('a'), NewCell('世'), &Cell{}, NewCell('b'), NewCell('c')}
+
+	if n := b.SetCells(0, 0, line); n != 4 {
+		t.Errorf("SetCells() = %d, want 4", n)
+	}
+	if got := b.String(); got != "a世b" {
+		t.Errorf("String() = %q, want %q", got, "a世b")
+	}
+
+	if n := b.SetCells(-1, 0, Line{NewCell('x'), NewCell('y')}); n != 1 {
+		t.Errorf("SetCells(-1, 0) = %d, want 1", n)
+	}
+	if got := b.Cell(0, 0).Rune; got != 'y' {
+		t.Errorf("Cell(0, 0) = %q, want 'y'", got)
+	}
+
+	if n := b.SetCells(0, 1, line); n != 0 {
+		t.Errorf("SetCells(0, 1) = %d, want 0", n)
+	}
+}
+
+func TestBufferEraseRect(t *testing.T) {
+	b := NewBuffer(6, 2)
+	protected := func(r rune) *Cell {
+		c := NewCell(r)
+		c.Protected = true
+		return c
+	}
+	b.SetCells(0, 0, Line{NewCell('a'), protected('b'), NewCell('c'), protected('世'), &Cell{}, NewCell('d')})
+	b.SetCells(0, 1, Line{protected('e'), NewCell('f')})
+
+	b.EraseRect(nil, b.Bounds())
+	if got, want := b.String(), " b 世\r\ne"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// Erasing from the middle of a protected wide cell keeps it whole.
+	b.EraseRect(nil, Rect(4, 0, 2, 1))
+	if got := b.Cell(3, 0).Rune; got != '世' {
+		t.Errorf("Cell(3, 0) = %q, want '世'", got)
+	}
+
+	b.FillRect(nil, b.Bounds())
+	if got := b.String(); got != "\r\n" {
+		t.Errorf("FillRect() didn't erase protected cells, got %q", got)
+	}
+}
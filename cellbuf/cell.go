@@ -32,6 +32,11 @@ type Cell struct {
 	// Rune is the main rune of the cell. This is zero if the cell is part of a
 	// wider cell.
 	Rune rune
+
+	// Protected is whether the cell is protected from selective erase, see
+	// [Buffer.EraseRect]. It doesn't change the appearance of the cell and
+	// isn't compared by [Cell.Equal].
+	Protected bool
 }
 
 // String returns the string content of the cell excluding any styles, links,
@@ -56,6 +61,59 @@ func (c *Cell) Equal(o *Cell) bool {
 		c.Link.Equal(o.Link)
 }
 
+// CellEqualOptions is a set of flags that relax the comparison of cells.
+type CellEqualOptions uint8
+
+// These are the available cell comparison options.
+const (
+	// IgnoreLinkID ignores the hyperlink parameters i.e. the link id when
+	// comparing cells. Only the hyperlink URLs are compared.
+	IgnoreLinkID CellEqualOptions = 1 << iota
+
+	// BlankEqualsEmpty treats a space cell with no style or link and a one
+	// column cell with no content as equal. Zero width cells are placeholders
+	// for the columns covered by a wide cell and are never treated as blank.
+	BlankEqualsEmpty
+)
+
+// CellEqual returns whether the two cells are equal using the given options.
+// A nil cell is considered a [BlankCell].
+func CellEqual(a, b *Cell, opts CellEqualOptions) bool {
+	if a == b {
+		return true
+	}
+	if a == nil {
+		a = &BlankCell
+	}
+	if b == nil {
+		b = &BlankCell
+	}
+
+	if opts&BlankEqualsEmpty != 0 && isBlankOrEmpty(a) && isBlankOrEmpty(b) {
+		return true
+	}
+
+	if opts&IgnoreLinkID == 0 {
+		return a.Equal(b)
+	}
+
+	return a.Width == b.Width &&
+		a.Rune == b.Rune &&
+		runesEqual(a.Comb, b.Comb) &&
+		a.Style.Equal(b.Style) &&
+		a.Link.URL == b.Link.URL
+}
+
+// isBlankOrEmpty returns whether the cell is a one column cell with no
+// content, style, or link, or a space cell with no style or link.
+func isBlankOrEmpty(c *Cell) bool {
+	return (c.Rune == ' ' || c.Rune == 0) &&
+		len(c.Comb) == 0 &&
+		c.Width == 1 &&
+		c.Style.Empty() &&
+		c.Link.Empty()
+}
+
 // Empty returns whether the cell is empty.
 func (c Cell) Empty() bool {
 	return c.Rune == 0 &&
@@ -72,6 +130,7 @@ func (c *Cell) Reset() {
 	c.Width = 0
 	c.Style.Reset()
 	c.Link.Reset()
+	c.Protected = false
 }
 
 // Clear returns whether the cell consists of only attributes that don't
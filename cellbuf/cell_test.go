@@ -0,0 +1,44 @@
+package cellbuf
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCellEqual(t *testing.T) {
+	link := Link{URL: "https://charm.sh", URLID: "id=1"}
+	otherID := Link{URL: "https://charm.sh", URLID: "id=2"}
+
+	styled := BlankCell
+	styled.Style.Background(ansi.Red)
+
+	tests := []struct {
+		name string
+		a, b *Cell
+		opts CellEqualOptions
+		want bool
+	}{
+		{"nil and blank", nil, &BlankCell, 0, true},
+		{"nil and empty", nil, &EmptyCell, 0, false},
+		{"nil and empty blank equals empty", nil, &Cell{Width: 1}, BlankEqualsEmpty, true},
+		{"blank and empty blank equals empty", &BlankCell, &Cell{Width: 1}, BlankEqualsEmpty, true},
+		{"styled blank and empty blank equals empty", &styled, &Cell{Width: 1}, BlankEqualsEmpty, false},
+		{"blank and placeholder blank equals empty", &BlankCell, &EmptyCell, BlankEqualsEmpty, false},
+		{"different link ids", &Cell{Rune: 'a', Width: 1, Link: link}, &Cell{Rune: 'a', Width: 1, Link: otherID}, 0, false},
+		{"different link ids ignore link id", &Cell{Rune: 'a', Width: 1, Link: link}, &Cell{Rune: 'a', Width: 1, Link: otherID}, IgnoreLinkID, true},
+		{"different link urls ignore link id", &Cell{Rune: 'a', Width: 1, Link: link}, &Cell{Rune: 'a', Width: 1}, IgnoreLinkID, false},
+		{"different runes all options", NewCell('a'), NewCell('b'), IgnoreLinkID | BlankEqualsEmpty, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CellEqual(tt.a, tt.b, tt.opts); got != tt.want {
+				t.Errorf("CellEqual() = %v, want %v", got, tt.want)
+			}
+			if got := CellEqual(tt.b, tt.a, tt.opts); got != tt.want {
+				t.Errorf("CellEqual() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
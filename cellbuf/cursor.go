@@ -0,0 +1,245 @@
+package cellbuf
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// CursorOverwriteFunc returns the text that, when printed at the given
+// position, moves the cursor n columns to the right without changing what's
+// displayed. It returns false when the cells can't be overwritten, for
+// example, when their style differs from the current pen.
+type CursorOverwriteFunc func(x, y, n int) (string, bool)
+
+// CursorOptimizer computes the cheapest sequence to move the cursor from one
+// position to another. It considers absolute addressing, relative movements,
+// carriage returns, line feeds, backspaces, tabs, and overwriting cells with
+// their own content, and picks the shortest.
+//
+// The zero value uses VT100 relative movements and absolute [ansi.CUP].
+type CursorOptimizer struct {
+	// Width and Height are the dimensions of the screen.
+	Width, Height int
+
+	// Tabs are the tab stops of the terminal. They are used when HardTabs
+	// is true.
+	Tabs *TabStops
+
+	// XtermLike enables sequences that are not supported by VT100 terminals
+	// such as [ansi.VPA], [ansi.HPA], and [ansi.CBT].
+	XtermLike bool
+
+	// RelativeCursor disables absolute cursor addressing. Use this when the
+	// screen doesn't start at the top of the terminal i.e. inline mode.
+	RelativeCursor bool
+
+	// HardTabs enables using tabs to move the cursor forward.
+	HardTabs bool
+
+	// AltScreen is whether the alternate screen buffer is in use. When
+	// false, line feeds are always preferred to move the cursor down since
+	// scrolling the screen is acceptable.
+	AltScreen bool
+}
+
+// notLocal returns whether the coordinates are not considered local movement
+// using the defined thresholds.
+// This takes the number of columns, and the coordinates of the current and
+// target positions.
+func notLocal(cols, fx, fy, tx, ty int) bool {
+	// The typical distance for a [ansi.CUP] sequence. Anything less than this
+	// is considered local movement.
+	const longDist = 8 - 1
+	return (tx > longDist) &&
+		(tx < cols-1-longDist) &&
+		(abs(ty-fy)+abs(tx-fx) > longDist)
+}
+
+// Move returns the cheapest sequence that moves the cursor from (fx, fy) to
+// (tx, ty). A negative from position means the cursor position is unknown
+// and absolute addressing is used unless RelativeCursor is true.
+// When overwrite is not nil, it is used to move the cursor forward by
+// printing the cells in its way when that's cheaper.
+func (o CursorOptimizer) Move(fx, fy, tx, ty int, overwrite CursorOverwriteFunc) (seq string) {
+	if !o.RelativeCursor {
+		// Method #0: Use [ansi.CUP] if the distance is long.
+		seq = ansi.CursorPosition(tx+1, ty+1)
+		if fx == -1 || fy == -1 || notLocal(o.Width, fx, fy, tx, ty) {
+			return
+		}
+	}
+
+	// Method #1: Use local movement sequences.
+	nseq := o.relativeMove(fx, fy, tx, ty, overwrite, false)
+	if len(seq) == 0 || len(nseq) < len(seq) {
+		seq = nseq
+	}
+
+	// Method #2: Use [ansi.CR] and local movement sequences.
+	nseq = "\r" + o.relativeMove(0, fy, tx, ty, overwrite, false)
+	if len(nseq) < len(seq) {
+		seq = nseq
+	}
+
+	if !o.RelativeCursor {
+		// Method #3: Use [ansi.CursorHomePosition] and local movement sequences.
+		nseq = ansi.CursorHomePosition + o.relativeMove(0, 0, tx, ty, overwrite, false)
+		if len(nseq) < len(seq) {
+			seq = nseq
+		}
+	}
+
+	if o.HardTabs && o.Tabs != nil {
+		// Method #4: Use tab optimized local movement sequences.
+		nseq := o.relativeMove(fx, fy, tx, ty, overwrite, true)
+		if len(nseq) < len(seq) {
+			seq = nseq
+		}
+
+		// Method #5: Use [ansi.CR] and tab optimized local movement sequences.
+		nseq = "\r" + o.relativeMove(0, fy, tx, ty, overwrite, true)
+		if len(nseq) < len(seq) {
+			seq = nseq
+		}
+
+		if !o.RelativeCursor {
+			// Method #6: Use [ansi.CursorHomePosition] and tab optimized local movement sequences.
+			nseq = ansi.CursorHomePosition + o.relativeMove(0, 0, tx, ty, overwrite, true)
+			if len(nseq) < len(seq) {
+				seq = nseq
+			}
+		}
+	}
+
+	return
+}
+
+// relativeMove returns the relative cursor movement sequence using one or two
+// of the following sequences [ansi.CUU], [ansi.CUD], [ansi.CUF], [ansi.CUB],
+// [ansi.VPA], [ansi.HPA].
+// When overwrite is not nil, this will try to optimize the sequence by using
+// the screen cells values to move the cursor instead of using escape
+// sequences.
+func (o CursorOptimizer) relativeMove(fx, fy, tx, ty int, overwrite CursorOverwriteFunc, useTabs bool) string {
+	var seq strings.Builder
+
+	if ty != fy {
+		var yseq string
+		if o.XtermLike && !o.RelativeCursor {
+			yseq = ansi.VerticalPositionAbsolute(ty + 1)
+		}
+
+		// OPTIM: Use [ansi.LF] and [ansi.ReverseIndex] as optimizations.
+
+		if ty > fy {
+			n := ty - fy
+			if cud := ansi.CursorDown(n); yseq == "" || len(cud) < len(yseq) {
+				yseq = cud
+			}
+			shouldScroll := !o.AltScreen
+			if lf := strings.Repeat("\n", n); shouldScroll || (fy+n < o.Height && len(lf) < len(yseq)) {
+				// TODO: Ensure we're not unintentionally scrolling the screen down.
+				yseq = lf
+			}
+		} else if ty < fy {
+			n := fy - ty
+			if cuu := ansi.CursorUp(n); yseq == "" || len(cuu) < len(yseq) {
+				yseq = cuu
+			}
+			if n == 1 && fy-1 > 0 {
+				// TODO: Ensure we're not unintentionally scrolling the screen up.
+				yseq = ansi.ReverseIndex
+			}
+		}
+
+		seq.WriteString(yseq)
+	}
+
+	if tx != fx {
+		var xseq string
+		if o.XtermLike && !o.RelativeCursor {
+			xseq = ansi.HorizontalPositionAbsolute(tx + 1)
+		}
+
+		if tx > fx {
+			n := tx - fx
+			if useTabs && o.HardTabs && o.Tabs != nil {
+				var tabs int
+				var col int
+				for col = fx; o.Tabs.Next(col) <= tx; col = o.Tabs.Next(col) {
+					tabs++
+					if col == o.Tabs.Next(col) || col >= o.Width-1 {
+						break
+					}
+				}
+
+				if tabs > 0 {
+					cht := ansi.CursorHorizontalForwardTab(tabs)
+					tab := strings.Repeat("\t", tabs)
+					if false && o.XtermLike && len(cht) < len(tab) {
+						// TODO: The linux console and some terminals such as
+						// Alacritty don't support [ansi.CHT]. Enable this when
+						// we have a way to detect this, or after 5 years when
+						// we're sure everyone has updated their terminals :P
+						seq.WriteString(cht)
+					} else {
+						seq.WriteString(tab)
+					}
+
+					n = tx - col
+					fx = col
+				}
+			}
+
+			if n == 0 {
+				// The tabs landed on the target column.
+				xseq = ""
+			} else {
+				if cuf := ansi.CursorForward(n); xseq == "" || len(cuf) < len(xseq) {
+					xseq = cuf
+				}
+
+				// If we have no attribute and style changes, overwrite is cheaper.
+				if overwrite != nil && ty >= 0 {
+					if ovw, ok := overwrite(fx, ty, n); ok && len(ovw) < len(xseq) {
+						xseq = ovw
+					}
+				}
+			}
+		} else if tx < fx {
+			n := fx - tx
+			if useTabs && o.HardTabs && o.Tabs != nil && o.XtermLike {
+				// VT100 does not support backward tabs [ansi.CBT].
+
+				col := fx
+
+				var cbt int // cursor backward tabs count
+				for o.Tabs.Prev(col) >= tx {
+					col = o.Tabs.Prev(col)
+					cbt++
+					if col == o.Tabs.Prev(col) || col <= 0 {
+						break
+					}
+				}
+
+				if cbt > 0 {
+					seq.WriteString(ansi.CursorBackwardTab(cbt))
+					n = col - tx
+				}
+			}
+
+			if bs := strings.Repeat("\b", n); xseq == "" || len(bs) < len(xseq) {
+				xseq = bs
+			}
+
+			if cub := ansi.CursorBackward(n); len(cub) < len(xseq) {
+				xseq = cub
+			}
+		}
+
+		seq.WriteString(xseq)
+	}
+
+	return seq.String()
+}
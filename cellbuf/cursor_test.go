@@ -0,0 +1,199 @@
+package cellbuf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// simulateCursor applies the cursor movements in seq starting at (x, y) and
+// returns the resulting cursor position. It only understands the sequences
+// produced by [CursorOptimizer].
+func simulateCursor(t *testing.T, o CursorOptimizer, x, y int, seq string) (int, int) {
+	t.Helper()
+	param := func(s string, def int) int {
+		if s == "" {
+			return def
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			t.Fatalf("invalid parameter %q in %q", s, seq)
+		}
+		return n
+	}
+
+	for i := 0; i < len(seq); i++ {
+		switch c := seq[i]; c {
+		case '\r':
+			x = 0
+		case '\n':
+			y = min(y+1, o.Height-1)
+		case '\b':
+			x = max(x-1, 0)
+		case '\t':
+			x = o.Tabs.Next(x)
+		case ansi.ESC:
+			i++
+			if i < len(seq) && seq[i] == 'M' {
+				y = max(y-1, 0)
+				continue
+			}
+			if i >= len(seq) || seq[i] != '[' {
+				t.Fatalf("unexpected escape sequence in %q", seq)
+			}
+			j := i + 1
+			for j < len(seq) && (seq[j] == ';' || (seq[j] >= '0' && seq[j] <= '9')) {
+				j++
+			}
+			if j >= len(seq) {
+				t.Fatalf("unterminated sequence in %q", seq)
+			}
+			params := strings.Split(seq[i+1:j], ";")
+			n := param(params[0], 1)
+			switch seq[j] {
+			case 'A':
+				y = max(y-n, 0)
+			case 'B':
+				y = min(y+n, o.Height-1)
+			case 'C':
+				x = min(x+n, o.Width-1)
+			case 'D':
+				x = max(x-n, 0)
+			case 'd':
+				y = n - 1
+			case '`':
+				x = n - 1
+			case 'Z':
+				for ; n > 0; n-- {
+					x = o.Tabs.Prev(x)
+				}
+			case 'H':
+				y = n - 1
+				x = 0
+				if len(params) > 1 {
+					x = param(params[1], 1) - 1
+				}
+			default:
+				t.Fatalf("unexpected sequence %q in %q", seq[i-1:j+1], seq)
+			}
+			i = j
+		default:
+			if c < ' ' {
+				t.Fatalf("unexpected control %q in %q", c, seq)
+			}
+			x++
+		}
+	}
+
+	return x, y
+}
+
+// testOverwrite overwrites the cells with a letter except for the cells in
+// column 5 which can't be overwritten.
+func testOverwrite(x, _, n int) (string, bool) {
+	if x <= 5 && x+n > 5 {
+		return "", false
+	}
+	return strings.Repeat("a", n), true
+}
+
+func TestCursorOptimizerMove(t *testing.T) {
+	const width, height = 24, 6
+	for _, o := range []CursorOptimizer{
+		{},
+		{XtermLike: true},
+		{HardTabs: true},
+		{XtermLike: true, HardTabs: true},
+		{RelativeCursor: true},
+		{RelativeCursor: true, XtermLike: true, HardTabs: true},
+		{AltScreen: true, XtermLike: true},
+	} {
+		o.Width, o.Height = width, height
+		o.Tabs = DefaultTabStops(width)
+		for _, overwrite := range []CursorOverwriteFunc{nil, testOverwrite} {
+			name := fmt.Sprintf("%+v/overwrite=%v", o, overwrite != nil)
+			t.Run(name, func(t *testing.T) {
+				for fy := 0; fy < height; fy++ {
+					for fx := 0; fx < width; fx++ {
+						for ty := 0; ty < height; ty++ {
+							for tx := 0; tx < width; tx++ {
+								seq := o.Move(fx, fy, tx, ty, overwrite)
+								if x, y := simulateCursor(t, o, fx, fy, seq); x != tx || y != ty {
+									t.Fatalf("move (%d,%d) -> (%d,%d) with %q ended at (%d,%d)",
+										fx, fy, tx, ty, seq, x, y)
+								}
+								if fx == tx && fy == ty && seq != "" {
+									t.Fatalf("move (%d,%d) -> (%d,%d) expected no sequence, got %q",
+										fx, fy, tx, ty, seq)
+								}
+								if cup := ansi.CursorPosition(tx+1, ty+1); !o.RelativeCursor && len(seq) > len(cup) {
+									t.Fatalf("move (%d,%d) -> (%d,%d) with %q is longer than %q",
+										fx, fy, tx, ty, seq, cup)
+								}
+							}
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCursorOptimizerMoveUnknown(t *testing.T) {
+	o := CursorOptimizer{Width: 80, Height: 24}
+	if seq, want := o.Move(-1, -1, 1, 2, nil), ansi.CursorPosition(2, 3); seq != want {
+		t.Errorf("expected %q, got %q", want, seq)
+	}
+}
+
+func TestCursorOptimizerMoveCheapest(t *testing.T) {
+	o := CursorOptimizer{Width: 80, Height: 24, XtermLike: true, Tabs: DefaultTabStops(80)}
+	cases := []struct {
+		name           string
+		fx, fy, tx, ty int
+		overwrite      CursorOverwriteFunc
+		hardTabs       bool
+		want           string
+	}{
+		{"right one", 3, 0, 4, 0, nil, false, "\x1b[C"},
+		{"left two", 4, 0, 2, 0, nil, false, "\b\b"},
+		{"down", 0, 0, 0, 2, nil, false, "\n\n"},
+		{"up one", 0, 3, 0, 2, nil, false, ansi.ReverseIndex},
+		{"line start", 10, 3, 0, 4, nil, false, "\r\n"},
+		{"home", 40, 20, 0, 0, nil, false, ansi.CursorHomePosition},
+		{"far", 0, 0, 40, 20, nil, false, "\x1b[21;41H"},
+		{"overwrite", 0, 0, 3, 0, testOverwrite, false, "aaa"},
+		{"tabs", 56, 1, 72, 1, nil, true, "\t\t"},
+		{"back tab", 79, 1, 72, 1, nil, true, "\x1b[Z"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := o
+			o.HardTabs = c.hardTabs
+			if seq := o.Move(c.fx, c.fy, c.tx, c.ty, c.overwrite); seq != c.want {
+				t.Errorf("expected %q, got %q", c.want, seq)
+			}
+		})
+	}
+}
+
+func BenchmarkCursorOptimizerMove(b *testing.B) {
+	const width, height = 80, 24
+	o := CursorOptimizer{
+		Width:     width,
+		Height:    height,
+		Tabs:      DefaultTabStops(width),
+		XtermLike: true,
+		HardTabs:  true,
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fx, fy := i%width, (i/width)%height
+		tx, ty := (i*7)%width, (i*3)%height
+		o.Move(fx, fy, tx, ty, testOverwrite)
+	}
+}
@@ -0,0 +1,243 @@
+package cellbuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// bufferJSON is the JSON representation of a [Buffer]. Styles and links are
+// deduplicated into tables and cells refer to them by their 1-based index. A
+// zero index means no style or link.
+type bufferJSON struct {
+	Width  int           `json:"width"`
+	Height int           `json:"height"`
+	Styles []styleJSON   `json:"styles,omitempty"`
+	Links  []Link        `json:"links,omitempty"`
+	Lines  [][]*cellJSON `json:"lines"`
+	// Wrapped holds the positions of the soft wrapped lines.
+	Wrapped []int `json:"wrapped,omitempty"`
+}
+
+// cellJSON is the JSON representation of a [Cell]. A nil cell is encoded as
+// null.
+type cellJSON struct {
+	Content string `json:"c,omitempty"`
+	Width   int    `json:"w"`
+	Style   int    `json:"s,omitempty"`
+	Link    int    `json:"l,omitempty"`
+	// Protected is whether the cell is protected from selective erase.
+	Protected bool `json:"p,omitempty"`
+}
+
+// styleJSON is the JSON representation of a [Style].
+type styleJSON struct {
+	Fg      string         `json:"fg,omitempty"`
+	Bg      string         `json:"bg,omitempty"`
+	Ul      string         `json:"ul,omitempty"`
+	Attrs   AttrMask       `json:"attrs,omitempty"`
+	UlStyle UnderlineStyle `json:"ul_style,omitempty"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It encodes the buffer cells along
+// with their styles and links in a compact form suitable for snapshots and
+// for sending screen state across processes.
+func (b Buffer) MarshalJSON() ([]byte, error) {
+	bj := bufferJSON{
+		Width:  b.Width(),
+		Height: b.Height(),
+		Lines:  make([][]*cellJSON, len(b.Lines)),
+	}
+
+	styles := map[styleJSON]int{}
+	links := map[Link]int{}
+	for y, l := range b.Lines {
+		line := make([]*cellJSON, len(l))
+		for x, c := range l {
+			if c == nil {
+				continue
+			}
+
+			cj := &cellJSON{Content: c.String(), Width: c.Width, Protected: c.Protected}
+			if !c.Style.Empty() {
+				sj := encodeStyle(c.Style)
+				idx, ok := styles[sj]
+				if !ok {
+					bj.Styles = append(bj.Styles, sj)
+					idx = len(bj.Styles)
+					styles[sj] = idx
+				}
+				cj.Style = idx
+			}
+			if !c.Link.Empty() {
+				idx, ok := links[c.Link]
+				if !ok {
+					bj.Links = append(bj.Links, c.Link)
+					idx = len(bj.Links)
+					links[c.Link] = idx
+				}
+				cj.Link = idx
+			}
+
+			line[x] = cj
+		}
+		bj.Lines[y] = line
+		if b.IsWrapped(y) {
+			bj.Wrapped = append(bj.Wrapped, y)
+		}
+	}
+
+	return json.Marshal(bj)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It decodes a buffer previously
+// encoded with [Buffer.MarshalJSON] replacing the buffer contents. The buffer
+// is left unchanged if the data is invalid.
+func (b *Buffer) UnmarshalJSON(data []byte) error {
+	var bj bufferJSON
+	if err := json.Unmarshal(data, &bj); err != nil {
+		return err
+	}
+
+	if bj.Width < 0 || bj.Height < 0 || len(bj.Lines) != bj.Height {
+		return ErrInvalidDimensions
+	}
+	for _, line := range bj.Lines {
+		if len(line) != bj.Width {
+			return ErrInvalidDimensions
+		}
+		for _, cj := range line {
+			if cj == nil {
+				continue
+			}
+			if cj.Style < 0 || cj.Style > len(bj.Styles) {
+				return fmt.Errorf("cellbuf: invalid style index %d", cj.Style)
+			}
+			if cj.Link < 0 || cj.Link > len(bj.Links) {
+				return fmt.Errorf("cellbuf: invalid link index %d", cj.Link)
+			}
+		}
+	}
+	for _, y := range bj.Wrapped {
+		if y < 0 || y >= bj.Height {
+			return fmt.Errorf("cellbuf: invalid wrapped line %d", y)
+		}
+	}
+
+	styles := make([]Style, len(bj.Styles))
+	for i, sj := range bj.Styles {
+		s, err := decodeStyle(sj)
+		if err != nil {
+			return err
+		}
+		styles[i] = s
+	}
+
+	nb := NewBuffer(bj.Width, bj.Height)
+	if len(nb.Lines) != len(bj.Lines) {
+		return ErrInvalidDimensions
+	}
+
+	for y, line := range bj.Lines {
+		for x, cj := range line {
+			if cj == nil {
+				continue
+			}
+
+			c := newGraphemeCell(cj.Content, cj.Width)
+			c.Protected = cj.Protected
+			if cj.Style > 0 {
+				c.Style = styles[cj.Style-1]
+			}
+			if cj.Link > 0 {
+				c.Link = bj.Links[cj.Link-1]
+			}
+
+			// Set the cell directly to preserve wide cell placeholders as they
+			// were encoded.
+			nb.Lines[y][x] = c
+		}
+	}
+
+	for _, y := range bj.Wrapped {
+		nb.SetWrapped(y, true)
+	}
+
+	// Keep the allocator, if any, and only replace the contents.
+	b.Lines, b.wrapped = nb.Lines, nb.wrapped
+
+	return nil
+}
+
+func encodeStyle(s Style) styleJSON {
+	return styleJSON{
+		Fg:      encodeColor(s.Fg),
+		Bg:      encodeColor(s.Bg),
+		Ul:      encodeColor(s.Ul),
+		Attrs:   s.Attrs,
+		UlStyle: s.UlStyle,
+	}
+}
+
+func decodeStyle(sj styleJSON) (s Style, err error) {
+	if s.Fg, err = decodeColor(sj.Fg); err != nil {
+		return
+	}
+	if s.Bg, err = decodeColor(sj.Bg); err != nil {
+		return
+	}
+	if s.Ul, err = decodeColor(sj.Ul); err != nil {
+		return
+	}
+	s.Attrs = sj.Attrs
+	s.UlStyle = sj.UlStyle
+	return
+}
+
+// encodeColor encodes a color to a string. Basic and extended ANSI colors
+// keep their palette index using the "basic:N" and "ext:N" forms while any
+// other color is encoded as a "#rrggbb" hex string.
+func encodeColor(c ansi.Color) string {
+	switch c := c.(type) {
+	case nil:
+		return ""
+	case ansi.BasicColor:
+		return "basic:" + strconv.Itoa(int(c))
+	case ansi.ExtendedColor:
+		return "ext:" + strconv.Itoa(int(c))
+	}
+
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// decodeColor decodes a color encoded with [encodeColor].
+func decodeColor(s string) (ansi.Color, error) {
+	switch {
+	case s == "":
+		return nil, nil
+	case strings.HasPrefix(s, "basic:"):
+		n, err := strconv.ParseUint(s[len("basic:"):], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("cellbuf: invalid color %q: %w", s, err)
+		}
+		return ansi.BasicColor(n), nil
+	case strings.HasPrefix(s, "ext:"):
+		n, err := strconv.ParseUint(s[len("ext:"):], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("cellbuf: invalid color %q: %w", s, err)
+		}
+		return ansi.ExtendedColor(n), nil
+	case strings.HasPrefix(s, "#") && len(s) == 7:
+		n, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("cellbuf: invalid color %q: %w", s, err)
+		}
+		return ansi.TrueColor(n), nil
+	}
+
+	return nil, fmt.Errorf("cellbuf: invalid color %q", s)
+}
@@ -0,0 +1,87 @@
+package cellbuf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestBufferJSON(t *testing.T) {
+	b := NewBuffer(5, 2)
+
+	var style Style
+	style.Bold(true).Foreground(ansi.Red).Background(ansi.ExtendedColor(196)).UnderlineColor(ansi.TrueColor(0x123456))
+	style.UnderlineStyle(CurlyUnderline)
+
+	a := NewCell('a')
+	a.Style = style
+	a.Link = Link{URL: "https://charm.sh", URLID: "id=1"}
+	b.SetCell(0, 0, a)
+	b.SetCell(1, 0, NewCell('世'))
+	b.SetCell(0, 1, NewCell('e', '́'))
+	b.SetCell(1, 1, &Cell{Rune: 'x', Width: 1, Style: style, Protected: true})
+	b.SetWrapped(0, true)
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got Buffer
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got.Width() != b.Width() || got.Height() != b.Height() {
+		t.Fatalf("size = %dx%d, want %dx%d", got.Width(), got.Height(), b.Width(), b.Height())
+	}
+
+	for y := 0; y < b.Height(); y++ {
+		for x := 0; x < b.Width(); x++ {
+			if want, got := b.Lines[y][x], got.Lines[y][x]; !cellEqual(want, got) {
+				t.Errorf("cell (%d,%d) = %#v, want %#v", x, y, got, want)
+			}
+		}
+	}
+
+	if !got.IsWrapped(0) || got.IsWrapped(1) {
+		t.Errorf("IsWrapped() = %v, %v, want true, false", got.IsWrapped(0), got.IsWrapped(1))
+	}
+
+	if got.String() != b.String() {
+		t.Errorf("String() = %q, want %q", got.String(), b.String())
+	}
+
+	if !got.Cell(1, 1).Protected {
+		t.Errorf("Cell(1, 1) isn't protected")
+	}
+
+	if _, ok := got.Cell(0, 0).Style.Fg.(ansi.BasicColor); !ok {
+		t.Errorf("foreground color type = %T, want ansi.BasicColor", got.Cell(0, 0).Style.Fg)
+	}
+}
+
+func TestBufferJSONInvalid(t *testing.T) {
+	cases := []string{
+		`{"width":2,"height":1,"lines":[]}`,
+		`{"width":2,"height":1,"lines":[[null]]}`,
+		`{"width":1,"height":1,"lines":[[{"c":"a","w":1,"s":1}]]}`,
+		`{"width":1,"height":1,"styles":[{"fg":"bogus"}],"lines":[[{"c":"a","w":1,"s":1}]]}`,
+		`{"width":1,"height":1,"lines":[[{"c":"a","w":1,"s":-1}]]}`,
+		`{"width":1,"height":1,"lines":[[{"c":"a","w":1,"l":2}]]}`,
+		`{"width":1,"height":1,"lines":[[{"c":"a","w":1}]],"wrapped":[1]}`,
+		`{"width":2,"height":2,"lines":[[null,null],[null]]}`,
+	}
+
+	for _, c := range cases {
+		b := NewBuffer(1, 1)
+		b.SetCell(0, 0, NewCell('x'))
+		if err := json.Unmarshal([]byte(c), b); err == nil {
+			t.Errorf("Unmarshal(%s) expected error", c)
+		}
+		if b.Width() != 1 || b.Height() != 1 || b.Cell(0, 0).Rune != 'x' {
+			t.Errorf("Unmarshal(%s) modified the buffer", c)
+		}
+	}
+}
@@ -155,7 +155,9 @@ func (s *Screen) scrollBuffer(b *Buffer, n, top, bot int, blank *Cell) {
 		// shift n lines downwards
 		limit := top - n
 		for line := bot; line >= limit && line >= 0 && line >= top; line-- {
-			copy(b.Lines[line], b.Lines[line+n])
+			// Swap the lines instead of copying the cells so that no cell is
+			// shared between lines.
+			b.Lines[line], b.Lines[line+n] = b.Lines[line+n], b.Lines[line]
 		}
 		for line := top; line < limit && line <= b.Height()-1 && line <= bot; line++ {
 			b.FillRect(blank, Rect(0, line, b.Width(), 1))
@@ -166,7 +168,7 @@ func (s *Screen) scrollBuffer(b *Buffer, n, top, bot int, blank *Cell) {
 		// shift n lines upwards
 		limit := bot - n
 		for line := top; line <= limit && line <= b.Height()-1 && line <= bot; line++ {
-			copy(b.Lines[line], b.Lines[line+n])
+			b.Lines[line], b.Lines[line+n] = b.Lines[line+n], b.Lines[line]
 		}
 		for line := bot; line > limit && line >= 0 && line >= top; line-- {
 			b.FillRect(blank, Rect(0, line, b.Width(), 1))
@@ -283,7 +283,7 @@ func (s *Screen) costEffective(from, to int, blank bool) bool {
 func (s *Screen) updateCost(from, to Line) (cost int) {
 	var fidx, tidx int
 	for i := s.newbuf.Width() - 1; i > 0; i, fidx, tidx = i-1, fidx+1, tidx+1 {
-		if !cellEqual(from.At(fidx), to.At(tidx)) {
+		if !s.cellEqual(from.At(fidx), to.At(tidx)) {
 			cost++
 		}
 	}
@@ -293,7 +293,7 @@ func (s *Screen) updateCost(from, to Line) (cost int) {
 func (s *Screen) updateCostBlank(to Line) (cost int) {
 	var tidx int
 	for i := s.newbuf.Width() - 1; i > 0; i, tidx = i-1, tidx+1 {
-		if !cellEqual(nil, to.At(tidx)) {
+		if !s.cellEqual(nil, to.At(tidx)) {
 			cost++
 		}
 	}
@@ -12,3 +12,81 @@ func ConvertLink(h Link, p colorprofile.Profile) Link {
 
 	return h
 }
+
+// ownerCell returns the cell that owns the given position. Wide cell
+// placeholders belong to the wide cell on their left.
+func (b *Buffer) ownerCell(x, y int) (*Cell, int) {
+	c := b.Cell(x, y)
+	for i := 1; c != nil && c.Width == 0 && c.Rune == 0 && i < maxCellWidth && x-i >= 0; i++ {
+		if w := b.Cell(x-i, y); w != nil && w.Width > i {
+			return w, x - i
+		}
+	}
+	return c, x
+}
+
+// LinkAt returns the hyperlink at the given position and the positions of
+// all the cells that belong to it, in reading order. Wide cells are reported
+// at their first column. It returns an empty link when there is no hyperlink
+// at the position.
+//
+// Cells belong to the same hyperlink when they are adjacent and have the
+// same link, including across soft wrapped lines, see [Buffer.IsWrapped].
+// Hyperlinks with an id, see [Link.URLID], also include all the other cells
+// with the same link, since applications use ids to mark hyperlinks they
+// split across lines or windows.
+//
+// This is useful to resolve which hyperlink was clicked with the mouse, and
+// to highlight it.
+func (b *Buffer) LinkAt(x, y int) (Link, []Position) {
+	c, x := b.ownerCell(x, y)
+	if c == nil || c.Link.URL == "" {
+		return Link{}, nil
+	}
+	link := c.Link
+
+	var cells []Position
+	if link.URLID != "" {
+		for y, l := range b.Lines {
+			for x, c := range l {
+				if c != nil && c.Link == link {
+					cells = append(cells, Pos(x, y))
+				}
+			}
+		}
+		return link, cells
+	}
+
+	// Walk back to the first cell of the link.
+	top, bottom := b.LogicalLine(y)
+	sx, sy := x, y
+	for {
+		px, py := sx-1, sy
+		if px < 0 {
+			if py <= top {
+				break
+			}
+			px, py = b.Width()-1, py-1
+		}
+		if pc, cx := b.ownerCell(px, py); pc == nil || pc.Link != link {
+			break
+		} else {
+			sx, sy = cx, py
+		}
+	}
+
+	// Then walk forward to collect the cells of the link.
+	for x, y := sx, sy; y <= bottom; {
+		c := b.Cell(x, y)
+		if c == nil || c.Link != link {
+			break
+		}
+		cells = append(cells, Pos(x, y))
+		x += max(c.Width, 1)
+		if x >= b.Width() {
+			x, y = 0, y+1
+		}
+	}
+
+	return link, cells
+}
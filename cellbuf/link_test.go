@@ -0,0 +1,71 @@
+package cellbuf
+
+import (
+	"reflect"
+	"testing"
+)
+
+// setLinkText writes s at the given position with the given link.
+func setLinkText(b *Buffer, x, y int, s string, link Link) {
+	for _, r := range s {
+		c := NewCell(r)
+		c.Link = link
+		b.SetCell(x, y, c)
+		x += c.Width
+		if x >= b.Width() {
+			x, y = 0, y+1
+		}
+	}
+}
+
+func TestBufferLinkAt(t *testing.T) {
+	wrapped := Link{URL: "https://example.com/wrapped"}
+	wide := Link{URL: "https://example.com/wide"}
+	split := Link{URL: "https://example.com/split", URLID: "s1"}
+
+	b := NewBuffer(8, 5)
+	// A link soft wrapped from line 0 to line 1.
+	setLinkText(b, 5, 0, "abcde", wrapped)
+	b.SetWrapped(0, true)
+	// A link with a wide character.
+	setLinkText(b, 1, 2, "a世b", wide)
+	// A link split by the application on lines 3 and 4 with an id.
+	setLinkText(b, 6, 3, "ab", split)
+	setLinkText(b, 0, 4, "c", split)
+
+	tests := []struct {
+		name  string
+		x, y  int
+		link  Link
+		cells []Position
+	}{
+		{"no link", 0, 0, Link{}, nil},
+		{"out of bounds", 9, 9, Link{}, nil},
+		{
+			"wrapped start", 5, 0, wrapped,
+			[]Position{Pos(5, 0), Pos(6, 0), Pos(7, 0), Pos(0, 1), Pos(1, 1)},
+		},
+		{
+			"wrapped end", 1, 1, wrapped,
+			[]Position{Pos(5, 0), Pos(6, 0), Pos(7, 0), Pos(0, 1), Pos(1, 1)},
+		},
+		{"after wrapped", 2, 1, Link{}, nil},
+		{"wide", 1, 2, wide, []Position{Pos(1, 2), Pos(2, 2), Pos(4, 2)}},
+		{"wide placeholder", 3, 2, wide, []Position{Pos(1, 2), Pos(2, 2), Pos(4, 2)}},
+		{"split by id", 0, 4, split, []Position{Pos(6, 3), Pos(7, 3), Pos(0, 4)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, cells := b.LinkAt(tt.x, tt.y)
+			if link != tt.link || !reflect.DeepEqual(cells, tt.cells) {
+				t.Errorf("LinkAt(%d, %d) = (%v, %v), want (%v, %v)", tt.x, tt.y, link, cells, tt.link, tt.cells)
+			}
+		})
+	}
+
+	// Without the wrapped flag, the link ends at the end of the line.
+	b.SetWrapped(0, false)
+	if _, cells := b.LinkAt(0, 1); !reflect.DeepEqual(cells, []Position{Pos(0, 1), Pos(1, 1)}) {
+		t.Errorf("LinkAt(0, 1) = %v, want the cells of line 1", cells)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/charmbracelet/colorprofile"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/capability"
 	"github.com/charmbracelet/x/term"
 )
 
@@ -18,230 +19,55 @@ import (
 // for the operation.
 var ErrInvalidDimensions = errors.New("invalid dimensions")
 
-// notLocal returns whether the coordinates are not considered local movement
-// using the defined thresholds.
-// This takes the number of columns, and the coordinates of the current and
-// target positions.
-func notLocal(cols, fx, fy, tx, ty int) bool {
-	// The typical distance for a [ansi.CUP] sequence. Anything less than this
-	// is considered local movement.
-	const longDist = 8 - 1
-	return (tx > longDist) &&
-		(tx < cols-1-longDist) &&
-		(abs(ty-fy)+abs(tx-fx) > longDist)
-}
-
-// relativeCursorMove returns the relative cursor movement sequence using one or two
-// of the following sequences [ansi.CUU], [ansi.CUD], [ansi.CUF], [ansi.CUB],
-// [ansi.VPA], [ansi.HPA].
-// When overwrite is true, this will try to optimize the sequence by using the
-// screen cells values to move the cursor instead of using escape sequences.
-func relativeCursorMove(s *Screen, fx, fy, tx, ty int, overwrite, useTabs bool) string {
-	var seq strings.Builder
-
-	width, height := s.newbuf.Width(), s.newbuf.Height()
-	if ty != fy {
-		var yseq string
-		if s.xtermLike && !s.opts.RelativeCursor {
-			yseq = ansi.VerticalPositionAbsolute(ty + 1)
-		}
-
-		// OPTIM: Use [ansi.LF] and [ansi.ReverseIndex] as optimizations.
-
-		if ty > fy {
-			n := ty - fy
-			if cud := ansi.CursorDown(n); yseq == "" || len(cud) < len(yseq) {
-				yseq = cud
-			}
-			shouldScroll := !s.opts.AltScreen
-			if lf := strings.Repeat("\n", n); shouldScroll || (fy+n < height && len(lf) < len(yseq)) {
-				// TODO: Ensure we're not unintentionally scrolling the screen down.
-				yseq = lf
-			}
-		} else if ty < fy {
-			n := fy - ty
-			if cuu := ansi.CursorUp(n); yseq == "" || len(cuu) < len(yseq) {
-				yseq = cuu
-			}
-			if n == 1 && fy-1 > 0 {
-				// TODO: Ensure we're not unintentionally scrolling the screen up.
-				yseq = ansi.ReverseIndex
-			}
-		}
-
-		seq.WriteString(yseq)
-	}
-
-	if tx != fx {
-		var xseq string
-		if s.xtermLike && !s.opts.RelativeCursor {
-			xseq = ansi.HorizontalPositionAbsolute(tx + 1)
-		}
-
-		if tx > fx {
-			n := tx - fx
-			if useTabs && s.opts.HardTabs {
-				var tabs int
-				var col int
-				for col = fx; s.tabs.Next(col) <= tx; col = s.tabs.Next(col) {
-					tabs++
-					if col == s.tabs.Next(col) || col >= width-1 {
-						break
-					}
-				}
-
-				if tabs > 0 {
-					cht := ansi.CursorHorizontalForwardTab(tabs)
-					tab := strings.Repeat("\t", tabs)
-					if false && s.xtermLike && len(cht) < len(tab) {
-						// TODO: The linux console and some terminals such as
-						// Alacritty don't support [ansi.CHT]. Enable this when
-						// we have a way to detect this, or after 5 years when
-						// we're sure everyone has updated their terminals :P
-						seq.WriteString(cht)
-					} else {
-						seq.WriteString(tab)
-					}
-
-					n = tx - col
-					fx = col
-				}
-			}
-
-			if cuf := ansi.CursorForward(n); xseq == "" || len(cuf) < len(xseq) {
-				xseq = cuf
-			}
-
-			// If we have no attribute and style changes, overwrite is cheaper.
-			var ovw string
-			if overwrite && ty >= 0 {
-				for i := 0; i < n; i++ {
-					cell := s.newbuf.Cell(fx+i, ty)
-					if cell != nil {
-						i += cell.Width - 1
-						if !cell.Style.Equal(s.cur.Style) || !cell.Link.Equal(s.cur.Link) {
-							overwrite = false
-							break
-						}
-					}
-				}
-			}
-
-			if overwrite && ty >= 0 {
-				for i := 0; i < n; i++ {
-					cell := s.newbuf.Cell(fx+i, ty)
-					if cell != nil {
-						ovw += cell.String()
-						i += cell.Width - 1
-					} else {
-						ovw += " "
-					}
-				}
-			}
-
-			if overwrite && len(ovw) < len(xseq) {
-				xseq = ovw
-			}
-		} else if tx < fx {
-			n := fx - tx
-			if useTabs && s.opts.HardTabs && s.xtermLike {
-				// VT100 does not support backward tabs [ansi.CBT].
-
-				col := fx
-
-				var cbt int // cursor backward tabs count
-				for s.tabs.Prev(col) >= tx {
-					col = s.tabs.Prev(col)
-					cbt++
-					if col == s.tabs.Prev(col) || col <= 0 {
-						break
-					}
-				}
-
-				if cbt > 0 {
-					seq.WriteString(ansi.CursorBackwardTab(cbt))
-					n = col - tx
-				}
-			}
-
-			if bs := strings.Repeat("\b", n); xseq == "" || len(bs) < len(xseq) {
-				xseq = bs
-			}
-
-			if cub := ansi.CursorBackward(n); len(cub) < len(xseq) {
-				xseq = cub
-			}
-		}
-
-		seq.WriteString(xseq)
+// cursorOptimizer returns the cursor movement optimizer for the current
+// screen state.
+func (s *Screen) cursorOptimizer() CursorOptimizer {
+	return CursorOptimizer{
+		Width:          s.newbuf.Width(),
+		Height:         s.newbuf.Height(),
+		Tabs:           s.tabs,
+		XtermLike:      s.xtermLike,
+		RelativeCursor: s.opts.RelativeCursor,
+		HardTabs:       s.opts.HardTabs,
+		AltScreen:      s.opts.AltScreen,
 	}
-
-	return seq.String()
 }
 
-// moveCursor moves and returns the cursor movement sequence to move the cursor
-// to the specified position.
-// When overwrite is true, this will try to optimize the sequence by using the
-// screen cells values to move the cursor instead of using escape sequences.
-func moveCursor(s *Screen, x, y int, overwrite bool) (seq string) {
-	fx, fy := s.cur.X, s.cur.Y
-
-	if !s.opts.RelativeCursor {
-		// Method #0: Use [ansi.CUP] if the distance is long.
-		seq = ansi.CursorPosition(x+1, y+1)
-		if fx == -1 || fy == -1 || notLocal(s.newbuf.Width(), fx, fy, x, y) {
-			return
-		}
-	}
-
-	// Method #1: Use local movement sequences.
-	nseq := relativeCursorMove(s, fx, fy, x, y, overwrite, false)
-	if len(seq) == 0 || len(nseq) < len(seq) {
-		seq = nseq
-	}
-
-	// Method #2: Use [ansi.CR] and local movement sequences.
-	nseq = "\r" + relativeCursorMove(s, 0, fy, x, y, overwrite, false)
-	if len(nseq) < len(seq) {
-		seq = nseq
-	}
-
-	if !s.opts.RelativeCursor {
-		// Method #3: Use [ansi.CursorHomePosition] and local movement sequences.
-		nseq = ansi.CursorHomePosition + relativeCursorMove(s, 0, 0, x, y, overwrite, false)
-		if len(nseq) < len(seq) {
-			seq = nseq
+// overwriteCells returns the content of the n cells starting at the given
+// position. It returns false if any of the cells has a different style or
+// link than the current pen i.e. printing them would require changing it.
+func (s *Screen) overwriteCells(x, y, n int) (string, bool) {
+	for i := 0; i < n; i++ {
+		cell := s.newbuf.Cell(x+i, y)
+		if cell != nil {
+			i += cell.Width - 1
+			if !cell.Style.Equal(s.cur.Style) || !cell.Link.Equal(s.cur.Link) {
+				return "", false
+			}
 		}
 	}
 
-	if s.opts.HardTabs {
-		// Method #4: Use tab optimized local movement sequences.
-		nseq := relativeCursorMove(s, fx, fy, x, y, overwrite, true)
-		if len(nseq) < len(seq) {
-			seq = nseq
-		}
-
-		// Method #5: Use [ansi.CR] and tab optimized local movement sequences.
-		nseq = "\r" + relativeCursorMove(s, 0, fy, x, y, overwrite, true)
-		if len(nseq) < len(seq) {
-			seq = nseq
-		}
-
-		if !s.opts.RelativeCursor {
-			// Method #6: Use [ansi.CursorHomePosition] and tab optimized local movement sequences.
-			nseq = ansi.CursorHomePosition + relativeCursorMove(s, 0, 0, x, y, overwrite, true)
-			if len(nseq) < len(seq) {
-				seq = nseq
-			}
+	var ovw strings.Builder
+	for i := 0; i < n; i++ {
+		cell := s.newbuf.Cell(x+i, y)
+		if cell != nil {
+			ovw.WriteString(cell.String())
+			i += cell.Width - 1
+		} else {
+			ovw.WriteByte(' ')
 		}
 	}
-
-	return
+	return ovw.String(), true
 }
 
 // moveCursor moves the cursor to the specified position.
 func (s *Screen) moveCursor(x, y int, overwrite bool) {
-	s.buf.WriteString(moveCursor(s, x, y, overwrite)) //nolint:errcheck
+	var ovw CursorOverwriteFunc
+	if overwrite {
+		ovw = s.overwriteCells
+	}
+	seq := s.cursorOptimizer().Move(s.cur.X, s.cur.Y, x, y, ovw)
+	s.buf.WriteString(seq) //nolint:errcheck
 	s.cur.X, s.cur.Y = x, y
 }
 
@@ -315,6 +141,12 @@ type ScreenOptions struct {
 	Height int
 	// Profile is the color profile to use when writing to the screen.
 	Profile colorprofile.Profile
+	// Capabilities are the terminal capabilities. When set, underline styles
+	// and colors are only used when the terminal supports
+	// [capability.StyledUnderlines], otherwise they fall back to plain
+	// underlines. When zero, they're only disabled for terminals known not to
+	// support them, like the Linux console.
+	Capabilities capability.Set
 	// RelativeCursor is whether to use relative cursor movements. This is
 	// useful when alt-screen is not used or when using inline mode.
 	RelativeCursor bool
@@ -324,6 +156,18 @@ type ScreenOptions struct {
 	ShowCursor bool
 	// HardTabs is whether to use hard tabs to optimize cursor movements.
 	HardTabs bool
+	// SyncOutput is whether to wrap screen updates in synchronized output
+	// mode [ansi.SynchronizedOutputMode] (mode 2026). Only enable this when
+	// the terminal supports it.
+	SyncOutput bool
+	// CellEqual is the set of options used to compare cells when computing
+	// the screen changes. Relaxing the comparison avoids repainting cells
+	// that look the same on the terminal.
+	CellEqual CellEqualOptions
+	// Allocator is the allocator used to allocate the cells of the screen
+	// buffers. With an allocator, the screen reuses its cells and steady
+	// updates don't allocate. See [Buffer.SetAllocator].
+	Allocator CellAllocator
 }
 
 // lineData represents the metadata for a line.
@@ -351,11 +195,13 @@ type Screen struct {
 	pos              Position // the position of the cursor after the last render
 	mu               sync.Mutex
 	method           ansi.Method
+	annotations      map[string]Annotation
 	altScreenMode    bool // whether alternate screen mode is enabled
 	cursorHidden     bool // whether text cursor mode is enabled
 	clear            bool // whether to force clear the screen
 	xtermLike        bool // whether to use xterm-like optimizations, otherwise, it uses vt100 only
 	queuedText       bool // whether we have queued non-zero width text queued up
+	styledUnderlines bool // whether to use underline styles and colors
 }
 
 // SetMethod sets the method used to calculate the width of cells.
@@ -368,6 +214,21 @@ func (s *Screen) UseHardTabs(v bool) {
 	s.opts.HardTabs = v
 }
 
+// UseSyncOutput sets whether to wrap screen updates in synchronized output
+// mode [ansi.SynchronizedOutputMode].
+func (s *Screen) UseSyncOutput(v bool) {
+	s.opts.SyncOutput = v
+}
+
+// Cursor returns the physical cursor state i.e. the cursor position and pen
+// after the last flush. A negative position means the cursor position is
+// unknown.
+func (s *Screen) Cursor() Cursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
 // SetColorProfile sets the color profile to use when writing to the screen.
 func (s *Screen) SetColorProfile(p colorprofile.Profile) {
 	s.opts.Profile = p
@@ -432,7 +293,7 @@ func (s *Screen) SetCell(x int, y int, cell *Cell) (v bool) {
 	if cell != nil {
 		cellWidth = cell.Width
 	}
-	if prev := s.curbuf.Cell(x, y); !cellEqual(prev, cell) {
+	if prev := s.curbuf.Cell(x, y); !s.cellEqual(prev, cell) {
 		chg, ok := s.touch[y]
 		if !ok {
 			chg = lineData{firstCell: x, lastCell: x + cellWidth}
@@ -494,6 +355,26 @@ func isXtermLike(termtype string) (v bool) {
 	return
 }
 
+// hasStyledUnderlines returns whether underline styles and colors can be used
+// with the given terminal. Explicit capabilities are used as is. Otherwise,
+// they're only disabled for known terminals that don't support them, since
+// most terminals, whatever their TERM, do.
+func hasStyledUnderlines(termtype string, caps capability.Set) bool {
+	if caps != capability.None {
+		return caps.Has(capability.StyledUnderlines)
+	}
+	if caps := capability.FromTerm(termtype); caps != capability.None {
+		return caps.Has(capability.StyledUnderlines)
+	}
+
+	name, _, _ := strings.Cut(termtype, "-")
+	switch name {
+	case "ansi", "cons25", "dumb", "linux", "vt100", "vt102", "vt220", "vt320":
+		return false
+	}
+	return true
+}
+
 // NewScreen creates a new Screen.
 func NewScreen(w io.Writer, opts *ScreenOptions) (s *Screen) {
 	s = new(Screen)
@@ -515,8 +396,11 @@ func NewScreen(w io.Writer, opts *ScreenOptions) (s *Screen) {
 
 	s.buf = new(bytes.Buffer)
 	s.xtermLike = isXtermLike(s.opts.Term)
+	s.styledUnderlines = hasStyledUnderlines(s.opts.Term, s.opts.Capabilities)
 	s.curbuf = NewBuffer(width, height)
 	s.newbuf = NewBuffer(width, height)
+	s.curbuf.SetAllocator(s.opts.Allocator)
+	s.newbuf.SetAllocator(s.opts.Allocator)
 	s.reset()
 
 	return
@@ -547,6 +431,12 @@ func cellEqual(a, b *Cell) bool {
 	return a.Equal(b)
 }
 
+// cellEqual returns whether the two cells are equal using the screen cell
+// comparison options. A nil cell is considered a [BlankCell].
+func (s *Screen) cellEqual(a, b *Cell) bool {
+	return CellEqual(a, b, s.opts.CellEqual)
+}
+
 // putCell draws a cell at the current cursor position.
 func (s *Screen) putCell(cell *Cell) {
 	width, height := s.newbuf.Width(), s.newbuf.Height()
@@ -620,6 +510,9 @@ func (s *Screen) updatePen(cell *Cell) {
 		style = ConvertStyle(style, s.opts.Profile)
 		link = ConvertLink(link, s.opts.Profile)
 	}
+	if !s.styledUnderlines {
+		style = PlainUnderline(style)
+	}
 
 	if !style.Equal(s.cur.Style) {
 		seq := style.DiffSequence(s.cur.Style)
@@ -643,7 +536,7 @@ func (s *Screen) updatePen(cell *Cell) {
 func (s *Screen) emitRange(line Line, n int) (eoi bool) {
 	for n > 0 {
 		var count int
-		for n > 1 && !cellEqual(line.At(0), line.At(1)) {
+		for n > 1 && !s.cellEqual(line.At(0), line.At(1)) {
 			s.putCell(line.At(0))
 			line = line[1:]
 			n--
@@ -656,16 +549,34 @@ func (s *Screen) emitRange(line Line, n int) (eoi bool) {
 		}
 
 		count = 2
-		for count < n && cellEqual(line.At(count), cell0) {
+		for count < n && s.cellEqual(line.At(count), cell0) {
 			count++
 		}
 
-		ech := ansi.EraseCharacter(count)
-		cup := ansi.CursorPosition(s.cur.X+count, s.cur.Y)
+		// Runs of blank cells can be erased using [ansi.ECH] followed by a
+		// cursor jump, or [ansi.EL] 0 when the run reaches the end of the
+		// line. Both use the current pen background color.
+		var erase, jump string
+		if s.xtermLike && (cell0 == nil || cell0.Clear()) {
+			if s.cur.X+count >= s.newbuf.Width() {
+				erase = ansi.EraseLineRight
+			} else {
+				erase = ansi.EraseCharacter(count)
+				if count < n {
+					jump = s.cursorOptimizer().Move(s.cur.X, s.cur.Y, s.cur.X+count, s.cur.Y, nil)
+				}
+			}
+		}
+
 		rep := ansi.RepeatPreviousCharacter(count)
-		if s.xtermLike && count > len(ech)+len(cup) && cell0 != nil && cell0.Clear() {
+		// REP repeats the last printed rune, so it can't be used for wide
+		// cells or their placeholders.
+		canRep := s.xtermLike && count > len(rep) &&
+			(cell0 == nil || (len(cell0.Comb) == 0 && cell0.Rune < 256 && cell0.Width == 1))
+		eraseCost := len(erase) + len(jump)
+		if erase != "" && count > eraseCost && (!canRep || eraseCost < len(rep)+1) {
 			s.updatePen(cell0)
-			s.buf.WriteString(ech) //nolint:errcheck
+			s.buf.WriteString(erase) //nolint:errcheck
 
 			// If this is the last cell, we don't need to move the cursor.
 			if count < n {
@@ -673,8 +584,7 @@ func (s *Screen) emitRange(line Line, n int) (eoi bool) {
 			} else {
 				return true // cursor in the middle
 			}
-		} else if s.xtermLike && count > len(rep) &&
-			(cell0 == nil || (len(cell0.Comb) == 0 && cell0.Rune < 256)) {
+		} else if canRep {
 			// We only support ASCII characters. Most terminals will handle
 			// non-ASCII characters correctly, but some might not, ahem xterm.
 			//
@@ -723,7 +633,7 @@ func (s *Screen) putRange(oldLine, newLine Line, y, start, end int) (eoi bool) {
 			if same == 0 && oldCell != nil && oldCell.Empty() {
 				continue
 			}
-			if cellEqual(oldCell, newCell) {
+			if s.cellEqual(oldCell, newCell) {
 				same++
 			} else {
 				if same > end-start {
@@ -756,7 +666,7 @@ func (s *Screen) clearToEnd(blank *Cell, force bool) {
 		for j := s.cur.X; j < s.curbuf.Width(); j++ {
 			if j >= 0 {
 				c := curline.At(j)
-				if !cellEqual(c, blank) {
+				if !s.cellEqual(c, blank) {
 					curline.Set(j, blank)
 					force = true
 				}
@@ -830,7 +740,7 @@ func (s *Screen) transformLine(y int) {
 	// Find the first changed cell in the line
 	var lineChanged bool
 	for i := 0; i < s.newbuf.Width(); i++ {
-		if !cellEqual(newLine.At(i), oldLine.At(i)) {
+		if !s.cellEqual(newLine.At(i), oldLine.At(i)) {
 			lineChanged = true
 			break
 		}
@@ -849,12 +759,12 @@ func (s *Screen) transformLine(y int) {
 		if blank == nil || blank.Clear() {
 			var oFirstCell, nFirstCell int
 			for oFirstCell = 0; oFirstCell < s.curbuf.Width(); oFirstCell++ {
-				if !cellEqual(oldLine.At(oFirstCell), blank) {
+				if !s.cellEqual(oldLine.At(oFirstCell), blank) {
 					break
 				}
 			}
 			for nFirstCell = 0; nFirstCell < s.newbuf.Width(); nFirstCell++ {
-				if !cellEqual(newLine.At(nFirstCell), blank) {
+				if !s.cellEqual(newLine.At(nFirstCell), blank) {
 					break
 				}
 			}
@@ -864,7 +774,7 @@ func (s *Screen) transformLine(y int) {
 
 				// Find the first differing cell
 				for firstCell < s.newbuf.Width() &&
-					cellEqual(oldLine.At(firstCell), newLine.At(firstCell)) {
+					s.cellEqual(oldLine.At(firstCell), newLine.At(firstCell)) {
 					firstCell++
 				}
 			} else if oFirstCell > nFirstCell {
@@ -891,7 +801,7 @@ func (s *Screen) transformLine(y int) {
 			}
 		} else {
 			// Find the first differing cell
-			for firstCell < s.newbuf.Width() && cellEqual(newLine.At(firstCell), oldLine.At(firstCell)) {
+			for firstCell < s.newbuf.Width() && s.cellEqual(newLine.At(firstCell), oldLine.At(firstCell)) {
 				firstCell++
 			}
 		}
@@ -905,14 +815,14 @@ func (s *Screen) transformLine(y int) {
 		if blank != nil && !blank.Clear() {
 			// Find the last differing cell
 			nLastCell = s.newbuf.Width() - 1
-			for nLastCell > firstCell && cellEqual(newLine.At(nLastCell), oldLine.At(nLastCell)) {
+			for nLastCell > firstCell && s.cellEqual(newLine.At(nLastCell), oldLine.At(nLastCell)) {
 				nLastCell--
 			}
 
 			if nLastCell >= firstCell {
 				s.move(firstCell, y)
 				s.putRange(oldLine, newLine, y, firstCell, nLastCell)
-				copy(oldLine[firstCell:], newLine[firstCell:])
+				s.copyCells(oldLine, newLine, firstCell)
 			}
 
 			return
@@ -920,24 +830,24 @@ func (s *Screen) transformLine(y int) {
 
 		// Find last non-blank cell in the old line.
 		oLastCell = s.curbuf.Width() - 1
-		for oLastCell > firstCell && cellEqual(oldLine.At(oLastCell), blank) {
+		for oLastCell > firstCell && s.cellEqual(oldLine.At(oLastCell), blank) {
 			oLastCell--
 		}
 
 		// Find last non-blank cell in the new line.
 		nLastCell = s.newbuf.Width() - 1
-		for nLastCell > firstCell && cellEqual(newLine.At(nLastCell), blank) {
+		for nLastCell > firstCell && s.cellEqual(newLine.At(nLastCell), blank) {
 			nLastCell--
 		}
 
 		if nLastCell == firstCell && s.el0Cost() < oLastCell-nLastCell {
 			s.move(firstCell, y)
-			if !cellEqual(newLine.At(firstCell), blank) {
+			if !s.cellEqual(newLine.At(firstCell), blank) {
 				s.putCell(newLine.At(firstCell))
 			}
 			s.clearToEnd(blank, false)
 		} else if nLastCell != oLastCell &&
-			!cellEqual(newLine.At(nLastCell), oldLine.At(oLastCell)) {
+			!s.cellEqual(newLine.At(nLastCell), oldLine.At(oLastCell)) {
 			s.move(firstCell, y)
 			if oLastCell-nLastCell > s.el0Cost() {
 				if s.putRange(oldLine, newLine, y, firstCell, nLastCell) {
@@ -954,8 +864,8 @@ func (s *Screen) transformLine(y int) {
 
 			// Find the last cells that really differ.
 			// Can be -1 if no cells differ.
-			for cellEqual(newLine.At(nLastCell), oldLine.At(oLastCell)) {
-				if !cellEqual(newLine.At(nLastCell-1), oldLine.At(oLastCell-1)) {
+			for s.cellEqual(newLine.At(nLastCell), oldLine.At(oLastCell)) {
+				if !s.cellEqual(newLine.At(nLastCell-1), oldLine.At(oLastCell-1)) {
 					break
 				}
 				nLastCell--
@@ -1015,7 +925,30 @@ func (s *Screen) transformLine(y int) {
 
 	// Update the old line with the new line
 	if s.newbuf.Width() >= firstCell && len(oldLine) != 0 {
-		copy(oldLine[firstCell:], newLine[firstCell:])
+		s.copyCells(oldLine, newLine, firstCell)
+	}
+}
+
+// copyCells copies the cells of the new line starting at the given column
+// to the old line. With an allocator, the cells are copied into the old line
+// cells instead of being shared between the buffers, so that the new buffer
+// can reuse its cells.
+func (s *Screen) copyCells(oldLine, newLine Line, first int) {
+	if s.opts.Allocator == nil {
+		copy(oldLine[first:], newLine[first:])
+		return
+	}
+
+	for i := first; i < len(oldLine) && i < len(newLine); i++ {
+		c, old := newLine[i], oldLine[i]
+		switch {
+		case c == nil:
+			oldLine[i] = nil
+		case old != nil:
+			*old = *c
+		default:
+			oldLine[i] = cloneCell(s.opts.Allocator, c)
+		}
 	}
 }
 
@@ -1060,14 +993,14 @@ func (s *Screen) clearBottom(total int, force bool) (top int) {
 			var col int
 			var ok bool
 			for col, ok = 0, true; ok && col < last; col++ {
-				ok = cellEqual(s.newbuf.Cell(col, row), blank)
+				ok = s.cellEqual(s.newbuf.Cell(col, row), blank)
 			}
 			if !ok {
 				break
 			}
 
 			for col = 0; ok && col < last; col++ {
-				ok = cellEqual(s.curbuf.Cell(col, row), blank)
+				ok = s.cellEqual(s.curbuf.Cell(col, row), blank)
 			}
 			if !ok {
 				top = row
@@ -1130,14 +1063,39 @@ func (s *Screen) clearUpdate(partial bool) {
 
 // Render implements Window.
 func (s *Screen) Render() {
+	s.Flush() //nolint:errcheck
+}
+
+// Flush renders the pending changes and writes them to the underlying
+// writer. When [ScreenOptions.SyncOutput] is set, the update is wrapped in
+// synchronized output mode so the terminal displays it atomically.
+func (s *Screen) Flush() error {
 	s.mu.Lock()
+	defer s.mu.Unlock()
+	restore := s.applyAnnotations()
 	s.render()
-	// Write the buffer
-	if s.buf.Len() > 0 {
-		s.w.Write(s.buf.Bytes()) //nolint:errcheck
+	restore()
+	return s.flush()
+}
+
+// flush writes the buffer to the underlying writer and resets it.
+func (s *Screen) flush() (err error) {
+	if s.buf.Len() == 0 {
+		return nil
+	}
+
+	out := s.buf
+	if s.opts.SyncOutput {
+		out = new(bytes.Buffer)
+		out.Grow(s.buf.Len() + len(ansi.SetSynchronizedOutputMode) + len(ansi.ResetSynchronizedOutputMode))
+		out.WriteString(ansi.SetSynchronizedOutputMode)
+		out.Write(s.buf.Bytes())
+		out.WriteString(ansi.ResetSynchronizedOutputMode)
 	}
+
+	_, err = s.w.Write(out.Bytes())
 	s.buf.Reset()
-	s.mu.Unlock()
+	return
 }
 
 func (s *Screen) render() {
@@ -1249,7 +1207,7 @@ func (s *Screen) render() {
 		s.curbuf.Resize(s.newbuf.Width(), s.newbuf.Height())
 		// Sync new lines to old lines
 		for i := oldh - 1; i < s.newbuf.Height(); i++ {
-			copy(s.curbuf.Line(i), s.newbuf.Line(i))
+			s.copyCells(s.curbuf.Line(i), s.newbuf.Line(i), 0)
 		}
 	}
 
@@ -1269,7 +1227,7 @@ func (s *Screen) render() {
 			nb.WriteString(ansi.HideCursor)
 			nb.Write(s.buf.Bytes())
 			nb.WriteString(ansi.ShowCursor)
-			*s.buf = *nb
+			s.buf = nb
 		}
 	}
 
@@ -1285,7 +1243,9 @@ func (s *Screen) Close() (err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	restore := s.applyAnnotations()
 	s.render()
+	restore()
 	s.updatePen(nil)
 	s.move(0, s.newbuf.Height()-1)
 	s.clearToEnd(nil, true)
@@ -1301,9 +1261,7 @@ func (s *Screen) Close() (err error) {
 	}
 
 	// Write the buffer
-	_, err = s.w.Write(s.buf.Bytes())
-	s.buf.Reset()
-	if err != nil {
+	if err = s.flush(); err != nil {
 		return
 	}
 
@@ -1436,7 +1394,9 @@ func (s *Screen) Printw(x, y int, str string) {
 // printString draws a string starting at the given position.
 func (s *Screen) printString(x, y int, str string, truncate bool, tail string) {
 	wrapCursor := func() {
-		// Wrap the string to the width of the window
+		// Wrap the string to the width of the window and mark the line as
+		// soft wrapped.
+		s.newbuf.SetWrapped(y, true)
 		x = 0
 		y++
 	}
@@ -1519,6 +1479,7 @@ func (s *Screen) printString(x, y int, str string, truncate bool, tail string) {
 				}
 			case ansi.Equal(seq, "\n"):
 				if y+1 < s.Height() {
+					s.newbuf.SetWrapped(y, false)
 					y++
 				}
 			case ansi.Equal(seq, "\r"):
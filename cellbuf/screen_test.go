@@ -0,0 +1,224 @@
+package cellbuf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/capability"
+)
+
+func TestScreenFlushSyncOutput(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreen(&out, &ScreenOptions{
+		Term:       "xterm-256color",
+		Width:      10,
+		Height:     2,
+		AltScreen:  true,
+		SyncOutput: true,
+	})
+
+	s.SetCell(0, 0, NewCell('a'))
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	got := out.String()
+	if !strings.HasPrefix(got, ansi.SetSynchronizedOutputMode) ||
+		!strings.HasSuffix(got, ansi.ResetSynchronizedOutputMode) {
+		t.Errorf("Flush() output %q is not wrapped in synchronized output mode", got)
+	}
+	if !strings.Contains(got, "a") {
+		t.Errorf("Flush() output %q does not contain the cell content", got)
+	}
+
+	// Nothing changed, nothing should be written.
+	out.Reset()
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Flush() wrote %q with no pending changes", out.String())
+	}
+}
+
+func TestScreenCursor(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreen(&out, &ScreenOptions{
+		Term:      "xterm-256color",
+		Width:     10,
+		Height:    2,
+		AltScreen: true,
+	})
+
+	if !s.MoveTo(3, 1) {
+		t.Fatal("MoveTo(3, 1) = false, want true")
+	}
+	if s.MoveTo(10, 0) {
+		t.Error("MoveTo(10, 0) = true, want false")
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	cur := s.Cursor()
+	if cur.X != 3 || cur.Y != 1 {
+		t.Errorf("Cursor() = (%d,%d), want (3,1)", cur.X, cur.Y)
+	}
+	if !cur.Style.Empty() {
+		t.Errorf("Cursor().Style = %#v, want empty", cur.Style)
+	}
+}
+
+func TestScreenEraseBlankRuns(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreen(&out, &ScreenOptions{
+		Term:      "xterm-256color",
+		Width:     80,
+		Height:    2,
+		AltScreen: true,
+	})
+
+	blank := BlankCell
+	blank.Style.Background(ansi.Blue)
+
+	// A styled blank run in the middle of the line followed by a styled blank
+	// run that reaches the end of the line.
+	s.SetCell(0, 0, NewCell('a'))
+	for x := 1; x < 80; x++ {
+		s.SetCell(x, 0, &blank)
+	}
+	s.SetCell(30, 0, NewCell('b'))
+
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "\x1b[?1049h\x1b[?25l\x1b[1;1Ha\x1b[44m \x1b[28b\x1b[mb\x1b[44m\x1b[K\x1b[m\r"
+	if got := out.String(); got != want {
+		t.Errorf("Flush() output = %q, want %q", got, want)
+	}
+}
+
+func TestScreenWideCellBlankRun(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreen(&out, &ScreenOptions{
+		Term:      "xterm-256color",
+		Width:     30,
+		Height:    1,
+		AltScreen: true,
+		CellEqual: BlankEqualsEmpty,
+	})
+
+	for x := 0; x < 30; x++ {
+		s.SetCell(x, 0, NewCell('x'))
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	out.Reset()
+
+	// The placeholder after the wide cell must not start a run with the
+	// blanks that follow it, REP would repeat the wide rune instead.
+	s.SetCell(0, 0, NewCell('a'))
+	s.SetCell(1, 0, NewCell('世'))
+	for x := 3; x < 29; x++ {
+		s.SetCell(x, 0, &BlankCell)
+	}
+	s.SetCell(29, 0, NewCell('b'))
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "a世 \x1b[25b\x1b[?7lb\x1b[?7h"
+	if got := out.String(); got != want {
+		t.Errorf("Flush() output = %q, want %q", got, want)
+	}
+}
+
+func TestScreenAnnotations(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreen(&out, &ScreenOptions{
+		Term:      "xterm-256color",
+		Width:     10,
+		Height:    2,
+		AltScreen: true,
+	})
+	s.SetMethod(ansi.GraphemeWidth)
+
+	s.SetContent("hello")
+	var style Style
+	style.Reverse(true)
+	s.SetAnnotation("ime", Annotation{Text: "ab", Style: style, Position: Pos(1, 0)})
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if !strings.Contains(out.String(), "\x1b[7mab") {
+		t.Errorf("Flush() output %q does not contain the annotation", out.String())
+	}
+
+	// The screen buffer must not be modified by annotations.
+	if c := s.Cell(1, 0); c == nil || c.Rune != 'e' {
+		t.Errorf("Cell(1, 0) = %#v, want 'e'", c)
+	}
+
+	// Removing the annotation restores the screen contents.
+	out.Reset()
+	s.RemoveAnnotation("ime")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "el") {
+		t.Errorf("Flush() output %q does not restore the screen contents", out.String())
+	}
+	if _, ok := s.Annotation("ime"); ok {
+		t.Error("Annotation() found a removed annotation")
+	}
+}
+
+func TestScreenStyledUnderlines(t *testing.T) {
+	tests := []struct {
+		name string
+		term string
+		caps capability.Set
+		want string
+		not  string
+	}{
+		{"supported", "xterm-256color", capability.StyledUnderlines, "\x1b[4:3;58:5:196m", ""},
+		{"from term", "xterm-kitty", capability.None, "\x1b[4:3;58:5:196m", ""},
+		{"default term", "xterm-256color", capability.None, "\x1b[4:3;58:5:196m", ""},
+		{"unknown term", "foo", capability.None, "\x1b[4:3;58:5:196m", ""},
+		{"fallback", "linux", capability.None, "\x1b[4m", "58"},
+		{"fallback from term", "st-256color", capability.None, "\x1b[4m", "58"},
+		{"fallback with caps", "xterm-kitty", capability.TrueColor, "\x1b[4m", "58"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s := NewScreen(&out, &ScreenOptions{
+				Term:         tt.term,
+				Width:        10,
+				Height:       2,
+				AltScreen:    true,
+				Capabilities: tt.caps,
+			})
+
+			c := NewCell('a')
+			c.Style.UnderlineStyle(CurlyUnderline).UnderlineColor(ansi.ExtendedColor(196))
+			s.SetCell(0, 0, c)
+			if err := s.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+
+			got := out.String()
+			if !strings.Contains(got, tt.want+"a") {
+				t.Errorf("Flush() output %q does not contain %q", got, tt.want+"a")
+			}
+			if tt.not != "" && strings.Contains(got, tt.not) {
+				t.Errorf("Flush() output %q contains %q", got, tt.not)
+			}
+		})
+	}
+}
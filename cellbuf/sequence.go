@@ -15,8 +15,9 @@ func ReadStyle(params ansi.Params, pen *Style) {
 		return
 	}
 
-	for i := 0; i < len(params); i++ {
-		param, hasMore, _ := params.Param(i, 0)
+	for i := 0; i < len(params); {
+		g := params.Group(i)
+		param, n := g[0].Param(0), len(g)
 		switch param {
 		case 0: // Reset
 			pen.Reset()
@@ -27,29 +28,8 @@ func ReadStyle(params ansi.Params, pen *Style) {
 		case 3: // Italic
 			pen.Italic(true)
 		case 4: // Underline
-			nextParam, _, ok := params.Param(i+1, 0)
-			if hasMore && ok { // Only accept subparameters i.e. separated by ":"
-				switch nextParam {
-				case 0, 1, 2, 3, 4, 5:
-					i++
-					switch nextParam {
-					case 0: // No Underline
-						pen.UnderlineStyle(NoUnderline)
-					case 1: // Single Underline
-						pen.UnderlineStyle(SingleUnderline)
-					case 2: // Double Underline
-						pen.UnderlineStyle(DoubleUnderline)
-					case 3: // Curly Underline
-						pen.UnderlineStyle(CurlyUnderline)
-					case 4: // Dotted Underline
-						pen.UnderlineStyle(DottedUnderline)
-					case 5: // Dashed Underline
-						pen.UnderlineStyle(DashedUnderline)
-					}
-				}
-			} else {
-				// Single Underline
-				pen.Underline(true)
+			if u, ok := ansi.ReadUnderlineStyle(g); ok {
+				pen.UnderlineStyle(u)
 			}
 		case 5: // Slow Blink
 			pen.SlowBlink(true)
@@ -79,10 +59,9 @@ func ReadStyle(params ansi.Params, pen *Style) {
 			pen.Foreground(ansi.Black + ansi.BasicColor(param-30)) //nolint:gosec
 		case 38: // Set foreground 256 or truecolor
 			var c color.Color
-			n := ReadStyleColor(params[i:], &c)
-			if n > 0 {
+			if m := ReadStyleColor(params[i:], &c); m > 0 {
 				pen.Foreground(c)
-				i += n - 1
+				n = max(n, m)
 			}
 		case 39: // Default foreground
 			pen.Foreground(nil)
@@ -90,19 +69,17 @@ func ReadStyle(params ansi.Params, pen *Style) {
 			pen.Background(ansi.Black + ansi.BasicColor(param-40)) //nolint:gosec
 		case 48: // Set background 256 or truecolor
 			var c color.Color
-			n := ReadStyleColor(params[i:], &c)
-			if n > 0 {
+			if m := ReadStyleColor(params[i:], &c); m > 0 {
 				pen.Background(c)
-				i += n - 1
+				n = max(n, m)
 			}
 		case 49: // Default Background
 			pen.Background(nil)
 		case 58: // Set underline color
 			var c color.Color
-			n := ReadStyleColor(params[i:], &c)
-			if n > 0 {
+			if m := ReadStyleColor(params[i:], &c); m > 0 {
 				pen.UnderlineColor(c)
-				i += n - 1
+				n = max(n, m)
 			}
 		case 59: // Default underline color
 			pen.UnderlineColor(nil)
@@ -111,15 +88,17 @@ func ReadStyle(params ansi.Params, pen *Style) {
 		case 100, 101, 102, 103, 104, 105, 106, 107: // Set bright background
 			pen.Background(ansi.BrightBlack + ansi.BasicColor(param-100)) //nolint:gosec
 		}
+		i += n
 	}
 }
 
 // ReadLink reads a hyperlink escape sequence from a data buffer.
 func ReadLink(p []byte, link *Link) {
-	params := bytes.Split(p, []byte{';'})
+	params := bytes.SplitN(p, []byte{';'}, 3)
 	if len(params) != 3 {
 		return
 	}
+	link.Reset()
 	for _, param := range bytes.Split(params[1], []byte{':'}) {
 		if bytes.HasPrefix(param, []byte("id=")) {
 			link.URLID = string(param)
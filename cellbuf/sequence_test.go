@@ -376,3 +376,56 @@ func TestReadStyleColor(t *testing.T) {
 		})
 	}
 }
+
+func TestReadStyleSubParams(t *testing.T) {
+	// 4:3;1 followed by an invalid underline style whose sub-parameter isn't
+	// read as an attribute.
+	params := ansi.Params{4 | parser.HasMoreFlag, 3, 1, 4 | parser.HasMoreFlag, 7}
+	var pen Style
+	ReadStyle(params, &pen)
+	want := Style{Attrs: BoldAttr, UlStyle: CurlyUnderline}
+	if !pen.Equal(want) {
+		t.Errorf("ReadStyle() = %+v, want %+v", pen, want)
+	}
+}
+
+func TestReadLink(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Link
+	}{
+		{"url", "8;;https://example.com", Link{URL: "https://example.com"}},
+		{"id", "8;id=1;https://example.com", Link{URL: "https://example.com", URLID: "id=1"}},
+		{"url with semicolons", "8;id=1;https://example.com/a;b;c", Link{URL: "https://example.com/a;b;c", URLID: "id=1"}},
+		{"reset", "8;;", Link{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := Link{URL: "https://old.example.com", URLID: "id=old"}
+			ReadLink([]byte(tt.data), &link)
+			if !link.Equal(tt.want) {
+				t.Errorf("ReadLink(%q) = %+v, want %+v", tt.data, link, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadStyleUnderlineMatchesSgrState(t *testing.T) {
+	for _, seq := range []string{"\x1b[4m", "\x1b[4:0m", "\x1b[4:3m", "\x1b[4:5m", "\x1b[4:9m", "\x1b[4;4:9m"} {
+		var s ansi.SgrState
+		s.Consume(seq)
+
+		var pen Style
+		p := ansi.NewParser()
+		p.SetHandler(ansi.Handler{
+			HandleCsi: func(_ ansi.Cmd, params ansi.Params) {
+				ReadStyle(params, &pen)
+			},
+		})
+		p.Parse([]byte(seq))
+		if pen.UlStyle != s.Underline {
+			t.Errorf("ReadStyle(%q) underline = %d, SgrState underline = %d", seq, pen.UlStyle, s.Underline)
+		}
+	}
+}
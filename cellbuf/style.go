@@ -29,3 +29,14 @@ func ConvertStyle(s Style, p colorprofile.Profile) Style {
 
 	return s
 }
+
+// PlainUnderline converts a style to only use plain underlines, for terminals
+// that don't support underline styles and colors. Styled underlines become
+// single underlines and the underline color is removed.
+func PlainUnderline(s Style) Style {
+	if s.UlStyle != NoUnderline {
+		s.UlStyle = SingleUnderline
+	}
+	s.Ul = nil
+	return s
+}
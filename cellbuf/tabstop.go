@@ -3,7 +3,12 @@ package cellbuf
 // DefaultTabInterval is the default tab interval.
 const DefaultTabInterval = 8
 
-// TabStops represents horizontal line tab stops.
+// maxTabInterval is the maximum tab interval supported by [TabStops]. Each
+// interval is stored in a single int bitmask.
+const maxTabInterval = 63
+
+// TabStops represents horizontal line tab stops. The zero value has no
+// columns and uses [DefaultTabInterval].
 type TabStops struct {
 	stops    []int
 	interval int
@@ -11,13 +16,12 @@ type TabStops struct {
 }
 
 // NewTabStops creates a new set of tab stops from a number of columns and an
-// interval.
+// interval. An interval less than 1 or greater than 63 is replaced with
+// [DefaultTabInterval].
 func NewTabStops(width, interval int) *TabStops {
 	ts := new(TabStops)
-	ts.interval = interval
 	ts.width = width
-	ts.stops = make([]int, (width+(interval-1))/interval)
-	ts.init(0, width)
+	ts.ResetEvery(interval)
 	return ts
 }
 
@@ -26,32 +30,68 @@ func DefaultTabStops(cols int) *TabStops {
 	return NewTabStops(cols, DefaultTabInterval)
 }
 
-// Resize resizes the tab stops to the given width.
+// Width returns the number of columns covered by the tab stops.
+func (ts TabStops) Width() int {
+	return ts.width
+}
+
+// Interval returns the interval used to initialize the tab stops.
+func (ts TabStops) Interval() int {
+	if ts.interval == 0 {
+		return DefaultTabInterval
+	}
+	return ts.interval
+}
+
+// Resize resizes the tab stops to the given width. Existing tab stops are
+// kept and new columns get a tab stop every interval columns.
 func (ts *TabStops) Resize(width int) {
 	if width == ts.width {
 		return
 	}
 
+	if width < 0 {
+		width = 0
+	}
+	if ts.interval == 0 {
+		ts.interval = DefaultTabInterval
+	}
+
+	size := (width + (ts.interval - 1)) / ts.interval
 	if width < ts.width {
-		size := (width + (ts.interval - 1)) / ts.interval
 		ts.stops = ts.stops[:size]
 	} else {
-		size := (width - ts.width + (ts.interval - 1)) / ts.interval
-		ts.stops = append(ts.stops, make([]int, size)...)
+		ts.stops = append(ts.stops, make([]int, size-len(ts.stops))...)
 	}
 
 	ts.init(ts.width, width)
 	ts.width = width
 }
 
+// ResetEvery removes all tab stops and sets a new tab stop every interval
+// columns starting from the first column. An interval less than 1 or greater
+// than 63 is replaced with [DefaultTabInterval].
+//
+// This is the generalized form of [ansi.DECST8C].
+func (ts *TabStops) ResetEvery(interval int) {
+	if interval < 1 || interval > maxTabInterval {
+		interval = DefaultTabInterval
+	}
+	if ts.width < 0 {
+		ts.width = 0
+	}
+
+	ts.interval = interval
+	ts.stops = make([]int, (ts.width+(interval-1))/interval)
+	ts.init(0, ts.width)
+}
+
 // IsStop returns true if the given column is a tab stop.
 func (ts TabStops) IsStop(col int) bool {
-	mask := ts.mask(col)
-	i := col >> 3
-	if i < 0 || i >= len(ts.stops) {
+	if col < 0 || col >= ts.width {
 		return false
 	}
-	return ts.stops[i]&mask != 0
+	return ts.stops[ts.index(col)]&ts.mask(col) != 0
 }
 
 // Next returns the next tab stop after the given column.
@@ -103,16 +143,33 @@ func (ts TabStops) Find(col, delta int) int {
 	return col
 }
 
-// Set adds a tab stop at the given column.
+// Stops returns the columns of all the tab stops in ascending order.
+func (ts TabStops) Stops() []int {
+	var cols []int
+	for col := 0; col < ts.width; col++ {
+		if ts.IsStop(col) {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// Set adds a tab stop at the given column. Columns outside of the tab stops
+// width are ignored.
 func (ts *TabStops) Set(col int) {
-	mask := ts.mask(col)
-	ts.stops[col>>3] |= mask
+	if col < 0 || col >= ts.width {
+		return
+	}
+	ts.stops[ts.index(col)] |= ts.mask(col)
 }
 
-// Reset removes the tab stop at the given column.
+// Reset removes the tab stop at the given column. Columns outside of the tab
+// stops width are ignored.
 func (ts *TabStops) Reset(col int) {
-	mask := ts.mask(col)
-	ts.stops[col>>3] &= ^mask
+	if col < 0 || col >= ts.width {
+		return
+	}
+	ts.stops[ts.index(col)] &= ^ts.mask(col)
 }
 
 // Clear removes all tab stops.
@@ -120,18 +177,31 @@ func (ts *TabStops) Clear() {
 	ts.stops = make([]int, len(ts.stops))
 }
 
+// Clone returns a copy of the tab stops.
+func (ts *TabStops) Clone() *TabStops {
+	n := new(TabStops)
+	*n = *ts
+	n.stops = append([]int(nil), ts.stops...)
+	return n
+}
+
+// index returns the index of the bitmask holding the given column.
+func (ts *TabStops) index(col int) int {
+	return col / ts.interval
+}
+
 // mask returns the mask for the given column.
 func (ts *TabStops) mask(col int) int {
-	return 1 << (col & (ts.interval - 1))
+	return 1 << (col % ts.interval)
 }
 
 // init initializes the tab stops starting from col until width.
 func (ts *TabStops) init(col, width int) {
 	for x := col; x < width; x++ {
 		if x%ts.interval == 0 {
-			ts.Set(x)
+			ts.stops[ts.index(x)] |= ts.mask(x)
 		} else {
-			ts.Reset(x)
+			ts.stops[ts.index(x)] &= ^ts.mask(x)
 		}
 	}
 }
@@ -294,3 +294,104 @@ func TestTabStopsResizeEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestTabStopsCustomInterval(t *testing.T) {
+	ts := NewTabStops(16, 4)
+	ts.Set(5)
+
+	want := []int{0, 4, 5, 8, 12}
+	if got := ts.Stops(); !intsEqual(got, want) {
+		t.Errorf("Stops() = %v, want %v", got, want)
+	}
+
+	// Setting a custom stop must not affect other columns sharing the same
+	// offset within the interval.
+	if ts.IsStop(1) || ts.IsStop(9) || ts.IsStop(13) {
+		t.Error("Set(5) affected other columns")
+	}
+}
+
+func TestTabStopsResetEvery(t *testing.T) {
+	ts := DefaultTabStops(20)
+	ts.Set(3)
+	ts.ResetEvery(5)
+
+	if got := ts.Interval(); got != 5 {
+		t.Errorf("Interval() = %d, want 5", got)
+	}
+	if got, want := ts.Stops(), []int{0, 5, 10, 15}; !intsEqual(got, want) {
+		t.Errorf("Stops() = %v, want %v", got, want)
+	}
+
+	ts.ResetEvery(0)
+	if got := ts.Interval(); got != DefaultTabInterval {
+		t.Errorf("Interval() = %d, want %d", got, DefaultTabInterval)
+	}
+	if got, want := ts.Stops(), []int{0, 8, 16}; !intsEqual(got, want) {
+		t.Errorf("Stops() = %v, want %v", got, want)
+	}
+}
+
+func TestTabStopsResizeKeepsStops(t *testing.T) {
+	ts := DefaultTabStops(10)
+	ts.Set(3)
+	ts.Reset(8)
+	ts.Resize(20)
+
+	if got, want := ts.Stops(), []int{0, 3, 16}; !intsEqual(got, want) {
+		t.Errorf("Stops() = %v, want %v", got, want)
+	}
+	if got := ts.Width(); got != 20 {
+		t.Errorf("Width() = %d, want 20", got)
+	}
+}
+
+func TestTabStopsOutOfBounds(t *testing.T) {
+	ts := DefaultTabStops(8)
+	ts.Set(-1)
+	ts.Set(8)
+	ts.Reset(100)
+
+	if got, want := ts.Stops(), []int{0}; !intsEqual(got, want) {
+		t.Errorf("Stops() = %v, want %v", got, want)
+	}
+}
+
+func TestTabStopsClone(t *testing.T) {
+	ts := DefaultTabStops(16)
+	c := ts.Clone()
+	c.Set(3)
+
+	if ts.IsStop(3) {
+		t.Error("modifying a clone affected the original tab stops")
+	}
+	if !c.IsStop(3) || !c.IsStop(8) {
+		t.Errorf("clone Stops() = %v, want [0 3 8]", c.Stops())
+	}
+}
+
+func TestTabStopsZeroValue(t *testing.T) {
+	var ts TabStops
+	if got := ts.Interval(); got != DefaultTabInterval {
+		t.Errorf("Interval() = %d, want %d", got, DefaultTabInterval)
+	}
+	ts.Resize(20)
+	if got, want := ts.Stops(), []int{0, 8, 16}; !intsEqual(got, want) {
+		t.Errorf("Stops() = %v, want %v", got, want)
+	}
+	if got := ts.Next(0); got != 8 {
+		t.Errorf("Next(0) = %d, want 8", got)
+	}
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -60,20 +60,53 @@ func New(w int, h int, flags int) (c *ConPty, err error) {
 		return nil, fmt.Errorf("failed to create pipes for pseudo console: %w", err)
 	}
 
-	if err := windows.CreatePipe(&c.outPipeFd, &ptyOut, nil, 0); err != nil {
+	// Release everything created so far if we fail to create the pseudo
+	// console.
+	pty := c
+	defer func() {
+		if err == nil {
+			return
+		}
+		handles := []windows.Handle{ptyIn, ptyOut}
+		if pty.inPipe != nil {
+			pty.inPipe.Close()  //nolint:errcheck
+			pty.outPipe.Close() //nolint:errcheck
+		} else {
+			handles = append(handles, pty.inPipeFd, pty.outPipeFd)
+		}
+		for _, h := range handles {
+			if h != 0 {
+				windows.CloseHandle(h) //nolint:errcheck
+			}
+		}
+		if *pty.hpc != 0 {
+			windows.ClosePseudoConsole(*pty.hpc)
+		}
+		if pty.attrList != nil {
+			pty.attrList.Delete()
+		}
+	}()
+
+	if err = windows.CreatePipe(&c.outPipeFd, &ptyOut, nil, 0); err != nil {
 		return nil, fmt.Errorf("failed to create pipes for pseudo console: %w", err)
 	}
 
-	if err := windows.CreatePseudoConsole(c.size, ptyIn, ptyOut, uint32(flags), c.hpc); err != nil {
+	if err = windows.CreatePseudoConsole(c.size, ptyIn, ptyOut, uint32(flags), c.hpc); err != nil {
 		return nil, fmt.Errorf("failed to create pseudo console: %w", err)
 	}
 
 	// We don't need the pty pipes anymore, these will get dup'd when the
-	// new process starts.
-	if err := windows.CloseHandle(ptyOut); err != nil {
+	// new process starts. A handle is invalid after CloseHandle even when it
+	// fails, so forget it before checking the error to avoid closing it
+	// twice.
+	err = windows.CloseHandle(ptyOut)
+	ptyOut = 0
+	if err != nil {
 		return nil, fmt.Errorf("failed to close pseudo console handle: %w", err)
 	}
-	if err := windows.CloseHandle(ptyIn); err != nil {
+	err = windows.CloseHandle(ptyIn)
+	ptyIn = 0
+	if err != nil {
 		return nil, fmt.Errorf("failed to close pseudo console handle: %w", err)
 	}
 
@@ -87,7 +120,7 @@ func New(w int, h int, flags int) (c *ConPty, err error) {
 		return nil, err
 	}
 
-	if err := c.attrList.Update(
+	if err = c.attrList.Update(
 		windows.PROC_THREAD_ATTRIBUTE_PSEUDOCONSOLE,
 		unsafe.Pointer(*c.hpc),
 		unsafe.Sizeof(*c.hpc),
@@ -111,6 +144,7 @@ func (p *ConPty) Close() error {
 			p.attrList.Delete()
 		}
 		windows.ClosePseudoConsole(*p.hpc)
+		*p.hpc = 0
 		err = errors.Join(p.inPipe.Close(), p.outPipe.Close())
 	})
 	return err
@@ -150,8 +184,11 @@ func (c *ConPty) Read(p []byte) (n int, err error) {
 	return int(l), err
 }
 
-// Resize resizes the pseudo-console.
+// Resize resizes the pseudo-console. The width and height must be positive.
 func (c *ConPty) Resize(w int, h int) error {
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("invalid pseudo console size %dx%d", w, h)
+	}
 	size := windows.Coord{X: int16(w), Y: int16(h)}
 	if err := windows.ResizePseudoConsole(*c.hpc, size); err != nil {
 		return fmt.Errorf("failed to resize pseudo console: %w", err)
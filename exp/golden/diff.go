@@ -0,0 +1,111 @@
+package golden
+
+import (
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// DefaultDiffContext is the default number of unchanged lines shown around
+// each change in the diff.
+const DefaultDiffContext = udiff.DefaultContextLines
+
+// ANSI sequences used to colorize the diff.
+const (
+	colorReset = "\x1b[m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// noColorEnv is the environment variable that disables the colorized diff.
+const noColorEnv = "NO_COLOR"
+
+// diff returns the unified diff between expected and got. It returns an empty
+// string when they are equal.
+func (o options) diff(expected, got string) string {
+	context := DefaultDiffContext
+	if o.context != nil {
+		context = *o.context
+	}
+
+	edits := udiff.Strings(expected, got)
+	diff, err := udiff.ToUnified("golden", "run", expected, edits, context)
+	if err != nil {
+		// Fall back to the default diff, this should never happen since the
+		// edits are computed from the same content.
+		diff = udiff.Unified("golden", "run", expected, got)
+	}
+
+	if o.useColor() {
+		diff = colorize(diff)
+	}
+
+	return diff
+}
+
+// failure returns the failure message of a golden file mismatch. It returns
+// an empty string when expected and got are equal.
+func (o options) failure(expected, got string) string {
+	diff := o.diff(expected, got)
+	if diff == "" {
+		return ""
+	}
+	if o.diffOnly {
+		return "output does not match, diff:\n\n" + diff
+	}
+	return "output does not match, expected:\n\n" + expected +
+		"\n\ngot:\n\n" + got +
+		"\n\ndiff:\n\n" + diff
+}
+
+// useColor returns whether the diff should be colorized. By default, the diff
+// is colorized when the standard output is a terminal and the NO_COLOR
+// environment variable isn't set.
+func (o options) useColor() bool {
+	if o.color != nil {
+		return *o.color
+	}
+	if os.Getenv(noColorEnv) != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize colorizes the lines of a unified diff.
+func colorize(diff string) string {
+	lines := strings.SplitAfter(diff, "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		text := strings.TrimSuffix(line, "\n")
+		var color string
+		switch {
+		case i < 2 && (strings.HasPrefix(text, "---") || strings.HasPrefix(text, "+++")):
+			color = colorBold
+		case strings.HasPrefix(text, "@@"):
+			color = colorCyan
+		case strings.HasPrefix(text, "-"):
+			color = colorRed
+		case strings.HasPrefix(text, "+"):
+			color = colorGreen
+		}
+
+		if color == "" {
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(color + text + colorReset)
+		b.WriteString(line[len(text):])
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package golden
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffContext(t *testing.T) {
+	var expected []string
+	for i := 0; i < 20; i++ {
+		expected = append(expected, "line")
+	}
+	got := append([]string(nil), expected...)
+	got[10] = "changed"
+
+	o := newOptions(WithColor(false), WithDiffContext(1))
+	diff := o.diff(strings.Join(expected, "\n")+"\n", strings.Join(got, "\n")+"\n")
+	want := "--- golden\n+++ run\n@@ -10,3 +10,3 @@\n line\n-line\n+changed\n line\n"
+	if diff != want {
+		t.Errorf("diff() = %q, want %q", diff, want)
+	}
+}
+
+func TestDiffColor(t *testing.T) {
+	o := newOptions(WithColor(true))
+	diff := o.diff("a\n", "b\n")
+	want := "\x1b[1m--- golden\x1b[m\n" +
+		"\x1b[1m+++ run\x1b[m\n" +
+		"\x1b[36m@@ -1 +1 @@\x1b[m\n" +
+		"\x1b[31m-a\x1b[m\n" +
+		"\x1b[32m+b\x1b[m\n"
+	if diff != want {
+		t.Errorf("diff() = %q, want %q", diff, want)
+	}
+}
+
+func TestFailure(t *testing.T) {
+	if msg := newOptions().failure("a", "a"); msg != "" {
+		t.Errorf("failure() = %q, want empty", msg)
+	}
+
+	msg := newOptions(WithColor(false), WithDiffOnly()).failure("a\n", "b\n")
+	if strings.Contains(msg, "expected:") || !strings.Contains(msg, "-a\n+b\n") {
+		t.Errorf("failure() = %q, want diff only", msg)
+	}
+
+	msg = newOptions(WithColor(false)).failure("a\n", "b\n")
+	if !strings.Contains(msg, "expected:\n\na\n") || !strings.Contains(msg, "got:\n\nb\n") {
+		t.Errorf("failure() = %q, want expected and got outputs", msg)
+	}
+}
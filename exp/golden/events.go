@@ -0,0 +1,136 @@
+package golden
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// RequireEqualEvents is a helper function to assert the given events are the
+// expected from the golden files. It's tailored to input event streams such as
+// []input.Event and makes parser regression tests declarative.
+//
+// Each event is serialized deterministically on its own line as its type
+// followed by its exported fields, for example:
+//
+//	input.KeyPressEvent{Text: "a", Code: 97}
+//
+// Fields with zero values are omitted.
+func RequireEqualEvents[E any](tb testing.TB, events []E, opts ...Option) {
+	tb.Helper()
+	requireEqual(tb, []byte(formatEvents(events)), false, newOptions(opts...))
+}
+
+// formatEvents returns the serialization of the given events.
+func formatEvents[E any](events []E) string {
+	var b strings.Builder
+	for _, e := range events {
+		b.WriteString(formatEvent(reflect.ValueOf(&e).Elem()))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// formatEvent returns the serialization of a single event. Unlike
+// [formatValue], named non-struct events are written with their type.
+func formatEvent(v reflect.Value) string {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.IsValid() && v.Type().Name() != "" && v.Kind() != reflect.Struct {
+		return fmt.Sprintf("%s(%s)", v.Type(), formatValue(v, false))
+	}
+	return formatValue(v, true)
+}
+
+// formatValue returns a deterministic representation of v. When stringer is
+// true, named scalar types implementing [fmt.Stringer] use their String
+// method.
+func formatValue(v reflect.Value, stringer bool) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return formatValue(v.Elem(), true)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "nil"
+		}
+		return "&" + formatValue(v.Elem(), true)
+	case reflect.Struct:
+		t := v.Type()
+		var fields []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			fields = append(fields, f.Name+": "+formatValue(v.Field(i), true))
+		}
+		return t.String() + "{" + strings.Join(fields, ", ") + "}"
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return strconv.Quote(string(b))
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = formatValue(v.Index(i), true)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case reflect.Map:
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries = append(entries, formatValue(iter.Key(), true)+": "+formatValue(iter.Value(), true))
+		}
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ", ") + "}"
+	case reflect.String:
+		if s, ok := stringerValue(v, stringer); ok {
+			return s
+		}
+		return strconv.Quote(v.String())
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return "<" + v.Type().String() + ">"
+	}
+
+	if s, ok := stringerValue(v, stringer); ok {
+		return s
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	default:
+		return "<" + v.Type().String() + ">"
+	}
+}
+
+// stringerValue returns the String method result of named values
+// implementing [fmt.Stringer].
+func stringerValue(v reflect.Value, stringer bool) (string, bool) {
+	if !stringer || v.Type().PkgPath() == "" || !v.CanInterface() {
+		return "", false
+	}
+	s, ok := v.Interface().(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+	return s.String(), true
+}
@@ -0,0 +1,48 @@
+package golden
+
+import (
+	"image/color"
+	"testing"
+)
+
+type testMod uint8
+
+func (m testMod) String() string {
+	if m == 1 {
+		return "ctrl"
+	}
+	return "none"
+}
+
+type (
+	testKeyEvent struct {
+		Text string
+		Mod  testMod
+		Code rune
+	}
+	testPasteEvent string
+	testColorEvent struct{ color.Color }
+	testMultiEvent []any
+	testFocusEvent struct{}
+)
+
+func TestRequireEqualEvents(t *testing.T) {
+	events := []any{
+		testKeyEvent{Text: "a", Code: 'a'},
+		testKeyEvent{Mod: 1, Code: 'c'},
+		testPasteEvent("hello\x1bworld"),
+		testColorEvent{color.RGBA{R: 0xff, A: 0xff}},
+		testMultiEvent{testFocusEvent{}, nil},
+	}
+	RequireEqualEvents(t, events)
+}
+
+func TestFormatEvents(t *testing.T) {
+	want := "golden.testKeyEvent{Text: \"x\", Mod: ctrl}\n" +
+		"golden.testMod(1)\n" +
+		"nil\n"
+	got := formatEvents([]any{testKeyEvent{Text: "x", Mod: 1}, testMod(1), nil})
+	if got != want {
+		t.Errorf("formatEvents() = %q, want %q", got, want)
+	}
+}
@@ -2,4 +2,18 @@ module github.com/charmbracelet/x/exp/golden
 
 go 1.19
 
-require github.com/aymanbagabas/go-udiff v0.2.0
+require (
+	github.com/aymanbagabas/go-udiff v0.2.0
+	github.com/charmbracelet/x/ansi v0.8.0
+	github.com/charmbracelet/x/cellbuf v0.0.7
+)
+
+require (
+	github.com/charmbracelet/colorprofile v0.1.10 // indirect
+	github.com/charmbracelet/x/term v0.2.1 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
@@ -1,18 +1,32 @@
 package golden
 
 import (
+	"bytes"
 	"flag"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
-
-	"github.com/aymanbagabas/go-udiff"
 )
 
 var update = flag.Bool("update", false, "update .golden files")
 
+// UpdateEnv is the environment variable that enables updating the golden
+// files when set to a true value such as "1" or "true". This is useful for
+// test runners and CI jobs that can't pass custom flags to go test.
+const UpdateEnv = "GOLDEN_UPDATE"
+
+// shouldUpdate returns whether the golden files should be updated, either by
+// the -update flag or the [UpdateEnv] environment variable.
+func shouldUpdate() bool {
+	if *update {
+		return true
+	}
+	v, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return v
+}
+
 // RequireEqual is a helper function to assert the given output is
 // the expected from the golden files, printing its diff in case it is not.
 //
@@ -21,16 +35,79 @@ var update = flag.Bool("update", false, "update .golden files")
 // your tests, [RequireEqual] will escape the control codes and sequences
 // before comparing the output with the golden files.
 //
-// You can update the golden files by running your tests with the -update flag.
-func RequireEqual(tb testing.TB, out []byte) {
+// You can update the golden files by running your tests with the -update flag
+// or by setting the GOLDEN_UPDATE environment variable to a true value.
+//
+// Use options such as [WithDir], [WithExtension], and [WithName] to change
+// where the golden files are stored, and [WithDiffContext], [WithColor], and
+// [WithDiffOnly] to change how mismatches are reported.
+func RequireEqual(tb testing.TB, out []byte, opts ...Option) {
 	tb.Helper()
+	requireEqual(tb, out, false, newOptions(opts...))
+}
 
-	golden := filepath.Join("testdata", tb.Name()+".golden")
-	if *update {
-		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil { //nolint: gomnd
-			tb.Fatal(err)
-		}
-		if err := os.WriteFile(golden, out, 0o600); err != nil { //nolint: gomnd
+// RequireEqualEscaped is like [RequireEqual] but the golden files contain the
+// escaped output of your tests. Control codes and escape sequences are
+// written as visible escapes, for example "\x1b[31m", which makes diffs of
+// styled output reviewable. Newlines are preserved.
+//
+// You can update the golden files by running your tests with the -update flag
+// or by setting the GOLDEN_UPDATE environment variable to a true value.
+func RequireEqualEscaped(tb testing.TB, out []byte, opts ...Option) {
+	tb.Helper()
+	requireEqual(tb, out, true, newOptions(opts...))
+}
+
+// AssertEqual is like [RequireEqual] but reports a mismatch with tb.Error
+// instead of tb.Fatal, which lets the test continue and report more
+// mismatches.
+func AssertEqual(tb testing.TB, out []byte, opts ...Option) bool {
+	tb.Helper()
+
+	o := newOptions(opts...)
+	expected, got, err := readGolden(tb, out, false, o)
+	if err != nil {
+		tb.Error(err)
+		return false
+	}
+	if msg := o.failure(expected, got); msg != "" {
+		tb.Error(msg)
+		return false
+	}
+	return true
+}
+
+// Check compares the given output with the golden file like [RequireEqual]
+// without failing the test. It returns the diff between the golden file and
+// the output and whether they are equal. This is useful for table-driven
+// tests that aggregate multiple mismatches. If the golden file can't be read,
+// the error message is returned as the diff.
+func Check(tb testing.TB, out []byte, opts ...Option) (diff string, ok bool) {
+	tb.Helper()
+
+	o := newOptions(opts...)
+	expected, got, err := readGolden(tb, out, false, o)
+	if err != nil {
+		return err.Error(), false
+	}
+	diff = o.diff(expected, got)
+	return diff, diff == ""
+}
+
+// RequireEqualExact is like [RequireEqual] but compares the output with the
+// golden file byte by byte. The output is never normalized, which makes it
+// suitable for binary data and terminal streams where "\r\n" is meaningful.
+// In case of a mismatch, the diff is printed using the escaped output.
+//
+// You can update the golden files by running your tests with the -update flag
+// or by setting the GOLDEN_UPDATE environment variable to a true value.
+func RequireEqualExact(tb testing.TB, out []byte, opts ...Option) {
+	tb.Helper()
+
+	o := newOptions(opts...)
+	golden := o.path(tb, ".golden")
+	if shouldUpdate() {
+		if err := writeGolden(golden, out); err != nil {
 			tb.Fatal(err)
 		}
 	}
@@ -40,12 +117,9 @@ func RequireEqual(tb testing.TB, out []byte) {
 		tb.Fatal(err)
 	}
 
-	goldenStr := escapeSeqs(string(goldenBts))
-	outStr := escapeSeqs(string(out))
-
-	diff := udiff.Unified("golden", "run", goldenStr, outStr)
-	if diff != "" {
-		tb.Fatalf("output does not match, expected:\n\n%s\n\ngot:\n\n%s\n\ndiff:\n\n%s", goldenStr, outStr, diff)
+	if !bytes.Equal(goldenBts, out) {
+		goldenStr, outStr := escapeSeqs(string(goldenBts)), escapeSeqs(string(out))
+		tb.Fatal(o.failure(goldenStr, outStr))
 	}
 }
 
@@ -57,6 +131,57 @@ func RequireEqualEscape(tb testing.TB, out []byte, escapes bool) {
 	RequireEqual(tb, out)
 }
 
+// requireEqual compares the given output with the golden file. When escaped
+// is true, the golden file contains the escaped output.
+func requireEqual(tb testing.TB, out []byte, escaped bool, o options) {
+	tb.Helper()
+
+	expected, got, err := readGolden(tb, out, escaped, o)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if msg := o.failure(expected, got); msg != "" {
+		tb.Fatal(msg)
+	}
+}
+
+// readGolden returns the escaped contents of the golden file and the escaped
+// output, updating the golden file first if needed.
+func readGolden(tb testing.TB, out []byte, escaped bool, o options) (expected, got string, err error) {
+	got = escapeSeqs(string(out))
+	golden := o.path(tb, ".golden")
+	if shouldUpdate() {
+		content := out
+		if escaped {
+			content = []byte(got)
+		}
+		if err := writeGolden(golden, content); err != nil {
+			return "", "", err
+		}
+	}
+
+	goldenBts, err := os.ReadFile(golden)
+	if err != nil {
+		return "", "", err
+	}
+
+	expected = string(goldenBts)
+	if !escaped {
+		expected = escapeSeqs(expected)
+	}
+
+	return expected, got, nil
+}
+
+// writeGolden writes the given content to the golden file, creating its
+// directory if needed.
+func writeGolden(golden string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil { //nolint: gomnd
+		return err
+	}
+	return os.WriteFile(golden, content, 0o600) //nolint: gomnd
+}
+
 // escapeSeqs escapes control codes and escape sequences from the given string.
 // The only preserved exception is the newline character.
 func escapeSeqs(in string) string {
@@ -1,6 +1,13 @@
 package golden
 
-import "testing"
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
 
 func TestRequireEqualUpdate(t *testing.T) {
 	enableUpdate(t)
@@ -11,11 +18,174 @@ func TestRequireEqualNoUpdate(t *testing.T) {
 	RequireEqual(t, []byte("test"))
 }
 
+func TestRequireEqualEscaped(t *testing.T) {
+	RequireEqualEscaped(t, []byte("\x1b[31mred\x1b[m\nplain\ttext"))
+}
+
 func enableUpdate(tb testing.TB) {
 	tb.Helper()
-	previous := update
+	previous := *update
 	*update = true
 	tb.Cleanup(func() {
-		update = previous
+		*update = previous
 	})
 }
+
+func TestRequireEqualUpdateEnv(t *testing.T) {
+	t.Setenv(UpdateEnv, "1")
+	if !shouldUpdate() {
+		t.Fatalf("shouldUpdate() = false with %s=1", UpdateEnv)
+	}
+	RequireEqual(t, []byte("env"))
+
+	t.Setenv(UpdateEnv, "false")
+	if shouldUpdate() {
+		t.Fatalf("shouldUpdate() = true with %s=false", UpdateEnv)
+	}
+}
+
+func TestRequireEqualJSON(t *testing.T) {
+	type item struct {
+		ID    int               `json:"id"`
+		Name  string            `json:"name"`
+		Attrs map[string]string `json:"attrs"`
+	}
+	v := struct {
+		Zebra     bool   `json:"zebra"`
+		CreatedAt string `json:"created_at"`
+		Items     []item `json:"items"`
+	}{
+		Zebra:     true,
+		CreatedAt: "2024-01-01T00:00:00Z",
+		Items: []item{
+			{ID: 42, Name: "one", Attrs: map[string]string{"b": "2", "a": "1"}},
+		},
+	}
+
+	RequireEqualJSON(t, v, WithRedact("created_at", "id"))
+}
+
+func TestMarshalJSONLargeInt(t *testing.T) {
+	v := struct {
+		N int64 `json:"n"`
+	}{N: 1<<62 + 1}
+	out, err := marshalJSON(v, newOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"n\": 4611686018427387905\n}\n"; string(out) != want {
+		t.Errorf("marshalJSON() = %q, want %q", out, want)
+	}
+}
+
+func testImage(offset uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 60), G: uint8(y * 60), B: offset, A: 0xff})
+		}
+	}
+	return img
+}
+
+func TestRequireEqualImage(t *testing.T) {
+	RequireEqualImage(t, testImage(0))
+}
+
+func TestRequireEqualImageTolerance(t *testing.T) {
+	RequireEqualImage(t, testImage(3), WithTolerance(3))
+}
+
+func TestDiffImages(t *testing.T) {
+	expected := testImage(0)
+	actual := testImage(0)
+	actual.SetNRGBA(1, 2, color.NRGBA{R: 0xff, A: 0xff})
+
+	diff, n := diffImages(expected, actual, 0)
+	if n != 1 {
+		t.Errorf("diffImages() = %d mismatching pixels, want 1", n)
+	}
+	if got := diff.NRGBAAt(1, 2); got != (color.NRGBA{R: 0xff, A: 0xff}) {
+		t.Errorf("diff pixel = %v, want red", got)
+	}
+
+	if _, n := diffImages(expected, testImage(5), 4); n != 16 {
+		t.Errorf("diffImages() = %d mismatching pixels, want 16", n)
+	}
+	if _, n := diffImages(expected, testImage(5), 5); n != 0 {
+		t.Errorf("diffImages() = %d mismatching pixels, want 0", n)
+	}
+}
+
+func TestGoldenPath(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		ext  string
+		want string
+	}{
+		{"default", nil, ".golden", filepath.Join("testdata", t.Name(), "default.golden")},
+		{"image", nil, ".png", filepath.Join("testdata", t.Name(), "image.png")},
+		{"dir", []Option{WithDir("golden")}, ".golden", filepath.Join("golden", t.Name(), "dir.golden")},
+		{"ext", []Option{WithExtension(".txt")}, ".golden", filepath.Join("testdata", t.Name(), "ext.txt")},
+		{"name", []Option{WithName(func(name string) string {
+			return strings.ReplaceAll(name, "/", "_")
+		})}, ".golden", filepath.Join("testdata", t.Name()+"_name.golden")},
+		{"platform", []Option{WithPlatformSuffix()}, ".golden", filepath.Join("testdata", t.Name(), "platform_"+runtime.GOOS+".golden")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions(tt.opts...).path(t, tt.ext); got != tt.want {
+				t.Errorf("path() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireEqualWithOptions(t *testing.T) {
+	RequireEqual(t, []byte("options"), WithDir(filepath.Join("testdata", "custom")), WithExtension(".txt"))
+}
+
+func TestRequireEqualExact(t *testing.T) {
+	RequireEqualExact(t, []byte("line\r\n\x00\xff\x1b[m\r\n"))
+}
+
+func TestAssertEqual(t *testing.T) {
+	if !AssertEqual(t, []byte("assert")) {
+		t.Fatal("AssertEqual() = false, want true")
+	}
+
+	ft := &fakeTB{TB: t}
+	if AssertEqual(ft, []byte("mismatch"), WithColor(false)) {
+		t.Error("AssertEqual() = true, want false")
+	}
+	if !ft.failed || ft.fatal {
+		t.Errorf("AssertEqual() failed = %v, fatal = %v, want non-fatal failure", ft.failed, ft.fatal)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if diff, ok := Check(t, []byte("check")); !ok || diff != "" {
+		t.Errorf("Check() = %q, %v, want empty diff", diff, ok)
+	}
+	diff, ok := Check(t, []byte("other"), WithColor(false))
+	if ok || !strings.Contains(diff, "-check") || !strings.Contains(diff, "+other") {
+		t.Errorf("Check() = %q, %v, want mismatch", diff, ok)
+	}
+	if _, ok := Check(t, nil, WithDir("missing")); ok {
+		t.Error("Check() = true for missing golden file, want false")
+	}
+}
+
+// fakeTB records failures without failing the wrapped test.
+type fakeTB struct {
+	testing.TB
+	failed bool
+	fatal  bool
+}
+
+func (f *fakeTB) Error(...any) { f.failed = true }
+
+func (f *fakeTB) Fatal(...any) { f.failed, f.fatal = true, true }
+
+func (f *fakeTB) Helper() {}
@@ -0,0 +1,107 @@
+package golden
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RequireEqualImage is a helper function to assert the given image is the
+// expected from the golden PNG files. Images are compared pixel by pixel
+// using their 8-bit RGBA values. Use [WithTolerance] to allow small per
+// channel differences.
+//
+// When the images don't match, a visual diff is written next to the golden
+// file with a ".diff.png" suffix. Mismatching pixels are painted red on top
+// of a faded copy of the expected image.
+//
+// You can update the golden files by running your tests with the -update flag
+// or by setting the GOLDEN_UPDATE environment variable to a true value.
+func RequireEqualImage(tb testing.TB, img image.Image, opts ...Option) {
+	tb.Helper()
+
+	o := newOptions(opts...)
+	golden := o.path(tb, ".png")
+	if shouldUpdate() {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			tb.Fatal(err)
+		}
+		if err := writeGolden(golden, buf.Bytes()); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(golden)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	expected, err := png.Decode(f)
+	f.Close() //nolint:errcheck
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	if eb, ob := expected.Bounds(), img.Bounds(); eb.Dx() != ob.Dx() || eb.Dy() != ob.Dy() {
+		tb.Fatalf("image size does not match, expected %dx%d, got %dx%d", eb.Dx(), eb.Dy(), ob.Dx(), ob.Dy())
+	}
+
+	diff, n := diffImages(expected, img, o.tolerance)
+	if n == 0 {
+		return
+	}
+
+	diffPath := strings.TrimSuffix(golden, filepath.Ext(golden)) + ".diff.png"
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, diff); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(diffPath, buf.Bytes(), 0o600); err != nil { //nolint: gomnd
+		tb.Fatal(err)
+	}
+
+	tb.Fatalf("image does not match, %d pixels differ, see %s", n, diffPath)
+}
+
+// diffImages compares the two images of the same size pixel by pixel and
+// returns a visual diff image and the number of mismatching pixels.
+func diffImages(expected, actual image.Image, tolerance uint8) (*image.NRGBA, int) {
+	eb, ab := expected.Bounds(), actual.Bounds()
+	diff := image.NewNRGBA(image.Rect(0, 0, eb.Dx(), eb.Dy()))
+
+	var n int
+	for y := 0; y < eb.Dy(); y++ {
+		for x := 0; x < eb.Dx(); x++ {
+			ec := color.NRGBAModel.Convert(expected.At(eb.Min.X+x, eb.Min.Y+y)).(color.NRGBA)
+			ac := color.NRGBAModel.Convert(actual.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)
+			if channelDiff(ec.R, ac.R) > tolerance ||
+				channelDiff(ec.G, ac.G) > tolerance ||
+				channelDiff(ec.B, ac.B) > tolerance ||
+				channelDiff(ec.A, ac.A) > tolerance {
+				n++
+				diff.SetNRGBA(x, y, color.NRGBA{R: 0xff, A: 0xff})
+				continue
+			}
+
+			// Faded grayscale copy of the expected pixel.
+			g := color.GrayModel.Convert(ec).(color.Gray)
+			v := 0xff - (0xff-g.Y)/4
+			diff.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 0xff})
+		}
+	}
+
+	return diff, n
+}
+
+// channelDiff returns the absolute difference between two color channels.
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,80 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// Redacted is the value used in place of redacted JSON values.
+const Redacted = "[REDACTED]"
+
+// RequireEqualJSON is like [RequireEqual] but it marshals the given value to
+// JSON before comparing it with the golden file. Object keys are sorted and
+// the output is indented to make the golden files stable and readable.
+//
+// Use [WithRedact] to redact volatile fields such as timestamps and IDs.
+func RequireEqualJSON(tb testing.TB, v any, opts ...Option) {
+	tb.Helper()
+
+	o := newOptions(opts...)
+	out, err := marshalJSON(v, o)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	requireEqual(tb, out, false, o)
+}
+
+// marshalJSON marshals the given value to stable indented JSON. The value is
+// first decoded into generic maps and slices so that object keys are sorted
+// regardless of the value type.
+func marshalJSON(v any, o options) ([]byte, error) {
+	bts, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep numbers as is, decoding them as float64 loses the precision of
+	// large integers.
+	var generic any
+	dec := json.NewDecoder(bytes.NewReader(bts))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	if len(o.redact) > 0 {
+		keys := make(map[string]struct{}, len(o.redact))
+		for _, k := range o.redact {
+			keys[k] = struct{}{}
+		}
+		generic = redact(generic, keys)
+	}
+
+	out, err := json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, '\n'), nil
+}
+
+// redact replaces the values of the given object keys with [Redacted].
+func redact(v any, keys map[string]struct{}) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, vv := range v {
+			if _, ok := keys[k]; ok {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redact(vv, keys)
+		}
+	case []any:
+		for i, vv := range v {
+			v[i] = redact(vv, keys)
+		}
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package golden
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// Option is an option for the golden file helpers.
+type Option func(*options)
+
+// options holds the golden file helpers options.
+type options struct {
+	redact    []string
+	tolerance uint8
+	dir       string
+	ext       string
+	name      []func(string) string
+	context   *int
+	color     *bool
+	diffOnly  bool
+}
+
+// newOptions returns the options after applying the given option list.
+func newOptions(opts ...Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithRedact redacts the values of the given object keys at any depth before
+// comparing JSON values. This is useful for volatile fields such as
+// timestamps and IDs. It only applies to [RequireEqualJSON].
+func WithRedact(keys ...string) Option {
+	return func(o *options) {
+		o.redact = append(o.redact, keys...)
+	}
+}
+
+// WithTolerance sets the maximum difference allowed between the color
+// channels of two pixels for them to be considered equal. It only applies to
+// [RequireEqualImage].
+func WithTolerance(tolerance uint8) Option {
+	return func(o *options) {
+		o.tolerance = tolerance
+	}
+}
+
+// WithDir sets the directory of the golden files. The default is "testdata".
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dir = dir
+	}
+}
+
+// WithExtension sets the extension of the golden files including the leading
+// dot. The default is ".golden", or ".png" for [RequireEqualImage].
+func WithExtension(ext string) Option {
+	return func(o *options) {
+		o.ext = ext
+	}
+}
+
+// WithName adds a function that transforms the test name used as the golden
+// file name. By default, the test name is used as is which means subtests
+// are stored in nested directories. Name functions are applied in order.
+func WithName(fn func(name string) string) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.name = append(o.name, fn)
+		}
+	}
+}
+
+// WithPlatformSuffix appends the current operating system to the golden file
+// name, for example "TestFoo_windows". This is useful when the output is
+// different on each platform.
+func WithPlatformSuffix() Option {
+	return WithName(func(name string) string {
+		return name + "_" + runtime.GOOS
+	})
+}
+
+// path returns the golden file path for the given test. The given extension
+// is used when no extension option is set.
+func (o options) path(tb testing.TB, ext string) string {
+	dir := o.dir
+	if dir == "" {
+		dir = "testdata"
+	}
+	if o.ext != "" {
+		ext = o.ext
+	}
+
+	name := tb.Name()
+	for _, fn := range o.name {
+		name = fn(name)
+	}
+
+	return filepath.Join(dir, name+ext)
+}
+
+// WithDiffContext sets the number of unchanged lines shown around each change
+// in the diff. Large matching regions outside of the context are elided. The
+// default is [DefaultDiffContext].
+func WithDiffContext(lines int) Option {
+	return func(o *options) {
+		if lines < 0 {
+			lines = 0
+		}
+		o.context = &lines
+	}
+}
+
+// WithColor forces enabling or disabling the colorized diff output. By
+// default, the diff is colorized when the standard output is a terminal and
+// the NO_COLOR environment variable isn't set.
+func WithColor(v bool) Option {
+	return func(o *options) {
+		o.color = &v
+	}
+}
+
+// WithDiffOnly omits the full expected and actual outputs from the failure
+// message and only prints the diff. This is useful for large outputs.
+func WithDiffOnly() Option {
+	return func(o *options) {
+		o.diffOnly = true
+	}
+}
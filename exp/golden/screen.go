@@ -0,0 +1,104 @@
+package golden
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// Screen represents a terminal screen that can be compared with golden files.
+// Both [vt.Terminal] and [cellbuf.Buffer] implement this interface.
+//
+// [vt.Terminal]: https://pkg.go.dev/github.com/charmbracelet/x/vt#Terminal
+type Screen interface {
+	// Width returns the width of the screen.
+	Width() int
+	// Height returns the height of the screen.
+	Height() int
+	// Cell returns the cell at the given position or nil if it's blank.
+	Cell(x, y int) *cellbuf.Cell
+}
+
+// RequireEqualScreen is a helper function to assert the contents of the given
+// screen are the expected from the golden files.
+//
+// The screen is serialized line by line. Styles and hyperlinks are written as
+// escape sequences before the cells they apply to, and they are reset at the
+// end of each line. Trailing blank cells are trimmed. The golden files contain
+// the escaped serialization, like [RequireEqualEscaped], which makes them
+// readable and reviewable.
+func RequireEqualScreen(tb testing.TB, scr Screen, opts ...Option) {
+	tb.Helper()
+	requireEqual(tb, []byte(renderScreen(scr)), true, newOptions(opts...))
+}
+
+// renderScreen returns a stable serialization of the given screen.
+func renderScreen(scr Screen) string {
+	lines := make([]string, scr.Height())
+	for y := range lines {
+		lines[y] = renderScreenLine(scr, y)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// renderScreenLine returns the serialization of the line y of the given
+// screen.
+func renderScreenLine(scr Screen, y int) string {
+	var b strings.Builder
+	var pen cellbuf.Style
+	var link cellbuf.Link
+
+	// Trim the trailing blank cells.
+	width := scr.Width()
+	for width > 0 && isBlankCell(scr.Cell(width-1, y)) {
+		width--
+	}
+
+	for x := 0; x < width; x++ {
+		c := scr.Cell(x, y)
+		if c == nil {
+			c = &cellbuf.BlankCell
+		}
+		if c.Empty() {
+			// Skip wide cells placeholders.
+			continue
+		}
+
+		if !c.Style.Equal(pen) {
+			if !pen.Empty() {
+				b.WriteString(ansi.ResetStyle)
+			}
+			if !c.Style.Empty() {
+				b.WriteString(c.Style.Sequence())
+			}
+			pen = c.Style
+		}
+		if !c.Link.Equal(link) {
+			var params []string
+			if c.Link.URLID != "" {
+				// The link params, like "id=foo".
+				params = append(params, c.Link.URLID)
+			}
+			b.WriteString(ansi.SetHyperlink(c.Link.URL, params...))
+			link = c.Link
+		}
+
+		b.WriteString(c.String())
+	}
+
+	if !link.Empty() {
+		b.WriteString(ansi.ResetHyperlink())
+	}
+	if !pen.Empty() {
+		b.WriteString(ansi.ResetStyle)
+	}
+
+	return b.String()
+}
+
+// isBlankCell returns whether the given cell is an unstyled space.
+func isBlankCell(c *cellbuf.Cell) bool {
+	return c == nil || c.Equal(&cellbuf.BlankCell)
+}
@@ -0,0 +1,34 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+func TestRequireEqualScreen(t *testing.T) {
+	buf := cellbuf.NewBuffer(10, 3)
+	var style cellbuf.Style
+	style.Bold(true).Foreground(ansi.Red)
+	buf.SetCell(0, 0, &cellbuf.Cell{Rune: 'h', Width: 1, Style: style})
+	buf.SetCell(1, 0, &cellbuf.Cell{Rune: 'i', Width: 1, Style: style})
+	buf.SetCell(3, 0, &cellbuf.Cell{Rune: 'x', Width: 1})
+	buf.SetCell(0, 1, &cellbuf.Cell{Rune: '世', Width: 2})
+	buf.SetCell(2, 1, &cellbuf.Cell{Rune: 'l', Width: 1, Link: cellbuf.Link{URL: "https://charm.sh"}})
+	buf.SetCell(0, 2, &cellbuf.Cell{Rune: 'g', Width: 1, Link: cellbuf.Link{URL: "https://charm.sh", URLID: "id=foo"}})
+
+	RequireEqualScreen(t, buf)
+}
+
+func TestRenderScreen(t *testing.T) {
+	buf := cellbuf.NewBuffer(4, 2)
+	var style cellbuf.Style
+	style.Italic(true)
+	buf.SetCell(1, 0, &cellbuf.Cell{Rune: 'a', Width: 1, Style: style})
+
+	want := " \x1b[3ma\x1b[m\n\n"
+	if got := renderScreen(buf); got != want {
+		t.Errorf("renderScreen() = %q, want %q", got, want)
+	}
+}
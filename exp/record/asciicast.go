@@ -0,0 +1,126 @@
+package record
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// AsciicastHeader is the header of an asciicast v2 recording.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/
+type AsciicastHeader struct {
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// AsciicastRecorder records a session in the asciicast v2 format used by
+// asciinema. It implements [Resizer]; use [Writer.Resize] to record resize
+// events timed with the writer's output.
+type AsciicastRecorder struct {
+	w       io.Writer
+	pending []byte
+	last    time.Duration
+	mu      sync.Mutex
+}
+
+var _ Resizer = &AsciicastRecorder{}
+
+// NewAsciicastRecorder returns a new [AsciicastRecorder] that writes to w. It
+// writes the given header right away. A zero timestamp is set to the current
+// time.
+func NewAsciicastRecorder(w io.Writer, header AsciicastHeader) (*AsciicastRecorder, error) {
+	if header.Timestamp == 0 {
+		header.Timestamp = time.Now().Unix()
+	}
+
+	v := struct {
+		Version int `json:"version"`
+		AsciicastHeader
+	}{2, header} //nolint:gomnd
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &AsciicastRecorder{w: w}, nil
+}
+
+// Record implements [Recorder]. It writes an output event. Incomplete UTF-8
+// sequences at the end of p are held until the next call since asciicast
+// events must be valid UTF-8.
+func (r *AsciicastRecorder) Record(elapsed time.Duration, p []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending = append(r.pending, p...)
+	n := completeUTF8(r.pending)
+	if n == 0 {
+		return nil
+	}
+
+	err := r.event(elapsed, "o", string(r.pending[:n]))
+	r.pending = append(r.pending[:0], r.pending[n:]...)
+	return err
+}
+
+// Resize implements [Resizer]. It records a terminal resize event.
+func (r *AsciicastRecorder) Resize(elapsed time.Duration, width, height int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.event(elapsed, "r", strconv.Itoa(width)+"x"+strconv.Itoa(height))
+}
+
+// Close implements [Recorder]. It writes any pending output.
+func (r *AsciicastRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+	err := r.event(r.last, "o", string(r.pending))
+	r.pending = r.pending[:0]
+	return err
+}
+
+// event writes an asciicast event line.
+func (r *AsciicastRecorder) event(elapsed time.Duration, code, data string) error {
+	r.last = elapsed
+	line, err := json.Marshal([]interface{}{
+		json.Number(strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64)), //nolint:gomnd
+		code,
+		data,
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	_, err = r.w.Write(append(line, '\n'))
+	return err //nolint:wrapcheck
+}
+
+// completeUTF8 returns the length of p without any incomplete UTF-8 sequence
+// at its end.
+func completeUTF8(p []byte) int {
+	// A UTF-8 sequence is at most 4 bytes long, so only the last 3 bytes can
+	// start an incomplete sequence.
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax+1; i-- {
+		if !utf8.RuneStart(p[i]) {
+			continue
+		}
+		if !utf8.FullRune(p[i:]) {
+			return i
+		}
+		break
+	}
+	return len(p)
+}
@@ -0,0 +1,3 @@
+module github.com/charmbracelet/x/exp/record
+
+go 1.19
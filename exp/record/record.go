@@ -0,0 +1,91 @@
+// Package record records terminal sessions. A [Writer] tees everything
+// written to the terminal into a [Recorder], such as a script(1) compatible
+// typescript and timing file pair or an asciicast v2 file.
+package record
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Recorder records terminal output.
+type Recorder interface {
+	// Record records the given output written after the given elapsed time
+	// since the start of the recording.
+	Record(elapsed time.Duration, p []byte) error
+
+	// Close finishes the recording.
+	Close() error
+}
+
+// Resizer is a [Recorder] that records terminal resize events.
+type Resizer interface {
+	Recorder
+
+	// Resize records a terminal resize to the given size after the given
+	// elapsed time since the start of the recording.
+	Resize(elapsed time.Duration, width, height int) error
+}
+
+// Writer is an [io.Writer] that writes to the terminal and records everything
+// written to a [Recorder].
+type Writer struct {
+	w     io.Writer
+	rec   Recorder
+	start time.Time
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+// NewWriter returns a new [Writer] that writes to w and records to rec. The
+// recording starts when the writer is created.
+func NewWriter(w io.Writer, rec Recorder) *Writer {
+	return newWriter(w, rec, time.Now)
+}
+
+// newWriter returns a new [Writer] with the given clock.
+func newWriter(w io.Writer, rec Recorder, now func() time.Time) *Writer {
+	return &Writer{w: w, rec: rec, start: now(), now: now}
+}
+
+// Write implements [io.Writer]. Only the bytes successfully written to the
+// terminal are recorded.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n, err := w.w.Write(p)
+	if n > 0 {
+		if rerr := w.rec.Record(w.now().Sub(w.start), p[:n]); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	return n, err //nolint:wrapcheck
+}
+
+// Resize records a terminal resize event using the writer's clock, so it's
+// timed like the recorded output. It does nothing if the recorder isn't a
+// [Resizer].
+func (w *Writer) Resize(width, height int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r, ok := w.rec.(Resizer)
+	if !ok {
+		return nil
+	}
+	return r.Resize(w.now().Sub(w.start), width, height) //nolint:wrapcheck
+}
+
+// Recorder returns the recorder of the writer.
+func (w *Writer) Recorder() Recorder {
+	return w.rec
+}
+
+// Close closes the recorder. It doesn't close the terminal writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rec.Close()
+}
@@ -0,0 +1,154 @@
+package record
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a clock that advances by step on every call.
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	now := start
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+var epoch = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestScriptRecorder(t *testing.T) {
+	var term, typescript, timing bytes.Buffer
+	clock := fakeClock(epoch, 250*time.Millisecond)
+	rec, err := newScriptRecorder(&typescript, &timing, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := newWriter(&term, rec, clock)
+	for _, s := range []string{"hello\r\n", "\x1b[31mworld\x1b[m"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := term.String(), "hello\r\n\x1b[31mworld\x1b[m"; got != want {
+		t.Errorf("terminal = %q, want %q", got, want)
+	}
+	wantScript := "Script started on 2024-01-02 03:04:05+00:00\n" +
+		"hello\r\n\x1b[31mworld\x1b[m" +
+		"\nScript done on 2024-01-02 03:04:06+00:00\n"
+	if got := typescript.String(); got != wantScript {
+		t.Errorf("typescript = %q, want %q", got, wantScript)
+	}
+	if got, want := timing.String(), "0.250000 7\n0.250000 13\n"; got != want {
+		t.Errorf("timing = %q, want %q", got, want)
+	}
+}
+
+func TestAsciicastRecorder(t *testing.T) {
+	var term, cast bytes.Buffer
+	rec, err := NewAsciicastRecorder(&cast, AsciicastHeader{
+		Width:     80,
+		Height:    24,
+		Timestamp: epoch.Unix(),
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := newWriter(&term, rec, fakeClock(epoch, 500*time.Millisecond))
+	// Split a multi-byte rune across writes.
+	for _, p := range [][]byte{[]byte("a\xe2\x94"), []byte("\x80b\x1b[m")} {
+		if _, err := w.Write(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Resize(100, 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"version":2,"width":80,"height":24,"timestamp":1704164645,"env":{"TERM":"xterm-256color"}}` + "\n" +
+		`[0.500000,"o","a"]` + "\n" +
+		`[1.000000,"o","─b\u001b[m"]` + "\n" +
+		`[1.500000,"r","100x30"]` + "\n"
+	if got := cast.String(); got != want {
+		t.Errorf("asciicast = %q, want %q", got, want)
+	}
+}
+
+func TestAsciicastRecorderConcurrentResize(t *testing.T) {
+	var cast bytes.Buffer
+	rec, err := NewAsciicastRecorder(&cast, AsciicastHeader{Width: 80, Height: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(io.Discard, rec)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			w.Write([]byte("a")) //nolint:errcheck
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			w.Resize(80, 24) //nolint:errcheck
+		}
+	}()
+	wg.Wait()
+
+	// Each event is written whole on its own line.
+	lines := strings.Split(strings.TrimSuffix(cast.String(), "\n"), "\n")
+	if got, want := len(lines), 201; got != want {
+		t.Errorf("got %d lines, want %d", got, want)
+	}
+}
+
+func TestWriterResizeUnsupported(t *testing.T) {
+	var term, typescript, timing bytes.Buffer
+	rec, err := newScriptRecorder(&typescript, &timing, fakeClock(epoch, time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(&term, rec)
+	if err := w.Resize(100, 30); err != nil {
+		t.Errorf("Resize() = %v, want nil", err)
+	}
+	if got := timing.String(); got != "" {
+		t.Errorf("timing = %q, want empty", got)
+	}
+}
+
+func TestCompleteUTF8(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"a\xe2\x94\x80", 4},
+		{"a\xe2\x94", 1},
+		{"a\xf0\x9f\x98", 1},
+		{"\xff", 1},
+	}
+	for _, tt := range tests {
+		if got := completeUTF8([]byte(tt.in)); got != tt.want {
+			t.Errorf("completeUTF8(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
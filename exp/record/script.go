@@ -0,0 +1,56 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// scriptTimeFormat is the time format used in typescript headers.
+const scriptTimeFormat = "2006-01-02 15:04:05-07:00"
+
+// ScriptRecorder records a session in the script(1) format. It writes the
+// output to a typescript file and the delays to a timing file, which can be
+// replayed with scriptreplay(1):
+//
+//	scriptreplay --timing=timing typescript
+type ScriptRecorder struct {
+	typescript io.Writer
+	timing     io.Writer
+	now        func() time.Time
+	last       time.Duration
+}
+
+// NewScriptRecorder returns a new [ScriptRecorder] that writes the output to
+// typescript and the timing information to timing. It writes the typescript
+// header right away.
+func NewScriptRecorder(typescript, timing io.Writer) (*ScriptRecorder, error) {
+	return newScriptRecorder(typescript, timing, time.Now)
+}
+
+// newScriptRecorder returns a new [ScriptRecorder] with the given clock.
+func newScriptRecorder(typescript, timing io.Writer, now func() time.Time) (*ScriptRecorder, error) {
+	r := &ScriptRecorder{typescript: typescript, timing: timing, now: now}
+	if _, err := fmt.Fprintf(typescript, "Script started on %s\n", now().Format(scriptTimeFormat)); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return r, nil
+}
+
+// Record implements [Recorder]. Each call writes a timing entry with the delay
+// since the previous output in seconds and the number of bytes written.
+func (r *ScriptRecorder) Record(elapsed time.Duration, p []byte) error {
+	delay := elapsed - r.last
+	r.last = elapsed
+	if _, err := r.typescript.Write(p); err != nil {
+		return err //nolint:wrapcheck
+	}
+	_, err := fmt.Fprintf(r.timing, "%.6f %d\n", delay.Seconds(), len(p))
+	return err //nolint:wrapcheck
+}
+
+// Close implements [Recorder]. It writes the typescript footer.
+func (r *ScriptRecorder) Close() error {
+	_, err := fmt.Fprintf(r.typescript, "\nScript done on %s\n", r.now().Format(scriptTimeFormat))
+	return err //nolint:wrapcheck
+}
@@ -3,8 +3,10 @@ package input
 import (
 	"bytes"
 	"io"
+	"sync"
 	"unicode/utf8"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/muesli/cancelreader"
 )
 
@@ -23,6 +25,7 @@ type Logger interface {
 type win32InputState struct {
 	ansiBuf                    [256]byte
 	ansiIdx                    int
+	ansiSeq                    []byte // the escape sequence of the last event, if any
 	utf16Buf                   [2]rune
 	utf16Half                  bool
 	lastCks                    uint32 // the last control key state for the previous event
@@ -51,6 +54,54 @@ type Reader struct {
 
 	parser Parser
 	logger Logger
+
+	// limits are the size and flood limits of the reader.
+	limits ansi.Limits
+
+	// reports is the number of consecutive terminal reports read since the
+	// last user input event.
+	reports int
+
+	// err is the error that stopped the last event loop. It's guarded by
+	// errMu since the loop may run in another goroutine.
+	err   error
+	errMu sync.Mutex
+
+	// pending are the events read by [Reader.Query] that are not responses
+	// to the queries.
+	pending []Event
+
+	// inflight is the read started by [Reader.Query] that is still in
+	// progress, if any.
+	inflight chan readResult
+
+	// stale is the number of primary device attributes queries sent by
+	// [Reader.Query] calls that timed out and whose responses were not read
+	// yet.
+	stale int
+
+	// metrics are the reader's metrics callbacks.
+	metrics Metrics
+}
+
+// Metrics are optional callbacks that report what the reader reads. They can
+// be used to export counters, for example to Prometheus, and to spot the
+// sequences a terminal sends that the reader doesn't recognize.
+type Metrics struct {
+	// BytesRead is called with the number of bytes read from the terminal
+	// input. It's not called for the Windows Console API input records,
+	// which aren't read as bytes.
+	BytesRead func(n int)
+
+	// Event is called for each event read, including the [UnknownEvent]
+	// events of unrecognized sequences.
+	Event func(ev Event)
+
+	// Discarded is called with each sequence discarded because it exceeds
+	// the reader limits, see [Reader.SetLimits]. Windows Console API events
+	// that don't come from an escape sequence are reported with an empty
+	// sequence.
+	Discarded func(seq []byte)
 }
 
 // NewReader returns a new input event reader. The reader reads input events
@@ -84,6 +135,29 @@ func (d *Reader) SetLogger(l Logger) {
 	d.logger = l
 }
 
+// SetLimits sets the limits of the reader. This protects the reader from
+// floods of malicious terminal input. Sequences exceeding
+// [ansi.Limits.MaxParams] parameters or [ansi.Limits.MaxDataLen] bytes are
+// discarded. For input, the data length is the length of the whole string
+// sequence.
+//
+// [ansi.Limits.MaxSequences] limits the number of consecutive terminal reports
+// and unrecognized sequences, i.e. events that don't come from the user such
+// as key, mouse, paste, and focus events. Reports beyond the limit are
+// discarded until the next user input event.
+func (d *Reader) SetLimits(l ansi.Limits) {
+	d.limits = l
+}
+
+// SetMetrics sets the metrics callbacks of the reader. The callbacks are
+// called from the goroutine that reads the events. SetMetrics is not safe to
+// call concurrently with reading events, so set the callbacks before reading
+// starts, i.e. before calling [Reader.ReadEvents], [Reader.Events], or
+// [Reader.Query].
+func (d *Reader) SetMetrics(m Metrics) {
+	d.metrics = m
+}
+
 // Read implements [io.Reader].
 func (d *Reader) Read(p []byte) (int, error) {
 	return d.rd.Read(p)
@@ -99,11 +173,50 @@ func (d *Reader) Close() error {
 	return d.rd.Close()
 }
 
+// ReadEvents reads input events from the terminal.
+//
+// It reads the events available in the input buffer and returns them.
+func (d *Reader) ReadEvents() ([]Event, error) {
+	if len(d.pending) > 0 {
+		events := d.pending
+		d.pending = nil
+		return events, nil
+	}
+	if d.inflight != nil {
+		r := <-d.inflight
+		d.inflight = nil
+		return d.dropStale(r.events), r.err
+	}
+	events, err := d.read()
+	return d.dropStale(events), err
+}
+
+// dropStale removes the responses to the primary device attributes queries
+// of timed out [Reader.Query] calls from events.
+func (d *Reader) dropStale(events []Event) []Event {
+	if d.stale == 0 {
+		return events
+	}
+	kept := events[:0]
+	for _, ev := range events {
+		if _, ok := ev.(PrimaryDeviceAttributesEvent); ok && d.stale > 0 {
+			d.stale--
+			continue
+		}
+		kept = append(kept, ev)
+	}
+	return kept
+}
+
 func (d *Reader) readEvents() (events []Event, err error) {
 	nb, err := d.rd.Read(d.buf[:])
 	if err != nil {
 		return nil, err
 	}
+	if d.metrics.BytesRead != nil {
+		d.metrics.BytesRead(nb)
+	}
+	defer func() { d.countEvents(events) }()
 
 	buf := d.buf[:nb]
 
@@ -134,6 +247,14 @@ func (d *Reader) readEvents() (events []Event, err error) {
 			}
 		}
 
+		if ev != nil && d.discard(buf[i:i+nb], ev) {
+			if d.metrics.Discarded != nil {
+				d.metrics.Discarded(buf[i : i+nb])
+			}
+			i += nb
+			continue
+		}
+
 		switch ev.(type) {
 		case UnknownEvent:
 			// If the sequence is not recognized by the parser, try looking it up.
@@ -169,3 +290,82 @@ func (d *Reader) readEvents() (events []Event, err error) {
 
 	return
 }
+
+// countEvents reports the events read to the metrics callbacks.
+func (d *Reader) countEvents(events []Event) {
+	if d.metrics.Event == nil {
+		return
+	}
+	for _, ev := range events {
+		d.metrics.Event(ev)
+	}
+}
+
+// discard reports whether the event of the given sequence exceeds the reader
+// limits and must be discarded.
+func (d *Reader) discard(seq []byte, ev Event) bool {
+	if isUserEvent(ev) {
+		d.reports = 0
+		return false
+	}
+
+	if d.limits.MaxSequences > 0 && d.reports >= d.limits.MaxSequences {
+		return true
+	}
+	d.reports++
+
+	if len(seq) < 2 { //nolint:gomnd
+		return false
+	}
+
+	intro, rest := seq[0], seq[1:]
+	if intro == ansi.ESC {
+		if seq[1] < '@' || seq[1] > '_' {
+			return false
+		}
+		// Convert the 7-bit introducer to its 8-bit C1 form.
+		intro, rest = seq[1]+0x40, seq[2:] //nolint:gomnd
+	}
+
+	switch intro {
+	case ansi.CSI, ansi.DCS:
+		if d.limits.MaxParams > 0 && countParams(rest) > d.limits.MaxParams {
+			return true
+		}
+	}
+
+	switch intro {
+	case ansi.DCS, ansi.OSC, ansi.APC, ansi.PM, ansi.SOS:
+		if d.limits.MaxDataLen > 0 && len(seq) > d.limits.MaxDataLen {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countParams returns the number of parameters at the start of the given
+// sequence body.
+func countParams(b []byte) int {
+	var n int
+	for i := 0; i < len(b) && b[i] >= '0' && b[i] <= '?'; i++ {
+		if n == 0 {
+			n = 1
+		}
+		if b[i] == ';' || b[i] == ':' {
+			n++
+		}
+	}
+	return n
+}
+
+// isUserEvent reports whether the event comes from the user as opposed to a
+// terminal report.
+func isUserEvent(ev Event) bool {
+	switch ev.(type) {
+	case KeyEvent, MouseEvent, MultiEvent, PasteEvent, PasteStartEvent,
+		PasteEndEvent, ComposedTextEvent, FocusEvent, BlurEvent, WindowSizeEvent:
+		return true
+	}
+	return false
+}
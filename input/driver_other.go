@@ -3,10 +3,8 @@
 
 package input
 
-// ReadEvents reads input events from the terminal.
-//
-// It reads the events available in the input buffer and returns them.
-func (d *Reader) ReadEvents() ([]Event, error) {
+// read reads input events from the terminal.
+func (d *Reader) read() ([]Event, error) {
 	return d.readEvents()
 }
 
@@ -2,8 +2,11 @@ package input
 
 import (
 	"io"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 func BenchmarkDriver(b *testing.B) {
@@ -23,3 +26,96 @@ func BenchmarkDriver(b *testing.B) {
 		}
 	}
 }
+
+func TestReaderLimits(t *testing.T) {
+	cases := []struct {
+		name   string
+		limits ansi.Limits
+		input  string
+		want   []Event
+	}{
+		{
+			name:  "no_limits",
+			input: "\x1b[?1;2c\x1b]11;rgb:0000/0000/0000\x07",
+			want:  []Event{PrimaryDeviceAttributesEvent{1, 2}, BackgroundColorEvent{ansi.XParseColor("rgb:0000/0000/0000")}},
+		},
+		{
+			name:   "max_params",
+			limits: ansi.Limits{MaxParams: 2},
+			input:  "\x1b[?1;2c\x1b[?1;2;3c",
+			want:   []Event{PrimaryDeviceAttributesEvent{1, 2}},
+		},
+		{
+			name:   "max_data_len",
+			limits: ansi.Limits{MaxDataLen: 16},
+			input:  "\x1b]11;rgb:0000/0000/0000\x07\x1b[?1c",
+			want:   []Event{PrimaryDeviceAttributesEvent{1}},
+		},
+		{
+			name:   "max_sequences",
+			limits: ansi.Limits{MaxSequences: 2},
+			input:  "\x1b[?1c\x1b[?2c\x1b[?3ca\x1b[?4c",
+			want: []Event{
+				PrimaryDeviceAttributesEvent{1},
+				PrimaryDeviceAttributesEvent{2},
+				KeyPressEvent{Code: 'a', Text: "a"},
+				PrimaryDeviceAttributesEvent{4},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			drv, err := NewReader(strings.NewReader(c.input), "dumb", 0)
+			if err != nil {
+				t.Fatalf("could not create driver: %v", err)
+			}
+			drv.SetLimits(c.limits)
+			events, err := drv.ReadEvents()
+			if err != nil {
+				t.Fatalf("error reading input: %v", err)
+			}
+			if !reflect.DeepEqual(events, c.want) {
+				t.Errorf("ReadEvents() = %#v, want %#v", events, c.want)
+			}
+		})
+	}
+}
+
+func TestReaderMetrics(t *testing.T) {
+	input := "a\x1b[?1c\x1b[?1;2;3c\x1b[9999z"
+	drv, err := NewReader(strings.NewReader(input), "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create driver: %v", err)
+	}
+	drv.SetLimits(ansi.Limits{MaxParams: 2})
+
+	var bytes int
+	var events []Event
+	var discarded []string
+	drv.SetMetrics(Metrics{
+		BytesRead: func(n int) { bytes += n },
+		Event:     func(ev Event) { events = append(events, ev) },
+		Discarded: func(seq []byte) { discarded = append(discarded, string(seq)) },
+	})
+	want, err := drv.ReadEvents()
+	if err != nil {
+		t.Fatalf("error reading input: %v", err)
+	}
+
+	if bytes != len(input) {
+		t.Errorf("bytes read = %d, want %d", bytes, len(input))
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %#v, want %#v", events, want)
+	}
+	if len(want) == 0 {
+		t.Fatalf("ReadEvents() = no events")
+	}
+	if _, ok := want[len(want)-1].(UnknownEvent); !ok {
+		t.Errorf("last event = %#v, want an UnknownEvent", want[len(want)-1])
+	}
+	if wantDiscarded := []string{"\x1b[?1;2;3c"}; !reflect.DeepEqual(discarded, wantDiscarded) {
+		t.Errorf("discarded = %q, want %q", discarded, wantDiscarded)
+	}
+}
@@ -16,10 +16,8 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-// ReadEvents reads input events from the terminal.
-//
-// It reads the events available in the input buffer and returns them.
-func (d *Reader) ReadEvents() ([]Event, error) {
+// read reads input events from the terminal.
+func (d *Reader) read() ([]Event, error) {
 	events, err := d.handleConInput(readConsoleInput)
 	if errors.Is(err, errNotConInputReader) {
 		return d.readEvents()
@@ -47,15 +45,28 @@ func (d *Reader) handleConInput(
 
 	var evs []Event
 	for _, event := range events {
-		if e := d.parser.parseConInputEvent(event, &d.keyState); e != nil {
-			if multi, ok := e.(MultiEvent); ok {
-				evs = append(evs, multi...)
-			} else {
-				evs = append(evs, e)
+		d.keyState.ansiSeq = nil
+		e := d.parser.parseConInputEvent(event, &d.keyState)
+		if e == nil {
+			continue
+		}
+		// Apply the reader limits like [Reader.readEvents]. Events that
+		// don't come from an escape sequence are only counted against
+		// [ansi.Limits.MaxSequences].
+		if seq := d.keyState.ansiSeq; d.discard(seq, e) {
+			if d.metrics.Discarded != nil {
+				d.metrics.Discarded(seq)
 			}
+			continue
+		}
+		if multi, ok := e.(MultiEvent); ok {
+			evs = append(evs, multi...)
+		} else {
+			evs = append(evs, e)
 		}
 	}
 
+	d.countEvents(evs)
 	return evs, nil
 }
 
@@ -213,7 +224,7 @@ func peekConsoleInput(console windows.Handle, inputRecords []xwindows.InputRecor
 // an event from win32-input-mode. Otherwise, it's a key event from the Windows
 // Console API and needs a state to decode ANSI escape sequences and utf16
 // runes.
-func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ uint16, r rune, keyDown bool, cks uint32, repeatCount uint16) (Event Event) {
+func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, sc uint16, r rune, keyDown bool, cks uint32, repeatCount uint16) (Event Event) {
 	defer func() {
 		// Respect the repeat count.
 		if repeatCount > 1 {
@@ -236,16 +247,12 @@ func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ u
 		state.utf16Half = false
 		state.utf16Buf[1] = r
 		codepoint := utf16.DecodeRune(state.utf16Buf[0], state.utf16Buf[1])
-		rw := utf8.EncodeRune(utf8Buf[:], codepoint)
-		r, _ = utf8.DecodeRune(utf8Buf[:rw])
-		key.Code = r
-		key.Text = string(r)
-		key.Mod = translateControlKeyState(cks)
-		key = ensureKeyCase(key, cks)
-		if keyDown {
-			return KeyPressEvent(key)
+		if !keyDown || codepoint == utf8.RuneError {
+			return nil
 		}
-		return KeyReleaseEvent(key)
+		// Surrogate pairs come from the IME or the emoji picker, not from a
+		// key press.
+		return ComposedTextEvent(string(codepoint))
 	}
 
 	var baseCode rune
@@ -279,6 +286,7 @@ func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ u
 			}
 
 			state.ansiIdx = 0
+			state.ansiSeq = state.ansiBuf[:n]
 			return Event
 		}
 	case vkc == xwindows.VK_BACK:
@@ -287,6 +295,10 @@ func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ u
 		baseCode = KeyTab
 	case vkc == xwindows.VK_RETURN:
 		baseCode = KeyEnter
+		if cks&xwindows.ENHANCED_KEY != 0 {
+			// The keypad Enter key is an enhanced key.
+			baseCode = KeyKpEnter
+		}
 	case vkc == xwindows.VK_SHIFT:
 		if cks&xwindows.SHIFT_PRESSED != 0 {
 			if cks&xwindows.ENHANCED_KEY != 0 {
@@ -336,29 +348,32 @@ func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ u
 	case vkc == xwindows.VK_SPACE:
 		baseCode = KeySpace
 	case vkc == xwindows.VK_PRIOR:
-		baseCode = KeyPgUp
+		baseCode = navKey(cks, sc, KeyPgUp, KeyKpPgUp)
 	case vkc == xwindows.VK_NEXT:
-		baseCode = KeyPgDown
+		baseCode = navKey(cks, sc, KeyPgDown, KeyKpPgDown)
 	case vkc == xwindows.VK_END:
-		baseCode = KeyEnd
+		baseCode = navKey(cks, sc, KeyEnd, KeyKpEnd)
 	case vkc == xwindows.VK_HOME:
-		baseCode = KeyHome
+		baseCode = navKey(cks, sc, KeyHome, KeyKpHome)
 	case vkc == xwindows.VK_LEFT:
-		baseCode = KeyLeft
+		baseCode = navKey(cks, sc, KeyLeft, KeyKpLeft)
 	case vkc == xwindows.VK_UP:
-		baseCode = KeyUp
+		baseCode = navKey(cks, sc, KeyUp, KeyKpUp)
 	case vkc == xwindows.VK_RIGHT:
-		baseCode = KeyRight
+		baseCode = navKey(cks, sc, KeyRight, KeyKpRight)
 	case vkc == xwindows.VK_DOWN:
-		baseCode = KeyDown
+		baseCode = navKey(cks, sc, KeyDown, KeyKpDown)
+	case vkc == xwindows.VK_CLEAR:
+		// The keypad 5 key when Num Lock is off.
+		baseCode = KeyKpBegin
 	case vkc == xwindows.VK_SELECT:
 		baseCode = KeySelect
 	case vkc == xwindows.VK_SNAPSHOT:
 		baseCode = KeyPrintScreen
 	case vkc == xwindows.VK_INSERT:
-		baseCode = KeyInsert
+		baseCode = navKey(cks, sc, KeyInsert, KeyKpInsert)
 	case vkc == xwindows.VK_DELETE:
-		baseCode = KeyDelete
+		baseCode = navKey(cks, sc, KeyDelete, KeyKpDelete)
 	case vkc >= '0' && vkc <= '9':
 		baseCode = rune(vkc)
 	case vkc >= 'A' && vkc <= 'Z':
@@ -416,6 +431,8 @@ func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ u
 		baseCode = KeyMediaStop
 	case vkc == xwindows.VK_MEDIA_PLAY_PAUSE:
 		baseCode = KeyMediaPlayPause
+	case vkc == xwindows.VK_PLAY:
+		baseCode = KeyMediaPlay
 	case vkc == xwindows.VK_OEM_1:
 		baseCode = ';'
 	case vkc == xwindows.VK_OEM_PLUS:
@@ -480,6 +497,18 @@ func (p *Parser) parseWin32InputKeyEvent(state *win32InputState, vkc uint16, _ u
 	return KeyReleaseEvent(key)
 }
 
+// navKey returns the keypad variant of a navigation key when the key comes
+// from the keypad with Num Lock off. The dedicated navigation keys have the
+// ENHANCED_KEY flag set, while the keypad keys don't. Synthesized key events
+// don't set the flag either, but they have no scan code, so keys without a
+// scan code are taken as the navigation keys.
+func navKey(cks uint32, sc uint16, key, kpKey rune) rune {
+	if cks&xwindows.ENHANCED_KEY != 0 || sc == 0 {
+		return key
+	}
+	return kpKey
+}
+
 // ensureKeyCase ensures that the key's text is in the correct case based on the
 // control key state.
 func ensureKeyCase(key Key, cks uint32) Key {
@@ -66,11 +66,56 @@ func TestWindowsInputEvents(t *testing.T) {
 			},
 			expected: []Event{KeyPressEvent{Code: 'A', BaseCode: 'a', Text: "A", Mod: ModShift}},
 		},
+		{
+			name: "navigation key",
+			events: []xwindows.InputRecord{
+				encodeKeyEvent(xwindows.KeyEventRecord{
+					KeyDown:         true,
+					VirtualKeyCode:  xwindows.VK_UP,
+					VirtualScanCode: 0x48,
+					ControlKeyState: xwindows.ENHANCED_KEY,
+				}),
+			},
+			expected: []Event{KeyPressEvent{Code: KeyUp, BaseCode: KeyUp}},
+		},
+		{
+			name: "synthesized navigation key",
+			events: []xwindows.InputRecord{
+				encodeKeyEvent(xwindows.KeyEventRecord{
+					KeyDown:        true,
+					VirtualKeyCode: xwindows.VK_UP,
+				}),
+			},
+			expected: []Event{KeyPressEvent{Code: KeyUp, BaseCode: KeyUp}},
+		},
+		{
+			name: "keypad navigation key",
+			events: []xwindows.InputRecord{
+				encodeKeyEvent(xwindows.KeyEventRecord{
+					KeyDown:         true,
+					VirtualKeyCode:  xwindows.VK_UP,
+					VirtualScanCode: 0x48,
+				}),
+			},
+			expected: []Event{KeyPressEvent{Code: KeyKpUp, BaseCode: KeyKpUp}},
+		},
+		{
+			name: "keypad enter key",
+			events: []xwindows.InputRecord{
+				encodeKeyEvent(xwindows.KeyEventRecord{
+					KeyDown:         true,
+					Char:            '\r',
+					VirtualKeyCode:  xwindows.VK_RETURN,
+					ControlKeyState: xwindows.ENHANCED_KEY,
+				}),
+			},
+			expected: []Event{KeyPressEvent{Code: KeyKpEnter, BaseCode: KeyKpEnter}},
+		},
 		{
 			name:   "utf16 rune",
 			events: encodeUtf16Rune('😊'), // smiley emoji '😊'
 			expected: []Event{
-				KeyPressEvent{Code: '😊', Text: "😊"},
+				ComposedTextEvent("😊"),
 			},
 			sequence: true,
 		},
@@ -263,3 +308,35 @@ func encodeUtf16Pair(r1, r2 rune) []xwindows.InputRecord {
 		}),
 	}
 }
+
+func TestHandleConInputLimits(t *testing.T) {
+	records := append(encodeSequence("\x1b[?1c\x1b[?1;2;3c"), encodeKeyEvent(xwindows.KeyEventRecord{
+		KeyDown:        true,
+		Char:           'a',
+		VirtualKeyCode: 'A',
+	}))
+	d := &Reader{rd: &conInputReader{}}
+	d.SetLimits(ansi.Limits{MaxParams: 2})
+
+	var discarded []string
+	d.SetMetrics(Metrics{
+		Discarded: func(seq []byte) { discarded = append(discarded, string(seq)) },
+	})
+	events, err := d.handleConInput(func(_ windows.Handle, recs []xwindows.InputRecord) (uint32, error) {
+		return uint32(copy(recs, records)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Event{
+		PrimaryDeviceAttributesEvent{1},
+		KeyPressEvent{Code: 'a', BaseCode: 'a', Text: "a"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %#v, want %#v", events, want)
+	}
+	if wantDiscarded := []string{"\x1b[?1;2;3c"}; !reflect.DeepEqual(discarded, wantDiscarded) {
+		t.Errorf("discarded = %q, want %q", discarded, wantDiscarded)
+	}
+}
@@ -242,6 +242,14 @@ func TestParseSequence(t *testing.T) {
 			[]byte("\x1b[97;;229u"),
 			[]Event{KeyPressEvent{Code: 'a', Text: "å"}},
 		},
+		seqTest{
+			[]byte("\x1b[0;;233u"),
+			[]Event{ComposedTextEvent("é")},
+		},
+		seqTest{
+			[]byte("\x1b[0;;26085:26412u"),
+			[]Event{ComposedTextEvent("日本")},
+		},
 
 		// focus/blur
 		seqTest{
@@ -836,3 +844,81 @@ func BenchmarkDetectSequenceMap(b *testing.B) {
 		}
 	}
 }
+
+func TestKeyNames(t *testing.T) {
+	// Every special key has a unique name.
+	seen := map[string]rune{}
+	for k := KeyUp; k <= KeyIsoLevel5Shift; k++ {
+		name, ok := keyTypeString[k]
+		if !ok || name == "" {
+			t.Errorf("key %d has no name", k)
+			continue
+		}
+		if other, ok := seen[name]; ok {
+			t.Errorf("keys %d and %d share the name %q", other, k, name)
+		}
+		seen[name] = k
+	}
+
+	// Names are part of the API and must not change.
+	cases := []struct {
+		key  Key
+		want string
+	}{
+		{Key{Code: KeyF13}, "f13"},
+		{Key{Code: KeyF35}, "f35"},
+		{Key{Code: KeyKp5}, "kp5"},
+		{Key{Code: KeyKpEnter}, "kpenter"},
+		{Key{Code: KeyKpBegin}, "kpbegin"},
+		{Key{Code: KeyKpHome, Mod: ModShift}, "shift+kphome"},
+		{Key{Code: KeyMediaPlayPause}, "mediaplaypause"},
+		{Key{Code: KeyRaiseVol}, "raisevol"},
+		{Key{Code: KeyIsoLevel3Shift}, "isolevel3shift"},
+		{Key{Code: KeyIsoLevel5Shift}, "isolevel5shift"},
+		{Key{Code: KeyLeftCtrl, Mod: ModCtrl}, "leftctrl"},
+	}
+	for _, c := range cases {
+		if got := c.key.String(); got != c.want {
+			t.Errorf("Key{Code: %d}.String() = %q, want %q", c.key.Code, got, c.want)
+		}
+	}
+}
+
+func TestKeypadKeysConsistent(t *testing.T) {
+	// The legacy, terminfo, and kitty decoders agree on keypad symbols.
+	cases := []struct {
+		seq  string
+		want Key
+	}{
+		{"\x1bOM", Key{Code: KeyKpEnter}},
+		{"\x1bOp", Key{Code: KeyKp0}},
+		{"\x1b[57399u", Key{Code: KeyKp0}},
+		{"\x1b[57414u", Key{Code: KeyKpEnter}},
+		{"\x1b[57423u", Key{Code: KeyKpHome}},
+		{"\x1b[57453u", Key{Code: KeyIsoLevel3Shift}},
+		{"\x1b[57376u", Key{Code: KeyF13}},
+		{"\x1b[57398u", Key{Code: KeyF35}},
+	}
+	p := NewParser(0)
+	for _, c := range cases {
+		_, ev := p.parseSequence([]byte(c.seq))
+		k, ok := ev.(KeyPressEvent)
+		if !ok || k.Code != c.want.Code {
+			t.Errorf("parseSequence(%q) = %#v, want key %q", c.seq, ev, c.want)
+		}
+	}
+
+	keys := defaultTerminfoKeys(0)
+	for cap, want := range map[string]rune{
+		"kent": KeyKpEnter,
+		"ka1":  KeyKpHome,
+		"ka3":  KeyKpPgUp,
+		"kb2":  KeyKpBegin,
+		"kc1":  KeyKpEnd,
+		"kc3":  KeyKpPgDown,
+	} {
+		if got := keys[cap].Code; got != want {
+			t.Errorf("terminfo %q = %d, want %d", cap, got, want)
+		}
+	}
+}
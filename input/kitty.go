@@ -226,6 +226,7 @@ func fromKittyMod(mod int) KeyMod {
 // See https://sw.kovidgoyal.net/kitty/keyboard-protocol/
 func parseKittyKeyboard(params ansi.Params) (Event Event) {
 	var isRelease bool
+	var noKey bool // text is not associated with a key
 	var key Key
 
 	// The index of parameters separated by semicolons ';'. Sub parameters are
@@ -240,6 +241,7 @@ func parseKittyKeyboard(params ansi.Params) (Event Event) {
 			case 0:
 				var foundKey bool
 				code := p.Param(1) // CSI u has a default value of 1
+				noKey = code == 0
 				key, foundKey = kittyKeyMap[code]
 				if !foundKey {
 					r := rune(code)
@@ -309,6 +311,12 @@ func parseKittyKeyboard(params ansi.Params) (Event Event) {
 		}
 	}
 
+	if noKey && len(key.Text) > 0 && !isRelease {
+		// A key code of zero with associated text is composed text, for
+		// example, from an IME.
+		return ComposedTextEvent(key.Text)
+	}
+
 	if len(key.Text) == 0 && unicode.IsPrint(key.Code) &&
 		(key.Mod <= ModShift || key.Mod == ModCapsLock || key.Mod == ModShift|ModCapsLock) {
 		if key.Mod == 0 {
@@ -0,0 +1,124 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by [Reader.Pump] when the event handler panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("input: event handler panic: %v", e.Value)
+}
+
+// loop reads events and calls fn for each of them until fn returns false or
+// reading fails. It returns the read error, if any, and records it for
+// [Reader.Err].
+func (d *Reader) loop(fn func(Event) bool) error {
+	d.setErr(nil)
+	for {
+		events, err := d.ReadEvents()
+		for _, ev := range events {
+			if !fn(ev) {
+				return nil
+			}
+		}
+		if err != nil {
+			d.setErr(err)
+			return err
+		}
+	}
+}
+
+// Err returns the error that stopped the last [Reader.Seq] iteration, if
+// any. Like [Reader.ReadEvents], it returns [io.EOF] when the input is
+// exhausted and [cancelreader.ErrCanceled] when the reader is canceled.
+//
+// [cancelreader.ErrCanceled]: https://pkg.go.dev/github.com/muesli/cancelreader#ErrCanceled
+func (d *Reader) Err() error {
+	d.errMu.Lock()
+	defer d.errMu.Unlock()
+	return d.err
+}
+
+// setErr records the error that stopped the event loop.
+func (d *Reader) setErr(err error) {
+	d.errMu.Lock()
+	d.err = err
+	d.errMu.Unlock()
+}
+
+// Events reads events in a new goroutine and sends them to the returned
+// events channel until ctx is done or reading fails. The events channel is
+// closed when reading stops, after which the error channel receives the
+// error that stopped it. When ctx is done, the reader is canceled and the
+// error is ctx.Err().
+//
+// Example:
+//
+//	events, errc := r.Events(ctx)
+//	for ev := range events {
+//	  log.Printf("%v", ev)
+//	}
+//	if err := <-errc; err != nil && err != io.EOF {
+//	  log.Fatal(err)
+//	}
+func (d *Reader) Events(ctx context.Context) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errc := make(chan error, 1)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.Cancel()
+		case <-done:
+		}
+	}()
+
+	go func() {
+		defer close(errc)
+		defer close(events)
+		defer close(done)
+
+		err := d.loop(func(ev Event) bool {
+			select {
+			case events <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		errc <- err
+	}()
+
+	return events, errc
+}
+
+// Pump reads events and calls fn for each of them until reading fails. A
+// panic in fn stops the pump and is returned as a [*PanicError], leaving the
+// reader usable. Otherwise, it returns the read error, see [Reader.Err].
+func (d *Reader) Pump(fn func(Event)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return d.loop(func(ev Event) bool {
+		fn(ev)
+		return true
+	})
+}
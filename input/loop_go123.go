@@ -0,0 +1,22 @@
+//go:build go1.23
+
+package input
+
+import "iter"
+
+// Seq returns an iterator over the events read from the reader. The
+// iteration stops when reading fails, use [Reader.Err] to get the error.
+//
+// Example:
+//
+//	for ev := range r.Seq() {
+//	  log.Printf("%v", ev)
+//	}
+//	if err := r.Err(); err != nil && err != io.EOF {
+//	  log.Fatal(err)
+//	}
+func (d *Reader) Seq() iter.Seq[Event] {
+	return func(yield func(Event) bool) {
+		d.loop(yield) //nolint:errcheck
+	}
+}
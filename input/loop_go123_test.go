@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package input
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestReaderSeq(t *testing.T) {
+	r := newTestReader(t, "a\x1b[A")
+	var got []Event
+	for ev := range r.Seq() {
+		got = append(got, ev)
+	}
+	if !reflect.DeepEqual(got, loopEvents) {
+		t.Errorf("Seq() = %#v, want %#v", got, loopEvents)
+	}
+	if err := r.Err(); err != io.EOF {
+		t.Errorf("Err() = %v, want %v", err, io.EOF)
+	}
+
+	// Breaking out of the loop stops reading without an error.
+	r = newTestReader(t, "a\x1b[A")
+	for range r.Seq() {
+		break
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
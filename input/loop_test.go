@@ -0,0 +1,83 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestReader(t *testing.T, input string) *Reader {
+	t.Helper()
+	r, err := NewReader(strings.NewReader(input), "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create reader: %v", err)
+	}
+	return r
+}
+
+var loopEvents = []Event{
+	KeyPressEvent{Code: 'a', Text: "a"},
+	KeyPressEvent{Code: KeyUp},
+}
+
+func TestReaderEvents(t *testing.T) {
+	r := newTestReader(t, "a\x1b[A")
+	events, errc := r.Events(context.Background())
+
+	var got []Event
+	for ev := range events {
+		got = append(got, ev)
+	}
+	if !reflect.DeepEqual(got, loopEvents) {
+		t.Errorf("Events() = %#v, want %#v", got, loopEvents)
+	}
+	if err := <-errc; err != io.EOF {
+		t.Errorf("Events() error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestReaderEventsCanceled(t *testing.T) {
+	r := newTestReader(t, "a\x1b[A")
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errc := r.Events(ctx)
+
+	<-events
+	cancel()
+	for range events { //nolint:revive
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Events() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestReaderPump(t *testing.T) {
+	r := newTestReader(t, "a\x1b[A")
+	var got []Event
+	err := r.Pump(func(ev Event) {
+		got = append(got, ev)
+	})
+	if err != io.EOF {
+		t.Errorf("Pump() error = %v, want %v", err, io.EOF)
+	}
+	if !reflect.DeepEqual(got, loopEvents) {
+		t.Errorf("Pump() events = %#v, want %#v", got, loopEvents)
+	}
+}
+
+func TestReaderPumpPanic(t *testing.T) {
+	r := newTestReader(t, "a\x1b[A")
+	err := r.Pump(func(Event) {
+		panic("boom")
+	})
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Pump() error = %v, want a *PanicError", err)
+	}
+	if pe.Value != "boom" || len(pe.Stack) == 0 {
+		t.Errorf("PanicError = %v with %d bytes of stack", pe.Value, len(pe.Stack))
+	}
+}
@@ -2,8 +2,6 @@ package input
 
 import (
 	"bytes"
-	"encoding/base64"
-	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -529,6 +527,12 @@ func (p *Parser) parseCsi(b []byte) (int, Event) {
 			}
 		}
 
+		// In-band resize report (mode 2048).
+		//  CSI 48 ; height ; width ; height_pixels ; width_pixels t
+		if winop.Op == 48 && len(winop.Args) >= 2 { //nolint:gomnd
+			return i, WindowSizeEvent{Width: winop.Args[1], Height: winop.Args[0]}
+		}
+
 		return i, winop
 	}
 	return i, UnknownEvent(b[:i])
@@ -665,22 +669,16 @@ func (p *Parser) parseOsc(b []byte) (int, Event) {
 	case 12:
 		return i, CursorColorEvent{ansi.XParseColor(data)}
 	case 52:
-		parts := strings.Split(data, ";")
-		if len(parts) == 0 {
-			return i, ClipboardEvent{}
-		}
-		if len(parts) != 2 || len(parts[0]) < 1 {
+		// The data starts after the "52;" command prefix. Without a ";" there
+		// is no data.
+		if start < 3 {
 			break
 		}
-
-		b64 := parts[1]
-		bts, err := base64.StdEncoding.DecodeString(b64)
+		sel, content, err := ansi.ParseClipboard(b[start-3 : end])
 		if err != nil {
 			break
 		}
-
-		sel := ClipboardSelection(parts[0][0])
-		return i, ClipboardEvent{Selection: sel, Content: string(bts)}
+		return i, ClipboardEvent{Selection: sel, Content: content}
 	}
 
 	return i, UnknownEvent(b[:i])
@@ -36,6 +36,39 @@ func TestParseSequence_Events(t *testing.T) {
 	}
 }
 
+func TestParseInBandResize(t *testing.T) {
+	var p Parser
+	n, got := p.parseSequence([]byte("\x1b[48;24;80;480;800t"))
+	if n != 19 {
+		t.Errorf("n = %d, want 19", n)
+	}
+	if want := (WindowSizeEvent{Width: 80, Height: 24}); got != want {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	_, got = p.parseSequence([]byte("\x1b[8;24;80t"))
+	if want := (WindowOpEvent{Op: 8, Args: []int{24, 80}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestParseClipboard(t *testing.T) {
+	var p Parser
+	cases := map[string]Event{
+		"\x1b]52;c;aGVsbG8=\x07":   ClipboardEvent{Selection: SystemClipboard, Content: "hello"},
+		"\x1b]52;p;aGVsbG8\x1b\\":  ClipboardEvent{Selection: PrimaryClipboard, Content: "hello"},
+		"\x1b]52;c;not base64\x07": UnknownEvent("\x1b]52;c;not base64\x07"),
+		"\x1b]52\x07":              UnknownEvent("\x1b]52\x07"),
+		"\x1b]52;\x07":             UnknownEvent("\x1b]52;\x07"),
+	}
+	for input, want := range cases {
+		n, got := p.parseSequence([]byte(input))
+		if n != len(input) || !reflect.DeepEqual(got, want) {
+			t.Errorf("parseSequence(%q) = %d, %#v, want %d, %#v", input, n, got, len(input), want)
+		}
+	}
+}
+
 func BenchmarkParseSequence(b *testing.B) {
 	var p Parser
 	input := []byte("\x1b\x1b[Ztest\x00\x1b]10;1234/1234/1234\x07\x1b[27;2;27~")
@@ -0,0 +1,223 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ErrQueryTimeout is returned by [Reader.Query] when the terminal didn't
+// answer the queries in time.
+var ErrQueryTimeout = errors.New("input: query timeout")
+
+// Query is a terminal query sent with [Reader.Query].
+type Query struct {
+	// Seq is the sequence sent to the terminal.
+	Seq string
+
+	// Match reports whether the event is the response to the query.
+	Match func(Event) bool
+}
+
+// QueryPrimaryDeviceAttributes returns a query for the primary device
+// attributes (DA1). The response is a [PrimaryDeviceAttributesEvent].
+func QueryPrimaryDeviceAttributes() Query {
+	return Query{ansi.RequestPrimaryDeviceAttributes, func(ev Event) bool {
+		_, ok := ev.(PrimaryDeviceAttributesEvent)
+		return ok
+	}}
+}
+
+// QueryCursorPosition returns a query for the cursor position (DSR). The
+// response is a [CursorPositionEvent].
+func QueryCursorPosition() Query {
+	return Query{ansi.RequestCursorPositionReport, func(ev Event) bool {
+		_, ok := ev.(CursorPositionEvent)
+		return ok
+	}}
+}
+
+// QueryMode returns a query for the setting of the given mode (DECRQM). The
+// response is a [ModeReportEvent] for the same mode.
+func QueryMode(m ansi.Mode) Query {
+	return Query{ansi.RequestMode(m), func(ev Event) bool {
+		r, ok := ev.(ModeReportEvent)
+		return ok && r.Mode == m
+	}}
+}
+
+// QueryForegroundColor returns a query for the default foreground color. The
+// response is a [ForegroundColorEvent].
+func QueryForegroundColor() Query {
+	return Query{ansi.RequestForegroundColor, func(ev Event) bool {
+		_, ok := ev.(ForegroundColorEvent)
+		return ok
+	}}
+}
+
+// QueryBackgroundColor returns a query for the default background color. The
+// response is a [BackgroundColorEvent].
+func QueryBackgroundColor() Query {
+	return Query{ansi.RequestBackgroundColor, func(ev Event) bool {
+		_, ok := ev.(BackgroundColorEvent)
+		return ok
+	}}
+}
+
+// QueryCursorColor returns a query for the cursor color. The response is a
+// [CursorColorEvent].
+func QueryCursorColor() Query {
+	return Query{ansi.RequestCursorColor, func(ev Event) bool {
+		_, ok := ev.(CursorColorEvent)
+		return ok
+	}}
+}
+
+// QueryTerminalVersion returns a query for the terminal name and version
+// (XTVERSION). The response is a [TerminalVersionEvent].
+func QueryTerminalVersion() Query {
+	return Query{ansi.RequestNameVersion, func(ev Event) bool {
+		_, ok := ev.(TerminalVersionEvent)
+		return ok
+	}}
+}
+
+// QueryKittyKeyboard returns a query for the enabled Kitty keyboard
+// enhancements. The response is a [KittyEnhancementsEvent].
+func QueryKittyKeyboard() Query {
+	return Query{ansi.RequestKittyKeyboard, func(ev Event) bool {
+		_, ok := ev.(KittyEnhancementsEvent)
+		return ok
+	}}
+}
+
+// QueryTermcap returns a query for the given Termcap/Terminfo capabilities
+// (XTGETTCAP). The response is a [CapabilityEvent].
+func QueryTermcap(caps ...string) Query {
+	return Query{ansi.RequestTermcap(caps...), func(ev Event) bool {
+		_, ok := ev.(CapabilityEvent)
+		return ok
+	}}
+}
+
+// readResult is the result of a read started by [Reader.Query].
+type readResult struct {
+	events []Event
+	err    error
+}
+
+// Query writes the queries to w, usually the terminal output, and reads the
+// terminal responses. It returns the responses in the same order as the
+// queries, a nil response means the terminal didn't answer the query.
+//
+// The queries are followed by a primary device attributes query (DA1) that
+// every terminal answers. Terminals answer queries in order, so once the DA1
+// response is read, the unanswered queries are known to be unsupported and
+// Query returns without waiting for the timeout. If the terminal doesn't
+// answer in time, Query returns the responses read so far and
+// [ErrQueryTimeout]. A timeout of 0 or less waits forever.
+//
+// Events that are not responses to the queries, such as key presses, are
+// kept and returned by the next call to [Reader.ReadEvents]. When Query times
+// out, the pending read continues in the background and its events are also
+// returned by the next call to [Reader.ReadEvents]. The late responses read
+// by the next Query are discarded instead of being taken as responses to its
+// own queries.
+//
+// Query must not be called concurrently with the other Reader methods that
+// read events, such as [Reader.ReadEvents] and [Reader.Events].
+//
+// Example:
+//
+//	res, err := r.Query(os.Stdout, time.Second,
+//	  input.QueryBackgroundColor(),
+//	  input.QueryMode(ansi.SynchronizedOutputMode),
+//	)
+//	if bg, ok := res[0].(input.BackgroundColorEvent); ok {
+//	  log.Printf("background: %v", bg.Color)
+//	}
+func (d *Reader) Query(w io.Writer, timeout time.Duration, queries ...Query) ([]Event, error) {
+	var seq string
+	for _, q := range queries {
+		seq += q.Seq
+	}
+	sentinel := QueryPrimaryDeviceAttributes()
+	seq += sentinel.Seq
+	if _, err := io.WriteString(w, seq); err != nil {
+		return nil, err
+	}
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	// Read events left from a previous query first. These were read before
+	// any timed out query was abandoned, so they are not late responses.
+	events := d.pending
+	d.pending = nil
+	fresh := false
+
+	results := make([]Event, len(queries))
+	var err error
+	for {
+		for i, ev := range events {
+			if fresh && d.stale > 0 {
+				// Until the sentinel of a timed out query is read, the
+				// terminal reports are late responses to that query.
+				if sentinel.Match(ev) {
+					d.stale--
+				} else if isUserEvent(ev) {
+					d.pending = append(d.pending, ev)
+				}
+				continue
+			}
+			if matchQuery(queries, results, ev) {
+				continue
+			}
+			if sentinel.Match(ev) {
+				d.pending = append(d.pending, events[i+1:]...)
+				return results, err
+			}
+			d.pending = append(d.pending, ev)
+		}
+		if err != nil {
+			return results, err
+		}
+
+		if d.inflight == nil {
+			ch := make(chan readResult, 1)
+			go func() {
+				events, err := d.read()
+				ch <- readResult{events, err}
+			}()
+			d.inflight = ch
+		}
+
+		select {
+		case r := <-d.inflight:
+			d.inflight = nil
+			events, err = r.events, r.err
+			fresh = true
+		case <-deadline:
+			d.stale++
+			return results, ErrQueryTimeout
+		}
+	}
+}
+
+// matchQuery assigns the event to the first unanswered query it matches. It
+// returns false when the event is not a response to the queries.
+func matchQuery(queries []Query, results []Event, ev Event) bool {
+	for i, q := range queries {
+		if results[i] == nil && q.Match(ev) {
+			results[i] = ev
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fakeTerminal answers the queries written to it with the given responses.
+type fakeTerminal struct {
+	w         io.Writer
+	responses map[string]string
+	written   strings.Builder
+}
+
+func (t *fakeTerminal) Write(p []byte) (int, error) {
+	t.written.Write(p)
+	var out string
+	for seq := string(p); seq != ""; {
+		_, _, n, _ := ansi.DecodeSequence(seq, 0, nil)
+		out += t.responses[seq[:n]]
+		seq = seq[n:]
+	}
+	go io.WriteString(t.w, out) //nolint:errcheck
+	return len(p), nil
+}
+
+func TestReaderQuery(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close() //nolint:errcheck
+	r, err := NewReader(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create reader: %v", err)
+	}
+
+	term := &fakeTerminal{w: pw, responses: map[string]string{
+		ansi.RequestBackgroundColor:                   "\x1b]11;rgb:0000/0000/0000\x07",
+		ansi.RequestMode(ansi.SynchronizedOutputMode): "\x1b[?2026;2$y",
+		ansi.RequestMode(ansi.BracketedPasteMode):     "\x1b[?2004;1$y",
+		// A key press typed while querying.
+		ansi.RequestPrimaryDeviceAttributes: "a\x1b[?62;4c",
+	}}
+
+	res, err := r.Query(term, time.Second,
+		QueryBackgroundColor(),
+		QueryKittyKeyboard(), // unsupported
+		QueryMode(ansi.BracketedPasteMode),
+		QueryMode(ansi.SynchronizedOutputMode),
+	)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	want := []Event{
+		BackgroundColorEvent{ansi.XParseColor("rgb:0000/0000/0000")},
+		nil,
+		ModeReportEvent{Mode: ansi.BracketedPasteMode, Value: ansi.ModeSet},
+		ModeReportEvent{Mode: ansi.SynchronizedOutputMode, Value: ansi.ModeReset},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("Query() = %#v, want %#v", res, want)
+	}
+
+	wantSeq := ansi.RequestBackgroundColor + ansi.RequestKittyKeyboard +
+		ansi.RequestMode(ansi.BracketedPasteMode) + ansi.RequestMode(ansi.SynchronizedOutputMode) +
+		ansi.RequestPrimaryDeviceAttributes
+	if got := term.written.String(); got != wantSeq {
+		t.Errorf("Query() wrote %q, want %q", got, wantSeq)
+	}
+
+	events, err := r.ReadEvents()
+	if err != nil {
+		t.Fatalf("ReadEvents() error = %v", err)
+	}
+	if want := []Event{KeyPressEvent{Code: 'a', Text: "a"}}; !reflect.DeepEqual(events, want) {
+		t.Errorf("ReadEvents() = %#v, want %#v", events, want)
+	}
+}
+
+func TestReaderQueryTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close() //nolint:errcheck
+	r, err := NewReader(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create reader: %v", err)
+	}
+
+	res, err := r.Query(io.Discard, 10*time.Millisecond, QueryCursorPosition())
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("Query() error = %v, want %v", err, ErrQueryTimeout)
+	}
+	if !reflect.DeepEqual(res, []Event{nil}) {
+		t.Errorf("Query() = %#v, want no responses", res)
+	}
+
+	// The read started by the query is returned by the next read.
+	go io.WriteString(pw, "b") //nolint:errcheck
+	events, err := r.ReadEvents()
+	if err != nil {
+		t.Fatalf("ReadEvents() error = %v", err)
+	}
+	if want := []Event{KeyPressEvent{Code: 'b', Text: "b"}}; !reflect.DeepEqual(events, want) {
+		t.Errorf("ReadEvents() = %#v, want %#v", events, want)
+	}
+}
+
+func TestReaderQueryLateResponses(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close() //nolint:errcheck
+	r, err := NewReader(pr, "dumb", 0)
+	if err != nil {
+		t.Fatalf("could not create reader: %v", err)
+	}
+
+	if _, err := r.Query(io.Discard, 10*time.Millisecond, QueryBackgroundColor()); !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("Query() error = %v, want %v", err, ErrQueryTimeout)
+	}
+
+	// The terminal answers the timed out query late, along with a key press,
+	// before answering the next one.
+	term := &fakeTerminal{w: pw, responses: map[string]string{
+		ansi.RequestBackgroundColor: "\x1b]11;rgb:ffff/ffff/ffff\x07\x1b[?62;4cc" +
+			"\x1b]11;rgb:0000/0000/0000\x07",
+		ansi.RequestPrimaryDeviceAttributes: "\x1b[?62;4c",
+	}}
+	res, err := r.Query(term, time.Second, QueryBackgroundColor())
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if want := []Event{BackgroundColorEvent{ansi.XParseColor("rgb:0000/0000/0000")}}; !reflect.DeepEqual(res, want) {
+		t.Errorf("Query() = %#v, want %#v", res, want)
+	}
+
+	events, err := r.ReadEvents()
+	if err != nil {
+		t.Fatalf("ReadEvents() error = %v", err)
+	}
+	if want := []Event{KeyPressEvent{Code: 'c', Text: "c"}}; !reflect.DeepEqual(events, want) {
+		t.Errorf("ReadEvents() = %#v, want %#v", events, want)
+	}
+}
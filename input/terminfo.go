@@ -142,6 +142,15 @@ func defaultTerminfoKeys(flags int) map[string]Key {
 		"kbs":  {Code: KeyBackspace},
 		"kcbt": {Code: KeyTab, Mod: ModShift},
 
+		// Keypad keys
+		// The 3x3 keypad corners and center, and the keypad Enter key.
+		"ka1":  {Code: KeyKpHome},
+		"ka3":  {Code: KeyKpPgUp},
+		"kb2":  {Code: KeyKpBegin},
+		"kc1":  {Code: KeyKpEnd},
+		"kc3":  {Code: KeyKpPgDown},
+		"kent": {Code: KeyKpEnter},
+
 		// Function keys
 		// This only includes the first 12 function keys. The rest are treated
 		// as modifiers of the first 12.
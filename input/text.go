@@ -0,0 +1,10 @@
+package input
+
+// ComposedTextEvent represents text composed by an input method editor (IME),
+// dead keys, or a compose sequence. Unlike a [KeyPressEvent], it doesn't
+// correspond to a key press and the text should be inserted as is.
+//
+// This is reported when using the Kitty Keyboard Protocol with associated
+// text reporting enabled, for text that isn't associated with a key, and by
+// the Windows Console API for characters sent as UTF-16 surrogate pairs.
+type ComposedTextEvent string
@@ -0,0 +1,104 @@
+package term
+
+import (
+	"sync"
+)
+
+// Size represents the size of a terminal in cells.
+type Size struct {
+	Width  int
+	Height int
+}
+
+// ResizeWatcher watches a terminal for size changes and emits the new sizes
+// over a channel. On Unix, it listens for SIGWINCH signals. On Windows, it
+// polls the console screen buffer size since console resize events are
+// delivered through the console input buffer which is owned by the input
+// reader.
+//
+// Other sources, such as in-band resize reports (mode 2048) or Windows
+// console input events, can be fed to the watcher using
+// [ResizeWatcher.Notify]. Consecutive duplicate sizes are only emitted once.
+type ResizeWatcher struct {
+	c    chan Size
+	done chan struct{}
+	fd   uintptr
+
+	mu        sync.Mutex
+	last      Size
+	closed    bool
+	closeOnce sync.Once
+	stop      func()
+}
+
+// NewResizeWatcher returns a new watcher for the terminal connected to the
+// given file descriptor. The current size of the terminal is emitted first
+// if it can be determined. Call [ResizeWatcher.Close] to stop watching.
+func NewResizeWatcher(fd uintptr) *ResizeWatcher {
+	w := &ResizeWatcher{
+		c:    make(chan Size, 1),
+		done: make(chan struct{}),
+		fd:   fd,
+	}
+	w.update()
+	w.stop = w.watch()
+	return w
+}
+
+// C returns the channel of size changes. The channel is closed when the
+// watcher is closed.
+func (w *ResizeWatcher) C() <-chan Size {
+	return w.c
+}
+
+// Notify reports a new terminal size to the watcher, for example, from an
+// in-band resize report (mode 2048). The size is emitted if it's different
+// from the last emitted size.
+func (w *ResizeWatcher) Notify(width, height int) {
+	w.emit(Size{Width: width, Height: height})
+}
+
+// Close stops watching the terminal and closes the channel.
+func (w *ResizeWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.stop()
+		w.mu.Lock()
+		w.closed = true
+		close(w.c)
+		w.mu.Unlock()
+	})
+	return nil
+}
+
+// update reads the current terminal size and emits it.
+func (w *ResizeWatcher) update() {
+	width, height, err := GetSize(w.fd)
+	if err != nil {
+		return
+	}
+	w.emit(Size{Width: width, Height: height})
+}
+
+// emit sends the given size if it's different from the last one. If the
+// channel is full, the pending size is replaced with the newer one.
+func (w *ResizeWatcher) emit(s Size) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || s == w.last {
+		return
+	}
+	w.last = s
+	for {
+		select {
+		case w.c <- s:
+			return
+		default:
+		}
+		// Drop the stale size.
+		select {
+		case <-w.c:
+		default:
+		}
+	}
+}
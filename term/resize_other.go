@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !zos && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!zos,!windows
+
+package term
+
+// watch does nothing on unsupported platforms. Sizes can still be reported
+// using [ResizeWatcher.Notify].
+func (w *ResizeWatcher) watch() (stop func()) {
+	return func() {}
+}
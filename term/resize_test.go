@@ -0,0 +1,41 @@
+package term_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+func TestResizeWatcherNotify(t *testing.T) {
+	w := term.NewResizeWatcher(^uintptr(0))
+	defer w.Close() //nolint:errcheck
+
+	w.Notify(80, 24)
+	w.Notify(80, 24)
+	w.Notify(100, 30)
+
+	// Only the latest size is kept when the channel is full.
+	select {
+	case s := <-w.C():
+		if want := (term.Size{Width: 100, Height: 30}); s != want {
+			t.Errorf("size = %v, want %v", s, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for size")
+	}
+
+	select {
+	case s := <-w.C():
+		t.Errorf("unexpected size %v", s)
+	default:
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-w.C(); ok {
+		t.Error("channel is not closed after Close")
+	}
+	w.Notify(10, 10) // must not panic after Close
+}
@@ -0,0 +1,29 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watch listens for SIGWINCH signals until the watcher is closed.
+func (w *ResizeWatcher) watch() (stop func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-sig:
+				w.update()
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build linux
+// +build linux
+
+package term_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"golang.org/x/sys/unix"
+)
+
+func TestResizeWatcherSignal(t *testing.T) {
+	file, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer file.Close()
+
+	fd := int(file.Fd())
+	if err := unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Col: 80, Row: 24}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := term.NewResizeWatcher(file.Fd())
+	defer w.Close() //nolint:errcheck
+
+	expect := func(want term.Size) {
+		t.Helper()
+		select {
+		case s := <-w.C():
+			if s != want {
+				t.Errorf("size = %v, want %v", s, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for size %v", want)
+		}
+	}
+
+	expect(term.Size{Width: 80, Height: 24})
+
+	if err := unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Col: 120, Row: 40}); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGWINCH); err != nil {
+		t.Fatal(err)
+	}
+	expect(term.Size{Width: 120, Height: 40})
+}
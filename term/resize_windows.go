@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package term
+
+import "time"
+
+// resizePollInterval is the interval used to poll the console size.
+const resizePollInterval = 100 * time.Millisecond
+
+// watch polls the console screen buffer size until the watcher is closed.
+func (w *ResizeWatcher) watch() (stop func()) {
+	ticker := time.NewTicker(resizePollInterval)
+	go func() {
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				w.update()
+			}
+		}
+	}()
+	return ticker.Stop
+}
@@ -1,5 +1,10 @@
 package term
 
+import "errors"
+
+// ErrNilState is returned when restoring a terminal to a nil state.
+var ErrNilState = errors.New("term: nil state")
+
 // State contains platform-specific state of a terminal.
 type State struct {
 	state
@@ -29,8 +34,11 @@ func SetState(fd uintptr, state *State) error {
 }
 
 // Restore restores the terminal connected to the given file descriptor to a
-// previous state.
+// previous state. It returns [ErrNilState] if oldState is nil.
 func Restore(fd uintptr, oldState *State) error {
+	if oldState == nil {
+		return ErrNilState
+	}
 	return restore(fd, oldState)
 }
 
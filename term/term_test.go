@@ -35,3 +35,39 @@ func TestIsTerminalTerm(t *testing.T) {
 		t.Fatalf("IsTerminal unexpectedly returned false for terminal file %s", file.Name())
 	}
 }
+
+func TestMakeRawRestore(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("unknown terminal path for GOOS %v", runtime.GOOS)
+	}
+	file, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	before, err := term.GetState(file.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldState, err := term.MakeRaw(file.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := term.Restore(file.Fd(), oldState); err != nil {
+		t.Fatal(err)
+	}
+	after, err := term.GetState(file.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *before != *after {
+		t.Errorf("Restore did not restore the terminal state, got %+v, want %+v", after, before)
+	}
+}
+
+func TestRestoreNilState(t *testing.T) {
+	if err := term.Restore(0, nil); err != term.ErrNilState {
+		t.Errorf("Restore(nil) = %v, want %v", err, term.ErrNilState)
+	}
+}
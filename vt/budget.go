@@ -0,0 +1,33 @@
+package vt
+
+import "errors"
+
+// ErrWriteBudget is returned by [Terminal.Write] when the write is larger
+// than the terminal's write budget, see [WithWriteBudget].
+var ErrWriteBudget = errors.New("vt: write budget exceeded")
+
+// WithWriteBudget returns an [Option] that limits [Terminal.Write] to
+// processing n bytes per call. A larger write processes the first n bytes and
+// returns n and [ErrWriteBudget]. The parser state, including a partially
+// written escape sequence, is kept, so the write can be resumed with the rest
+// of the data on a later call.
+//
+// Hosts that run the terminal on a UI thread can use it to cap the time spent
+// parsing a large burst of output in a single frame. A budget of 0 or less
+// means no limit, which is the default.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithWriteBudget(64*1024))
+//
+//	// On each frame:
+//	n, err := vterm.Write(pending)
+//	pending = pending[n:]
+//	if errors.Is(err, vt.ErrWriteBudget) {
+//		// Resume on the next frame.
+//	}
+func WithWriteBudget(n int) Option {
+	return func(t *Terminal) {
+		t.writeBudget = n
+	}
+}
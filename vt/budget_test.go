@@ -0,0 +1,29 @@
+package vt
+
+import "testing"
+
+func TestWriteBudget(t *testing.T) {
+	term := NewTerminal(10, 5, WithWriteBudget(4))
+
+	// The budget splits the device attributes request in the middle.
+	p := []byte("ab\x1b[c")
+	n, err := term.Write(p)
+	if n != 4 || err != ErrWriteBudget {
+		t.Fatalf("Write() = %d, %v, want 4, %v", n, err, ErrWriteBudget)
+	}
+	if got := readAll(t, term); got != "" {
+		t.Errorf("Read() = %q before the write is resumed, want nothing", got)
+	}
+
+	// Resuming the write completes the sequence.
+	n, err = term.Write(p[n:])
+	if n != 1 || err != nil {
+		t.Fatalf("Write() = %d, %v, want 1, nil", n, err)
+	}
+	if got, want := readAll(t, term), "\x1b[?62;1;6;22c"; got != want {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+	if got, want := termText(term)[0], "ab        "; got != want {
+		t.Errorf("line 0 = %q, want %q", got, want)
+	}
+}
@@ -35,4 +35,21 @@ type Callbacks struct {
 	// CursorStyle callback. When set, this function is called when the cursor
 	// style changes.
 	CursorStyle func(style CursorStyle, blink bool)
+
+	// PrinterController callback. When set, this function is called when the
+	// printer controller mode is turned on or off with the media copy (MC)
+	// sequences "CSI 5 i" and "CSI 4 i". While on, the terminal output is
+	// sent to the [Callbacks.Print] callback instead of the screen.
+	PrinterController func(on bool)
+
+	// Print callback. When set, this function is called with the data sent to
+	// the printer, either the terminal output in printer controller mode or
+	// the screen text requested with media copy (MC). When not set, the data
+	// is discarded.
+	Print func(data []byte)
+
+	// CursorBlink callback. When set, this function is called when the cursor
+	// starts or stops blinking, either with [ansi.CursorBlinkMode] or with a
+	// cursor style change.
+	CursorBlink func(blink bool)
 }
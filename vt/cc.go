@@ -7,6 +7,10 @@ import (
 
 // handleControl handles a control character.
 func (t *Terminal) handleControl(r byte) {
+	if t.handleFlowControl(r) {
+		return
+	}
+
 	switch r {
 	case ansi.NUL: // Null [ansi.NUL]
 		// Ignored
@@ -59,7 +63,6 @@ func (t *Terminal) linefeed() {
 func (t *Terminal) index() {
 	x, y := t.scr.CursorPosition()
 	scroll := t.scr.ScrollRegion()
-	// TODO: Handle scrollback whenever we add it.
 	if y == scroll.Max.Y-1 && x >= scroll.Min.X && x < scroll.Max.X {
 		t.scr.ScrollUp(1)
 	} else if y < scroll.Max.Y-1 || !cellbuf.Pos(x, y).In(scroll) {
@@ -43,3 +43,155 @@ var (
 		'~': "·", // U+00B7
 	}
 )
+
+// National replacement character sets (NRCS). These are only used when
+// [ansi.NationalReplacementCharsetMode] is set.
+//
+// See: https://vt100.net/docs/vt510-rm/chapter2.html#S2.4
+var (
+	Dutch = CharSet{
+		'#':  "£", // U+00A3
+		'@':  "¾", // U+00BE
+		'[':  "ĳ", // U+0133
+		'\\': "½", // U+00BD
+		']':  "|", // U+007C
+		'{':  "¨", // U+00A8
+		'|':  "ƒ", // U+0192
+		'}':  "¼", // U+00BC
+		'~':  "´", // U+00B4
+	}
+
+	Finnish = CharSet{
+		'[':  "Ä", // U+00C4
+		'\\': "Ö", // U+00D6
+		']':  "Å", // U+00C5
+		'^':  "Ü", // U+00DC
+		'`':  "é", // U+00E9
+		'{':  "ä", // U+00E4
+		'|':  "ö", // U+00F6
+		'}':  "å", // U+00E5
+		'~':  "ü", // U+00FC
+	}
+
+	French = CharSet{
+		'#':  "£", // U+00A3
+		'@':  "à", // U+00E0
+		'[':  "°", // U+00B0
+		'\\': "ç", // U+00E7
+		']':  "§", // U+00A7
+		'{':  "é", // U+00E9
+		'|':  "ù", // U+00F9
+		'}':  "è", // U+00E8
+		'~':  "¨", // U+00A8
+	}
+
+	FrenchCanadian = CharSet{
+		'@':  "à", // U+00E0
+		'[':  "â", // U+00E2
+		'\\': "ç", // U+00E7
+		']':  "ê", // U+00EA
+		'^':  "î", // U+00EE
+		'`':  "ô", // U+00F4
+		'{':  "é", // U+00E9
+		'|':  "ù", // U+00F9
+		'}':  "è", // U+00E8
+		'~':  "û", // U+00FB
+	}
+
+	German = CharSet{
+		'@':  "§", // U+00A7
+		'[':  "Ä", // U+00C4
+		'\\': "Ö", // U+00D6
+		']':  "Ü", // U+00DC
+		'{':  "ä", // U+00E4
+		'|':  "ö", // U+00F6
+		'}':  "ü", // U+00FC
+		'~':  "ß", // U+00DF
+	}
+
+	Italian = CharSet{
+		'#':  "£", // U+00A3
+		'@':  "§", // U+00A7
+		'[':  "°", // U+00B0
+		'\\': "ç", // U+00E7
+		']':  "é", // U+00E9
+		'`':  "ù", // U+00F9
+		'{':  "à", // U+00E0
+		'|':  "ò", // U+00F2
+		'}':  "è", // U+00E8
+		'~':  "ì", // U+00EC
+	}
+
+	NorwegianDanish = CharSet{
+		'@':  "Ä", // U+00C4
+		'[':  "Æ", // U+00C6
+		'\\': "Ø", // U+00D8
+		']':  "Å", // U+00C5
+		'^':  "Ü", // U+00DC
+		'`':  "ä", // U+00E4
+		'{':  "æ", // U+00E6
+		'|':  "ø", // U+00F8
+		'}':  "å", // U+00E5
+		'~':  "ü", // U+00FC
+	}
+
+	Spanish = CharSet{
+		'#':  "£", // U+00A3
+		'@':  "§", // U+00A7
+		'[':  "¡", // U+00A1
+		'\\': "Ñ", // U+00D1
+		']':  "¿", // U+00BF
+		'{':  "°", // U+00B0
+		'|':  "ñ", // U+00F1
+		'}':  "ç", // U+00E7
+	}
+
+	Swedish = CharSet{
+		'@':  "É", // U+00C9
+		'[':  "Ä", // U+00C4
+		'\\': "Ö", // U+00D6
+		']':  "Å", // U+00C5
+		'^':  "Ü", // U+00DC
+		'`':  "é", // U+00E9
+		'{':  "ä", // U+00E4
+		'|':  "ö", // U+00F6
+		'}':  "å", // U+00E5
+		'~':  "ü", // U+00FC
+	}
+
+	Swiss = CharSet{
+		'#':  "ù", // U+00F9
+		'@':  "à", // U+00E0
+		'[':  "é", // U+00E9
+		'\\': "ç", // U+00E7
+		']':  "ê", // U+00EA
+		'^':  "î", // U+00EE
+		'_':  "è", // U+00E8
+		'`':  "ô", // U+00F4
+		'{':  "ä", // U+00E4
+		'|':  "ö", // U+00F6
+		'}':  "ü", // U+00FC
+		'~':  "û", // U+00FB
+	}
+)
+
+// nrcsCharSets maps the final byte of a [ansi.SCS] sequence to its national
+// replacement character set.
+var nrcsCharSets = map[byte]CharSet{
+	'4': Dutch,
+	'C': Finnish,
+	'5': Finnish,
+	'R': French,
+	'f': French,
+	'Q': FrenchCanadian,
+	'9': FrenchCanadian,
+	'K': German,
+	'Y': Italian,
+	'E': NorwegianDanish,
+	'6': NorwegianDanish,
+	'`': NorwegianDanish,
+	'Z': Spanish,
+	'H': Swedish,
+	'7': Swedish,
+	'=': Swiss,
+}
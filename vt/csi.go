@@ -12,7 +12,9 @@ func (t *Terminal) handleCsi(cmd ansi.Cmd, params ansi.Params) {
 	case 'a':
 	case ansi.Command(0, 0, 0):
 	}
-	if !t.handlers.handleCsi(cmd, params) {
+	handled := t.handlers.handleCsi(cmd, params)
+	t.countSequence("CSI", handled)
+	if !handled {
 		t.logf("unhandled sequence: CSI %q", paramsString(cmd, params))
 	}
 }
@@ -29,7 +31,7 @@ func (t *Terminal) handleRequestMode(params ansi.Params, isAnsi bool) {
 	}
 
 	setting := t.modes[mode]
-	t.buf.WriteString(ansi.ReportMode(mode, setting))
+	t.respond(ansi.ReportMode(mode, setting))
 }
 
 func paramsString(cmd ansi.Cmd, params ansi.Params) string {
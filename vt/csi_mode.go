@@ -42,6 +42,12 @@ func (t *Terminal) setAltScreenMode(on bool) {
 	} else {
 		t.scr = &t.scrs[0]
 	}
+	// The cursor blink mode is shared by both screens, keep the cursor of the
+	// new screen in sync with it. The cursor doesn't visibly change, so
+	// there's no need to call the callback.
+	t.scr.mu.Lock()
+	t.scr.cur.Steady = !t.modes[ansi.CursorBlinkMode].IsSet()
+	t.scr.mu.Unlock()
 	if t.Callbacks.AltScreen != nil {
 		t.Callbacks.AltScreen(on)
 	}
@@ -64,6 +70,8 @@ func (t *Terminal) setMode(mode ansi.Mode, setting ansi.ModeSetting) {
 	switch mode {
 	case ansi.TextCursorEnableMode:
 		t.scr.setCursorHidden(!setting.IsSet())
+	case ansi.CursorBlinkMode:
+		t.scr.setCursorBlink(setting.IsSet())
 	case ansi.AltScreenMode:
 		t.setAltScreenMode(setting.IsSet())
 	case ansi.SaveCursorMode:
@@ -14,6 +14,13 @@ const (
 type Cursor struct {
 	Pen Style
 
+	// Link is the hyperlink of the printed cells, set with OSC 8.
+	Link Link
+
+	// Protected is whether the printed cells are protected from selective
+	// erase, set with [ansi.DECSCA].
+	Protected bool
+
 	Position
 
 	Style  CursorStyle
@@ -4,14 +4,18 @@ import "github.com/charmbracelet/x/ansi"
 
 // handleDcs handles a DCS escape sequence.
 func (t *Terminal) handleDcs(cmd ansi.Cmd, params ansi.Params, data []byte) {
-	if !t.handlers.handleDcs(cmd, params, data) {
+	handled := t.handlers.handleDcs(cmd, params, data)
+	t.countSequence("DCS", handled)
+	if !handled {
 		t.logf("unhandled sequence: DCS %q %q", paramsString(cmd, params), data)
 	}
 }
 
 // handleApc handles an APC escape sequence.
 func (t *Terminal) handleApc(data []byte) {
-	if !t.handlers.handleApc(data) {
+	handled := t.handlers.handleApc(data)
+	t.countSequence("APC", handled)
+	if !handled {
 		t.logf("unhandled sequence: APC %q", data)
 	}
 }
@@ -0,0 +1,188 @@
+package vt
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// CellDiff is a cell that differs between two screens. A nil cell is a cell
+// outside the bounds of its screen.
+type CellDiff struct {
+	X, Y      int
+	Want, Got *Cell
+}
+
+// String returns a human-readable representation of the cell difference.
+func (d CellDiff) String() string {
+	return fmt.Sprintf("(%d,%d): want %s, got %s", d.X, d.Y, describeCell(d.Want), describeCell(d.Got))
+}
+
+// ScreenDiff describes the differences between two screens.
+type ScreenDiff struct {
+	// WantSize and GotSize are the sizes of the two screens.
+	WantSize, GotSize Rectangle
+	// WantCursor and GotCursor are the cursor positions of the two screens.
+	WantCursor, GotCursor Position
+	// Cells are the cells that differ, in row-major order.
+	Cells []CellDiff
+}
+
+// Empty returns whether the two screens are the same.
+func (d ScreenDiff) Empty() bool {
+	return d.WantSize == d.GotSize && d.WantCursor == d.GotCursor && len(d.Cells) == 0
+}
+
+// String returns a compact human-readable representation of the differences,
+// one per line. It returns an empty string if there are no differences.
+func (d ScreenDiff) String() string {
+	var b strings.Builder
+	if d.WantSize != d.GotSize {
+		fmt.Fprintf(&b, "size: want %dx%d, got %dx%d\n",
+			d.WantSize.Dx(), d.WantSize.Dy(), d.GotSize.Dx(), d.GotSize.Dy())
+	}
+	if d.WantCursor != d.GotCursor {
+		fmt.Fprintf(&b, "cursor: want (%d,%d), got (%d,%d)\n",
+			d.WantCursor.X, d.WantCursor.Y, d.GotCursor.X, d.GotCursor.Y)
+	}
+	for _, c := range d.Cells {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// DiffScreens compares two screens cell-by-cell and returns their
+// differences. Blank and empty cells are considered equal. This is useful
+// in tests to report why a screen doesn't match the expected one.
+func DiffScreens(want, got *Screen) ScreenDiff {
+	want.mu.RLock()
+	defer want.mu.RUnlock()
+	got.mu.RLock()
+	defer got.mu.RUnlock()
+
+	d := ScreenDiff{
+		WantSize:   want.buf.Bounds(),
+		GotSize:    got.buf.Bounds(),
+		WantCursor: want.cur.Position,
+		GotCursor:  got.cur.Position,
+	}
+
+	w := max(want.buf.Width(), got.buf.Width())
+	h := max(want.buf.Height(), got.buf.Height())
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			wc, gc := want.buf.Cell(x, y), got.buf.Cell(x, y)
+			if wc != nil && gc != nil && cellsEqual(wc, gc) {
+				continue
+			}
+			if wc == nil && gc == nil {
+				continue
+			}
+			d.Cells = append(d.Cells, CellDiff{X: x, Y: y, Want: wc, Got: gc})
+		}
+	}
+
+	return d
+}
+
+// cellsEqual returns whether the two cells are equal. An unstyled space and
+// a one column cell with no content are considered equal.
+func cellsEqual(a, b *Cell) bool {
+	return a.Equal(b) || (isBlankOrEmpty(a) && isBlankOrEmpty(b))
+}
+
+// isBlankOrEmpty returns whether the cell is a one column cell with no
+// content, style, or link, or a space cell with no style or link. Wide cell
+// placeholders have zero width and are never blank.
+func isBlankOrEmpty(c *Cell) bool {
+	return (c.Rune == ' ' || c.Rune == 0) &&
+		len(c.Comb) == 0 &&
+		c.Width == 1 &&
+		c.Style.Empty() &&
+		c.Link.Empty()
+}
+
+// describeCell returns a compact description of a cell's content, style, and
+// hyperlink.
+func describeCell(c *Cell) string {
+	if c == nil {
+		return "<none>"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q", c.String())
+	if c.Width > 1 {
+		fmt.Fprintf(&b, " width=%d", c.Width)
+	}
+	if s := describeStyle(c.Style); s != "" {
+		b.WriteByte(' ')
+		b.WriteString(s)
+	}
+	if c.Link.URL != "" {
+		fmt.Fprintf(&b, " link=%s", c.Link.URL)
+	}
+	return b.String()
+}
+
+// describeStyle returns a space separated list of the style attributes and
+// colors.
+func describeStyle(s Style) string {
+	var parts []string
+	for _, a := range []struct {
+		attr cellbuf.AttrMask
+		name string
+	}{
+		{cellbuf.BoldAttr, "bold"},
+		{cellbuf.FaintAttr, "faint"},
+		{cellbuf.ItalicAttr, "italic"},
+		{cellbuf.SlowBlinkAttr, "blink"},
+		{cellbuf.RapidBlinkAttr, "rapidblink"},
+		{cellbuf.ReverseAttr, "reverse"},
+		{cellbuf.ConcealAttr, "conceal"},
+		{cellbuf.StrikethroughAttr, "strikethrough"},
+	} {
+		if s.Attrs&a.attr != 0 {
+			parts = append(parts, a.name)
+		}
+	}
+	switch s.UlStyle {
+	case cellbuf.NoUnderline:
+	case cellbuf.SingleUnderline:
+		parts = append(parts, "underline")
+	case cellbuf.DoubleUnderline:
+		parts = append(parts, "underline=double")
+	case cellbuf.CurlyUnderline:
+		parts = append(parts, "underline=curly")
+	case cellbuf.DottedUnderline:
+		parts = append(parts, "underline=dotted")
+	case cellbuf.DashedUnderline:
+		parts = append(parts, "underline=dashed")
+	}
+	if s.Fg != nil {
+		parts = append(parts, "fg="+describeColor(s.Fg))
+	}
+	if s.Bg != nil {
+		parts = append(parts, "bg="+describeColor(s.Bg))
+	}
+	if s.Ul != nil {
+		parts = append(parts, "ul="+describeColor(s.Ul))
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeColor returns the palette index of indexed colors and the hex
+// representation of other colors.
+func describeColor(c color.Color) string {
+	switch c := c.(type) {
+	case ansi.BasicColor:
+		return fmt.Sprintf("%d", c)
+	case ansi.ExtendedColor:
+		return fmt.Sprintf("%d", c)
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,38 @@
+package vt
+
+import "testing"
+
+func TestDiffScreens(t *testing.T) {
+	want := newTestTerminal(t, 5, 2)
+	got := newTestTerminal(t, 5, 2)
+	want.Write([]byte("hello\r\n\x1b[1;31mab"))
+	got.Write([]byte("hello\r\nab\x1b[4mc"))
+
+	if d := DiffScreens(want.Screen(), want.Screen()); !d.Empty() {
+		t.Errorf("expected no differences, got:\n%s", d)
+	}
+
+	d := DiffScreens(want.Screen(), got.Screen())
+	expected := "cursor: want (2,1), got (3,1)\n" +
+		"(0,1): want \"a\" bold fg=1, got \"a\"\n" +
+		"(1,1): want \"b\" bold fg=1, got \"b\"\n" +
+		"(2,1): want \" \", got \"c\" underline\n"
+	if d.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, d)
+	}
+}
+
+func TestDiffScreensSize(t *testing.T) {
+	want := newTestTerminal(t, 2, 1)
+	got := newTestTerminal(t, 3, 1)
+	got.Write([]byte("\x1b[3G"))
+	got.Write([]byte("x"))
+
+	d := DiffScreens(want.Screen(), got.Screen())
+	expected := "size: want 2x1, got 3x1\n" +
+		"cursor: want (0,0), got (2,0)\n" +
+		"(2,0): want <none>, got \"x\"\n"
+	if d.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, d)
+	}
+}
@@ -1,12 +1,16 @@
 package vt
 
 import (
+	"image/color"
+
 	"github.com/charmbracelet/x/ansi"
 )
 
 // handleEsc handles an escape sequence.
 func (t *Terminal) handleEsc(cmd ansi.Cmd) {
-	if !t.handlers.handleEsc(int(cmd)) {
+	handled := t.handlers.handleEsc(int(cmd))
+	t.countSequence("ESC", handled)
+	if !handled {
 		var str string
 		if inter := cmd.Intermediate(); inter != 0 {
 			str += string(inter) + " "
@@ -26,9 +30,13 @@ func (t *Terminal) fullReset() {
 
 	// TODO: Do we reset all modes here? Investigate.
 	t.resetModes()
+	t.kitty = [2]kittyKeyboard{}
+	t.c1Bits8 = false
 
 	t.gl, t.gr = 0, 1
 	t.gsingle = 0
 	t.charsets = [4]CharSet{}
+	t.fg, t.bg, t.cur = nil, nil, nil
+	t.colors = [256]color.Color{}
 	t.atPhantom = false
 }
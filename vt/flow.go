@@ -0,0 +1,161 @@
+package vt
+
+import (
+	"io"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// delayedResponse is a terminal response that becomes readable at a later
+// time.
+type delayedResponse struct {
+	data string
+	at   time.Time
+}
+
+// WithResponseDelay returns an [Option] that delays the terminal responses,
+// such as device attributes, cursor position, and mode reports, by d before
+// they can be read. Input sent in the meantime, like keys, is read first.
+// This reproduces the slow replies of real terminals, especially over
+// remote connections.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithResponseDelay(50*time.Millisecond))
+func WithResponseDelay(d time.Duration) Option {
+	return func(t *Terminal) {
+		t.responseDelay = d
+	}
+}
+
+// WithReadChunkSize returns an [Option] that limits [Terminal.Read] to n
+// bytes at a time, so that responses and input can be split in the middle
+// of escape sequences like they can be when read from a real terminal.
+func WithReadChunkSize(n int) Option {
+	return func(t *Terminal) {
+		t.readChunkSize = n
+	}
+}
+
+// WithFlowControl returns an [Option] that simulates XON/XOFF software flow
+// control with an output buffer of size bytes.
+//
+// When a write is larger than the buffer, the terminal sends XOFF (DC3)
+// after the first size bytes and XON (DC1) after the rest, as a terminal
+// that can't keep up with its input would. And when the application sends
+// XOFF, the terminal stops sending input until the application sends XON.
+func WithFlowControl(size int) Option {
+	return func(t *Terminal) {
+		t.flowControl = size
+	}
+}
+
+// WithResponseRouter returns an [Option] that passes each terminal response,
+// such as device attributes, cursor position, and mode reports, whole to
+// route along with id instead of queueing it for [Terminal.Read]. Hosts that
+// embed several terminals over one transport can use id to tag and queue the
+// responses per terminal, so that the replies to concurrent queries never
+// interleave. Input, like keys and pastes, is still read with
+// [Terminal.Read].
+//
+// The route function is called while the terminal is locked and must not
+// call the terminal's methods. With a response delay, it's called from a
+// timer goroutine once the response is due. Responses are always routed in
+// order.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithResponseRouter("pane-1", func(id, resp string) {
+//		mux.Send(id, resp)
+//	}))
+func WithResponseRouter(id string, route func(id, resp string)) Option {
+	return func(t *Terminal) {
+		t.routeID = id
+		t.route = route
+	}
+}
+
+// WithResponseWriter returns an [Option] that writes the terminal responses,
+// such as device attributes, cursor position, and mode reports, to w instead
+// of queueing them for [Terminal.Read]. Input, like keys and pastes, is still
+// read with [Terminal.Read]. A response router set with [WithResponseRouter]
+// takes precedence.
+//
+// Like a response router, w is written to while the terminal is locked and
+// must not call the terminal's methods.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithResponseWriter(pty))
+func WithResponseWriter(w io.Writer) Option {
+	return func(t *Terminal) {
+		t.respWriter = w
+	}
+}
+
+// respond sends a response to the application, delayed when the terminal
+// has a response delay. The response uses 8-bit C1 controls when the
+// application asked for them with [ansi.S8C1T].
+func (t *Terminal) respond(s string) {
+	if t.c1Bits8 {
+		s = ansi.To8BitControls(s)
+	}
+	if t.responseDelay <= 0 {
+		t.deliver(s)
+		return
+	}
+	t.delayed = append(t.delayed, delayedResponse{data: s, at: time.Now().Add(t.responseDelay)})
+	if t.route != nil || t.respWriter != nil {
+		// Routed and written responses aren't read, flush them when they're
+		// due.
+		time.AfterFunc(t.responseDelay, func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.flushDelayed()
+		})
+	}
+}
+
+// deliver passes a response to the response router or writer, or to the
+// input buffer when the terminal has neither.
+func (t *Terminal) deliver(s string) {
+	if t.route != nil {
+		t.route(t.routeID, s)
+		return
+	}
+	if t.respWriter != nil {
+		if _, err := io.WriteString(t.respWriter, s); err != nil {
+			t.logf("error writing response: %v", err)
+		}
+		return
+	}
+	t.buf.WriteString(s)
+}
+
+// flushDelayed delivers the delayed responses that are due.
+func (t *Terminal) flushDelayed() {
+	now := time.Now()
+	var i int
+	for ; i < len(t.delayed) && !t.delayed[i].at.After(now); i++ {
+		t.deliver(t.delayed[i].data)
+	}
+	t.delayed = t.delayed[i:]
+}
+
+// handleFlowControl handles the XON and XOFF control characters sent by the
+// application. It reports whether the character was handled.
+func (t *Terminal) handleFlowControl(c byte) bool {
+	if t.flowControl <= 0 {
+		return false
+	}
+	switch c {
+	case ansi.DC1: // XON
+		t.inputPaused = false
+	case ansi.DC3: // XOFF
+		t.inputPaused = true
+	default:
+		return false
+	}
+	return true
+}
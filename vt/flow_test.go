@@ -0,0 +1,132 @@
+package vt
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// readAll reads what's available from the terminal.
+func readAll(t *testing.T, term *Terminal) string {
+	t.Helper()
+	var s string
+	buf := make([]byte, 64)
+	for {
+		n, err := term.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			return s
+		}
+		s += string(buf[:n])
+	}
+}
+
+func TestResponseDelay(t *testing.T) {
+	term := NewTerminal(10, 5, WithResponseDelay(50*time.Millisecond))
+	term.Write([]byte("\x1b[6n")) //nolint:errcheck
+	term.SendText("a")
+
+	// The key is read before the delayed cursor position report.
+	if got := readAll(t, term); got != "a" {
+		t.Errorf("Read() = %q, want %q", got, "a")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if got, want := readAll(t, term), "\x1b[1;1R"; got != want {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+}
+
+func TestReadChunkSize(t *testing.T) {
+	term := NewTerminal(10, 5, WithReadChunkSize(3))
+	term.Write([]byte("\x1b[c")) //nolint:errcheck
+
+	want := []string{"\x1b[?", "62;", "1;6", ";22", "c"}
+	buf := make([]byte, 64)
+	for _, w := range want {
+		n, err := term.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf[:n]); got != w {
+			t.Errorf("Read() = %q, want %q", got, w)
+		}
+	}
+}
+
+func TestFlowControl(t *testing.T) {
+	term := NewTerminal(10, 5, WithFlowControl(4))
+
+	// Writes that fit in the buffer don't need flow control.
+	term.Write([]byte("abcd")) //nolint:errcheck
+	if got := readAll(t, term); got != "" {
+		t.Errorf("Read() = %q, want nothing", got)
+	}
+
+	term.Write([]byte("efghijkl")) //nolint:errcheck
+	if got, want := readAll(t, term), "\x13\x11"; got != want {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+	// All the output is processed.
+	if got, want := termText(term)[1], "kl        "; got != want {
+		t.Errorf("line 1 = %q, want %q", got, want)
+	}
+
+	// The application pauses the terminal input with XOFF.
+	term.Write([]byte("\x13")) //nolint:errcheck
+	term.SendText("x")
+	if got := readAll(t, term); got != "" {
+		t.Errorf("Read() = %q while paused, want nothing", got)
+	}
+	term.Write([]byte("\x11")) //nolint:errcheck
+	if got := readAll(t, term); got != "x" {
+		t.Errorf("Read() = %q, want %q", got, "x")
+	}
+}
+
+func TestResponseRouter(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	route := func(id, resp string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, id+":"+resp)
+	}
+	a := NewTerminal(10, 5, WithResponseRouter("a", route))
+	b := NewTerminal(10, 5, WithResponseRouter("b", route), WithResponseDelay(20*time.Millisecond))
+
+	b.Write([]byte("\x1b[6n")) //nolint:errcheck
+	a.Write([]byte("\x1b[c"))  //nolint:errcheck
+	a.SendText("x")
+
+	// Input isn't routed.
+	if s := readAll(t, a); s != "x" {
+		t.Errorf("Read() = %q, want %q", s, "x")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"a:\x1b[?62;1;6;22c", "b:\x1b[1;1R"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("routed = %q, want %q", got, want)
+	}
+}
+
+func TestTerminal8BitControls(t *testing.T) {
+	term := NewTerminal(10, 5)
+	term.Write([]byte(ansi.S8C1T + ansi.RequestCursorPositionReport + ansi.RequestPrimaryDeviceAttributes)) //nolint:errcheck
+	if got, want := readAll(t, term), "\x9b1;1R\x9b?62;1;6;22c"; got != want {
+		t.Errorf("8-bit responses = %q, want %q", got, want)
+	}
+
+	term.Write([]byte(ansi.S7C1T + ansi.RequestCursorPositionReport)) //nolint:errcheck
+	if got, want := readAll(t, term), "\x1b[1;1R"; got != want {
+		t.Errorf("7-bit responses = %q, want %q", got, want)
+	}
+}
@@ -17,6 +17,9 @@ func (t *Terminal) Blur() {
 }
 
 func (t *Terminal) focus(focus bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if mode, ok := t.modes[ansi.FocusEventMode]; ok && mode.IsSet() {
 		if focus {
 			t.buf.WriteString(ansi.Focus)
@@ -1,6 +1,8 @@
 package vt
 
 import (
+	"strconv"
+
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/cellbuf"
 )
@@ -153,12 +155,30 @@ func (t *Terminal) registerDefaultOscHandlers() {
 		1, // Set icon name
 		2, // Set window title
 	} {
+		cmd := cmd
 		t.RegisterOscHandler(cmd, func(data []byte) bool {
 			t.handleTitle(cmd, data)
 			return true
 		})
 	}
 
+	t.RegisterOscHandler(8, func(data []byte) bool {
+		// Hyperlink [ansi.SetHyperlink]
+		t.handleHyperlink(data)
+		return true
+	})
+
+	for _, cmd := range []int{
+		4,   // Set/Query palette colors
+		104, // Reset palette colors
+	} {
+		cmd := cmd
+		t.RegisterOscHandler(cmd, func(data []byte) bool {
+			t.handlePaletteColor(cmd, data)
+			return true
+		})
+	}
+
 	for _, cmd := range []int{
 		10,  // Set/Query foreground color
 		11,  // Set/Query background color
@@ -167,6 +187,7 @@ func (t *Terminal) registerDefaultOscHandlers() {
 		111, // Reset background color
 		112, // Reset cursor color
 	} {
+		cmd := cmd
 		t.RegisterOscHandler(cmd, func(data []byte) bool {
 			t.handleDefaultColor(cmd, data)
 			return true
@@ -200,38 +221,52 @@ func (t *Terminal) registerDefaultEscHandlers() {
 		return true
 	})
 
-	for _, cmd := range []int{
-		ansi.Command(0, '(', 'A'), // UK G0
-		ansi.Command(0, ')', 'A'), // UK G1
-		ansi.Command(0, '*', 'A'), // UK G2
-		ansi.Command(0, '+', 'A'), // UK G3
-		ansi.Command(0, '(', 'B'), // USASCII G0
-		ansi.Command(0, ')', 'B'), // USASCII G1
-		ansi.Command(0, '*', 'B'), // USASCII G2
-		ansi.Command(0, '+', 'B'), // USASCII G3
-		ansi.Command(0, '(', '0'), // Special G0
-		ansi.Command(0, ')', '0'), // Special G1
-		ansi.Command(0, '*', '0'), // Special G2
-		ansi.Command(0, '+', '0'), // Special G3
-	} {
-		t.RegisterEscHandler(cmd, func() bool {
-			// Select Character Set [ansi.SCS]
-			c := ansi.Cmd(cmd)
-			set := c.Intermediate() - '('
-			switch c.Final() {
-			case 'A': // UK Character Set
-				t.charsets[set] = UK
-			case 'B': // USASCII Character Set
-				t.charsets[set] = nil // USASCII is the default
-			case '0': // Special Drawing Character Set
-				t.charsets[set] = SpecialDrawing
-			default:
-				return false
-			}
-			return true
-		})
+	for _, inter := range []byte{'(', ')', '*', '+'} { // G0, G1, G2, and G3
+		finals := []byte{'A', 'B', '0'} // UK, USASCII, and Special Drawing
+		for final := range nrcsCharSets {
+			finals = append(finals, final)
+		}
+		for _, final := range finals {
+			cmd := ansi.Command(0, inter, final)
+			t.RegisterEscHandler(cmd, func() bool {
+				// Select Character Set [ansi.SCS]
+				c := ansi.Cmd(cmd)
+				set := c.Intermediate() - '('
+				switch c.Final() {
+				case 'A': // UK Character Set
+					t.charsets[set] = UK
+				case 'B': // USASCII Character Set
+					t.charsets[set] = nil // USASCII is the default
+				case '0': // Special Drawing Character Set
+					t.charsets[set] = SpecialDrawing
+				default:
+					// National Replacement Character Sets are ignored unless
+					// [ansi.DECNRCM] is set.
+					charset, ok := nrcsCharSets[c.Final()]
+					if !ok {
+						return false
+					}
+					if t.isModeSet(ansi.NationalReplacementCharsetMode) {
+						t.charsets[set] = charset
+					}
+				}
+				return true
+			})
+		}
 	}
 
+	t.RegisterEscHandler(ansi.Command(0, ' ', 'F'), func() bool {
+		// Send 7-bit C1 Controls [ansi.S7C1T]
+		t.c1Bits8 = false
+		return true
+	})
+
+	t.RegisterEscHandler(ansi.Command(0, ' ', 'G'), func() bool {
+		// Send 8-bit C1 Controls [ansi.S8C1T]
+		t.c1Bits8 = true
+		return true
+	})
+
 	t.RegisterEscHandler('D', func() bool {
 		// Index [ansi.IND]
 		t.index()
@@ -382,10 +417,12 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 			rect := cellbuf.Rect(0, 0, width, y+1)
 			t.scr.Fill(t.scr.blankCell(), rect)
 		case 2: // erase screen
-			fallthrough
-		case 3: // erase display
-			// TODO: Scrollback buffer support?
 			t.scr.Clear()
+		case 3: // erase display and saved lines
+			t.scr.Clear()
+			if sb := t.Scrollback(); sb != nil {
+				sb.Clear()
+			}
 		default:
 			return false
 		}
@@ -415,6 +452,80 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 		return true
 	})
 
+	t.RegisterCsiHandler(ansi.Command('?', 0, 'J'), func(params ansi.Params) bool {
+		// Selective Erase in Display [ansi.DECSED]
+		n, _, _ := params.Param(0, 0)
+		width, height := t.Width(), t.Height()
+		x, y := t.scr.CursorPosition()
+		switch n {
+		case 0: // Erase screen below (from after cursor position)
+			rect1 := cellbuf.Rect(x, y, width, 1)            // cursor to end of line
+			rect2 := cellbuf.Rect(0, y+1, width, height-y-1) // next line onwards
+			t.scr.Erase(t.scr.blankCell(), rect1, rect2)
+		case 1: // Erase screen above (including cursor)
+			rect := cellbuf.Rect(0, 0, width, y+1)
+			t.scr.Erase(t.scr.blankCell(), rect)
+		case 2: // Erase screen
+			t.scr.Erase(t.scr.blankCell(), t.scr.Bounds())
+		default:
+			return false
+		}
+		return true
+	})
+
+	t.RegisterCsiHandler(ansi.Command('?', 0, 'K'), func(params ansi.Params) bool {
+		// Selective Erase in Line [ansi.DECSEL]
+		n, _, _ := params.Param(0, 0)
+		x, y := t.scr.CursorPosition()
+		w := t.scr.Width()
+
+		switch n {
+		case 0: // Erase from cursor to end of line
+			t.scr.Erase(t.scr.blankCell(), cellbuf.Rect(x, y, w-x, 1))
+			t.atPhantom = false
+		case 1: // Erase from start of line to cursor
+			t.scr.Erase(t.scr.blankCell(), cellbuf.Rect(0, y, x+1, 1))
+		case 2: // Erase entire line
+			t.scr.Erase(t.scr.blankCell(), cellbuf.Rect(0, y, w, 1))
+		default:
+			return false
+		}
+		return true
+	})
+
+	t.RegisterCsiHandler('i', func(params ansi.Params) bool {
+		// Media Copy (MC)
+		n, _, _ := params.Param(0, 0)
+		switch n {
+		case 0: // Print screen
+			t.printLines(0, t.Height())
+		case 4: // Turn off printer controller mode
+			t.setPrinterController(false)
+		case 5: // Turn on printer controller mode
+			t.setPrinterController(true)
+		default:
+			return false
+		}
+		return true
+	})
+
+	t.RegisterCsiHandler(ansi.Command('?', 0, 'i'), func(params ansi.Params) bool {
+		// Media Copy DEC (MC)
+		n, _, _ := params.Param(0, 0)
+		switch n {
+		case 1: // Print cursor line
+			_, y := t.scr.CursorPosition()
+			t.printLines(y, y+1)
+		case 4, 5: // Turn off and on auto print mode
+			// We don't support auto print mode, ignore it.
+		case 10, 11: // Print composed display and all pages
+			t.printLines(0, t.Height())
+		default:
+			return false
+		}
+		return true
+	})
+
 	t.RegisterCsiHandler('L', func(params ansi.Params) bool {
 		// Insert Line [ansi.IL]
 		n, _, _ := params.Param(0, 1)
@@ -514,7 +625,7 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 		}
 
 		// Do we fully support VT220?
-		t.buf.WriteString(ansi.PrimaryDeviceAttributes(
+		t.respond(ansi.PrimaryDeviceAttributes(
 			62, // VT220
 			1,  // 132 columns
 			6,  // Selective Erase
@@ -531,7 +642,7 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 		}
 
 		// Do we fully support VT220?
-		t.buf.WriteString(ansi.SecondaryDeviceAttributes(
+		t.respond(ansi.SecondaryDeviceAttributes(
 			1,  // VT220
 			10, // Version 1.0
 			0,  // ROM Cartridge is always zero
@@ -625,10 +736,10 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 		case 5: // Operating Status
 			// We're always ready ;)
 			// See: https://vt100.net/docs/vt510-rm/DSR-OS.html
-			t.buf.WriteString(ansi.DeviceStatusReport(ansi.DECStatusReport(0)))
+			t.respond(ansi.DeviceStatusReport(ansi.DECStatusReport(0)))
 		case 6: // Cursor Position Report [ansi.CPR]
 			x, y := t.scr.CursorPosition()
-			t.buf.WriteString(ansi.CursorPositionReport(x+1, y+1))
+			t.respond(ansi.CursorPositionReport(x+1, y+1))
 		default:
 			return false
 		}
@@ -636,6 +747,28 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 		return true
 	})
 
+	t.RegisterCsiHandler('t', func(params ansi.Params) bool {
+		// Window Manipulation [ansi.XTWINOPS]
+		n, _, _ := params.Param(0, 0)
+		switch n {
+		case ansi.RequestWindowSizeWinOp:
+			if t.cellWidth <= 0 || t.cellHeight <= 0 {
+				return false
+			}
+			t.respond(ansi.WindowOp(4, t.Height()*t.cellHeight, t.Width()*t.cellWidth))
+		case ansi.RequestCellSizeWinOp:
+			if t.cellWidth <= 0 || t.cellHeight <= 0 {
+				return false
+			}
+			t.respond(ansi.WindowOp(6, t.cellHeight, t.cellWidth))
+		case ansi.RequestTextAreaSizeWinOp:
+			t.respond(ansi.WindowOp(8, t.Height(), t.Width()))
+		default:
+			return false
+		}
+		return true
+	})
+
 	t.RegisterCsiHandler(ansi.Command('?', 0, 'n'), func(params ansi.Params) bool {
 		n, _, ok := params.Param(0, 1)
 		if !ok || n == 0 {
@@ -645,7 +778,7 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 		switch n {
 		case 6: // Extended Cursor Position Report [ansi.DECXCPR]
 			x, y := t.scr.CursorPosition()
-			t.buf.WriteString(ansi.ExtendedCursorPositionReport(x+1, y+1, 0)) // We don't support page numbers
+			t.respond(ansi.ExtendedCursorPositionReport(x+1, y+1, 0)) // We don't support page numbers
 		default:
 			return false
 		}
@@ -667,11 +800,29 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 
 	t.RegisterCsiHandler(ansi.Command(0, ' ', 'q'), func(params ansi.Params) bool {
 		// Set Cursor Style [ansi.DECSCUSR]
-		style := 1
-		if param, _, ok := params.Param(0, 0); ok && param > style {
-			style = param
+		param, _, _ := params.Param(0, 0)
+		style := ansi.CursorStyle(param)
+		blink := style.Blinking()
+		t.scr.setCursorStyle(CursorStyle(style.Shape()), blink)
+		if blink {
+			t.modes[ansi.CursorBlinkMode] = ansi.ModeSet
+		} else {
+			t.modes[ansi.CursorBlinkMode] = ansi.ModeReset
+		}
+		return true
+	})
+
+	t.RegisterCsiHandler(ansi.Command(0, '"', 'q'), func(params ansi.Params) bool {
+		// Select Character Protection Attribute [ansi.DECSCA]
+		n, _, _ := params.Param(0, 0)
+		switch n {
+		case 0, 2:
+			t.scr.setCursorProtected(false)
+		case 1:
+			t.scr.setCursorProtected(true)
+		default:
+			return false
 		}
-		t.scr.setCursorStyle(CursorStyle((style/2)+1), style%2 == 1)
 		return true
 	})
 
@@ -737,4 +888,35 @@ func (t *Terminal) registerDefaultCsiHandlers() {
 
 		return true
 	})
+
+	t.RegisterCsiHandler(ansi.Command('?', 0, 'u'), func(params ansi.Params) bool {
+		// Request Kitty Keyboard [ansi.RequestKittyKeyboard]
+		t.respond("\x1b[?" + strconv.Itoa(t.KittyKeyboardFlags()) + "u")
+		return true
+	})
+
+	t.RegisterCsiHandler(ansi.Command('=', 0, 'u'), func(params ansi.Params) bool {
+		// Set Kitty Keyboard [ansi.KittyKeyboard]
+		flags, _, _ := params.Param(0, 0)
+		mode, _, _ := params.Param(1, 1)
+		t.setKittyKeyboard(flags, mode)
+		return true
+	})
+
+	t.RegisterCsiHandler(ansi.Command('>', 0, 'u'), func(params ansi.Params) bool {
+		// Push Kitty Keyboard [ansi.PushKittyKeyboard]
+		flags, _, _ := params.Param(0, 0)
+		t.pushKittyKeyboard(flags)
+		return true
+	})
+
+	t.RegisterCsiHandler(ansi.Command('<', 0, 'u'), func(params ansi.Params) bool {
+		// Pop Kitty Keyboard [ansi.PopKittyKeyboard]
+		n, _, _ := params.Param(0, 1)
+		if n < 1 {
+			n = 1
+		}
+		t.popKittyKeyboard(n)
+		return true
+	})
 }
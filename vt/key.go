@@ -23,8 +23,22 @@ type Key struct {
 	Mod  KeyMod
 }
 
-// SendKey returns the default key map.
+// SendKey sends a key press to the terminal. Keys are encoded using the Kitty
+// keyboard protocol when the application enabled it, otherwise they use their
+// legacy encoding.
 func (t *Terminal) SendKey(k Key) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sendKey(k)
+}
+
+func (t *Terminal) sendKey(k Key) {
+	if seq, ok := kittyKey(k, t.KittyKeyboardFlags()); ok {
+		t.buf.WriteString(seq) //nolint:errcheck
+		return
+	}
+
 	var seq string
 
 	ack := t.isModeSet(ansi.CursorKeysMode)    // Application cursor keys mode
@@ -268,6 +282,9 @@ func (t *Terminal) SendKey(k Key) {
 
 	case Key{Code: KeyTab, Mod: ModShift}:
 		seq = "\x1b[Z"
+
+	default:
+		seq = keyText(k)
 	}
 
 	if k.Mod&ModAlt != 0 {
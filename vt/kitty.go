@@ -0,0 +1,296 @@
+package vt
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// kittyStackSize is the maximum number of entries in a Kitty keyboard flags
+// stack. When full, the oldest entries are discarded.
+const kittyStackSize = 16
+
+// kittyKeyboard holds the Kitty keyboard protocol progressive enhancement
+// flags of a screen.
+//
+// See: https://sw.kovidgoyal.net/kitty/keyboard-protocol/#progressive-enhancement
+type kittyKeyboard struct {
+	flags int
+	stack []int
+}
+
+// kittyKeyboard returns the Kitty keyboard state of the active screen. The
+// main and alternate screens each have their own state.
+func (t *Terminal) kittyKeyboard() *kittyKeyboard {
+	if t.scr == &t.scrs[1] {
+		return &t.kitty[1]
+	}
+	return &t.kitty[0]
+}
+
+// KittyKeyboardFlags returns the Kitty keyboard protocol flags enabled on the
+// active screen.
+func (t *Terminal) KittyKeyboardFlags() int {
+	return t.kittyKeyboard().flags
+}
+
+// setKittyKeyboard sets the flags of the active screen. Mode 1 replaces the
+// flags, mode 2 sets the given flags, and mode 3 resets the given flags.
+func (t *Terminal) setKittyKeyboard(flags, mode int) {
+	k := t.kittyKeyboard()
+	flags &= ansi.KittyAllFlags
+	switch mode {
+	case 1:
+		k.flags = flags
+	case 2:
+		k.flags |= flags
+	case 3:
+		k.flags &^= flags
+	}
+}
+
+// pushKittyKeyboard saves the flags of the active screen and replaces them.
+func (t *Terminal) pushKittyKeyboard(flags int) {
+	k := t.kittyKeyboard()
+	if len(k.stack) >= kittyStackSize {
+		k.stack = k.stack[1:]
+	}
+	k.stack = append(k.stack, k.flags)
+	k.flags = flags & ansi.KittyAllFlags
+}
+
+// popKittyKeyboard restores the flags of the active screen saved by n pushes.
+// Popping more entries than the stack has resets the flags.
+func (t *Terminal) popKittyKeyboard(n int) {
+	k := t.kittyKeyboard()
+	if n >= len(k.stack) {
+		k.stack = k.stack[:0]
+		k.flags = 0
+		return
+	}
+	k.flags = k.stack[len(k.stack)-n]
+	k.stack = k.stack[:len(k.stack)-n]
+}
+
+// kittyLegacyKeys are the functional keys that keep their legacy number and
+// final byte in the Kitty keyboard protocol.
+var kittyLegacyKeys = map[rune]struct {
+	num   int
+	final byte
+}{
+	KeyUp:     {1, 'A'},
+	KeyDown:   {1, 'B'},
+	KeyRight:  {1, 'C'},
+	KeyLeft:   {1, 'D'},
+	KeyBegin:  {1, 'E'},
+	KeyHome:   {1, 'H'},
+	KeyEnd:    {1, 'F'},
+	KeyInsert: {2, '~'},
+	KeyDelete: {3, '~'},
+	KeyPgUp:   {5, '~'},
+	KeyPgDown: {6, '~'},
+	KeyF1:     {1, 'P'},
+	KeyF2:     {1, 'Q'},
+	KeyF3:     {13, '~'},
+	KeyF4:     {1, 'S'},
+	KeyF5:     {15, '~'},
+	KeyF6:     {17, '~'},
+	KeyF7:     {18, '~'},
+	KeyF8:     {19, '~'},
+	KeyF9:     {20, '~'},
+	KeyF10:    {21, '~'},
+	KeyF11:    {23, '~'},
+	KeyF12:    {24, '~'},
+}
+
+// Kitty keyboard protocol key code ranges.
+const (
+	kittyKeypadFirst   = 57399 // KP_0
+	kittyKeypadLast    = 57427 // KP_BEGIN
+	kittyModifierFirst = 57441 // LEFT_SHIFT
+)
+
+// kittyKeyCodes are the key codes of the keys reported as "CSI code u" in the
+// Kitty keyboard protocol.
+var kittyKeyCodes = map[rune]int{
+	KeyEscape:    27,
+	KeyEnter:     13,
+	KeyTab:       9,
+	KeyBackspace: 127,
+
+	KeyCapsLock:    57358,
+	KeyScrollLock:  57359,
+	KeyNumLock:     57360,
+	KeyPrintScreen: 57361,
+	KeyPause:       57362,
+	KeyMenu:        57363,
+
+	KeyKp0:        57399,
+	KeyKp1:        57400,
+	KeyKp2:        57401,
+	KeyKp3:        57402,
+	KeyKp4:        57403,
+	KeyKp5:        57404,
+	KeyKp6:        57405,
+	KeyKp7:        57406,
+	KeyKp8:        57407,
+	KeyKp9:        57408,
+	KeyKpDecimal:  57409,
+	KeyKpDivide:   57410,
+	KeyKpMultiply: 57411,
+	KeyKpMinus:    57412,
+	KeyKpPlus:     57413,
+	KeyKpEnter:    57414,
+	KeyKpEqual:    57415,
+	KeyKpSep:      57416,
+	KeyKpComma:    57416,
+	KeyKpLeft:     57417,
+	KeyKpRight:    57418,
+	KeyKpUp:       57419,
+	KeyKpDown:     57420,
+	KeyKpPgUp:     57421,
+	KeyKpPgDown:   57422,
+	KeyKpHome:     57423,
+	KeyKpEnd:      57424,
+	KeyKpInsert:   57425,
+	KeyKpDelete:   57426,
+	KeyKpBegin:    57427,
+
+	KeyMediaPlay:        57428,
+	KeyMediaPause:       57429,
+	KeyMediaPlayPause:   57430,
+	KeyMediaReverse:     57431,
+	KeyMediaStop:        57432,
+	KeyMediaFastForward: 57433,
+	KeyMediaRewind:      57434,
+	KeyMediaNext:        57435,
+	KeyMediaPrev:        57436,
+	KeyMediaRecord:      57437,
+	KeyLowerVol:         57438,
+	KeyRaiseVol:         57439,
+	KeyMute:             57440,
+
+	KeyLeftShift:      57441,
+	KeyLeftCtrl:       57442,
+	KeyLeftAlt:        57443,
+	KeyLeftSuper:      57444,
+	KeyLeftHyper:      57445,
+	KeyLeftMeta:       57446,
+	KeyRightShift:     57447,
+	KeyRightCtrl:      57448,
+	KeyRightAlt:       57449,
+	KeyRightSuper:     57450,
+	KeyRightHyper:     57451,
+	KeyRightMeta:      57452,
+	KeyIsoLevel3Shift: 57453,
+	KeyIsoLevel5Shift: 57454,
+}
+
+// kittyModifiers returns the Kitty keyboard protocol modifiers bitmask.
+func kittyModifiers(m KeyMod) int {
+	var mods int
+	if m&ModShift != 0 {
+		mods |= 1
+	}
+	if m&ModAlt != 0 {
+		mods |= 2
+	}
+	if m&ModCtrl != 0 {
+		mods |= 4
+	}
+	if m&ModMeta != 0 {
+		mods |= 32
+	}
+	return mods
+}
+
+// keyText returns the text a key press enters, or an empty string if the key
+// doesn't enter text.
+func keyText(k Key) string {
+	if k.Code >= KeyExtended || !unicode.IsPrint(k.Code) || k.Mod&(ModCtrl|ModMeta) != 0 {
+		return ""
+	}
+	if k.Mod&ModShift != 0 {
+		return string(unicode.ToUpper(k.Code))
+	}
+	return string(k.Code)
+}
+
+// kittyKey returns the Kitty keyboard protocol encoding of a key press for
+// the given flags. It returns false when the key keeps its legacy encoding.
+func kittyKey(k Key, flags int) (string, bool) {
+	disambiguate := flags&ansi.KittyDisambiguateEscapeCodes != 0
+	allKeys := flags&ansi.KittyReportAllKeysAsEscapeCodes != 0
+	if !disambiguate && !allKeys {
+		return "", false
+	}
+
+	mods := kittyModifiers(k.Mod)
+	if lk, ok := kittyLegacyKeys[k.Code]; ok {
+		if mods == 0 && !allKeys {
+			return "", false
+		}
+		var b strings.Builder
+		b.WriteString("\x1b[")
+		if lk.num != 1 || mods != 0 {
+			b.WriteString(strconv.Itoa(lk.num))
+		}
+		if mods != 0 {
+			b.WriteByte(';')
+			b.WriteString(strconv.Itoa(mods + 1))
+		}
+		b.WriteByte(lk.final)
+		return b.String(), true
+	}
+
+	code, ok := kittyKeyCodes[k.Code]
+	var text string
+	if !ok {
+		if k.Code >= KeyExtended {
+			return "", false
+		}
+		// Text keys are reported using their unshifted code point.
+		code = int(unicode.ToLower(k.Code))
+		if k.Mod&ModAlt == 0 {
+			text = keyText(k)
+		}
+	}
+
+	switch {
+	case allKeys:
+	case code >= kittyModifierFirst:
+		// Modifier keys are only reported with all keys as escape codes.
+		return "", false
+	case k.Code == KeyEscape:
+	case k.Mod&^ModShift != 0:
+	case mods != 0 && text == "":
+	case code > 0xe000 && (code < kittyKeypadFirst || code > kittyKeypadLast):
+		// Keys without a legacy encoding.
+	default:
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[")
+	b.WriteString(strconv.Itoa(code))
+	withText := allKeys && flags&ansi.KittyReportAssociatedKeys != 0 && text != ""
+	if mods != 0 || withText {
+		b.WriteByte(';')
+		if mods != 0 {
+			b.WriteString(strconv.Itoa(mods + 1))
+		}
+	}
+	if withText {
+		b.WriteByte(';')
+		for i, r := range text {
+			if i > 0 {
+				b.WriteByte(':')
+			}
+			b.WriteString(strconv.Itoa(int(r)))
+		}
+	}
+	b.WriteByte('u')
+	return b.String(), true
+}
@@ -0,0 +1,85 @@
+package vt
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestKittyKeyboardStack(t *testing.T) {
+	term := NewTerminal(10, 5)
+	term.Write([]byte(ansi.PushKittyKeyboard(1)))      //nolint:errcheck
+	term.Write([]byte(ansi.PushKittyKeyboard(8 | 16))) //nolint:errcheck
+	term.Write([]byte(ansi.KittyKeyboard(1, 2)))       //nolint:errcheck
+	term.Write([]byte(ansi.RequestKittyKeyboard))      //nolint:errcheck
+	if got, want := readAll(t, term), "\x1b[?25u"; got != want {
+		t.Errorf("flags = %q, want %q", got, want)
+	}
+
+	// The alternate screen has its own flags.
+	term.Write([]byte(ansi.SetAltScreenMode)) //nolint:errcheck
+	if got := term.KittyKeyboardFlags(); got != 0 {
+		t.Errorf("alt screen flags = %d, want 0", got)
+	}
+	term.Write([]byte(ansi.ResetAltScreenMode)) //nolint:errcheck
+
+	term.Write([]byte(ansi.KittyKeyboard(16, 3))) //nolint:errcheck
+	if got := term.KittyKeyboardFlags(); got != 9 {
+		t.Errorf("flags = %d, want 9", got)
+	}
+	term.Write([]byte(ansi.PopKittyKeyboard(1))) //nolint:errcheck
+	if got := term.KittyKeyboardFlags(); got != 1 {
+		t.Errorf("flags after pop = %d, want 1", got)
+	}
+	term.Write([]byte(ansi.PopKittyKeyboard(5))) //nolint:errcheck
+	if got := term.KittyKeyboardFlags(); got != 0 {
+		t.Errorf("flags after popping all = %d, want 0", got)
+	}
+}
+
+func TestKittyKeyboardSendKey(t *testing.T) {
+	cases := []struct {
+		name  string
+		flags int
+		key   Key
+		want  string
+	}{
+		{"legacy text", 0, Key{Code: 'a'}, "a"},
+		{"legacy shifted text", 0, Key{Code: 'a', Mod: ModShift}, "A"},
+		{"legacy alt text", 0, Key{Code: 'a', Mod: ModAlt}, "\x1ba"},
+		{"disambiguate text", 1, Key{Code: 'a'}, "a"},
+		{"disambiguate shifted text", 1, Key{Code: 'a', Mod: ModShift}, "A"},
+		{"disambiguate ctrl", 1, Key{Code: 'c', Mod: ModCtrl}, "\x1b[99;5u"},
+		{"disambiguate alt", 1, Key{Code: 'a', Mod: ModAlt}, "\x1b[97;3u"},
+		{"disambiguate escape", 1, Key{Code: KeyEscape}, "\x1b[27u"},
+		{"disambiguate enter", 1, Key{Code: KeyEnter}, "\r"},
+		{"disambiguate shift enter", 1, Key{Code: KeyEnter, Mod: ModShift}, "\x1b[13;2u"},
+		{"disambiguate up", 1, Key{Code: KeyUp}, "\x1b[A"},
+		{"disambiguate ctrl up", 1, Key{Code: KeyUp, Mod: ModCtrl}, "\x1b[1;5A"},
+		{"disambiguate ctrl delete", 1, Key{Code: KeyDelete, Mod: ModCtrl}, "\x1b[3;5~"},
+		{"disambiguate left shift", 1, Key{Code: KeyLeftShift}, ""},
+		{"all keys text", 8, Key{Code: 'a'}, "\x1b[97u"},
+		{"all keys shifted text", 8, Key{Code: 'a', Mod: ModShift}, "\x1b[97;2u"},
+		{"all keys enter", 8, Key{Code: KeyEnter}, "\x1b[13u"},
+		{"all keys backspace", 8, Key{Code: KeyBackspace}, "\x1b[127u"},
+		{"all keys f1", 8, Key{Code: KeyF1}, "\x1b[P"},
+		{"all keys f3", 8, Key{Code: KeyF3}, "\x1b[13~"},
+		{"all keys keypad", 8, Key{Code: KeyKp1}, "\x1b[57400u"},
+		{"all keys left shift", 8, Key{Code: KeyLeftShift, Mod: ModShift}, "\x1b[57441;2u"},
+		{"associated text", 8 | 16, Key{Code: 'a'}, "\x1b[97;;97u"},
+		{"associated shifted text", 8 | 16, Key{Code: 'a', Mod: ModShift}, "\x1b[97;2;65u"},
+		{"associated ctrl text", 8 | 16, Key{Code: 'a', Mod: ModCtrl}, "\x1b[97;5u"},
+		{"associated text without all keys", 1 | 16, Key{Code: 'a'}, "a"},
+		{"associated enter", 8 | 16, Key{Code: KeyEnter}, "\x1b[13u"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTerminal(10, 5)
+			term.Write([]byte(ansi.PushKittyKeyboard(tc.flags))) //nolint:errcheck
+			term.SendKey(tc.key)
+			if got := readAll(t, term); got != tc.want {
+				t.Errorf("SendKey(%v) = %q, want %q", tc.key, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package vt
+
+// Metrics are optional callbacks that report what the terminal processes.
+// Embedders can use them to export counters, for example to Prometheus, and
+// to spot the sequences an application uses that the terminal doesn't
+// support. The callbacks are called while the terminal is locked and must not
+// call the terminal's methods.
+type Metrics struct {
+	// BytesProcessed is called after each write with the number of bytes
+	// processed.
+	BytesProcessed func(n int)
+
+	// Sequence is called for each escape sequence the terminal parses. The
+	// kind is the sequence type, one of "ESC", "CSI", "OSC", "DCS", and
+	// "APC", and handled reports whether the terminal supports it.
+	Sequence func(kind string, handled bool)
+}
+
+// WithMetrics returns an [Option] that sets the terminal's metrics
+// callbacks.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithMetrics(vt.Metrics{
+//		Sequence: func(kind string, handled bool) {
+//			sequences.WithLabelValues(kind, strconv.FormatBool(handled)).Inc()
+//		},
+//	}))
+func WithMetrics(m Metrics) Option {
+	return func(t *Terminal) {
+		t.metrics = m
+	}
+}
+
+// countSequence reports a parsed escape sequence to the metrics callbacks.
+func (t *Terminal) countSequence(kind string, handled bool) {
+	if t.metrics.Sequence != nil {
+		t.metrics.Sequence(kind, handled)
+	}
+}
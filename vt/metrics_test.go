@@ -0,0 +1,37 @@
+package vt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	var bytes int
+	seqs := map[string]int{}
+	term := NewTerminal(10, 5, WithMetrics(Metrics{
+		BytesProcessed: func(n int) { bytes += n },
+		Sequence: func(kind string, handled bool) {
+			if !handled {
+				kind = "unhandled " + kind
+			}
+			seqs[kind]++
+		},
+	}))
+
+	term.Write([]byte("a\x1b[1mb\x1b[0m")) //nolint:errcheck
+	term.Write([]byte("\x1b]2;title\a"))   //nolint:errcheck
+	term.Write([]byte("\x1b[99z\x1b7"))    //nolint:errcheck
+
+	if want := 27; bytes != want {
+		t.Errorf("bytes processed = %d, want %d", bytes, want)
+	}
+	want := map[string]int{
+		"CSI":           2,
+		"OSC":           1,
+		"ESC":           1,
+		"unhandled CSI": 1,
+	}
+	if !reflect.DeepEqual(seqs, want) {
+		t.Errorf("sequences = %v, want %v", seqs, want)
+	}
+}
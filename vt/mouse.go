@@ -91,6 +91,9 @@ func (m MouseMotion) Mouse() mouse {
 // TODO: Support [Utf8ExtMouseMode], [UrxvtExtMouseMode], and
 // [SgrPixelExtMouseMode].
 func (t *Terminal) SendMouse(m Mouse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	var (
 		enc  ansi.Mode
 		mode ansi.Mode
@@ -1,5 +1,11 @@
 package vt
 
+import (
+	"image/color"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
 // Logger represents a logger interface.
 type Logger interface {
 	Printf(format string, v ...interface{})
@@ -21,6 +27,107 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithScrollback returns an [Option] that sets the scrollback of the main
+// screen. Lines scrolled off the top of the main screen are added to it.
+// The alternate screen has no scrollback.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithScrollback(vt.NewScrollback(100000, true)))
+func WithScrollback(sb *Scrollback) Option {
+	return func(t *Terminal) {
+		t.scrs[0].sb = sb
+	}
+}
+
+// WithDefaultColors returns an [Option] that sets the terminal's default
+// foreground, background, and cursor colors. The colors set by the
+// application with OSC 10, 11, and 12 override them until they're reset with
+// OSC 110, 111, and 112. A nil color keeps the built-in default.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithDefaultColors(fg, bg, nil))
+func WithDefaultColors(fg, bg, cur color.Color) Option {
+	return func(t *Terminal) {
+		if fg != nil {
+			t.defFg = fg
+		}
+		if bg != nil {
+			t.defBg = bg
+		}
+		if cur != nil {
+			t.defCur = cur
+		}
+	}
+}
+
+// WithPalette returns an [Option] that sets the first colors of the
+// terminal's 256-color palette, used to resolve indexed colors. Nil colors,
+// and the colors past the end of p, keep the standard xterm colors. The
+// colors set by the application with OSC 4 override the palette until
+// they're reset with OSC 104.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithPalette(solarized[:16]))
+func WithPalette(p []color.Color) Option {
+	return func(t *Terminal) {
+		copy(t.palette[:], p)
+	}
+}
+
+// WithModes returns an [Option] that sets the initial settings of the given
+// terminal modes, overriding their defaults. The modes are restored to these
+// settings when the terminal is reset with [ansi.RIS].
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithModes(map[ansi.Mode]ansi.ModeSetting{
+//		ansi.AutoWrapMode:       ansi.ModeReset,
+//		ansi.BracketedPasteMode: ansi.ModeSet,
+//	}))
+func WithModes(modes map[ansi.Mode]ansi.ModeSetting) Option {
+	return func(t *Terminal) {
+		if t.initModes == nil {
+			t.initModes = make(map[ansi.Mode]ansi.ModeSetting, len(modes))
+		}
+		for mode, setting := range modes {
+			t.initModes[mode] = setting
+		}
+		t.resetModes()
+	}
+}
+
+// WithCellSize returns an [Option] that sets the size of a terminal cell in
+// pixels. The terminal uses it to answer the text area and cell size window
+// operations, [ansi.RequestTextAreaPixelSize] and [ansi.RequestCellSize],
+// which go unanswered by default.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithCellSize(10, 20))
+func WithCellSize(width, height int) Option {
+	return func(t *Terminal) {
+		t.cellWidth = width
+		t.cellHeight = height
+	}
+}
+
+// WithCallbacks returns an [Option] that sets the terminal's callbacks. It's
+// the same as setting [Terminal.Callbacks] after creating the terminal.
+//
+// Example:
+//
+//	vterm := vt.NewTerminal(80, 24, vt.WithCallbacks(vt.Callbacks{
+//		Bell: func() { log.Print("bell") },
+//	}))
+func WithCallbacks(cb Callbacks) Option {
+	return func(t *Terminal) {
+		t.Callbacks = cb
+	}
+}
+
 // logf logs a formatted message if the terminal has a logger.
 func (t *Terminal) logf(format string, v ...interface{}) {
 	if t.logger != nil {
@@ -0,0 +1,69 @@
+package vt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestWithModes(t *testing.T) {
+	term := NewTerminal(10, 5, WithModes(map[ansi.Mode]ansi.ModeSetting{
+		ansi.AutoWrapMode:       ansi.ModeReset,
+		ansi.BracketedPasteMode: ansi.ModeSet,
+	}))
+	check := func() {
+		t.Helper()
+		if term.isModeSet(ansi.AutoWrapMode) {
+			t.Errorf("auto wrap mode is set, want reset")
+		}
+		if !term.isModeSet(ansi.BracketedPasteMode) {
+			t.Errorf("bracketed paste mode is reset, want set")
+		}
+		if !term.isModeSet(ansi.TextCursorEnableMode) {
+			t.Errorf("text cursor enable mode is reset, want its default")
+		}
+	}
+	check()
+
+	// A full reset restores the configured modes.
+	term.Write([]byte(ansi.SetAutoWrapMode + ansi.ResetBracketedPasteMode + ansi.RIS)) //nolint:errcheck
+	check()
+}
+
+func TestWithCellSize(t *testing.T) {
+	term := NewTerminal(80, 24)
+	term.Write([]byte(ansi.RequestTextAreaSize + ansi.RequestCellSize + ansi.RequestTextAreaPixelSize)) //nolint:errcheck
+	if got, want := readAll(t, term), "\x1b[8;24;80t"; got != want {
+		t.Errorf("responses without cell size = %q, want %q", got, want)
+	}
+
+	term = NewTerminal(80, 24, WithCellSize(10, 20))
+	term.Write([]byte(ansi.RequestCellSize + ansi.RequestTextAreaPixelSize)) //nolint:errcheck
+	if got, want := readAll(t, term), "\x1b[6;20;10t\x1b[4;480;800t"; got != want {
+		t.Errorf("responses = %q, want %q", got, want)
+	}
+}
+
+func TestWithResponseWriter(t *testing.T) {
+	var out bytes.Buffer
+	term := NewTerminal(10, 5, WithResponseWriter(&out))
+	term.Write([]byte(ansi.RequestPrimaryDeviceAttributes)) //nolint:errcheck
+	if got, want := out.String(), "\x1b[?62;1;6;22c"; got != want {
+		t.Errorf("written responses = %q, want %q", got, want)
+	}
+	if got := readAll(t, term); got != "" {
+		t.Errorf("read responses = %q, want none", got)
+	}
+}
+
+func TestWithCallbacks(t *testing.T) {
+	var bells int
+	term := NewTerminal(10, 5, WithCallbacks(Callbacks{
+		Bell: func() { bells++ },
+	}))
+	term.Write([]byte("\a\a")) //nolint:errcheck
+	if bells != 2 {
+		t.Errorf("bells = %d, want 2", bells)
+	}
+}
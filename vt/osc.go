@@ -3,13 +3,17 @@ package vt
 import (
 	"bytes"
 	"image/color"
+	"strconv"
 
 	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
 )
 
 // handleOsc handles an OSC escape sequence.
 func (t *Terminal) handleOsc(cmd int, data []byte) {
-	if !t.handlers.handleOsc(cmd, data) {
+	handled := t.handlers.handleOsc(cmd, data)
+	t.countSequence("OSC", handled)
+	if !handled {
 		t.logf("unhandled sequence: OSC %q", data)
 	}
 }
@@ -45,6 +49,14 @@ func (t *Terminal) handleTitle(cmd int, data []byte) {
 	}
 }
 
+// handleHyperlink handles OSC 8 hyperlinks. The hyperlink applies to the
+// cells printed until it's reset with an empty URI.
+func (t *Terminal) handleHyperlink(data []byte) {
+	var link Link
+	cellbuf.ReadLink(data, &link)
+	t.scr.setCursorLink(link)
+}
+
 func (t *Terminal) handleDefaultColor(cmd int, data []byte) {
 	var setCol func(color.Color)
 	var col color.Color
@@ -77,20 +89,18 @@ func (t *Terminal) handleDefaultColor(cmd int, data []byte) {
 			}
 
 			if enc != nil && col != nil {
-				t.buf.WriteString(enc(ansi.XRGBColorizer{Color: col}))
-			}
-		} else {
-			col := ansi.XParseColor(string(parts[1]))
-			if col == nil {
-				return
+				t.respond(enc(ansi.XRGBColorizer{Color: col}))
 			}
+			return
 		}
-	case 110:
-		col = defaultFg
-	case 111:
-		col = defaultBg
-	case 112:
-		col = defaultCur
+
+		col = ansi.XParseColor(string(parts[1]))
+		if col == nil {
+			return
+		}
+	case 110, 111, 112:
+		// A nil color resets to the default color.
+		col = nil
 	}
 
 	switch cmd {
@@ -104,3 +114,32 @@ func (t *Terminal) handleDefaultColor(cmd int, data []byte) {
 
 	setCol(col)
 }
+
+// handlePaletteColor handles OSC 4 and OSC 104 palette colors.
+func (t *Terminal) handlePaletteColor(cmd int, data []byte) {
+	parts := bytes.Split(data, []byte{';'})[1:]
+	switch cmd {
+	case 4: // Set/Query palette colors
+		for i := 0; i+1 < len(parts); i += 2 {
+			idx, err := strconv.Atoi(string(parts[i]))
+			if err != nil || idx < 0 || idx > 255 {
+				continue
+			}
+			if s := string(parts[i+1]); s == "?" {
+				t.respond(ansi.SetPaletteColor(idx, ansi.XRGBColorizer{Color: t.IndexedColor(idx)}))
+			} else if col := ansi.XParseColor(s); col != nil {
+				t.SetIndexedColor(idx, col)
+			}
+		}
+	case 104: // Reset palette colors
+		if len(parts) == 0 {
+			t.colors = [256]color.Color{}
+			return
+		}
+		for _, p := range parts {
+			if idx, err := strconv.Atoi(string(p)); err == nil {
+				t.SetIndexedColor(idx, nil)
+			}
+		}
+	}
+}
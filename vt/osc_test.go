@@ -0,0 +1,69 @@
+package vt
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTerminalDefaultColors(t *testing.T) {
+	fg := color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+	bg := color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}
+	term := NewTerminal(10, 5, WithDefaultColors(fg, bg, nil))
+	if got := term.ForegroundColor(); got != fg {
+		t.Errorf("ForegroundColor() = %v, want %v", got, fg)
+	}
+	if got := term.CursorColor(); got != defaultCur {
+		t.Errorf("CursorColor() = %v, want %v", got, defaultCur)
+	}
+
+	// OSC 11 overrides the default until OSC 111.
+	term.Write([]byte(ansi.SetBackgroundColor(ansi.TrueColor(0xff0000)))) //nolint:errcheck
+	term.Write([]byte(ansi.RequestBackgroundColor))                       //nolint:errcheck
+	if got, want := readAll(t, term), "\x1b]11;rgb:ffff/0000/0000\x07"; got != want {
+		t.Errorf("background report = %q, want %q", got, want)
+	}
+	term.Write([]byte(ansi.ResetBackgroundColor)) //nolint:errcheck
+	if got := term.BackgroundColor(); got != bg {
+		t.Errorf("BackgroundColor() = %v, want %v", got, bg)
+	}
+}
+
+func TestTerminalPalette(t *testing.T) {
+	red := color.RGBA{R: 0xcc, A: 0xff}
+	term := NewTerminal(10, 5, WithPalette([]color.Color{nil, red}))
+	if got := term.ResolveColor(ansi.Red); got != red {
+		t.Errorf("ResolveColor(Red) = %v, want %v", got, red)
+	}
+	if got, want := term.ResolveColor(ansi.ExtendedColor(2)), ansi.ExtendedColor(2); got != want {
+		t.Errorf("ResolveColor(2) = %v, want %v", got, want)
+	}
+	if got, want := term.ResolveColor(ansi.TrueColor(0x123456)), ansi.TrueColor(0x123456); got != want {
+		t.Errorf("ResolveColor(TrueColor) = %v, want %v", got, want)
+	}
+
+	// OSC 4 overrides the palette until OSC 104.
+	term.Write([]byte("\x1b]4;1;#00ff00;2;?\x07")) //nolint:errcheck
+	if got, want := readAll(t, term), "\x1b]4;2;rgb:0000/8080/0000\x07"; got != want {
+		t.Errorf("palette report = %q, want %q", got, want)
+	}
+	if r, g, b, _ := term.IndexedColor(1).RGBA(); r != 0 || g != 0xffff || b != 0 {
+		t.Errorf("IndexedColor(1) = %v, want green", term.IndexedColor(1))
+	}
+	term.Write([]byte(ansi.ResetPaletteColor(1))) //nolint:errcheck
+	if got := term.IndexedColor(1); got != red {
+		t.Errorf("IndexedColor(1) = %v, want %v", got, red)
+	}
+}
+
+func TestTerminalTitleCommands(t *testing.T) {
+	term := NewTerminal(10, 5)
+	term.Write([]byte(ansi.SetWindowTitle("title") + ansi.SetIconName("icon"))) //nolint:errcheck
+	if got := term.title; got != "title" {
+		t.Errorf("title = %q, want %q", got, "title")
+	}
+	if got := term.iconName; got != "icon" {
+		t.Errorf("iconName = %q, want %q", got, "icon")
+	}
+}
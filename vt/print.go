@@ -0,0 +1,78 @@
+package vt
+
+import (
+	"bytes"
+	"strings"
+)
+
+// printerOff are the sequences that turn off the printer controller mode. The
+// 8-bit CSI form isn't recognized since it can be part of UTF-8 text.
+var printerOff = [][]byte{[]byte("\x1b[4i")}
+
+// setPrinterController turns the printer controller mode on or off. While on,
+// the terminal output is sent to the printer, see [Callbacks.Print], instead
+// of the screen.
+func (t *Terminal) setPrinterController(on bool) {
+	if t.printing == on {
+		return
+	}
+	t.printing = on
+	if t.Callbacks.PrinterController != nil {
+		t.Callbacks.PrinterController(on)
+	}
+}
+
+// printByte sends a byte written in printer controller mode to the printer.
+// It turns the mode off when the byte completes a printer off sequence.
+func (t *Terminal) printByte(b byte) {
+	t.printBuf = append(t.printBuf, b)
+	for _, off := range printerOff {
+		if bytes.HasSuffix(t.printBuf, off) {
+			t.printBuf = t.printBuf[:len(t.printBuf)-len(off)]
+			t.flushPrint(true)
+			t.setPrinterController(false)
+			return
+		}
+	}
+}
+
+// flushPrint sends the pending printer data to the printer. Unless all is
+// true, the data that might be the start of a printer off sequence is kept
+// until the next write.
+func (t *Terminal) flushPrint(all bool) {
+	n := len(t.printBuf)
+	if !all {
+		for _, off := range printerOff {
+			for k := len(off) - 1; k > 0; k-- {
+				if bytes.HasSuffix(t.printBuf, off[:k]) {
+					n = min(n, len(t.printBuf)-k)
+					break
+				}
+			}
+		}
+	}
+	if n > 0 {
+		t.print(t.printBuf[:n])
+	}
+	t.printBuf = append(t.printBuf[:0], t.printBuf[n:]...)
+}
+
+// print sends data to the printer.
+func (t *Terminal) print(data []byte) {
+	if t.Callbacks.Print != nil {
+		t.Callbacks.Print(data)
+	}
+}
+
+// printLines sends the text of the screen lines from top to bottom,
+// exclusive, to the printer. Each line ends with a new line.
+func (t *Terminal) printLines(top, bottom int) {
+	var b strings.Builder
+	t.scr.mu.RLock()
+	for y := top; y < bottom; y++ {
+		b.WriteString(t.scr.buf.Line(y).String())
+		b.WriteByte('\n')
+	}
+	t.scr.mu.RUnlock()
+	t.print([]byte(b.String()))
+}
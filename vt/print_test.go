@@ -0,0 +1,62 @@
+package vt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrinterController(t *testing.T) {
+	term := NewTerminal(10, 2)
+	var printed string
+	var modes []bool
+	term.Callbacks.Print = func(data []byte) {
+		printed += string(data)
+	}
+	term.Callbacks.PrinterController = func(on bool) {
+		modes = append(modes, on)
+	}
+
+	term.Write([]byte("ab\x1b[5ihello\x1b[1mworld\x1b")) //nolint:errcheck
+	if printed != "hello\x1b[1mworld" {
+		t.Errorf("printed = %q, want %q", printed, "hello\x1b[1mworld")
+	}
+
+	// The printer off sequence can be split across writes.
+	term.Write([]byte("[4icd")) //nolint:errcheck
+	if printed != "hello\x1b[1mworld" {
+		t.Errorf("printed = %q, want %q", printed, "hello\x1b[1mworld")
+	}
+	if got := termText(term); got[0] != "abcd      " {
+		t.Errorf("screen = %q, want %q", got[0], "abcd      ")
+	}
+	if want := []bool{true, false}; !reflect.DeepEqual(modes, want) {
+		t.Errorf("printer controller = %v, want %v", modes, want)
+	}
+
+	// A partial printer off sequence that doesn't complete is printed.
+	printed = ""
+	term.Write([]byte("\x1b[5ix\x1b[")) //nolint:errcheck
+	term.Write([]byte("5y\x1b[4i"))     //nolint:errcheck
+	if printed != "x\x1b[5y" {
+		t.Errorf("printed = %q, want %q", printed, "x\x1b[5y")
+	}
+}
+
+func TestPrintScreen(t *testing.T) {
+	term := NewTerminal(6, 2)
+	var printed string
+	term.Callbacks.Print = func(data []byte) {
+		printed += string(data)
+	}
+
+	term.Write([]byte("abc\r\ndef\x1b[i")) //nolint:errcheck
+	if printed != "abc\ndef\n" {
+		t.Errorf("print screen = %q, want %q", printed, "abc\ndef\n")
+	}
+
+	printed = ""
+	term.Write([]byte("\x1b[?1i")) //nolint:errcheck
+	if printed != "def\n" {
+		t.Errorf("print line = %q, want %q", printed, "def\n")
+	}
+}
@@ -16,6 +16,9 @@ type Screen struct {
 	cur, saved Cursor
 	// scroll is the scroll region.
 	scroll Rectangle
+	// sb is the scrollback that receives the lines scrolled off the top of
+	// the screen. It's nil when there's no scrollback.
+	sb *Scrollback
 	// mutex for the screen.
 	mu sync.RWMutex
 }
@@ -53,6 +56,15 @@ func (s *Screen) Cell(x int, y int) *Cell {
 	return s.buf.Cell(x, y)
 }
 
+// LinkAt returns the hyperlink at the given x, y position and the positions
+// of all its cells, including across soft wrapped lines. See
+// [cellbuf.Buffer.LinkAt].
+func (s *Screen) LinkAt(x, y int) (Link, []Position) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buf.LinkAt(x, y)
+}
+
 // SetCell sets the cell at the given x, y position.
 // It returns true if the cell was set successfully.
 func (s *Screen) SetCell(x, y int, c *Cell) bool {
@@ -69,6 +81,21 @@ func (s *Screen) SetCell(x, y int, c *Cell) bool {
 	return v
 }
 
+// IsWrapped returns whether the line at the given y position was soft wrapped
+// i.e. it continues on the next line.
+func (s *Screen) IsWrapped(y int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buf.IsWrapped(y)
+}
+
+// setWrapped sets whether the line at the given y position was soft wrapped.
+func (s *Screen) setWrapped(y int, v bool) {
+	s.mu.Lock()
+	s.buf.SetWrapped(y, v)
+	s.mu.Unlock()
+}
+
 // Height returns the height of the screen.
 func (s *Screen) Height() int {
 	s.mu.RLock()
@@ -130,6 +157,25 @@ func (s *Screen) Fill(c *Cell, rects ...Rectangle) {
 	}
 }
 
+// Erase fills the screen or part of it like [Screen.Fill] but leaves the
+// protected cells untouched. This is used by the selective erase operations.
+func (s *Screen) Erase(c *Cell, rects ...Rectangle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(rects) == 0 {
+		s.buf.EraseRect(c, s.buf.Bounds())
+	} else {
+		for _, r := range rects {
+			s.buf.EraseRect(c, r)
+		}
+	}
+	if s.cb.Damage != nil {
+		for _, r := range rects {
+			s.cb.Damage(RectDamage(r))
+		}
+	}
+}
+
 // setHorizontalMargins sets the horizontal margins.
 func (s *Screen) setHorizontalMargins(left, right int) {
 	s.mu.Lock()
@@ -264,12 +310,27 @@ func (s *Screen) setCursorHidden(hidden bool) {
 // setCursorStyle sets the cursor style.
 func (s *Screen) setCursorStyle(style CursorStyle, blink bool) {
 	s.mu.Lock()
+	wasSteady := s.cur.Steady
 	s.cur.Style = style
 	s.cur.Steady = !blink
 	s.mu.Unlock()
 	if s.cb.CursorStyle != nil {
 		s.cb.CursorStyle(style, !blink)
 	}
+	if s.cb.CursorBlink != nil && wasSteady != !blink {
+		s.cb.CursorBlink(blink)
+	}
+}
+
+// setCursorBlink sets whether the cursor blinks.
+func (s *Screen) setCursorBlink(blink bool) {
+	s.mu.Lock()
+	wasSteady := s.cur.Steady
+	s.cur.Steady = !blink
+	s.mu.Unlock()
+	if s.cb.CursorBlink != nil && wasSteady != !blink {
+		s.cb.CursorBlink(blink)
+	}
 }
 
 // cursorPen returns the cursor pen.
@@ -279,6 +340,34 @@ func (s *Screen) cursorPen() Style {
 	return s.cur.Pen
 }
 
+// cursorLink returns the cursor hyperlink.
+func (s *Screen) cursorLink() Link {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cur.Link
+}
+
+// setCursorLink sets the cursor hyperlink.
+func (s *Screen) setCursorLink(link Link) {
+	s.mu.Lock()
+	s.cur.Link = link
+	s.mu.Unlock()
+}
+
+// cursorProtected returns whether the cursor prints protected cells.
+func (s *Screen) cursorProtected() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cur.Protected
+}
+
+// setCursorProtected sets whether the cursor prints protected cells.
+func (s *Screen) setCursorProtected(protected bool) {
+	s.mu.Lock()
+	s.cur.Protected = protected
+	s.mu.Unlock()
+}
+
 // ShowCursor shows the cursor.
 func (s *Screen) ShowCursor() {
 	s.setCursorHidden(false)
@@ -324,15 +413,39 @@ func (s *Screen) DeleteCell(n int) {
 }
 
 // ScrollUp scrolls the content up n lines within the given region. Lines
-// scrolled past the top margin are lost. This is equivalent to [ansi.SU] which
-// moves the cursor to the top margin and performs a [ansi.DL] operation.
+// scrolled past the top margin are lost, unless the screen has a scrollback
+// and the region spans the whole width from the top of the screen, in which
+// case they're added to the scrollback. This is equivalent to [ansi.SU]
+// which moves the cursor to the top margin and performs a [ansi.DL]
+// operation.
 func (s *Screen) ScrollUp(n int) {
+	s.pushScrollback(n)
 	x, y := s.CursorPosition()
 	s.setCursor(s.cur.X, 0, true)
 	s.DeleteLine(n)
 	s.setCursor(x, y, false)
 }
 
+// pushScrollback adds the top n lines of the screen to the scrollback before
+// they're scrolled off the screen.
+func (s *Screen) pushScrollback(n int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.sb == nil || n <= 0 {
+		return
+	}
+
+	scroll := s.scroll
+	if scroll.Min.Y != 0 || scroll.Min.X != 0 || scroll.Max.X != s.buf.Width() {
+		return
+	}
+
+	n = min(n, scroll.Dy())
+	for y := 0; y < n; y++ {
+		s.sb.Push(s.buf.Line(y), s.buf.IsWrapped(y))
+	}
+}
+
 // ScrollDown scrolls the content down n lines within the given region. Lines
 // scrolled past the bottom margin are lost. This is equivalent to [ansi.SD]
 // which moves the cursor to top margin and performs a [ansi.IL] operation.
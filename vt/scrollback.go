@@ -0,0 +1,392 @@
+package vt
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// scrollbackBlockLines is the number of lines stored in a scrollback block.
+// Blocks are compressed as a whole, so bigger blocks compress better but
+// are slower to decompress when reading a single line.
+const scrollbackBlockLines = 256
+
+// scrollbackBlock is a group of packed lines. Once full, the block is
+// sealed and its data is compressed when compression is enabled.
+type scrollbackBlock struct {
+	// data is the packed lines, compressed when the block is compressed.
+	data []byte
+	// offs are the offsets of each line in the uncompressed data.
+	offs []int32
+	// compressed is whether data is compressed.
+	compressed bool
+}
+
+// Scrollback stores the lines scrolled off the top of the screen in a
+// compact form suitable for retaining a large history.
+//
+// Instead of keeping a [Cell] per column, lines are packed into bytes:
+// trailing blank cells are dropped, styles and hyperlinks are interned and
+// stored as small indexes, and cells are stored as their UTF-8 content and
+// width. Optionally, older lines are compressed in blocks. Lines are
+// unpacked when read.
+//
+// Interned styles and hyperlinks are kept until [Scrollback.Clear] is
+// called.
+//
+// It is safe to use a Scrollback from multiple goroutines.
+type Scrollback struct {
+	mu sync.Mutex
+
+	blocks []*scrollbackBlock
+	skip   int // lines evicted from the first block
+	len    int
+	max    int
+
+	compress bool
+
+	styles   []Style
+	styleIDs map[string]int
+	links    []Link
+	linkIDs  map[Link]int
+
+	// The last decompressed block and its data.
+	cached     *scrollbackBlock
+	cachedData []byte
+
+	// scratch buffer used to pack lines.
+	scratch []byte
+	// zw is the compressor used to compress sealed blocks.
+	zw *flate.Writer
+}
+
+// NewScrollback creates a new scrollback that retains up to maxLines lines.
+// When full, the oldest lines are discarded. A maxLines of 0 or less means
+// there is no limit. When compress is true, older lines are compressed to
+// use less memory at the cost of slower access.
+func NewScrollback(maxLines int, compress bool) *Scrollback {
+	sb := new(Scrollback)
+	sb.max = maxLines
+	sb.compress = compress
+	sb.reset()
+	return sb
+}
+
+// reset resets the scrollback to its initial empty state.
+func (sb *Scrollback) reset() {
+	sb.blocks = nil
+	sb.skip = 0
+	sb.len = 0
+	// Style and link 0 are always the empty style and link.
+	sb.styles = []Style{{}}
+	sb.styleIDs = map[string]int{Style{}.Sequence(): 0}
+	sb.links = []Link{{}}
+	sb.linkIDs = map[Link]int{{}: 0}
+	sb.cached, sb.cachedData = nil, nil
+}
+
+// Len returns the number of lines in the scrollback.
+func (sb *Scrollback) Len() int {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.len
+}
+
+// MaxLines returns the maximum number of lines retained in the scrollback.
+func (sb *Scrollback) MaxLines() int {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.max
+}
+
+// Clear removes all the lines from the scrollback.
+func (sb *Scrollback) Clear() {
+	sb.mu.Lock()
+	sb.reset()
+	sb.mu.Unlock()
+}
+
+// Size returns the approximate number of bytes used to store the lines.
+func (sb *Scrollback) Size() int {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	var n int
+	for _, b := range sb.blocks {
+		n += cap(b.data) + 4*cap(b.offs)
+	}
+	return n
+}
+
+// Push adds a line to the bottom of the scrollback. The wrapped flag reports
+// whether the line was soft wrapped i.e. it continues on the next line.
+func (sb *Scrollback) Push(line cellbuf.Line, wrapped bool) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	sb.scratch = sb.pack(sb.scratch[:0], line, wrapped)
+
+	var b *scrollbackBlock
+	if n := len(sb.blocks); n > 0 && len(sb.blocks[n-1].offs) < scrollbackBlockLines {
+		b = sb.blocks[n-1]
+	} else {
+		if n > 0 {
+			sb.seal(sb.blocks[n-1])
+		}
+		b = new(scrollbackBlock)
+		sb.blocks = append(sb.blocks, b)
+	}
+
+	b.offs = append(b.offs, int32(len(b.data))) //nolint:gosec
+	b.data = append(b.data, sb.scratch...)
+	sb.len++
+
+	if sb.max > 0 && sb.len > sb.max {
+		sb.skip++
+		sb.len--
+		if sb.skip >= len(sb.blocks[0].offs) {
+			if sb.cached == sb.blocks[0] {
+				sb.cached, sb.cachedData = nil, nil
+			}
+			sb.blocks[0] = nil
+			sb.blocks = sb.blocks[1:]
+			sb.skip = 0
+		}
+	}
+}
+
+// Line returns the line at the given index where 0 is the oldest line. It
+// returns nil if the index is out of bounds. Trailing blank cells are not
+// stored, so the returned line can be shorter than the screen width.
+func (sb *Scrollback) Line(i int) cellbuf.Line {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	data := sb.lineData(i)
+	if data == nil {
+		return nil
+	}
+	line, _ := sb.unpack(data)
+	return line
+}
+
+// IsWrapped returns whether the line at the given index was soft wrapped.
+func (sb *Scrollback) IsWrapped(i int) bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	data := sb.lineData(i)
+	return len(data) > 0 && data[0]&1 != 0
+}
+
+// lineData returns the packed data of the line at the given index.
+func (sb *Scrollback) lineData(i int) []byte {
+	if i < 0 || i >= sb.len {
+		return nil
+	}
+
+	i += sb.skip
+	b := sb.blocks[i/scrollbackBlockLines]
+	i %= scrollbackBlockLines
+
+	data := b.data
+	if b.compressed {
+		if sb.cached != b {
+			r := flate.NewReader(bytes.NewReader(b.data))
+			d, err := io.ReadAll(r)
+			if err != nil {
+				return nil
+			}
+			sb.cached, sb.cachedData = b, d
+		}
+		data = sb.cachedData
+	}
+
+	end := len(data)
+	if i+1 < len(b.offs) {
+		end = int(b.offs[i+1])
+	}
+	return data[b.offs[i]:end]
+}
+
+// seal shrinks a full block to its size and compresses it when compression
+// is enabled.
+func (sb *Scrollback) seal(b *scrollbackBlock) {
+	if !sb.compress {
+		b.data = append([]byte(nil), b.data...)
+		return
+	}
+
+	var buf bytes.Buffer
+	if sb.zw == nil {
+		sb.zw, _ = flate.NewWriter(&buf, flate.BestSpeed)
+	} else {
+		sb.zw.Reset(&buf)
+	}
+	sb.zw.Write(b.data) //nolint:errcheck
+	sb.zw.Close()       //nolint:errcheck
+	if buf.Len() >= len(b.data) {
+		b.data = append([]byte(nil), b.data...)
+		return
+	}
+
+	b.data = append([]byte(nil), buf.Bytes()...)
+	b.compressed = true
+}
+
+// styleID returns the interned index of the style.
+func (sb *Scrollback) styleID(s Style) int {
+	key := s.Sequence()
+	id, ok := sb.styleIDs[key]
+	if !ok {
+		id = len(sb.styles)
+		sb.styles = append(sb.styles, s)
+		sb.styleIDs[key] = id
+	}
+	return id
+}
+
+// linkID returns the interned index of the hyperlink.
+func (sb *Scrollback) linkID(l Link) int {
+	id, ok := sb.linkIDs[l]
+	if !ok {
+		id = len(sb.links)
+		sb.links = append(sb.links, l)
+		sb.linkIDs[l] = id
+	}
+	return id
+}
+
+// pack appends the packed representation of the line to buf.
+//
+// A packed line is a flags byte followed by runs of cells sharing the same
+// style and hyperlink. Each run is the number of cells, the style index, and
+// the hyperlink index, followed by the cells. Each cell is its content
+// length times 4 plus its width, followed by the width if it's 3 or more,
+// and its UTF-8 content. All numbers are unsigned varints.
+func (sb *Scrollback) pack(buf []byte, line cellbuf.Line, wrapped bool) []byte {
+	var flags byte
+	if wrapped {
+		flags |= 1
+	}
+	buf = append(buf, flags)
+
+	// Drop trailing blank cells.
+	n := len(line)
+	for n > 0 && (line[n-1] == nil || line[n-1].Equal(&cellbuf.BlankCell)) {
+		n--
+	}
+
+	for i := 0; i < n; {
+		style, link := cellStyleLink(line[i])
+		j := i + 1
+		for j < n {
+			s, l := cellStyleLink(line[j])
+			if !s.Equal(style) || l != link {
+				break
+			}
+			j++
+		}
+
+		buf = binary.AppendUvarint(buf, uint64(j-i))               //nolint:gosec
+		buf = binary.AppendUvarint(buf, uint64(sb.styleID(style))) //nolint:gosec
+		buf = binary.AppendUvarint(buf, uint64(sb.linkID(link)))   //nolint:gosec
+		for ; i < j; i++ {
+			c := line[i]
+			if c == nil {
+				c = &cellbuf.BlankCell
+			}
+			var size int
+			if c.Rune != 0 {
+				size = utf8.RuneLen(c.Rune)
+				for _, r := range c.Comb {
+					size += utf8.RuneLen(r)
+				}
+			}
+			w := min(c.Width, 3)
+			buf = binary.AppendUvarint(buf, uint64(size*4+w)) //nolint:gosec
+			if w == 3 {
+				buf = binary.AppendUvarint(buf, uint64(c.Width)) //nolint:gosec
+			}
+			if c.Rune != 0 {
+				buf = utf8.AppendRune(buf, c.Rune)
+				for _, r := range c.Comb {
+					buf = utf8.AppendRune(buf, r)
+				}
+			}
+		}
+	}
+
+	return buf
+}
+
+// unpack decodes a packed line. It returns the line and whether it was soft
+// wrapped.
+func (sb *Scrollback) unpack(data []byte) (line cellbuf.Line, wrapped bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	wrapped = data[0]&1 != 0
+	data = data[1:]
+
+	next := func() int {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			data = nil
+			return 0
+		}
+		data = data[n:]
+		return int(v) //nolint:gosec
+	}
+
+	line = cellbuf.Line{}
+	for len(data) > 0 {
+		count, sid, lid := next(), next(), next()
+		var style Style
+		var link Link
+		if sid < len(sb.styles) {
+			style = sb.styles[sid]
+		}
+		if lid < len(sb.links) {
+			link = sb.links[lid]
+		}
+		for ; count > 0 && len(data) > 0; count-- {
+			h := next()
+			size, width := h/4, h%4
+			if width == 3 {
+				width = next()
+			}
+			if size > len(data) {
+				break
+			}
+			content := data[:size]
+			data = data[size:]
+
+			c := new(Cell)
+			c.Width = width
+			c.Style = style
+			c.Link = link
+			for k, r := range string(content) {
+				if k == 0 {
+					c.Rune = r
+				} else {
+					c.Comb = append(c.Comb, r)
+				}
+			}
+			line = append(line, c)
+		}
+	}
+
+	return line, wrapped
+}
+
+// cellStyleLink returns the style and hyperlink of a cell, or the empty
+// style and hyperlink for a nil cell.
+func cellStyleLink(c *Cell) (Style, Link) {
+	if c == nil {
+		return Style{}, Link{}
+	}
+	return c.Style, c.Link
+}
@@ -0,0 +1,156 @@
+package vt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// scrollbackLineString returns the content of a scrollback line.
+func scrollbackLineString(sb *Scrollback, i int) string {
+	return sb.Line(i).String()
+}
+
+func TestScrollbackPushLine(t *testing.T) {
+	sb := NewScrollback(0, false)
+	line := cellbuf.Line{
+		cellbuf.NewCell('a'),
+		{Rune: 'b', Width: 1, Style: Style{Fg: ansi.Red, Attrs: cellbuf.BoldAttr}},
+		{Rune: '世', Width: 2, Link: Link{URL: "https://charm.sh"}},
+		{},
+		{Rune: 'e', Comb: []rune{'́'}, Width: 1},
+		nil,
+		&cellbuf.BlankCell,
+	}
+	sb.Push(line, true)
+	sb.Push(nil, false)
+
+	if sb.Len() != 2 {
+		t.Fatalf("expected 2 lines, got %d", sb.Len())
+	}
+	if !sb.IsWrapped(0) || sb.IsWrapped(1) {
+		t.Errorf("unexpected wrapped flags")
+	}
+
+	got := sb.Line(0)
+	if len(got) != 5 {
+		t.Fatalf("expected trailing blanks to be dropped, got %d cells", len(got))
+	}
+	for i, c := range got {
+		if !c.Equal(line[i]) {
+			t.Errorf("cell %d: expected %#v, got %#v", i, line[i], c)
+		}
+	}
+	if l := sb.Line(1); len(l) != 0 {
+		t.Errorf("expected an empty line, got %q", l.String())
+	}
+	if sb.Line(2) != nil || sb.Line(-1) != nil {
+		t.Errorf("expected nil for out of bounds lines")
+	}
+}
+
+func TestScrollbackMaxLines(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compress=%v", compress), func(t *testing.T) {
+			const max = scrollbackBlockLines*2 + 10
+			sb := NewScrollback(max, compress)
+			for i := 0; i < scrollbackBlockLines*5; i++ {
+				sb.Push(stringLine(fmt.Sprintf("line %d", i)), i%2 == 0)
+			}
+			if sb.Len() != max {
+				t.Fatalf("expected %d lines, got %d", max, sb.Len())
+			}
+			first := scrollbackBlockLines*5 - max
+			for _, i := range []int{0, 1, scrollbackBlockLines, max - 1, 5} {
+				if got, want := scrollbackLineString(sb, i), fmt.Sprintf("line %d", first+i); got != want {
+					t.Errorf("line %d: expected %q, got %q", i, want, got)
+				}
+				if sb.IsWrapped(i) != ((first+i)%2 == 0) {
+					t.Errorf("line %d: unexpected wrapped flag", i)
+				}
+			}
+
+			sb.Clear()
+			if sb.Len() != 0 || sb.Line(0) != nil {
+				t.Errorf("expected an empty scrollback")
+			}
+		})
+	}
+}
+
+func TestScrollbackCompression(t *testing.T) {
+	const lines = 100000
+	const width = 80
+	line := make(cellbuf.Line, width)
+	for i := range line {
+		line[i] = &Cell{Rune: rune('a' + i%26), Width: 1, Style: Style{Fg: ansi.ExtendedColor(i / 8)}}
+	}
+
+	plain := NewScrollback(lines, false)
+	compressed := NewScrollback(lines, true)
+	for i := 0; i < lines; i++ {
+		plain.Push(line, false)
+		compressed.Push(line, false)
+	}
+
+	// A cell takes more than a hundred bytes in a screen buffer.
+	if size := plain.Size(); size > lines*width*4 {
+		t.Errorf("expected packed lines to use less than %d bytes, got %d", lines*width*4, size)
+	}
+	if compressed.Size() >= plain.Size()/4 {
+		t.Errorf("expected compressed lines to use less than %d bytes, got %d", plain.Size()/4, compressed.Size())
+	}
+	for _, i := range []int{0, lines / 2, lines - 1} {
+		if got, want := scrollbackLineString(compressed, i), line.String(); got != want {
+			t.Errorf("line %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestTerminalScrollback(t *testing.T) {
+	sb := NewScrollback(100, false)
+	term := NewTerminal(5, 3, WithScrollback(sb))
+	term.Write([]byte("one\r\ntwo\r\nthree\r\nfour\r\nfive"))
+
+	if term.Scrollback() != sb {
+		t.Fatalf("expected the terminal scrollback")
+	}
+	if sb.Len() != 2 {
+		t.Fatalf("expected 2 lines in the scrollback, got %d", sb.Len())
+	}
+	for i, want := range []string{"one", "two"} {
+		if got := scrollbackLineString(sb, i); got != want {
+			t.Errorf("line %d: expected %q, got %q", i, want, got)
+		}
+	}
+
+	// Scrolling within a region that doesn't start at the top doesn't
+	// affect the scrollback.
+	term.Write([]byte("\x1b[2;3r\x1b[3;1H\n\n"))
+	if sb.Len() != 2 {
+		t.Errorf("expected 2 lines in the scrollback, got %d", sb.Len())
+	}
+
+	// The alternate screen has no scrollback.
+	term.Write([]byte("\x1b[r\x1b[?1049h\n\n\n\n"))
+	if sb.Len() != 2 {
+		t.Errorf("expected 2 lines in the scrollback, got %d", sb.Len())
+	}
+
+	// Erasing the saved lines clears the scrollback.
+	term.Write([]byte("\x1b[?1049l\x1b[3J"))
+	if sb.Len() != 0 {
+		t.Errorf("expected an empty scrollback, got %d lines", sb.Len())
+	}
+}
+
+// stringLine returns a line with the given ASCII content.
+func stringLine(s string) cellbuf.Line {
+	line := make(cellbuf.Line, len(s))
+	for i, r := range s {
+		line[i] = cellbuf.NewCell(r)
+	}
+	return line
+}
@@ -16,9 +16,15 @@ import (
 type Terminal struct {
 	handlers
 
-	// The terminal's indexed 256 colors.
+	// The terminal's indexed 256 colors set with OSC 4. They override the
+	// palette.
 	colors [256]color.Color
 
+	// The palette and default colors of the terminal. See [WithPalette] and
+	// [WithDefaultColors].
+	palette              [256]color.Color
+	defFg, defBg, defCur color.Color
+
 	// Both main and alt screens.
 	scrs [2]Screen
 
@@ -28,12 +34,16 @@ type Terminal struct {
 	// log is the logger to use.
 	logger Logger
 
-	// terminal default colors.
+	// terminal default colors set with OSC 10, 11, and 12. They override
+	// the configured default colors.
 	fg, bg, cur color.Color
 
 	// Terminal modes.
 	modes map[ansi.Mode]ansi.ModeSetting
 
+	// Kitty keyboard protocol state of the main and alt screens.
+	kitty [2]kittyKeyboard
+
 	// The current focused screen.
 	scr *Screen
 
@@ -66,6 +76,41 @@ type Terminal struct {
 	// atPhantom indicates if the cursor is out of bounds.
 	// When true, and a character is written, the cursor is moved to the next line.
 	atPhantom bool
+
+	// Response delay, read chunking, and flow control simulation. See
+	// [WithResponseDelay], [WithReadChunkSize], and [WithFlowControl].
+	responseDelay time.Duration
+	delayed       []delayedResponse
+	readChunkSize int
+	flowControl   int
+	inputPaused   bool
+
+	// Response routing, see [WithResponseRouter] and [WithResponseWriter].
+	routeID    string
+	route      func(id, resp string)
+	respWriter io.Writer
+
+	// The modes set on construction and reset, see [WithModes].
+	initModes map[ansi.Mode]ansi.ModeSetting
+
+	// The size of a cell in pixels, see [WithCellSize].
+	cellWidth, cellHeight int
+
+	// The maximum number of bytes processed per write, see
+	// [WithWriteBudget].
+	writeBudget int
+
+	// metrics are the terminal's metrics callbacks, see [WithMetrics].
+	metrics Metrics
+
+	// c1Bits8 indicates if responses use 8-bit C1 controls, see
+	// [ansi.S8C1T].
+	c1Bits8 bool
+
+	// Printer controller mode and the pending printer data. See
+	// [Callbacks.Print].
+	printing bool
+	printBuf []byte
 }
 
 var (
@@ -98,9 +143,9 @@ func NewTerminal(w, h int, opts ...Option) *Terminal {
 	t.parser.SetDataSize(1024 * 1024 * 4) // 4MB data buffer
 	t.resetModes()
 	t.tabstops = cellbuf.DefaultTabStops(w)
-	t.fg = defaultFg
-	t.bg = defaultBg
-	t.cur = defaultCur
+	t.defFg = defaultFg
+	t.defBg = defaultBg
+	t.defCur = defaultCur
 	t.registerDefaultHandlers()
 
 	for _, opt := range opts {
@@ -115,12 +160,27 @@ func (t *Terminal) Screen() *Screen {
 	return t.scr
 }
 
+// Scrollback returns the scrollback of the main screen. It returns nil if the
+// terminal has no scrollback. See [WithScrollback].
+func (t *Terminal) Scrollback() *Scrollback {
+	return t.scrs[0].sb
+}
+
 // Cell returns the current focused screen cell at the given x, y position. It returns nil if the cell
 // is out of bounds.
 func (t *Terminal) Cell(x, y int) *Cell {
 	return t.scr.Cell(x, y)
 }
 
+// MouseLink returns the hyperlink under the given mouse event on the active
+// screen and the positions of all its cells. It returns an empty link when
+// there is no hyperlink under the mouse. Hosts use this to open hyperlinks on
+// click.
+func (t *Terminal) MouseLink(m Mouse) (Link, []Position) {
+	ev := m.Mouse()
+	return t.scr.LinkAt(ev.X, ev.Y)
+}
+
 // Height returns the height of the terminal.
 func (t *Terminal) Height() int {
 	return t.scr.Height()
@@ -137,6 +197,12 @@ func (t *Terminal) CursorPosition() Position {
 	return cellbuf.Pos(x, y)
 }
 
+// CursorBlink returns whether the cursor blinks. Applications change it with
+// [ansi.CursorBlinkMode] and with the cursor style, see [ansi.DECSCUSR].
+func (t *Terminal) CursorBlink() bool {
+	return !t.scr.Cursor().Steady
+}
+
 // Resize resizes the terminal.
 func (t *Terminal) Resize(width int, height int) {
 	x, y := t.scr.CursorPosition()
@@ -162,7 +228,7 @@ func (t *Terminal) Resize(width int, height int) {
 
 	t.scrs[0].Resize(width, height)
 	t.scrs[1].Resize(width, height)
-	t.tabstops = cellbuf.DefaultTabStops(width)
+	t.tabstops.Resize(width)
 
 	t.setCursor(x, y)
 }
@@ -170,16 +236,24 @@ func (t *Terminal) Resize(width int, height int) {
 // Read reads data from the terminal input buffer.
 func (t *Terminal) Read(p []byte) (n int, err error) {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
 	if t.closed {
+		t.mu.Unlock()
 		return 0, io.EOF
 	}
 
-	if t.buf.Len() == 0 {
+	t.flushDelayed()
+	if t.buf.Len() == 0 || t.inputPaused {
+		t.mu.Unlock()
+		// Don't hold the lock while waiting for input so writers aren't
+		// blocked by an idle reader.
 		time.Sleep(10 * time.Millisecond)
 		return 0, nil
 	}
+	defer t.mu.Unlock()
+
+	if t.readChunkSize > 0 && len(p) > t.readChunkSize {
+		p = p[:t.readChunkSize]
+	}
 
 	return t.buf.Read(p)
 }
@@ -197,17 +271,44 @@ func (t *Terminal) Close() error {
 	return nil
 }
 
-// Write writes data to the terminal output buffer.
+// Write writes data to the terminal output buffer. When the terminal has a
+// write budget, it processes at most the budget and returns
+// [ErrWriteBudget] if data is left, see [WithWriteBudget].
 func (t *Terminal) Write(p []byte) (n int, err error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	end := len(p)
+	if t.writeBudget > 0 && end > t.writeBudget {
+		end = t.writeBudget
+	}
+
 	var i int
-	for i < len(p) {
-		t.parser.Advance(p[i])
+	for i < end {
+		if t.flowControl > 0 && i == t.flowControl {
+			// The output buffer is full, ask the application to wait.
+			t.buf.WriteByte(ansi.DC3) // XOFF
+		}
+		if t.printing {
+			t.printByte(p[i])
+		} else {
+			t.parser.Advance(p[i])
+		}
 		// TODO: Support grapheme clusters (mode 2027).
 		i++
 	}
+	if t.printing {
+		t.flushPrint(false)
+	}
+	if t.flowControl > 0 && i > t.flowControl {
+		t.buf.WriteByte(ansi.DC1) // XON
+	}
+	if t.metrics.BytesProcessed != nil {
+		t.metrics.BytesProcessed(i)
+	}
+	if i < len(p) {
+		return i, ErrWriteBudget
+	}
 
 	return i, nil
 }
@@ -222,6 +323,9 @@ func (t *Terminal) InputPipe() io.Writer {
 // If bracketed paste mode is enabled, the text is bracketed with the
 // appropriate escape sequences.
 func (t *Terminal) Paste(text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if t.isModeSet(ansi.BracketedPasteMode) {
 		t.buf.WriteString(ansi.BracketedPasteStart)
 		defer t.buf.WriteString(ansi.BracketedPasteEnd)
@@ -232,64 +336,86 @@ func (t *Terminal) Paste(text string) {
 
 // SendText sends text to the terminal.
 func (t *Terminal) SendText(text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.buf.WriteString(text)
 }
 
 // SendKeys sends multiple keys to the terminal.
 func (t *Terminal) SendKeys(keys ...Key) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	for _, k := range keys {
-		t.SendKey(k)
+		t.sendKey(k)
 	}
 }
 
 // ForegroundColor returns the terminal's foreground color.
 func (t *Terminal) ForegroundColor() color.Color {
-	return t.fg
+	if t.fg != nil {
+		return t.fg
+	}
+	return t.defFg
 }
 
-// SetForegroundColor sets the terminal's foreground color.
+// SetForegroundColor sets the terminal's foreground color. A nil color resets
+// it to the default foreground color, see [WithDefaultColors].
 func (t *Terminal) SetForegroundColor(c color.Color) {
 	t.fg = c
 }
 
 // BackgroundColor returns the terminal's background color.
 func (t *Terminal) BackgroundColor() color.Color {
-	return t.bg
+	if t.bg != nil {
+		return t.bg
+	}
+	return t.defBg
 }
 
-// SetBackgroundColor sets the terminal's background color.
+// SetBackgroundColor sets the terminal's background color. A nil color resets
+// it to the default background color, see [WithDefaultColors].
 func (t *Terminal) SetBackgroundColor(c color.Color) {
 	t.bg = c
 }
 
 // CursorColor returns the terminal's cursor color.
 func (t *Terminal) CursorColor() color.Color {
-	return t.cur
+	if t.cur != nil {
+		return t.cur
+	}
+	return t.defCur
 }
 
-// SetCursorColor sets the terminal's cursor color.
+// SetCursorColor sets the terminal's cursor color. A nil color resets it to
+// the default cursor color, see [WithDefaultColors].
 func (t *Terminal) SetCursorColor(c color.Color) {
 	t.cur = c
 }
 
 // IndexedColor returns a terminal's indexed color. An indexed color is a color
-// between 0 and 255.
+// between 0 and 255. The colors set with [Terminal.SetIndexedColor] override
+// the palette, see [WithPalette].
 func (t *Terminal) IndexedColor(i int) color.Color {
 	if i < 0 || i > 255 {
 		return nil
 	}
 
-	c := t.colors[i]
-	if c == nil {
-		// Return the default color.
-		return ansi.ExtendedColor(i) //nolint:gosec
+	if c := t.colors[i]; c != nil {
+		return c
+	}
+	if c := t.palette[i]; c != nil {
+		return c
 	}
 
-	return c
+	// Return the default color.
+	return ansi.ExtendedColor(i) //nolint:gosec
 }
 
 // SetIndexedColor sets a terminal's indexed color.
-// The index must be between 0 and 255.
+// The index must be between 0 and 255. A nil color resets it to the palette
+// color.
 func (t *Terminal) SetIndexedColor(i int, c color.Color) {
 	if i < 0 || i > 255 {
 		return
@@ -298,7 +424,21 @@ func (t *Terminal) SetIndexedColor(i int, c color.Color) {
 	t.colors[i] = c
 }
 
+// ResolveColor returns the color that c is rendered with. Basic and extended
+// ANSI colors are looked up with [Terminal.IndexedColor], other colors are
+// returned as is. A nil color means the default foreground or background
+// color, depending on its use.
+func (t *Terminal) ResolveColor(c color.Color) color.Color {
+	switch c := c.(type) {
+	case ansi.BasicColor:
+		return t.IndexedColor(int(c))
+	case ansi.ExtendedColor:
+		return t.IndexedColor(int(c))
+	}
+	return c
+}
+
 // resetTabStops resets the terminal tab stops to the default set.
 func (t *Terminal) resetTabStops() {
-	t.tabstops = cellbuf.DefaultTabStops(t.Width())
+	t.tabstops.ResetEvery(cellbuf.DefaultTabInterval)
 }
@@ -1,8 +1,10 @@
 package vt
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/cellbuf"
 )
 
@@ -1496,6 +1498,107 @@ var cases = []struct {
 		pos: cellbuf.Pos(1, 1),
 	},
 
+	// Selective Erase in Display [ansi.DECSED]
+	{
+		name: "DECSED Erase Below Keeps Protected",
+		w:    8, h: 3,
+		input: []string{
+			"AB\x1b[1\"qCD\x1b[0\"qEF\r\n",
+			"GH\x1b[1\"qIJ\x1b[2\"qKL",
+			"\x1b[1;2H",
+			"\x1b[?0J",
+		},
+		want: []string{
+			"A CD    ",
+			"  IJ    ",
+			"        ",
+		},
+		pos: cellbuf.Pos(1, 0),
+	},
+	{
+		name: "DECSED Erase Above Keeps Protected",
+		w:    8, h: 2,
+		input: []string{
+			"\x1b[1\"qAB\x1b[\"qCD\r\n",
+			"EF\x1b[1\"qGH",
+			"\x1b[2;2H",
+			"\x1b[?1J",
+		},
+		want: []string{
+			"AB      ",
+			"  GH    ",
+		},
+		pos: cellbuf.Pos(1, 1),
+	},
+	{
+		name: "DECSED Erase Screen Keeps Protected",
+		w:    8, h: 2,
+		input: []string{
+			"AB\x1b[1\"qCD\x1b[0\"qEF\r\n",
+			"GH",
+			"\x1b[?2J",
+		},
+		want: []string{
+			"  CD    ",
+			"        ",
+		},
+		pos: cellbuf.Pos(2, 1),
+	},
+	{
+		name: "ED Erases Protected",
+		w:    8, h: 1,
+		input: []string{
+			"\x1b[1\"qABCD",
+			"\x1b[2J",
+		},
+		want: []string{"        "},
+		pos:  cellbuf.Pos(4, 0),
+	},
+
+	// Selective Erase in Line [ansi.DECSEL]
+	{
+		name: "DECSEL Erase Right Keeps Protected",
+		w:    8, h: 1,
+		input: []string{
+			"AB\x1b[1\"qCD\x1b[0\"qEF",
+			"\x1b[1;2H",
+			"\x1b[?K",
+		},
+		want: []string{"A CD    "},
+		pos:  cellbuf.Pos(1, 0),
+	},
+	{
+		name: "DECSEL Erase Left Keeps Protected",
+		w:    8, h: 1,
+		input: []string{
+			"AB\x1b[1\"qCD\x1b[0\"qEF",
+			"\x1b[1;6H",
+			"\x1b[?1K",
+		},
+		want: []string{"  CD    "},
+		pos:  cellbuf.Pos(5, 0),
+	},
+	{
+		name: "DECSEL Erase Line Keeps Protected",
+		w:    8, h: 1,
+		input: []string{
+			"AB\x1b[1\"qCD\x1b[0\"qEF",
+			"\x1b[?2K",
+		},
+		want: []string{"  CD    "},
+		pos:  cellbuf.Pos(6, 0),
+	},
+	{
+		name: "EL Erases Protected",
+		w:    8, h: 1,
+		input: []string{
+			"\x1b[1\"qABCD",
+			"\x1b[2K",
+		},
+		want: []string{"        "},
+		pos:  cellbuf.Pos(4, 0),
+	},
+
 	// Reverse Index [ansi.RI]
 	{
 		name: "RI No Scroll Region Top of Screen",
@@ -1781,6 +1884,40 @@ var cases = []struct {
 		want: []string{"                       "},
 		pos:  cellbuf.Pos(22, 0),
 	},
+
+	// Select Character Set [ansi.SCS]
+	{
+		name: "SCS German NRCS with DECNRCM",
+		w:    8, h: 1,
+		input: []string{
+			"\x1b[?42h", // enable NRCS
+			"\x1b(K",    // G0 = German
+			"[\\]{|}~",
+		},
+		want: []string{"ÄÖÜäöüß "},
+		pos:  cellbuf.Pos(7, 0),
+	},
+	{
+		name: "SCS German NRCS Ignored without DECNRCM",
+		w:    8, h: 1,
+		input: []string{
+			"\x1b(K", // G0 = German
+			"[\\]{|}~",
+		},
+		want: []string{"[\\]{|}~ "},
+		pos:  cellbuf.Pos(7, 0),
+	},
+	{
+		name: "SCS French NRCS in G1",
+		w:    8, h: 1,
+		input: []string{
+			"\x1b[?42h", // enable NRCS
+			"\x1b)R",    // G1 = French
+			"@\x0e@\x0f@",
+		},
+		want: []string{"@à@     "},
+		pos:  cellbuf.Pos(3, 0),
+	},
 }
 
 // TestTerminal tests the terminal.
@@ -1824,3 +1961,96 @@ func termText(term *Terminal) []string {
 	}
 	return lines
 }
+
+func TestTerminalMouseLink(t *testing.T) {
+	term := newTestTerminal(t, 6, 3)
+	term.Write([]byte("ab" + ansi.SetHyperlink("https://example.com") + "cdefg" + ansi.ResetHyperlink() + "h"))
+
+	link, cells := term.MouseLink(MouseClick{X: 0, Y: 1, Button: MouseLeft})
+	if link.URL != "https://example.com" {
+		t.Fatalf("MouseLink() = %q, want %q", link.URL, "https://example.com")
+	}
+	want := []Position{{X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 0, Y: 1}}
+	if !reflect.DeepEqual(cells, want) {
+		t.Errorf("MouseLink() cells = %v, want %v", cells, want)
+	}
+
+	if link, _ := term.MouseLink(MouseClick{X: 1, Y: 1, Button: MouseLeft}); link.URL != "" {
+		t.Errorf("MouseLink() = %q, want no link", link.URL)
+	}
+}
+
+func TestTerminalWrappedLines(t *testing.T) {
+	term := newTestTerminal(t, 4, 3)
+	term.Write([]byte("abcdef\r\nxy"))
+
+	scr := term.Screen()
+	if !scr.IsWrapped(0) {
+		t.Error("line 0 should be wrapped")
+	}
+	if scr.IsWrapped(1) || scr.IsWrapped(2) {
+		t.Error("lines 1 and 2 should not be wrapped")
+	}
+
+	// Scrolling moves the wrapped flags along with their lines.
+	term.Write([]byte("\r\n"))
+	if scr.IsWrapped(0) {
+		t.Error("line 0 should not be wrapped after scrolling")
+	}
+
+	// Erasing the screen resets the wrapped flags.
+	term.Write([]byte("\x1b[H1234567\x1b[2J"))
+	if scr.IsWrapped(0) {
+		t.Error("line 0 should not be wrapped after erasing the screen")
+	}
+}
+
+func TestTerminalCursorBlink(t *testing.T) {
+	term := newTestTerminal(t, 4, 2)
+	var changes []bool
+	term.Callbacks.CursorBlink = func(blink bool) {
+		changes = append(changes, blink)
+	}
+	if !term.CursorBlink() {
+		t.Error("cursor should blink by default")
+	}
+
+	term.Write([]byte(ansi.ResetCursorBlinkMode))
+	if term.CursorBlink() {
+		t.Error("cursor should not blink after resetting the mode")
+	}
+	term.Write([]byte(ansi.SetCursorBlinkMode + ansi.SetCursorBlinkMode))
+	if !term.CursorBlink() {
+		t.Error("cursor should blink after setting the mode")
+	}
+
+	// A steady cursor style stops blinking and is reported by the mode.
+	term.Write([]byte(ansi.SetCursorStyle(2) + ansi.RequestCursorBlinkMode))
+	if term.CursorBlink() {
+		t.Error("cursor should not blink with a steady cursor style")
+	}
+	if got, want := readAll(t, term), ansi.ReportMode(ansi.CursorBlinkMode, ansi.ModeReset); got != want {
+		t.Errorf("mode report = %q, want %q", got, want)
+	}
+
+	if want := []bool{false, true, false}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("CursorBlink changes = %v, want %v", changes, want)
+	}
+
+	term.Write([]byte(ansi.SetCursorStyle(ansi.BlinkingBarCursor)))
+	if cur := term.Screen().Cursor(); cur.Style != CursorBar || cur.Steady {
+		t.Errorf("cursor style = %v, steady %v, want bar, blinking", cur.Style, cur.Steady)
+	}
+
+	// The mode applies to both screens.
+	for _, seq := range []string{ansi.ResetCursorBlinkMode, ansi.SetCursorStyle(2)} {
+		term.Write([]byte(ansi.SetAltScreenMode + ansi.SetCursorBlinkMode))
+		term.Write([]byte(seq + ansi.ResetAltScreenMode + ansi.RequestCursorBlinkMode))
+		if term.CursorBlink() {
+			t.Errorf("cursor should not blink after %q on the alternate screen", seq)
+		}
+		if got, want := readAll(t, term), ansi.ReportMode(ansi.CursorBlinkMode, ansi.ModeReset); got != want {
+			t.Errorf("mode report after %q = %q, want %q", seq, got, want)
+		}
+	}
+}
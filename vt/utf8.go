@@ -17,6 +17,7 @@ func (t *Terminal) handleUtf8(r rune) {
 		// moves cursor down similar to [Terminal.linefeed] except it doesn't
 		// respects [ansi.LNM] mode.
 		// This will rest the phantom state i.e. pending wrap state.
+		t.scr.setWrapped(y, true)
 		t.index()
 		_, y = t.scr.CursorPosition()
 		x = 0
@@ -44,10 +45,11 @@ func (t *Terminal) handleUtf8(r rune) {
 
 	cell := &Cell{
 		Style: t.scr.cursorPen(),
-		Link:  Link{}, // TODO: Link support
+		Link:  t.scr.cursorLink(),
 		// FIXME: This is incorrect and ignores combining characters
-		Rune:  firstRune(content),
-		Width: width,
+		Rune:      firstRune(content),
+		Width:     width,
+		Protected: t.scr.cursorProtected(),
 	}
 
 	if t.scr.SetCell(x, y, cell) {
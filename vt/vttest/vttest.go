@@ -0,0 +1,343 @@
+// Package vttest provides a harness to test terminal programs against a
+// virtual terminal emulator.
+//
+// A [Harness] connects a program, either a command running in a PTY or a pair
+// of pipes, to a [vt.Terminal]. Tests can send keys, mouse events, and resize
+// the terminal, wait for the screen to reach a given state, and compare the
+// final screen with golden files.
+package vttest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/charmbracelet/x/vt"
+	"github.com/charmbracelet/x/xpty"
+)
+
+// Default harness options.
+const (
+	DefaultWidth         = 80
+	DefaultHeight        = 24
+	DefaultDuration      = time.Second
+	DefaultCheckInterval = 50 * time.Millisecond
+)
+
+// options represents the harness options.
+type options struct {
+	width, height int
+}
+
+// Option is a harness option.
+type Option func(*options)
+
+// WithSize sets the initial size of the terminal. The default is 80x24.
+func WithSize(width, height int) Option {
+	return func(o *options) {
+		o.width = width
+		o.height = height
+	}
+}
+
+// WaitingForContext is the context for a [Harness.WaitFor].
+type WaitingForContext struct {
+	Duration      time.Duration
+	CheckInterval time.Duration
+}
+
+// WaitForOption changes how a [Harness.WaitFor] will behave.
+type WaitForOption func(*WaitingForContext)
+
+// WithDuration sets how much time a WaitFor will wait for the condition.
+func WithDuration(d time.Duration) WaitForOption {
+	return func(wf *WaitingForContext) {
+		wf.Duration = d
+	}
+}
+
+// WithCheckInterval sets how much time a WaitFor should sleep between every
+// check.
+func WithCheckInterval(d time.Duration) WaitForOption {
+	return func(wf *WaitingForContext) {
+		wf.CheckInterval = d
+	}
+}
+
+// Harness runs a program against a virtual terminal.
+type Harness struct {
+	tb   testing.TB
+	term *vt.Terminal
+	mu   sync.Mutex
+
+	// out is the program output and in is the program input.
+	out io.Reader
+	in  io.Writer
+
+	pty xpty.Pty
+	cmd *exec.Cmd
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New returns a harness that connects the given program output and input to
+// a virtual terminal. Everything read from out is written to the terminal,
+// and the terminal responses and input events are written to in.
+//
+// The harness is closed when the test finishes.
+func New(tb testing.TB, out io.Reader, in io.Writer, opts ...Option) *Harness {
+	tb.Helper()
+
+	o := options{width: DefaultWidth, height: DefaultHeight}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h := &Harness{
+		tb:   tb,
+		term: vt.NewTerminal(o.width, o.height),
+		out:  out,
+		in:   in,
+		done: make(chan struct{}),
+	}
+
+	go h.readOutput()
+	go h.writeInput()
+	tb.Cleanup(h.Close)
+
+	return h
+}
+
+// Start starts the given command in a PTY connected to a virtual terminal.
+// The command is killed when the test finishes if it's still running.
+func Start(tb testing.TB, cmd *exec.Cmd, opts ...Option) *Harness {
+	tb.Helper()
+
+	o := options{width: DefaultWidth, height: DefaultHeight}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	pty, err := xpty.NewPty(o.width, o.height)
+	if err != nil {
+		tb.Fatalf("vttest: failed to open pty: %v", err)
+	}
+	if err := pty.Start(cmd); err != nil {
+		pty.Close() //nolint:errcheck
+		tb.Fatalf("vttest: failed to start command: %v", err)
+	}
+
+	h := New(tb, pty, pty, opts...)
+	h.pty = pty
+	h.cmd = cmd
+
+	return h
+}
+
+// Terminal returns the virtual terminal of the harness. Use [Harness.Do] to
+// access the terminal while the program is running.
+func (h *Harness) Terminal() *vt.Terminal {
+	return h.term
+}
+
+// Do calls fn with the virtual terminal while holding the harness lock.
+func (h *Harness) Do(fn func(t *vt.Terminal)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn(h.term)
+}
+
+// SendKeys sends the given keys to the program.
+func (h *Harness) SendKeys(keys ...vt.Key) {
+	h.Do(func(t *vt.Terminal) {
+		t.SendKeys(keys...)
+	})
+}
+
+// SendText sends the given text to the program.
+func (h *Harness) SendText(text string) {
+	h.Do(func(t *vt.Terminal) {
+		t.SendText(text)
+	})
+}
+
+// SendMouse sends the given mouse event to the program. The event is only
+// sent if the program enabled mouse tracking.
+func (h *Harness) SendMouse(m vt.Mouse) {
+	h.Do(func(t *vt.Terminal) {
+		t.SendMouse(m)
+	})
+}
+
+// Paste pastes the given text to the program.
+func (h *Harness) Paste(text string) {
+	h.Do(func(t *vt.Terminal) {
+		t.Paste(text)
+	})
+}
+
+// Resize resizes the terminal. When the program runs in a PTY, the PTY is
+// resized as well which notifies the program.
+func (h *Harness) Resize(width, height int) {
+	h.tb.Helper()
+	h.Do(func(t *vt.Terminal) {
+		t.Resize(width, height)
+	})
+	if h.pty != nil {
+		if err := h.pty.Resize(width, height); err != nil {
+			h.tb.Fatalf("vttest: failed to resize pty: %v", err)
+		}
+	}
+}
+
+// Screen returns the text content of the terminal screen without styles.
+// Trailing spaces are removed from each line.
+func (h *Harness) Screen() string {
+	var s string
+	h.Do(func(t *vt.Terminal) {
+		s = screenText(t)
+	})
+	return s
+}
+
+// WaitFor waits until the condition matches the terminal. The condition is
+// called while holding the harness lock. The default duration is 1s and the
+// default check interval is 50ms.
+func (h *Harness) WaitFor(condition func(t *vt.Terminal) bool, opts ...WaitForOption) {
+	h.tb.Helper()
+
+	wf := WaitingForContext{
+		Duration:      DefaultDuration,
+		CheckInterval: DefaultCheckInterval,
+	}
+	for _, opt := range opts {
+		opt(&wf)
+	}
+
+	start := time.Now()
+	for {
+		var ok bool
+		h.Do(func(t *vt.Terminal) {
+			ok = condition(t)
+		})
+		if ok {
+			return
+		}
+		if time.Since(start) > wf.Duration {
+			break
+		}
+		time.Sleep(wf.CheckInterval)
+	}
+
+	h.tb.Fatalf("vttest: condition not met after %s. Last screen:\n%s", wf.Duration, h.Screen())
+}
+
+// WaitForText waits until the text content of the screen, as returned by
+// [Harness.Screen], matches the given regular expression.
+func (h *Harness) WaitForText(re *regexp.Regexp, opts ...WaitForOption) {
+	h.tb.Helper()
+	h.WaitFor(func(t *vt.Terminal) bool {
+		return re.MatchString(screenText(t))
+	}, opts...)
+}
+
+// RequireEqualScreen compares the terminal screen with the golden file using
+// [golden.RequireEqualScreen].
+func (h *Harness) RequireEqualScreen(opts ...golden.Option) {
+	h.tb.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	golden.RequireEqualScreen(h.tb, h.term, opts...)
+}
+
+// Wait waits for the command started with [Start] to exit.
+func (h *Harness) Wait(ctx context.Context) error {
+	if h.cmd == nil {
+		return errors.New("vttest: no command started")
+	}
+	return xpty.WaitProcess(ctx, h.cmd)
+}
+
+// Close stops the harness. It kills the command started with [Start] if it's
+// still running and closes the PTY.
+func (h *Harness) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+		if h.cmd != nil && h.cmd.Process != nil && h.cmd.ProcessState == nil {
+			h.cmd.Process.Kill() //nolint:errcheck
+		}
+		if h.pty != nil {
+			h.pty.Close() //nolint:errcheck
+		}
+		h.term.Close() //nolint:errcheck
+	})
+}
+
+// readOutput writes the program output to the terminal.
+func (h *Harness) readOutput() {
+	buf := make([]byte, 4096) //nolint:gomnd
+	for {
+		n, err := h.out.Read(buf)
+		if n > 0 {
+			h.mu.Lock()
+			h.term.Write(buf[:n]) //nolint:errcheck
+			h.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeInput writes the terminal input to the program.
+func (h *Harness) writeInput() {
+	buf := make([]byte, 4096) //nolint:gomnd
+	for {
+		select {
+		case <-h.done:
+			return
+		default:
+		}
+
+		// The terminal read doesn't block and sleeps for a short time when
+		// there's no input. The terminal guards its own input buffer, so we
+		// don't hold the harness lock here; holding it would stall the output
+		// reader for every poll.
+		n, err := h.term.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			continue
+		}
+		if _, err := h.in.Write(buf[:n]); err != nil {
+			return
+		}
+	}
+}
+
+// screenText returns the text content of the terminal screen.
+func screenText(t *vt.Terminal) string {
+	lines := make([]string, t.Height())
+	for y := range lines {
+		var b strings.Builder
+		for x := 0; x < t.Width(); x++ {
+			c := t.Cell(x, y)
+			if c == nil {
+				b.WriteByte(' ')
+				continue
+			}
+			b.WriteString(c.String())
+		}
+		lines[y] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
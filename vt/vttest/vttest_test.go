@@ -0,0 +1,61 @@
+package vttest
+
+import (
+	"bufio"
+	"io"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/vt"
+)
+
+func TestHarnessPipes(t *testing.T) {
+	outr, outw := io.Pipe()
+	inr, inw := io.Pipe()
+
+	// A tiny program that echoes every input line in red. The terminal sends
+	// a carriage return when pressing enter.
+	go func() {
+		defer outw.Close()                //nolint:errcheck
+		io.WriteString(outw, "ready\r\n") //nolint:errcheck
+		r := bufio.NewReader(inr)
+		for {
+			line, err := r.ReadString('\r')
+			if err != nil {
+				return
+			}
+			io.WriteString(outw, "\x1b[31m"+strings.TrimSuffix(line, "\r")+"\x1b[m\r\n") //nolint:errcheck
+		}
+	}()
+
+	h := New(t, outr, inw, WithSize(20, 4))
+	h.WaitForText(regexp.MustCompile(`^ready`))
+
+	h.SendText("hello")
+	h.SendKeys(vt.Key{Code: vt.KeyEnter})
+	h.WaitForText(regexp.MustCompile(`(?m)^hello$`))
+
+	if want := "ready\nhello\n\n"; h.Screen() != want {
+		t.Errorf("Screen() = %q, want %q", h.Screen(), want)
+	}
+	h.RequireEqualScreen()
+
+	h.Resize(10, 2)
+	h.Do(func(term *vt.Terminal) {
+		if term.Width() != 10 || term.Height() != 2 {
+			t.Errorf("size = %dx%d, want 10x2", term.Width(), term.Height())
+		}
+	})
+}
+
+func TestHarnessStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a Unix shell")
+	}
+
+	h := Start(t, exec.Command("sh", "-c", "printf 'hello from pty'; sleep 5"), WithSize(30, 3))
+	h.WaitForText(regexp.MustCompile(`hello from pty`))
+}
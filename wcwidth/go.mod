@@ -2,4 +2,7 @@ module github.com/charmbracelet/x/wcwidth
 
 go 1.18
 
-require golang.org/x/text v0.21.0
+require (
+	github.com/mattn/go-runewidth v0.0.16
+	github.com/rivo/uniseg v0.4.7
+)
@@ -1,21 +1,123 @@
+// Package wcwidth calculates the number of cells runes and strings occupy in
+// a terminal. It supports different width policies so that packages rendering
+// to and emulating terminals can share the same configurable implementation.
 package wcwidth
 
 import (
 	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
-// RuneWidth returns fixed-width width of rune.
+// Policy determines how the width of runes and strings is calculated.
+type Policy uint8
+
+// Width policies.
+const (
+	// Legacy calculates the width rune by rune, similar to the wcwidth(3)
+	// function found in most C libraries.
+	Legacy Policy = iota
+
+	// Grapheme calculates the width grapheme cluster by grapheme cluster
+	// using the Unicode 15 tables of uniseg. Emoji sequences and combining
+	// characters are treated as a single cell group like modern terminals
+	// supporting grapheme clustering (mode 2027) do.
+	Grapheme
+)
+
+// Condition represents a width calculation configuration.
+type Condition struct {
+	// Policy is the width policy.
+	Policy Policy
+
+	// EastAsianAmbiguousWide treats East Asian ambiguous characters as wide
+	// (2 cells) instead of narrow (1 cell). This is the expected behavior in
+	// CJK locales.
+	EastAsianAmbiguousWide bool
+}
+
+// DefaultCondition is the condition used by [RuneWidth] and [StringWidth].
+// It uses the [Legacy] policy with the go-runewidth defaults, which are
+// derived from the locale.
+var DefaultCondition = &Condition{
+	Policy:                 Legacy,
+	EastAsianAmbiguousWide: runewidth.DefaultCondition.EastAsianWidth,
+}
+
+// RuneWidth returns the number of cells the given rune occupies.
+func (c *Condition) RuneWidth(r rune) int {
+	switch c.Policy {
+	case Grapheme:
+		if c.EastAsianAmbiguousWide && runewidth.IsAmbiguousWidth(r) {
+			return 2
+		}
+		return uniseg.StringWidth(string(r))
+	default:
+		return c.legacy().RuneWidth(r)
+	}
+}
+
+// StringWidth returns the number of cells the given string occupies. The
+// string shouldn't contain any escape sequences.
+func (c *Condition) StringWidth(s string) (n int) {
+	switch c.Policy {
+	case Grapheme:
+		state := -1
+		for len(s) > 0 {
+			var cluster string
+			var w int
+			cluster, s, w, state = uniseg.FirstGraphemeClusterInString(s, state)
+			if c.EastAsianAmbiguousWide && w == 1 {
+				for _, r := range cluster {
+					if runewidth.IsAmbiguousWidth(r) {
+						w = 2
+					}
+					break
+				}
+			}
+			n += w
+		}
+		return n
+	default:
+		return c.legacy().StringWidth(s)
+	}
+}
+
+// legacy returns the go-runewidth condition matching c. The other
+// go-runewidth settings, like StrictEmojiNeutral, keep their defaults.
+func (c *Condition) legacy() *runewidth.Condition {
+	switch c.EastAsianAmbiguousWide {
+	case runewidth.DefaultCondition.EastAsianWidth:
+		return runewidth.DefaultCondition
+	case true:
+		return eastAsianCondition
+	default:
+		return narrowCondition
+	}
+}
+
+var (
+	narrowCondition = &runewidth.Condition{
+		EastAsianWidth:     false,
+		StrictEmojiNeutral: runewidth.DefaultCondition.StrictEmojiNeutral,
+	}
+	eastAsianCondition = &runewidth.Condition{
+		EastAsianWidth:     true,
+		StrictEmojiNeutral: runewidth.DefaultCondition.StrictEmojiNeutral,
+	}
+)
+
+// RuneWidth returns fixed-width width of rune using [DefaultCondition].
 //
-// Deprecated: this is now a wrapper around go-runewidth. Use go-runewidth
-// directly.
+// Deprecated: this is a wrapper around go-runewidth unless
+// [DefaultCondition] is changed. Use go-runewidth directly, or a [Condition].
 func RuneWidth(r rune) int {
-	return runewidth.RuneWidth(r)
+	return DefaultCondition.RuneWidth(r)
 }
 
-// StringWidth returns fixed-width width of string.
+// StringWidth returns fixed-width width of string using [DefaultCondition].
 //
-// Deprecated: this is now a wrapper around go-runewidth. Use go-runewidth
-// directly.
+// Deprecated: this is a wrapper around go-runewidth unless
+// [DefaultCondition] is changed. Use go-runewidth directly, or a [Condition].
 func StringWidth(s string) (n int) {
-	return runewidth.StringWidth(s)
+	return DefaultCondition.StringWidth(s)
 }
@@ -0,0 +1,70 @@
+package wcwidth
+
+import (
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestConditionWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		cond Condition
+		s    string
+		want int
+	}{
+		{"legacy ascii", Condition{}, "hello", 5},
+		{"legacy wide", Condition{}, "世界", 4},
+		{"legacy ambiguous", Condition{}, "±", 1},
+		{"legacy ambiguous wide", Condition{EastAsianAmbiguousWide: true}, "±", 2},
+		{"legacy combining", Condition{}, "é", 1},
+		{"legacy flag", Condition{}, "🇺🇸", 1},
+		{"grapheme ascii", Condition{Policy: Grapheme}, "hello", 5},
+		{"grapheme wide", Condition{Policy: Grapheme}, "世界", 4},
+		{"grapheme ambiguous", Condition{Policy: Grapheme}, "±", 1},
+		{"grapheme ambiguous wide", Condition{Policy: Grapheme, EastAsianAmbiguousWide: true}, "±", 2},
+		{"grapheme zwj", Condition{Policy: Grapheme}, "👩‍💻", 2},
+		{"grapheme flag", Condition{Policy: Grapheme}, "🇺🇸", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.StringWidth(tt.s); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionRuneWidth(t *testing.T) {
+	tests := []struct {
+		cond Condition
+		r    rune
+		want int
+	}{
+		{Condition{}, 'a', 1},
+		{Condition{}, '世', 2},
+		{Condition{}, '́', 0},
+		{Condition{EastAsianAmbiguousWide: true}, '±', 2},
+		{Condition{Policy: Grapheme}, '世', 2},
+		{Condition{Policy: Grapheme}, '±', 1},
+		{Condition{Policy: Grapheme, EastAsianAmbiguousWide: true}, '±', 2},
+	}
+	for _, tt := range tests {
+		if got := tt.cond.RuneWidth(tt.r); got != tt.want {
+			t.Errorf("RuneWidth(%q) with %+v = %d, want %d", tt.r, tt.cond, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultConditionMatchesRunewidth(t *testing.T) {
+	for _, s := range []string{"hello", "世界", "±", "☺", "é", "🇺🇸", "👩‍💻"} {
+		if got, want := StringWidth(s), runewidth.StringWidth(s); got != want {
+			t.Errorf("StringWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+	for _, r := range []rune{'a', '世', '±', '☺', '́'} {
+		if got, want := RuneWidth(r), runewidth.RuneWidth(r); got != want {
+			t.Errorf("RuneWidth(%q) = %d, want %d", r, got, want)
+		}
+	}
+}
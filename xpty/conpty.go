@@ -15,11 +15,7 @@ var _ Pty = &ConPty{}
 
 // NewConPty creates a new ConPty.
 func NewConPty(width, height int, opts ...PtyOption) (*ConPty, error) {
-	var opt Options
-	for _, o := range opts {
-		o(opt)
-	}
-
+	opt := newOptions(opts...)
 	c, err := conpty.New(width, height, opt.Flags)
 	if err != nil {
 		return nil, err
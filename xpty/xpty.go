@@ -39,11 +39,29 @@ type Pty interface {
 
 // Options represents PTY options.
 type Options struct {
+	// Flags are the platform specific flags used to create the PTY. On
+	// Windows, these are the ConPTY creation flags.
 	Flags int
 }
 
 // PtyOption is a PTY option.
-type PtyOption func(o Options)
+type PtyOption func(o *Options)
+
+// WithFlags sets the platform specific flags used to create the PTY.
+func WithFlags(flags int) PtyOption {
+	return func(o *Options) {
+		o.Flags = flags
+	}
+}
+
+// newOptions returns the options after applying the given PTY options.
+func newOptions(opts ...PtyOption) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
 
 // NewPty creates a new PTY.
 //
@@ -0,0 +1,63 @@
+package xpty
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	if o := newOptions(WithFlags(2)); o.Flags != 2 {
+		t.Errorf("Flags = %d, want 2", o.Flags)
+	}
+}
+
+func TestUnixPty(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix PTYs are not supported on Windows")
+	}
+
+	p, err := NewPty(80, 24)
+	if err != nil {
+		t.Skipf("unable to open a PTY: %v", err)
+	}
+	defer p.Close() //nolint:errcheck
+
+	if err := p.Resize(100, 30); err != nil {
+		t.Fatal(err)
+	}
+	w, h, err := p.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != 100 || h != 30 {
+		t.Errorf("Size() = %dx%d, want 100x30", w, h)
+	}
+
+	cmd := exec.Command("echo", "hello")
+	if err := p.Start(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if err := WaitProcess(context.Background(), cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 64)
+	var out []byte
+	for !bytes.Contains(out, []byte("hello")) {
+		n, err := p.Read(buf)
+		out = append(out, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Contains(out, []byte("hello")) {
+		t.Errorf("output = %q, want it to contain %q", out, "hello")
+	}
+}